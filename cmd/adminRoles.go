@@ -0,0 +1,562 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/logger"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// defaultAdminRoleName is the contract name of core.DefaultAdminRole
+const defaultAdminRoleName = "DEFAULT_ADMIN_ROLE"
+
+// roleNamePattern matches role names, which the contracts hash with keccak256 into the role
+var roleNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Administer the Lumino contracts",
+}
+
+var adminRolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Manage the AccessControl roles of the JobManager",
+	Long: `Grants, revokes, renounces and lists the AccessControl roles of the JobManager. Holding
+DEFAULT_ADMIN_ROLE authorizes executeJob --isAdmin and --isRandom, so admin keys are rotated by
+granting the role to the new key and revoking it from the old one.
+
+A role is given by its name, like DEFAULT_ADMIN_ROLE or JOB_ADMIN_ROLE, or as its 32-byte hex value.
+
+Example:
+  ./lumino admin roles grant -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --account 0x1234567890123456789012345678901234567890
+  ./lumino admin roles revoke -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --account 0x1234567890123456789012345678901234567890 --role DEFAULT_ADMIN_ROLE
+  ./lumino admin roles renounce -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --role JOB_ADMIN_ROLE
+  ./lumino admin roles list --fromBlock 2500000`,
+}
+
+var adminRolesGrantCmd = &cobra.Command{
+	Use:   "grant",
+	Short: "Grant a JobManager role to an account",
+	Run:   initialiseGrantRole,
+}
+
+var adminRolesRevokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke a JobManager role from an account",
+	Run:   initialiseRevokeRole,
+}
+
+var adminRolesRenounceCmd = &cobra.Command{
+	Use:   "renounce",
+	Short: "Renounce a JobManager role held by the sending account",
+	Run:   initialiseRenounceRole,
+}
+
+var adminRolesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the holders of the JobManager roles from the RoleGranted and RoleRevoked events",
+	Run:   initialiseListRoles,
+}
+
+func initialiseGrantRole(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteGrantRole(cmd.Flags())
+}
+
+func initialiseRevokeRole(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteRevokeRole(cmd.Flags())
+}
+
+func initialiseRenounceRole(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteRenounceRole(cmd.Flags())
+}
+
+func initialiseListRoles(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteListRoles(cmd.Flags())
+}
+
+// ExecuteGrantRole grants the role passed with --role to the account passed with --account.
+// Exits with error if the sender does not hold the admin role of the role or the transaction fails.
+func (*UtilsStruct) ExecuteGrantRole(flagSet *pflag.FlagSet) {
+	executeRoleAction(flagSet, types.RoleActionGrant)
+}
+
+// ExecuteRevokeRole revokes the role passed with --role from the account passed with --account.
+// Exits with error if the sender does not hold the admin role of the role, the account is the
+// last holder of DEFAULT_ADMIN_ROLE or the transaction fails.
+func (*UtilsStruct) ExecuteRevokeRole(flagSet *pflag.FlagSet) {
+	executeRoleAction(flagSet, types.RoleActionRevoke)
+}
+
+// ExecuteRenounceRole renounces the role passed with --role for the sending account.
+// Exits with error if the sender is the last holder of DEFAULT_ADMIN_ROLE or the transaction fails.
+func (*UtilsStruct) ExecuteRenounceRole(flagSet *pflag.FlagSet) {
+	executeRoleAction(flagSet, types.RoleActionRenounce)
+}
+
+// executeRoleAction runs a role transaction from the command line. This function:
+// 1. Sets up blockchain connection, logging and the sending account
+// 2. Resolves the role and the account it applies to, which is the sender for renounce
+// 3. Refuses to revoke or renounce DEFAULT_ADMIN_ROLE if the account is its last holder in the role events
+// 4. Submits the transaction through UpdateRole
+// Exits with error if any step fails.
+func executeRoleAction(flagSet *pflag.FlagSet, action types.RoleAction) {
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("%s: Config: %+v", action, config)
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	logger.SetLoggerParameters(client, address)
+	log.Debug("Checking to assign log file...")
+	protoUtils.AssignLogFile(flagSet)
+
+	log.Debug("Getting password...")
+	password := protoUtils.AssignPassword(flagSet)
+
+	roleFlag, err := flagSet.GetString("role")
+	utils.CheckError("Error in getting role: ", err)
+	role, err := parseRole(roleFlag)
+	utils.CheckError("Error in parsing role: ", err)
+
+	target := common.HexToAddress(address)
+	if action != types.RoleActionRenounce {
+		accountFlag, err := flagSet.GetString("account")
+		utils.CheckError("Error in getting account: ", err)
+		if !common.IsHexAddress(accountFlag) {
+			log.Fatalf("Invalid account address %q", accountFlag)
+		}
+		target = common.HexToAddress(accountFlag)
+	}
+
+	if action != types.RoleActionGrant && role == core.DefaultAdminRole {
+		fromBlock, err := flagSet.GetUint64("fromBlock")
+		utils.CheckError("Error in getting fromBlock: ", err)
+		lastHolder, err := isLastRoleHolder(client, fromBlock, role, target)
+		utils.CheckError("Error in getting role holders: ", err)
+		if lastHolder {
+			log.Fatalf("%s is the last holder of %s, grant it to another account before removing it", target.Hex(), defaultAdminRoleName)
+		}
+	}
+
+	account := types.Account{
+		Address:  address,
+		Password: password,
+	}
+
+	txnHash, err := cmdUtils.UpdateRole(client, config, account, action, role, target)
+	utils.CheckError(fmt.Sprintf("Error in %s: ", action), err)
+
+	fields := logrus.Fields{
+		"role":    roleFlag,
+		"account": target.Hex(),
+	}
+	if txnHash == (common.Hash{}) {
+		log.WithFields(fields).Info("Role already up to date, no transaction sent")
+		return
+	}
+	fields["txHash"] = txnHash.Hex()
+	log.WithFields(fields).Infof("%s succeeded", action)
+}
+
+// UpdateRole grants, revokes or renounces a JobManager role. This function:
+// 1. Validates the client
+// 2. Skips the transaction if the account already holds, or already lacks, the role
+// 3. Checks that the sender holds the admin role of the role, or for renounce that it is the account
+// 4. Constructs and submits the transaction and monitors its confirmation
+// Returns the transaction hash once it is mined, or an empty hash if no transaction was needed.
+func (*UtilsStruct) UpdateRole(client *ethclient.Client, config types.Configurations, account types.Account, action types.RoleAction, role common.Hash, target common.Address) (common.Hash, error) {
+	if client == nil {
+		log.Error("Client is nil")
+		return common.Hash{}, errors.New("client is nil")
+	}
+
+	opts := protoUtils.GetOptions()
+	holdsRole, err := jobsManagerUtils.HasRole(client, &opts, role, target)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to check role of %s: %w", target.Hex(), err)
+	}
+	if holdsRole == (action == types.RoleActionGrant) {
+		return common.Hash{}, nil
+	}
+
+	sender := common.HexToAddress(account.Address)
+	if action == types.RoleActionRenounce {
+		if target != sender {
+			return common.Hash{}, fmt.Errorf("only %s can renounce its own role", target.Hex())
+		}
+	} else {
+		adminRole, err := jobsManagerUtils.GetRoleAdmin(client, &opts, role)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get admin role: %w", err)
+		}
+		isAdmin, err := jobsManagerUtils.HasRole(client, &opts, adminRole, sender)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to check role of %s: %w", sender.Hex(), err)
+		}
+		if !isAdmin {
+			return common.Hash{}, fmt.Errorf("%s does not hold the admin role %s", sender.Hex(), common.Hash(adminRole).Hex())
+		}
+	}
+
+	log.WithFields(logrus.Fields{
+		"action":  action,
+		"role":    role.Hex(),
+		"account": target.Hex(),
+	}).Debug("Executing role transaction")
+
+	txnArgs := types.TransactionOptions{
+		Client:          client,
+		AccountAddress:  account.Address,
+		Password:        account.Password,
+		ChainId:         core.ChainID,
+		Config:          config,
+		ContractAddress: core.JobManagerAddress,
+		MethodName:      string(action),
+		Parameters:      []interface{}{[32]byte(role), target},
+		ABI:             bindings.JobManagerABI,
+	}
+
+	txnOpts := protoUtils.GetTransactionOpts(txnArgs)
+
+	var txn *Types.Transaction
+	switch action {
+	case types.RoleActionGrant:
+		txn, err = jobsManagerUtils.GrantRole(client, txnOpts, role, target)
+	case types.RoleActionRevoke:
+		txn, err = jobsManagerUtils.RevokeRole(client, txnOpts, role, target)
+	case types.RoleActionRenounce:
+		txn, err = jobsManagerUtils.RenounceRole(client, txnOpts, role, target)
+	default:
+		return common.Hash{}, fmt.Errorf("unknown role action %q", action)
+	}
+	if err != nil {
+		log.WithError(err).WithField("action", action).Error("Failed to send role transaction")
+		return common.Hash{}, err
+	}
+
+	if txn == nil {
+		log.Error("Transaction is nil")
+		return common.Hash{}, errors.New("transaction is nil")
+	}
+
+	txnHash := transactionUtils.Hash(txn)
+	log.WithFields(logrus.Fields{
+		"txHash": txnHash.Hex(),
+		"action": action,
+	}).Info("Role transaction submitted")
+
+	err = protoUtils.WaitForBlockCompletion(client, txnHash.Hex())
+	if err != nil {
+		log.WithError(err).WithField("action", action).Error("Failed to wait for block completion")
+		return common.Hash{}, err
+	}
+
+	return txnHash, nil
+}
+
+// HasAdminRole reports whether the address holds DEFAULT_ADMIN_ROLE on the JobManager.
+// Returns error if the address is invalid or the role cannot be checked.
+func (*UtilsStruct) HasAdminRole(client *ethclient.Client, address string) (bool, error) {
+	if !common.IsHexAddress(address) {
+		return false, fmt.Errorf("invalid address %q", address)
+	}
+	opts := protoUtils.GetOptions()
+	hasRole, err := jobsManagerUtils.HasRole(client, &opts, core.DefaultAdminRole, common.HexToAddress(address))
+	if err != nil {
+		return false, fmt.Errorf("failed to check admin role: %w", err)
+	}
+	return hasRole, nil
+}
+
+// ExecuteListRoles lists the holders of the JobManager roles, optionally only of the role
+// passed with --role, as a table or JSON.
+// Exits with error if the events cannot be read.
+func (*UtilsStruct) ExecuteListRoles(flagSet *pflag.FlagSet) {
+	format, err := getOutputFormat(flagSet)
+	utils.CheckError("Error in getting output format: ", err)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("ExecuteListRoles: Config: %+v", config)
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+	logger.SetLoggerParameters(client, "")
+
+	roleNames := map[common.Hash]string{core.DefaultAdminRole: defaultAdminRoleName}
+	var roleFilter *common.Hash
+	roleFlag, err := flagSet.GetString("role")
+	utils.CheckError("Error in getting role: ", err)
+	if roleFlag != "" {
+		role, err := parseRole(roleFlag)
+		utils.CheckError("Error in parsing role: ", err)
+		roleFilter = &role
+		if !strings.HasPrefix(roleFlag, "0x") {
+			roleNames[role] = roleFlag
+		}
+	}
+
+	fromBlock, err := flagSet.GetUint64("fromBlock")
+	utils.CheckError("Error in getting fromBlock: ", err)
+
+	holders, err := getRoleHolders(client, fromBlock, roleFilter, roleNames)
+	utils.CheckError("Error in getting role holders: ", err)
+
+	err = renderRoleHolders(os.Stdout, format, holders)
+	utils.CheckError("Error in showing role holders: ", err)
+}
+
+// getRoleHolders replays the RoleGranted and RoleRevoked events from fromBlock to the latest
+// block, in chunks of EventBlockRange blocks, and returns the accounts still holding a role
+// sorted by role and block. Grants before fromBlock are not seen.
+// Returns error if the events cannot be read.
+func getRoleHolders(client *ethclient.Client, fromBlock uint64, roleFilter *common.Hash, roleNames map[common.Hash]string) ([]types.RoleHolder, error) {
+	latest, err := jobEventUtils.GetLatestBlockNumber(client)
+	if err != nil {
+		return nil, err
+	}
+
+	grants := make(map[common.Hash]map[common.Address]types.RoleEvent)
+	for from := fromBlock; from <= latest; from += core.EventBlockRange {
+		to := min(from+core.EventBlockRange-1, latest)
+		events, err := roleEventUtils.FilterRoleEvents(client, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if roleFilter != nil && event.Role != *roleFilter {
+				continue
+			}
+			switch event.Kind {
+			case types.RoleEventGranted:
+				if grants[event.Role] == nil {
+					grants[event.Role] = make(map[common.Address]types.RoleEvent)
+				}
+				grants[event.Role][event.Account] = event
+			case types.RoleEventRevoked:
+				delete(grants[event.Role], event.Account)
+			}
+		}
+	}
+
+	holders := make([]types.RoleHolder, 0)
+	for role, accounts := range grants {
+		for account, event := range accounts {
+			holders = append(holders, types.RoleHolder{
+				Role:      role.Hex(),
+				RoleName:  roleNames[role],
+				Account:   account.Hex(),
+				GrantedBy: event.Sender.Hex(),
+				Block:     event.BlockNumber,
+				TxHash:    event.TxHash.Hex(),
+			})
+		}
+	}
+	sort.Slice(holders, func(i, j int) bool {
+		if holders[i].Role != holders[j].Role {
+			return holders[i].Role < holders[j].Role
+		}
+		if holders[i].Block != holders[j].Block {
+			return holders[i].Block < holders[j].Block
+		}
+		return holders[i].Account < holders[j].Account
+	})
+	return holders, nil
+}
+
+// isLastRoleHolder reports whether account is the only holder of role found in the role events
+// from fromBlock, so that revoking or renouncing it would leave the JobManager without a holder.
+// Returns error if the events cannot be read.
+func isLastRoleHolder(client *ethclient.Client, fromBlock uint64, role common.Hash, account common.Address) (bool, error) {
+	holders, err := getRoleHolders(client, fromBlock, &role, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(holders) == 1 && holders[0].Account == account.Hex(), nil
+}
+
+// renderRoleHolders writes the role holders to out as JSON or as a table
+func renderRoleHolders(out io.Writer, format string, holders []types.RoleHolder) error {
+	if format == outputFormatJSON {
+		return writeJSON(out, holders)
+	}
+	if len(holders) == 0 {
+		_, err := fmt.Fprintln(out, "No role holders")
+		return err
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Role", "Account", "Granted By", "Block", "Tx Hash"})
+	for _, holder := range holders {
+		role := holder.Role
+		if holder.RoleName != "" {
+			role = holder.RoleName
+		}
+		table.Append([]string{role, holder.Account, holder.GrantedBy, strconv.FormatUint(holder.Block, 10), holder.TxHash})
+	}
+	table.Render()
+	return nil
+}
+
+// parseRole returns the role given by its name or its 32-byte hex value. Named roles are
+// the keccak256 hash of the name, except DEFAULT_ADMIN_ROLE which is zero.
+// Returns error if the value is neither.
+func parseRole(value string) (common.Hash, error) {
+	switch {
+	case value == defaultAdminRoleName:
+		return core.DefaultAdminRole, nil
+	case strings.HasPrefix(value, "0x"):
+		data, err := hexutil.Decode(value)
+		if err != nil || len(data) != common.HashLength {
+			return common.Hash{}, fmt.Errorf("invalid role %q, expected 32 bytes of hex", value)
+		}
+		return common.BytesToHash(data), nil
+	case roleNamePattern.MatchString(value):
+		return crypto.Keccak256Hash([]byte(value)), nil
+	default:
+		return common.Hash{}, fmt.Errorf("invalid role %q, expected a name like %s or 32 bytes of hex", value, defaultAdminRoleName)
+	}
+}
+
+// Initializes the admin command group in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(adminRolesCmd)
+	adminRolesCmd.AddCommand(adminRolesGrantCmd, adminRolesRevokeCmd, adminRolesRenounceCmd, adminRolesListCmd)
+
+	for _, cmd := range []*cobra.Command{adminRolesGrantCmd, adminRolesRevokeCmd, adminRolesRenounceCmd} {
+		var (
+			Account  string
+			Password string
+			Role     string
+		)
+
+		cmd.Flags().StringVarP(&Account, "address", "a", "", "address of the sender")
+		cmd.Flags().StringVarP(&Password, "password", "", "", "password path of the sender to protect the keystore")
+		if cmd == adminRolesGrantCmd {
+			cmd.Flags().StringVarP(&Role, "role", "", defaultAdminRoleName, "role name or 32-byte hex value")
+		} else {
+			cmd.Flags().StringVarP(&Role, "role", "", "", "role name or 32-byte hex value")
+
+			roleErr := cmd.MarkFlagRequired("role")
+			utils.CheckError("Role error: ", roleErr)
+		}
+
+		addrErr := cmd.MarkFlagRequired("address")
+		utils.CheckError("Address error: ", addrErr)
+	}
+
+	for _, cmd := range []*cobra.Command{adminRolesRevokeCmd, adminRolesRenounceCmd} {
+		var FromBlock uint64
+
+		cmd.Flags().Uint64VarP(&FromBlock, "fromBlock", "", 0, "block to scan the role events from when removing DEFAULT_ADMIN_ROLE")
+	}
+
+	for _, cmd := range []*cobra.Command{adminRolesGrantCmd, adminRolesRevokeCmd} {
+		var Target string
+
+		cmd.Flags().StringVarP(&Target, "account", "", "", "address of the account the role applies to")
+
+		accountErr := cmd.MarkFlagRequired("account")
+		utils.CheckError("Account error: ", accountErr)
+	}
+
+	var (
+		Role      string
+		FromBlock uint64
+		Output    string
+	)
+
+	adminRolesListCmd.Flags().StringVarP(&Role, "role", "", "", "only list the holders of this role")
+	adminRolesListCmd.Flags().Uint64VarP(&FromBlock, "fromBlock", "", 0, "block to scan the role events from, the JobManager deployment block covers every grant")
+	adminRolesListCmd.Flags().StringVarP(&Output, "output", "o", outputFormatTable, "output format (table, json)")
+}
+
+// FilterRoleEvents returns the RoleGranted and RoleRevoked events emitted by the
+// JobManager between fromBlock and toBlock inclusive, in log order.
+// Returns error if the logs cannot be fetched or decoded.
+func (RoleEventUtils) FilterRoleEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.RoleEvent, error) {
+	jobManagerABI, err := bindings.JobManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JobManager ABI: %w", err)
+	}
+	grantedID := jobManagerABI.Events[string(types.RoleEventGranted)].ID
+	revokedID := jobManagerABI.Events[string(types.RoleEventRevoked)].ID
+
+	jobManagerAddress := common.HexToAddress(core.JobManagerAddress)
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{jobManagerAddress},
+		Topics:    [][]common.Hash{{grantedID, revokedID}},
+	}
+	logs, err := utils.ClientInterface.FilterLogs(client, context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter JobManager logs: %w", err)
+	}
+
+	filterer, err := bindings.NewJobManagerFilterer(jobManagerAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JobManager filterer: %w", err)
+	}
+
+	events := make([]types.RoleEvent, 0, len(logs))
+	for _, vLog := range logs {
+		if vLog.Removed || len(vLog.Topics) == 0 {
+			continue
+		}
+		event := types.RoleEvent{
+			BlockNumber: vLog.BlockNumber,
+			TxHash:      vLog.TxHash,
+			LogIndex:    vLog.Index,
+		}
+		switch vLog.Topics[0] {
+		case grantedID:
+			granted, err := filterer.ParseRoleGranted(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode RoleGranted: %w", err)
+			}
+			event.Kind = types.RoleEventGranted
+			event.Role = granted.Role
+			event.Account = granted.Account
+			event.Sender = granted.Sender
+		case revokedID:
+			revoked, err := filterer.ParseRoleRevoked(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode RoleRevoked: %w", err)
+			}
+			event.Kind = types.RoleEventRevoked
+			event.Role = revoked.Role
+			event.Account = revoked.Account
+			event.Sender = revoked.Sender
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,592 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	mocks2 "lumino/utils/mocks"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests role transactions including:
+// 1. Granting, revoking and renouncing a role
+// 2. Skipping the transaction when the role is already up to date
+// 3. A sender without the admin role of the role
+// 4. Renouncing the role of another account
+// 5. Nil client handling and transaction failures
+func TestUpdateRole(t *testing.T) {
+	client := &ethclient.Client{}
+	account := types.Account{
+		Address:  "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+		Password: "password",
+	}
+	sender := common.HexToAddress(account.Address)
+	target := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	role := crypto.Keccak256Hash([]byte("JOB_ADMIN_ROLE"))
+	adminRole := [32]byte(core.DefaultAdminRole)
+
+	tests := []struct {
+		name          string
+		client        *ethclient.Client
+		action        types.RoleAction
+		target        common.Address
+		setupMocks    func(*mocks.JobsManagerInterface, *mocks.UtilsInterface, *mocks.TransactionInterface)
+		expectedHash  common.Hash
+		expectedError bool
+	}{
+		{
+			name:   "grant",
+			client: client,
+			action: types.RoleActionGrant,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(false, nil)
+				jobsMock.On("GetRoleAdmin", client, mock.Anything, [32]byte(role)).Return(adminRole, nil)
+				jobsMock.On("HasRole", client, mock.Anything, adminRole, sender).Return(true, nil)
+				utilsMock.On("GetTransactionOpts", mock.MatchedBy(func(txnArgs types.TransactionOptions) bool {
+					return txnArgs.MethodName == "grantRole" && txnArgs.ContractAddress == core.JobManagerAddress
+				})).Return(nil)
+				jobsMock.On("GrantRole", client, mock.Anything, [32]byte(role), target).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+			},
+			expectedHash: common.HexToHash("0x123"),
+		},
+		{
+			name:   "revoke",
+			client: client,
+			action: types.RoleActionRevoke,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(true, nil)
+				jobsMock.On("GetRoleAdmin", client, mock.Anything, [32]byte(role)).Return(adminRole, nil)
+				jobsMock.On("HasRole", client, mock.Anything, adminRole, sender).Return(true, nil)
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				jobsMock.On("RevokeRole", client, mock.Anything, [32]byte(role), target).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+			},
+			expectedHash: common.HexToHash("0x123"),
+		},
+		{
+			name:   "renounce",
+			client: client,
+			action: types.RoleActionRenounce,
+			target: sender,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), sender).Return(true, nil)
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				jobsMock.On("RenounceRole", client, mock.Anything, [32]byte(role), sender).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+			},
+			expectedHash: common.HexToHash("0x123"),
+		},
+		{
+			name:   "role already granted",
+			client: client,
+			action: types.RoleActionGrant,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(true, nil)
+			},
+		},
+		{
+			name:   "role already revoked",
+			client: client,
+			action: types.RoleActionRevoke,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(false, nil)
+			},
+		},
+		{
+			name:   "sender without the admin role",
+			client: client,
+			action: types.RoleActionGrant,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(false, nil)
+				jobsMock.On("GetRoleAdmin", client, mock.Anything, [32]byte(role)).Return(adminRole, nil)
+				jobsMock.On("HasRole", client, mock.Anything, adminRole, sender).Return(false, nil)
+			},
+			expectedError: true,
+		},
+		{
+			name:   "renouncing the role of another account",
+			client: client,
+			action: types.RoleActionRenounce,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(true, nil)
+			},
+			expectedError: true,
+		},
+		{
+			name:   "role cannot be checked",
+			client: client,
+			action: types.RoleActionGrant,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(false, errors.New("rpc error"))
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When ethereum client is nil",
+			action: types.RoleActionGrant,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When there is a transaction error",
+			client: client,
+			action: types.RoleActionGrant,
+			target: target,
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				jobsMock.On("HasRole", client, mock.Anything, [32]byte(role), target).Return(false, nil)
+				jobsMock.On("GetRoleAdmin", client, mock.Anything, [32]byte(role)).Return(adminRole, nil)
+				jobsMock.On("HasRole", client, mock.Anything, adminRole, sender).Return(true, nil)
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				jobsMock.On("GrantRole", client, mock.Anything, [32]byte(role), target).Return(nil, errors.New("transaction error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobsMock := new(mocks.JobsManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+			txMock := new(mocks.TransactionInterface)
+
+			originalJobsManagerUtils := jobsManagerUtils
+			originalProtoUtils := protoUtils
+			originalTransactionUtils := transactionUtils
+			defer func() {
+				jobsManagerUtils = originalJobsManagerUtils
+				protoUtils = originalProtoUtils
+				transactionUtils = originalTransactionUtils
+			}()
+			jobsManagerUtils = jobsMock
+			protoUtils = utilsMock
+			transactionUtils = txMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(jobsMock, utilsMock, txMock)
+
+			utils := &UtilsStruct{}
+			txnHash, err := utils.UpdateRole(tt.client, types.Configurations{}, account, tt.action, role, tt.target)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHash, txnHash)
+		})
+	}
+}
+
+// Tests checking DEFAULT_ADMIN_ROLE on the JobManager
+func TestHasAdminRole(t *testing.T) {
+	client := &ethclient.Client{}
+	admin := common.HexToAddress("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771")
+	other := common.HexToAddress("0x1234567890123456789012345678901234567890")
+	broken := common.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	jobsMock := new(mocks.JobsManagerInterface)
+	utilsMock := new(mocks.UtilsInterface)
+	originalJobsManagerUtils := jobsManagerUtils
+	originalProtoUtils := protoUtils
+	defer func() {
+		jobsManagerUtils = originalJobsManagerUtils
+		protoUtils = originalProtoUtils
+	}()
+	jobsManagerUtils = jobsMock
+	protoUtils = utilsMock
+
+	utilsMock.On("GetOptions").Return(bind.CallOpts{})
+	jobsMock.On("HasRole", client, mock.Anything, [32]byte(core.DefaultAdminRole), admin).Return(true, nil)
+	jobsMock.On("HasRole", client, mock.Anything, [32]byte(core.DefaultAdminRole), other).Return(false, nil)
+	jobsMock.On("HasRole", client, mock.Anything, [32]byte(core.DefaultAdminRole), broken).Return(false, errors.New("rpc error"))
+
+	utils := &UtilsStruct{}
+	hasRole, err := utils.HasAdminRole(client, admin.Hex())
+	assert.NoError(t, err)
+	assert.True(t, hasRole)
+
+	hasRole, err = utils.HasAdminRole(client, other.Hex())
+	assert.NoError(t, err)
+	assert.False(t, hasRole)
+
+	_, err = utils.HasAdminRole(client, broken.Hex())
+	assert.Error(t, err)
+
+	_, err = utils.HasAdminRole(client, "not-an-address")
+	assert.Error(t, err)
+}
+
+// Tests replaying the role events into the current holders covering:
+// 1. Grants and revocations across scan chunks
+// 2. Filtering by role
+// 3. Events that cannot be read
+func TestGetRoleHolders(t *testing.T) {
+	client := &ethclient.Client{}
+	jobRole := crypto.Keccak256Hash([]byte("JOB_ADMIN_ROLE"))
+	alice := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	bob := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	deployer := common.HexToAddress("0x3000000000000000000000000000000000000003")
+	roleNames := map[common.Hash]string{core.DefaultAdminRole: defaultAdminRoleName}
+
+	firstChunk := []types.RoleEvent{
+		{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: deployer, Sender: deployer, BlockNumber: 5},
+		{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: alice, Sender: deployer, BlockNumber: 8},
+		{Kind: types.RoleEventGranted, Role: jobRole, Account: bob, Sender: alice, BlockNumber: 9},
+	}
+	secondChunk := []types.RoleEvent{
+		{Kind: types.RoleEventRevoked, Role: core.DefaultAdminRole, Account: deployer, Sender: alice, BlockNumber: 12},
+	}
+
+	tests := []struct {
+		name            string
+		roleFilter      *common.Hash
+		eventsErr       error
+		expectedHolders []types.RoleHolder
+		expectedError   bool
+	}{
+		{
+			name: "all roles",
+			expectedHolders: []types.RoleHolder{
+				{Role: core.DefaultAdminRole.Hex(), RoleName: defaultAdminRoleName, Account: alice.Hex(), GrantedBy: deployer.Hex(), Block: 8, TxHash: common.Hash{}.Hex()},
+				{Role: jobRole.Hex(), Account: bob.Hex(), GrantedBy: alice.Hex(), Block: 9, TxHash: common.Hash{}.Hex()},
+			},
+		},
+		{
+			name:       "one role",
+			roleFilter: &jobRole,
+			expectedHolders: []types.RoleHolder{
+				{Role: jobRole.Hex(), Account: bob.Hex(), GrantedBy: alice.Hex(), Block: 9, TxHash: common.Hash{}.Hex()},
+			},
+		},
+		{
+			name:          "events cannot be read",
+			eventsErr:     errors.New("rpc error"),
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventMock := new(mocks.JobEventInterface)
+			roleEventMock := new(mocks.RoleEventInterface)
+			originalJobEventUtils := jobEventUtils
+			originalRoleEventUtils := roleEventUtils
+			originalEventBlockRange := core.EventBlockRange
+			defer func() {
+				jobEventUtils = originalJobEventUtils
+				roleEventUtils = originalRoleEventUtils
+				core.EventBlockRange = originalEventBlockRange
+			}()
+			jobEventUtils = eventMock
+			roleEventUtils = roleEventMock
+			core.EventBlockRange = 10
+
+			eventMock.On("GetLatestBlockNumber", client).Return(uint64(15), nil)
+			roleEventMock.On("FilterRoleEvents", client, uint64(1), uint64(10)).Return(firstChunk, tt.eventsErr)
+			roleEventMock.On("FilterRoleEvents", client, uint64(11), uint64(15)).Return(secondChunk, nil)
+
+			holders, err := getRoleHolders(client, 1, tt.roleFilter, roleNames)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedHolders, holders)
+		})
+	}
+}
+
+// Tests the last holder check of the role commands covering:
+// 1. Revoking DEFAULT_ADMIN_ROLE from its last holder is refused
+// 2. Renouncing DEFAULT_ADMIN_ROLE as its last holder is refused
+// 3. Revoking DEFAULT_ADMIN_ROLE from one of several holders
+// 4. Revoking another role is not checked
+func TestExecuteRoleAction(t *testing.T) {
+	var client *ethclient.Client
+	sender := common.HexToAddress("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771")
+	alice := common.HexToAddress("0x1000000000000000000000000000000000000001")
+
+	onlyAlice := []types.RoleEvent{
+		{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: alice, Sender: alice, BlockNumber: 5},
+	}
+	onlySender := []types.RoleEvent{
+		{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: sender, Sender: sender, BlockNumber: 5},
+	}
+	both := append(onlyAlice, types.RoleEvent{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: sender, Sender: alice, BlockNumber: 6})
+
+	tests := []struct {
+		name          string
+		action        types.RoleAction
+		role          string
+		events        []types.RoleEvent
+		expectedFatal bool
+	}{
+		{
+			name:          "revoke from the last admin",
+			action:        types.RoleActionRevoke,
+			role:          defaultAdminRoleName,
+			events:        onlyAlice,
+			expectedFatal: true,
+		},
+		{
+			name:          "renounce as the last admin",
+			action:        types.RoleActionRenounce,
+			role:          defaultAdminRoleName,
+			events:        onlySender,
+			expectedFatal: true,
+		},
+		{
+			name:   "revoke from one of several admins",
+			action: types.RoleActionRevoke,
+			role:   defaultAdminRoleName,
+			events: both,
+		},
+		{
+			name:   "revoke another role",
+			action: types.RoleActionRevoke,
+			role:   "JOB_ADMIN_ROLE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			flagSetMock := new(mocks.FlagSetInterface)
+			cmdMock := new(mocks.UtilsCmdInterface)
+			eventMock := new(mocks.JobEventInterface)
+			roleEventMock := new(mocks.RoleEventInterface)
+
+			originalProtoUtils := protoUtils
+			originalFlagSetUtils := flagSetUtils
+			originalCmdUtils := cmdUtils
+			originalJobEventUtils := jobEventUtils
+			originalRoleEventUtils := roleEventUtils
+			defer func() {
+				protoUtils = originalProtoUtils
+				flagSetUtils = originalFlagSetUtils
+				cmdUtils = originalCmdUtils
+				jobEventUtils = originalJobEventUtils
+				roleEventUtils = originalRoleEventUtils
+				log.ExitFunc = nil
+			}()
+			protoUtils = utilsMock
+			flagSetUtils = flagSetMock
+			cmdUtils = cmdMock
+			jobEventUtils = eventMock
+			roleEventUtils = roleEventMock
+
+			flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flagSet.String("role", tt.role, "")
+			flagSet.String("account", alice.Hex(), "")
+			flagSet.Uint64("fromBlock", 1, "")
+
+			cmdMock.On("GetConfigData").Return(types.Configurations{}, nil)
+			utilsMock.On("ConnectToEthClient", mock.AnythingOfType("string")).Return(client)
+			flagSetMock.On("GetStringAddress", mock.Anything).Return(sender.Hex(), nil)
+			utilsMock.On("AssignLogFile", mock.Anything)
+			utilsMock.On("AssignPassword", mock.Anything).Return("password")
+			eventMock.On("GetLatestBlockNumber", client).Return(uint64(10), nil)
+			roleEventMock.On("FilterRoleEvents", client, uint64(1), uint64(10)).Return(tt.events, nil)
+			cmdMock.On("UpdateRole", client, mock.Anything, mock.Anything, tt.action, mock.Anything, mock.Anything).Return(common.HexToHash("0x1"), nil)
+
+			var fatal bool
+			log.ExitFunc = func(int) { fatal = true }
+
+			executeRoleAction(flagSet, tt.action)
+
+			assert.Equal(t, tt.expectedFatal, fatal)
+			if tt.events == nil {
+				roleEventMock.AssertNotCalled(t, "FilterRoleEvents", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+// Tests finding the last holder of a role covering:
+// 1. The only remaining holder after the other holders are revoked
+// 2. One of several holders
+// 3. An account that is not a holder found in the events
+// 4. Events that cannot be read
+func TestIsLastRoleHolder(t *testing.T) {
+	client := &ethclient.Client{}
+	alice := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	bob := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	deployer := common.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	granted := []types.RoleEvent{
+		{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: deployer, Sender: deployer, BlockNumber: 5},
+		{Kind: types.RoleEventGranted, Role: core.DefaultAdminRole, Account: alice, Sender: deployer, BlockNumber: 8},
+	}
+	revoked := append(granted, types.RoleEvent{Kind: types.RoleEventRevoked, Role: core.DefaultAdminRole, Account: deployer, Sender: alice, BlockNumber: 9})
+
+	tests := []struct {
+		name          string
+		events        []types.RoleEvent
+		eventsErr     error
+		account       common.Address
+		expected      bool
+		expectedError bool
+	}{
+		{
+			name:     "only remaining holder",
+			events:   revoked,
+			account:  alice,
+			expected: true,
+		},
+		{
+			name:    "one of several holders",
+			events:  granted,
+			account: alice,
+		},
+		{
+			name:    "not a holder",
+			events:  revoked,
+			account: bob,
+		},
+		{
+			name:          "events cannot be read",
+			eventsErr:     errors.New("rpc error"),
+			account:       alice,
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventMock := new(mocks.JobEventInterface)
+			roleEventMock := new(mocks.RoleEventInterface)
+			originalJobEventUtils := jobEventUtils
+			originalRoleEventUtils := roleEventUtils
+			defer func() {
+				jobEventUtils = originalJobEventUtils
+				roleEventUtils = originalRoleEventUtils
+			}()
+			jobEventUtils = eventMock
+			roleEventUtils = roleEventMock
+
+			eventMock.On("GetLatestBlockNumber", client).Return(uint64(10), nil)
+			roleEventMock.On("FilterRoleEvents", client, uint64(1), uint64(10)).Return(tt.events, tt.eventsErr)
+
+			lastHolder, err := isLastRoleHolder(client, 1, core.DefaultAdminRole, tt.account)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, lastHolder)
+		})
+	}
+}
+
+// Tests rendering role holders as a table, using the role name when known
+func TestRenderRoleHolders(t *testing.T) {
+	holders := []types.RoleHolder{
+		{Role: core.DefaultAdminRole.Hex(), RoleName: defaultAdminRoleName, Account: "0xAlice", GrantedBy: "0xDeployer", Block: 8},
+	}
+
+	var out bytes.Buffer
+	assert.NoError(t, renderRoleHolders(&out, outputFormatTable, holders))
+	assert.Contains(t, out.String(), defaultAdminRoleName)
+	assert.Contains(t, out.String(), "0xAlice")
+
+	out.Reset()
+	assert.NoError(t, renderRoleHolders(&out, outputFormatTable, nil))
+	assert.Equal(t, "No role holders\n", out.String())
+}
+
+// Tests parsing roles given by name or as hex
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		value         string
+		expectedRole  common.Hash
+		expectedError bool
+	}{
+		{value: "DEFAULT_ADMIN_ROLE", expectedRole: common.Hash{}},
+		{value: "JOB_ADMIN_ROLE", expectedRole: crypto.Keccak256Hash([]byte("JOB_ADMIN_ROLE"))},
+		{value: "0x0000000000000000000000000000000000000000000000000000000000000001", expectedRole: common.HexToHash("0x01")},
+		{value: "0x01", expectedError: true},
+		{value: "0xzz00000000000000000000000000000000000000000000000000000000000001", expectedError: true},
+		{value: "job admin", expectedError: true},
+		{value: "", expectedError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			role, err := parseRole(tt.value)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRole, role)
+		})
+	}
+}
+
+// Tests decoding the RoleGranted and RoleRevoked events of the JobManager
+func TestFilterRoleEvents(t *testing.T) {
+	var client *ethclient.Client
+
+	jobManagerABI, err := bindings.JobManagerMetaData.GetAbi()
+	assert.NoError(t, err)
+	role := common.HexToHash("0x01")
+	account := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	sender := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	topics := func(event string) []common.Hash {
+		return []common.Hash{jobManagerABI.Events[event].ID, role, common.BytesToHash(account.Bytes()), common.BytesToHash(sender.Bytes())}
+	}
+
+	logs := []ethTypes.Log{
+		{Topics: topics("RoleGranted"), BlockNumber: 10},
+		{Topics: topics("RoleRevoked"), BlockNumber: 11, Removed: true},
+		{Topics: topics("RoleRevoked"), BlockNumber: 12, Index: 2},
+	}
+
+	clientMock := new(mocks2.ClientUtils)
+	originalClientInterface := utils.ClientInterface
+	defer func() {
+		utils.ClientInterface = originalClientInterface
+	}()
+	utils.ClientInterface = clientMock
+
+	clientMock.On("FilterLogs", mock.Anything, mock.Anything, mock.MatchedBy(func(query ethereum.FilterQuery) bool {
+		return query.FromBlock.Uint64() == 10 && query.ToBlock.Uint64() == 12 &&
+			query.Addresses[0] == common.HexToAddress(core.JobManagerAddress) && len(query.Topics[0]) == 2
+	})).Return(logs, nil)
+
+	events, err := RoleEventUtils{}.FilterRoleEvents(client, 10, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.RoleEvent{
+		{Kind: types.RoleEventGranted, Role: role, Account: account, Sender: sender, BlockNumber: 10},
+		{Kind: types.RoleEventRevoked, Role: role, Account: account, Sender: sender, BlockNumber: 12, LogIndex: 2},
+	}, events)
+}
@@ -0,0 +1,166 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"lumino/core/types"
+	"lumino/path"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// GetRound loads the persisted assignment round. A missing round file is
+// treated as an empty round.
+func (AssignmentRoundUtils) GetRound() (types.AssignmentRound, error) {
+	round := types.AssignmentRound{Assignments: make(map[string]types.RoundAssignment)}
+
+	roundPath, err := path.PathUtilsInterface.GetAssignmentRoundFilePath()
+	if err != nil {
+		return round, err
+	}
+	if _, err := path.OSUtilsInterface.Stat(roundPath); path.OSUtilsInterface.IsNotExist(err) {
+		return round, nil
+	}
+
+	data, err := path.OSUtilsInterface.ReadFile(roundPath)
+	if err != nil {
+		return round, fmt.Errorf("failed to read assignment round: %w", err)
+	}
+	if len(data) == 0 {
+		return round, nil
+	}
+	if err := json.Unmarshal(data, &round); err != nil {
+		return round, fmt.Errorf("failed to parse assignment round: %w", err)
+	}
+	if round.Assignments == nil {
+		round.Assignments = make(map[string]types.RoundAssignment)
+	}
+	return round, nil
+}
+
+// SaveRound persists the assignment round atomically by writing to a temporary
+// file and renaming it over the existing round file.
+func (AssignmentRoundUtils) SaveRound(round types.AssignmentRound) error {
+	roundPath, err := path.PathUtilsInterface.GetAssignmentRoundFilePath()
+	if err != nil {
+		return err
+	}
+
+	round.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(round, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal assignment round: %w", err)
+	}
+	tmpPath := roundPath + ".tmp"
+	if err := path.OSUtilsInterface.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write assignment round: %w", err)
+	}
+	if err := path.OSUtilsInterface.Rename(tmpPath, roundPath); err != nil {
+		return fmt.Errorf("failed to replace assignment round: %w", err)
+	}
+	return nil
+}
+
+// loadAssignmentRound returns the assignment round of the epoch. A round left
+// over from an earlier epoch is discarded and a fresh round is started.
+func loadAssignmentRound(epoch uint32, strategyName string) (types.AssignmentRound, error) {
+	round, err := assignmentRoundUtils.GetRound()
+	if err != nil {
+		return types.AssignmentRound{}, err
+	}
+	if round.Epoch != epoch {
+		if round.Epoch != 0 {
+			log.WithFields(logrus.Fields{
+				"previousEpoch": round.Epoch,
+				"epoch":         epoch,
+			}).Debug("Starting new assignment round")
+		}
+		round = types.AssignmentRound{
+			Epoch:       epoch,
+			Strategy:    strategyName,
+			Assignments: make(map[string]types.RoundAssignment),
+		}
+	}
+	return round, nil
+}
+
+// reconcilePendingAssignments resolves assignments left pending by an interrupted
+// round. This function:
+// 1. Marks pending jobs that have an assignee on chain as assigned
+// 2. Drops pending jobs without an assignee so the round submits them again
+// Returns error if the job details cannot be read from the chain.
+func reconcilePendingAssignments(client *ethclient.Client, opts *bind.CallOpts, round *types.AssignmentRound) error {
+	for jobId, assignment := range round.Assignments {
+		if assignment.Status != types.AssignmentStatusPending {
+			continue
+		}
+		id, ok := new(big.Int).SetString(jobId, 10)
+		if !ok {
+			delete(round.Assignments, jobId)
+			continue
+		}
+		jobDetails, err := jobsManagerUtils.GetJobDetails(client, opts, id)
+		if err != nil {
+			return fmt.Errorf("failed to get details of pending job %s: %w", jobId, err)
+		}
+
+		if jobDetails.Assignee == (common.Address{}) {
+			log.WithField("jobId", jobId).Info("Pending assignment did not reach the chain, resubmitting")
+			delete(round.Assignments, jobId)
+			continue
+		}
+		log.WithFields(logrus.Fields{
+			"jobId":    jobId,
+			"assignee": jobDetails.Assignee.Hex(),
+		}).Info("Pending assignment found on chain")
+		assignment.Status = types.AssignmentStatusAssigned
+		assignment.Staker = jobDetails.Assignee.Hex()
+		assignment.UpdatedAt = time.Now()
+		round.Assignments[jobId] = assignment
+	}
+	return nil
+}
+
+// assignableJob reads a job from chain and reports whether it still needs an assignee.
+// GetActiveJobs also returns jobs assigned in earlier epochs and jobs already running,
+// which the round of the current epoch does not know about, so a job is skipped when it
+// has an assignee on chain or its status is past Queued.
+// Returns the job details, or error if the job cannot be read from the chain.
+func assignableJob(client *ethclient.Client, opts *bind.CallOpts, jobId *big.Int) (types.JobContract, bool, error) {
+	jobDetails, err := jobsManagerUtils.GetJobDetails(client, opts, jobId)
+	if err != nil {
+		return jobDetails, false, fmt.Errorf("failed to get job details: %w", err)
+	}
+	if jobDetails.Assignee != (common.Address{}) {
+		log.WithFields(logrus.Fields{
+			"jobId":    jobId.String(),
+			"assignee": jobDetails.Assignee.Hex(),
+		}).Debug("Job already assigned on chain")
+		return jobDetails, false, nil
+	}
+	status, err := jobsManagerUtils.GetJobStatus(client, opts, jobId)
+	if err != nil {
+		return jobDetails, false, fmt.Errorf("failed to get job status: %w", err)
+	}
+	if types.JobStatus(status) > types.JobStatusQueued {
+		log.WithFields(logrus.Fields{
+			"jobId":  jobId.String(),
+			"status": types.JobStatus(status).String(),
+		}).Debug("Job past Queued, not assigning it")
+		return jobDetails, false, nil
+	}
+	return jobDetails, true, nil
+}
+
+// recordAssignment stores the state of a job in the round and persists the round
+func recordAssignment(round *types.AssignmentRound, assignment types.RoundAssignment) error {
+	assignment.UpdatedAt = time.Now()
+	round.Assignments[assignment.JobID] = assignment
+	return assignmentRoundUtils.SaveRound(*round)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests the assignment round file across restarts:
+// 1. A missing round file loads as a fresh round of the epoch
+// 2. A saved round is reloaded unchanged within the same epoch
+// 3. A round of an earlier epoch is discarded
+func TestAssignmentRound(t *testing.T) {
+	roundPath := filepath.Join(t.TempDir(), "assignment-round.json")
+
+	pathMock := new(pathMocks.PathInterface)
+	pathMock.On("GetAssignmentRoundFilePath").Return(roundPath, nil)
+
+	originalPathUtils := path.PathUtilsInterface
+	originalOSUtils := path.OSUtilsInterface
+	originalAssignmentRoundUtils := assignmentRoundUtils
+	defer func() {
+		path.PathUtilsInterface = originalPathUtils
+		path.OSUtilsInterface = originalOSUtils
+		assignmentRoundUtils = originalAssignmentRoundUtils
+	}()
+	path.PathUtilsInterface = pathMock
+	path.OSUtilsInterface = path.OSUtils{}
+	assignmentRoundUtils = AssignmentRoundUtils{}
+
+	round, err := loadAssignmentRound(5, AssignmentStrategyRoundRobin)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(5), round.Epoch)
+	assert.Empty(t, round.Assignments)
+
+	err = recordAssignment(&round, types.RoundAssignment{JobID: "3", Staker: "0x2", Status: types.AssignmentStatusPending})
+	assert.NoError(t, err)
+
+	// A restarted daemon sees the pending assignment of the same epoch
+	reloaded, err := loadAssignmentRound(5, AssignmentStrategyRoundRobin)
+	assert.NoError(t, err)
+	assert.Equal(t, AssignmentStrategyRoundRobin, reloaded.Strategy)
+	assert.Equal(t, types.AssignmentStatusPending, reloaded.Assignments["3"].Status)
+	assert.Equal(t, "0x2", reloaded.Assignments["3"].Staker)
+
+	next, err := loadAssignmentRound(6, AssignmentStrategyRoundRobin)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(6), next.Epoch)
+	assert.False(t, next.Completed)
+	assert.Empty(t, next.Assignments)
+}
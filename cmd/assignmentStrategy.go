@@ -0,0 +1,266 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"encoding/binary"
+	"fmt"
+	"lumino/core/types"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// Names of the built-in assignment strategies, selectable through the assignmentStrategy config
+const (
+	AssignmentStrategyAdmin         = "admin"
+	AssignmentStrategyRoundRobin    = "round-robin"
+	AssignmentStrategyLeastLoaded   = "least-loaded"
+	AssignmentStrategyStakeWeighted = "stake-weighted"
+	AssignmentStrategyRandom        = "random"
+)
+
+// AssignmentRequest is a job waiting for assignment together with the stakers
+// able to run it, ordered by staker ID
+type AssignmentRequest struct {
+	JobID      *big.Int
+	Candidates []types.StakerContract
+}
+
+// JobAssignment is a single decision of an assignment strategy
+type JobAssignment struct {
+	JobID  *big.Int
+	Staker common.Address
+}
+
+// AssignmentStrategy decides which staker executes each unassigned job.
+// Given the same epoch, jobs and candidates a strategy must return the same
+// decisions, so any admin can reproduce and audit an assignment round.
+type AssignmentStrategy interface {
+	// Name returns the config name of the strategy
+	Name() string
+	// NeedsStakers reports whether the strategy picks from the discovered active stakers
+	NeedsStakers() bool
+	// Assign maps every requested job to one of its candidates
+	Assign(client *ethclient.Client, epoch uint32, requests []AssignmentRequest) ([]JobAssignment, error)
+}
+
+// assignmentStrategies holds the constructors of all selectable strategies keyed by name
+var assignmentStrategies = map[string]func(account types.Account) AssignmentStrategy{
+	AssignmentStrategyAdmin: func(account types.Account) AssignmentStrategy {
+		return AdminStrategy{admin: common.HexToAddress(account.Address)}
+	},
+	AssignmentStrategyRoundRobin:    func(types.Account) AssignmentStrategy { return RoundRobinStrategy{} },
+	AssignmentStrategyLeastLoaded:   func(types.Account) AssignmentStrategy { return LeastLoadedStrategy{} },
+	AssignmentStrategyStakeWeighted: func(types.Account) AssignmentStrategy { return StakeWeightedStrategy{} },
+	AssignmentStrategyRandom:        func(types.Account) AssignmentStrategy { return RandomStrategy{} },
+}
+
+// NewAssignmentStrategy returns the strategy registered under name for the admin account
+func NewAssignmentStrategy(name string, account types.Account) (AssignmentStrategy, error) {
+	newStrategy, ok := assignmentStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown assignment strategy %q, expected one of %v", name, assignmentStrategyNames())
+	}
+	return newStrategy(account), nil
+}
+
+// isKnownAssignmentStrategy reports whether name is a registered strategy
+func isKnownAssignmentStrategy(name string) bool {
+	_, ok := assignmentStrategies[name]
+	return ok
+}
+
+// assignmentStrategyNames returns the names of all registered strategies in sorted order
+func assignmentStrategyNames() []string {
+	names := make([]string, 0, len(assignmentStrategies))
+	for name := range assignmentStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// assignmentSeed derives the deterministic seed of a job in an epoch as
+// keccak256(epoch || jobId), so every admin computes the same value
+func assignmentSeed(epoch uint32, jobId *big.Int) *big.Int {
+	epochBytes := make([]byte, 4)
+	binary.BigEndian.PutUint32(epochBytes, epoch)
+	return new(big.Int).SetBytes(crypto.Keccak256(epochBytes, common.LeftPadBytes(jobId.Bytes(), 32)))
+}
+
+// AdminStrategy assigns every job to the admin node itself
+type AdminStrategy struct {
+	admin common.Address
+}
+
+func (AdminStrategy) Name() string       { return AssignmentStrategyAdmin }
+func (AdminStrategy) NeedsStakers() bool { return false }
+
+// Assign maps all jobs to the admin address, ignoring the candidates
+func (s AdminStrategy) Assign(client *ethclient.Client, epoch uint32, requests []AssignmentRequest) ([]JobAssignment, error) {
+	assignments := make([]JobAssignment, len(requests))
+	for i, request := range requests {
+		assignments[i] = JobAssignment{JobID: request.JobID, Staker: s.admin}
+	}
+	return assignments, nil
+}
+
+// requireCandidates returns an error naming the first request without candidates
+func requireCandidates(requests []AssignmentRequest) error {
+	for _, request := range requests {
+		if len(request.Candidates) == 0 {
+			return fmt.Errorf("no candidate stakers for job %s", request.JobID.String())
+		}
+	}
+	return nil
+}
+
+// RoundRobinStrategy hands jobs to the candidates in turn. The starting candidate
+// rotates with the epoch so the first staker is not always favoured.
+type RoundRobinStrategy struct{}
+
+func (RoundRobinStrategy) Name() string       { return AssignmentStrategyRoundRobin }
+func (RoundRobinStrategy) NeedsStakers() bool { return true }
+
+// Assign maps job i to its candidate (epoch + i) mod len(candidates)
+func (RoundRobinStrategy) Assign(client *ethclient.Client, epoch uint32, requests []AssignmentRequest) ([]JobAssignment, error) {
+	if err := requireCandidates(requests); err != nil {
+		return nil, err
+	}
+	assignments := make([]JobAssignment, len(requests))
+	for i, request := range requests {
+		staker := request.Candidates[(int(epoch)+i)%len(request.Candidates)]
+		assignments[i] = JobAssignment{JobID: request.JobID, Staker: staker.Address}
+	}
+	return assignments, nil
+}
+
+// LeastLoadedStrategy hands each job to the candidate with the fewest jobs,
+// counting the job each staker already holds on chain and the jobs assigned
+// earlier in the same round. Ties go to the lowest staker ID.
+type LeastLoadedStrategy struct{}
+
+func (LeastLoadedStrategy) Name() string       { return AssignmentStrategyLeastLoaded }
+func (LeastLoadedStrategy) NeedsStakers() bool { return true }
+
+// Assign maps every job to its currently least loaded candidate
+func (LeastLoadedStrategy) Assign(client *ethclient.Client, epoch uint32, requests []AssignmentRequest) ([]JobAssignment, error) {
+	if err := requireCandidates(requests); err != nil {
+		return nil, err
+	}
+
+	opts := protoUtils.GetOptions()
+	loads := make(map[common.Address]int)
+	for _, request := range requests {
+		for _, staker := range request.Candidates {
+			if _, known := loads[staker.Address]; known {
+				continue
+			}
+			load, err := stakerLoad(client, &opts, staker.Address)
+			if err != nil {
+				return nil, err
+			}
+			loads[staker.Address] = load
+		}
+	}
+
+	assignments := make([]JobAssignment, len(requests))
+	for i, request := range requests {
+		selected := request.Candidates[0].Address
+		for _, staker := range request.Candidates {
+			if loads[staker.Address] < loads[selected] {
+				selected = staker.Address
+			}
+		}
+		loads[selected]++
+		assignments[i] = JobAssignment{JobID: request.JobID, Staker: selected}
+	}
+	return assignments, nil
+}
+
+// stakerLoad returns the number of jobs a staker currently holds according to getJobForStaker
+func stakerLoad(client *ethclient.Client, opts *bind.CallOpts, staker common.Address) (int, error) {
+	jobId, err := jobsManagerUtils.GetJobForStaker(client, opts, staker)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get job for staker %s: %w", staker.Hex(), err)
+	}
+	if jobId == nil || jobId.Sign() == 0 {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// StakeWeightedStrategy picks a candidate with a probability proportional to its
+// stake. The draw is keccak256(epoch || jobId) mod totalStake, so it is reproducible.
+type StakeWeightedStrategy struct{}
+
+func (StakeWeightedStrategy) Name() string       { return AssignmentStrategyStakeWeighted }
+func (StakeWeightedStrategy) NeedsStakers() bool { return true }
+
+// Assign maps every job to the candidate whose cumulative stake range contains the draw
+func (StakeWeightedStrategy) Assign(client *ethclient.Client, epoch uint32, requests []AssignmentRequest) ([]JobAssignment, error) {
+	assignments := make([]JobAssignment, len(requests))
+	for i, request := range requests {
+		totalStake := new(big.Int)
+		for _, staker := range request.Candidates {
+			if staker.Stake != nil {
+				totalStake.Add(totalStake, staker.Stake)
+			}
+		}
+		if totalStake.Sign() <= 0 {
+			return nil, fmt.Errorf("no candidate stakers with stake for job %s", request.JobID.String())
+		}
+
+		draw := new(big.Int).Mod(assignmentSeed(epoch, request.JobID), totalStake)
+		cumulative := new(big.Int)
+		for _, staker := range request.Candidates {
+			if staker.Stake == nil {
+				continue
+			}
+			cumulative.Add(cumulative, staker.Stake)
+			if draw.Cmp(cumulative) < 0 {
+				assignments[i] = JobAssignment{JobID: request.JobID, Staker: staker.Address}
+				break
+			}
+		}
+	}
+	return assignments, nil
+}
+
+// RandomStrategy picks a candidate uniformly at random. The draw is
+// keccak256(epoch || jobId) mod len(candidates), so it is reproducible.
+type RandomStrategy struct{}
+
+func (RandomStrategy) Name() string       { return AssignmentStrategyRandom }
+func (RandomStrategy) NeedsStakers() bool { return true }
+
+// Assign maps every job to the candidate selected by its epoch-seeded draw
+func (RandomStrategy) Assign(client *ethclient.Client, epoch uint32, requests []AssignmentRequest) ([]JobAssignment, error) {
+	if err := requireCandidates(requests); err != nil {
+		return nil, err
+	}
+	assignments := make([]JobAssignment, len(requests))
+	for i, request := range requests {
+		numCandidates := big.NewInt(int64(len(request.Candidates)))
+		index := new(big.Int).Mod(assignmentSeed(epoch, request.JobID), numCandidates).Int64()
+		assignments[i] = JobAssignment{JobID: request.JobID, Staker: request.Candidates[index].Address}
+	}
+	return assignments, nil
+}
+
+// logAssignment records a strategy decision with everything needed to reproduce it
+func logAssignment(strategy AssignmentStrategy, epoch uint32, assignment JobAssignment, numCandidates int) {
+	log.WithFields(logrus.Fields{
+		"strategy":   strategy.Name(),
+		"epoch":      epoch,
+		"jobId":      assignment.JobID.String(),
+		"staker":     assignment.Staker.Hex(),
+		"candidates": numCandidates,
+		"seed":       assignmentSeed(epoch, assignment.JobID).Text(16),
+	}).Info("Assigning job")
+}
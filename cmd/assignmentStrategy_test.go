@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var (
+	stakerA = types.StakerContract{Id: 1, Address: common.HexToAddress("0xa"), Stake: big.NewInt(1)}
+	stakerB = types.StakerContract{Id: 2, Address: common.HexToAddress("0xb"), Stake: big.NewInt(1)}
+	stakerC = types.StakerContract{Id: 3, Address: common.HexToAddress("0xc"), Stake: big.NewInt(1)}
+)
+
+func assignmentRequests(jobIds []*big.Int, candidates []types.StakerContract) []AssignmentRequest {
+	requests := make([]AssignmentRequest, len(jobIds))
+	for i, jobId := range jobIds {
+		requests[i] = AssignmentRequest{JobID: jobId, Candidates: candidates}
+	}
+	return requests
+}
+
+func assignedStakers(assignments []JobAssignment) []common.Address {
+	stakers := make([]common.Address, len(assignments))
+	for i, assignment := range assignments {
+		stakers[i] = assignment.Staker
+	}
+	return stakers
+}
+
+// Tests strategy lookup by config name
+func TestNewAssignmentStrategy(t *testing.T) {
+	account := types.Account{Address: "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771"}
+
+	for _, name := range assignmentStrategyNames() {
+		strategy, err := NewAssignmentStrategy(name, account)
+		assert.NoError(t, err)
+		assert.Equal(t, name, strategy.Name())
+	}
+
+	_, err := NewAssignmentStrategy("first-come", account)
+	assert.Error(t, err)
+}
+
+// Tests the deterministic strategies:
+// 1. Admin assigns everything to the admin
+// 2. Round-robin rotates its starting staker with the epoch
+// 3. Random and stake-weighted reproduce the same decisions for the same epoch
+// 4. Stake-weighted never picks a staker without stake share
+func TestDeterministicAssignmentStrategies(t *testing.T) {
+	var client *ethclient.Client
+	jobIds := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4)}
+	candidates := []types.StakerContract{stakerA, stakerB, stakerC}
+
+	admin := AdminStrategy{admin: common.HexToAddress("0xad")}
+	assignments, err := admin.Assign(client, 7, assignmentRequests(jobIds, nil))
+	assert.NoError(t, err)
+	for _, assignment := range assignments {
+		assert.Equal(t, common.HexToAddress("0xad"), assignment.Staker)
+	}
+
+	assignments, err = RoundRobinStrategy{}.Assign(client, 7, assignmentRequests(jobIds, candidates))
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{stakerB.Address, stakerC.Address, stakerA.Address, stakerB.Address}, assignedStakers(assignments))
+
+	for _, strategy := range []AssignmentStrategy{RandomStrategy{}, StakeWeightedStrategy{}} {
+		first, err := strategy.Assign(client, 7, assignmentRequests(jobIds, candidates))
+		assert.NoError(t, err)
+		second, err := strategy.Assign(client, 7, assignmentRequests(jobIds, candidates))
+		assert.NoError(t, err)
+		assert.Equal(t, first, second, strategy.Name())
+		assert.Len(t, first, len(jobIds))
+	}
+
+	whale := types.StakerContract{Id: 4, Address: common.HexToAddress("0xd"), Stake: big.NewInt(1000)}
+	zeroStake := types.StakerContract{Id: 5, Address: common.HexToAddress("0xe"), Stake: big.NewInt(0)}
+	assignments, err = StakeWeightedStrategy{}.Assign(client, 7, assignmentRequests(jobIds, []types.StakerContract{zeroStake, whale}))
+	assert.NoError(t, err)
+	for _, assignment := range assignments {
+		assert.Equal(t, whale.Address, assignment.Staker)
+	}
+
+	_, err = RoundRobinStrategy{}.Assign(client, 7, assignmentRequests(jobIds, nil))
+	assert.Error(t, err)
+	_, err = StakeWeightedStrategy{}.Assign(client, 7, assignmentRequests(jobIds, nil))
+	assert.Error(t, err)
+}
+
+// Tests least-loaded assignment with cases:
+// 1. Stakers already holding a job are picked last
+// 2. Errors reading staker load are returned
+func TestLeastLoadedStrategy(t *testing.T) {
+	var client *ethclient.Client
+	jobIds := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+	candidates := []types.StakerContract{stakerA, stakerB, stakerC}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(*mocks.JobsManagerInterface)
+		wantStakers []common.Address
+		wantErr     bool
+	}{
+		{
+			name: "busy stakers are picked last",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, stakerA.Address).Return(big.NewInt(9), nil)
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, stakerB.Address).Return(big.NewInt(0), nil)
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, stakerC.Address).Return(big.NewInt(0), nil)
+			},
+			wantStakers: []common.Address{stakerB.Address, stakerC.Address, stakerA.Address},
+		},
+		{
+			name: "error reading staker load",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("rpc error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobsMock := new(mocks.JobsManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+
+			originalJobsManagerUtils := jobsManagerUtils
+			originalProtoUtils := protoUtils
+			defer func() {
+				jobsManagerUtils = originalJobsManagerUtils
+				protoUtils = originalProtoUtils
+			}()
+
+			jobsManagerUtils = jobsMock
+			protoUtils = utilsMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(jobsMock)
+
+			assignments, err := LeastLoadedStrategy{}.Assign(client, 1, assignmentRequests(jobIds, candidates))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStakers, assignedStakers(assignments))
+		})
+	}
+}
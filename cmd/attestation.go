@@ -0,0 +1,284 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	luminoAccounts "lumino/accounts"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resultAttestationVersion is the version of the attestation format signed by this client
+const resultAttestationVersion = 1
+
+// verifyAttestationCmd verifies the signed attestation of a job's results
+var verifyAttestationCmd = &cobra.Command{
+	Use:   "verifyAttestation",
+	Short: "Verify who produced the results of a job",
+	Long: `Verifies the attestation an executor signed when it completed a job. The signer is
+recovered from the signature and checked against the assignee of the job on-chain, and the
+manifest of the results, manifest.json next to the attestation unless --manifest is given,
+is checked against the digest the executor signed.
+
+Example:
+  ./lumino verifyAttestation --file results-21/attestation.json
+  ./lumino verifyAttestation --file attestation.json --manifest manifest.json`,
+	Run: initialiseVerifyAttestation,
+}
+
+func initialiseVerifyAttestation(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteVerifyAttestation(cmd.Flags())
+}
+
+// ExecuteVerifyAttestation verifies the attestation of a job's results. This function:
+// 1. Reads the attestation and the manifest it describes
+// 2. Reads the job from the JobManager the attestation names
+// 3. Recovers the signer and checks it is the job's on-chain assignee and the manifest matches
+// Exits with error if the attestation cannot be read or fails verification.
+func (*UtilsStruct) ExecuteVerifyAttestation(flagSet *pflag.FlagSet) {
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("ExecuteVerifyAttestation: Config: %+v", config)
+
+	attestationPath, err := flagSet.GetString("file")
+	utils.CheckError("Error in getting file: ", err)
+	manifestPath, err := flagSet.GetString("manifest")
+	utils.CheckError("Error in getting manifest: ", err)
+
+	attestation, err := readResultAttestation(attestationPath)
+	utils.CheckError("Error in reading attestation: ", err)
+
+	var manifest []byte
+	if manifestPath == "" {
+		manifestPath = filepath.Join(filepath.Dir(attestationPath), resultsManifestName)
+		manifest, err = os.ReadFile(manifestPath)
+		if errors.Is(err, os.ErrNotExist) {
+			log.WithField("manifest", manifestPath).Warn("No manifest next to the attestation, only the signer is verified")
+			manifest, err = nil, nil
+		}
+	} else {
+		manifest, err = os.ReadFile(manifestPath)
+	}
+	utils.CheckError("Error in reading manifest: ", err)
+
+	jobId, ok := new(big.Int).SetString(attestation.JobID, 10)
+	if !ok || jobId.Sign() < 0 {
+		log.Fatalf("Invalid jobId %q in attestation", attestation.JobID)
+	}
+	client := protoUtils.ConnectToEthClient(config.Provider)
+	opts := protoUtils.GetOptions()
+	jobDetails, err := jobsManagerUtils.GetJobDetails(client, &opts, jobId)
+	utils.CheckError("Error in getting job details: ", err)
+
+	signer, err := verifyResultAttestation(attestation, manifest, jobDetails)
+	utils.CheckError("Attestation verification failed: ", err)
+
+	log.WithFields(logrus.Fields{
+		"jobId":           attestation.JobID,
+		"signer":          signer.Hex(),
+		"manifestChecked": manifest != nil,
+		"completedAt":     attestation.CompletedAt,
+		"pipelineVersion": attestation.PipelineVersion,
+	}).Info("Attestation verified, the results were produced by the job's assignee")
+}
+
+// attestJobResults signs the attestation of the results described by the manifest with the
+// account's key. This function:
+// 1. Hashes the manifest as saved in manifest.json with SHA-256
+// 2. Describes the job, its assignee, the manifest digest, the execution times and versions.
+// completedAt is when the job finished, not when its results were packaged
+// 3. Signs the attestation with the keystore of the account
+// Returns the signed attestation, and error if the manifest cannot be encoded or signing fails.
+func attestJobResults(account types.Account, manifest types.ResultManifest, startedAt time.Time, completedAt time.Time, pipelineVersion string) (types.ResultAttestation, error) {
+	data, err := marshalResultManifest(manifest)
+	if err != nil {
+		return types.ResultAttestation{}, err
+	}
+	digest := sha256.Sum256(data)
+	attestation := types.ResultAttestation{
+		Version:         resultAttestationVersion,
+		ChainID:         core.ChainID.String(),
+		JobManager:      common.HexToAddress(core.JobManagerAddress).Hex(),
+		JobID:           manifest.JobID,
+		Creator:         manifest.Creator,
+		Assignee:        common.HexToAddress(account.Address).Hex(),
+		ManifestSHA256:  hex.EncodeToString(digest[:]),
+		StartedAt:       startedAt.UTC(),
+		CompletedAt:     completedAt.UTC(),
+		PipelineVersion: pipelineVersion,
+		ClientVersion:   core.VersionWithMeta,
+	}
+
+	hash, err := resultAttestationHash(attestation)
+	if err != nil {
+		return attestation, err
+	}
+	defaultPath, err := path.PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return attestation, fmt.Errorf("failed to get default path: %w", err)
+	}
+	signature, err := luminoAccounts.AccountUtilsInterface.SignData(hash, account, filepath.Join(defaultPath, "keystore_files"))
+	if err != nil {
+		return attestation, fmt.Errorf("failed to sign result attestation: %w", err)
+	}
+	if len(signature) != crypto.SignatureLength {
+		return attestation, fmt.Errorf("failed to sign result attestation: signature is %d bytes", len(signature))
+	}
+	// Report the recovery ID as 27/28, as personal_sign and most wallets do
+	signature[crypto.RecoveryIDOffset] += 27
+	attestation.Signature = hexutil.Encode(signature)
+	return attestation, nil
+}
+
+// resultAttestationHash returns the EIP-191 personal message hash of the attestation's
+// JSON encoding without its signature
+func resultAttestationHash(attestation types.ResultAttestation) ([]byte, error) {
+	attestation.Signature = ""
+	payload, err := json.Marshal(attestation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result attestation: %w", err)
+	}
+	return accounts.TextHash(payload), nil
+}
+
+// recoverAttestationSigner recovers the address that signed the attestation.
+// Returns error if the signature is malformed.
+func recoverAttestationSigner(attestation types.ResultAttestation) (common.Address, error) {
+	signature, err := hexutil.Decode(attestation.Signature)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return common.Address{}, errors.New("attestation signature is not a 65-byte hex string")
+	}
+	if signature[crypto.RecoveryIDOffset] >= 27 {
+		signature[crypto.RecoveryIDOffset] -= 27
+	}
+	hash, err := resultAttestationHash(attestation)
+	if err != nil {
+		return common.Address{}, err
+	}
+	publicKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover attestation signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// verifyResultAttestation checks the attestation against the job it describes. This function:
+// 1. Checks the attestation names the JobManager and chain of this client
+// 2. Recovers the signer and checks it is the assignee the attestation names
+// 3. Checks the job exists on-chain with the attested creator and the signer as its assignee
+// 4. Checks the manifest, when given, is the one whose digest was signed
+// Returns the signer, and error if any check fails.
+func verifyResultAttestation(attestation types.ResultAttestation, manifest []byte, jobDetails types.JobContract) (common.Address, error) {
+	jobManager := common.HexToAddress(core.JobManagerAddress)
+	if attestation.ChainID != core.ChainID.String() || !strings.EqualFold(attestation.JobManager, jobManager.Hex()) {
+		return common.Address{}, fmt.Errorf("attestation is for JobManager %s on chain %s, expected %s on chain %s",
+			attestation.JobManager, attestation.ChainID, jobManager.Hex(), core.ChainID.String())
+	}
+
+	signer, err := recoverAttestationSigner(attestation)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !strings.EqualFold(signer.Hex(), attestation.Assignee) {
+		return signer, fmt.Errorf("attestation is signed by %s, not by its assignee %s", signer.Hex(), attestation.Assignee)
+	}
+
+	if jobDetails.Creator == (common.Address{}) {
+		return signer, fmt.Errorf("job %s does not exist", attestation.JobID)
+	}
+	if signer != jobDetails.Assignee {
+		return signer, fmt.Errorf("attestation is signed by %s, but job %s is assigned to %s",
+			signer.Hex(), attestation.JobID, jobDetails.Assignee.Hex())
+	}
+	if !strings.EqualFold(attestation.Creator, jobDetails.Creator.Hex()) {
+		return signer, fmt.Errorf("attestation names creator %s, but job %s was created by %s",
+			attestation.Creator, attestation.JobID, jobDetails.Creator.Hex())
+	}
+
+	if manifest != nil {
+		digest := sha256.Sum256(manifest)
+		if hex.EncodeToString(digest[:]) != attestation.ManifestSHA256 {
+			return signer, fmt.Errorf("manifest SHA-256 %s does not match the attested %s",
+				hex.EncodeToString(digest[:]), attestation.ManifestSHA256)
+		}
+	}
+	return signer, nil
+}
+
+// readResultAttestation reads an attestation saved by SaveResultAttestation or fetchResults
+func readResultAttestation(attestationPath string) (types.ResultAttestation, error) {
+	var attestation types.ResultAttestation
+	data, err := os.ReadFile(attestationPath)
+	if err != nil {
+		return attestation, err
+	}
+	if err := json.Unmarshal(data, &attestation); err != nil {
+		return attestation, fmt.Errorf("failed to parse %s: %w", attestationPath, err)
+	}
+	if attestation.Version != resultAttestationVersion {
+		return attestation, fmt.Errorf("unsupported attestation version %d", attestation.Version)
+	}
+	return attestation, nil
+}
+
+// readAttestedResults reads the manifest and attestation saved in the job directory when the
+// results of the job were delivered, so that a retried delivery reuses them.
+// Returns error if either is missing or the attestation does not cover the manifest.
+func readAttestedResults(jobId string) (types.ResultManifest, error) {
+	var manifest types.ResultManifest
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(jobId)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to get job directory: %w", err)
+	}
+	attestation, err := readResultAttestation(filepath.Join(jobDirPath, resultsAttestationName))
+	if err != nil {
+		return manifest, err
+	}
+	data, err := os.ReadFile(filepath.Join(jobDirPath, resultsManifestName))
+	if err != nil {
+		return manifest, err
+	}
+	digest := sha256.Sum256(data)
+	if attestation.JobID != jobId || hex.EncodeToString(digest[:]) != attestation.ManifestSHA256 {
+		return manifest, fmt.Errorf("attestation of job %s does not cover its manifest", jobId)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse %s: %w", resultsManifestName, err)
+	}
+	return manifest, nil
+}
+
+// Initializes the verifyAttestation command with its flags.
+func init() {
+	rootCmd.AddCommand(verifyAttestationCmd)
+
+	var (
+		File     string
+		Manifest string
+	)
+
+	verifyAttestationCmd.Flags().StringVarP(&File, "file", "", "", "path of the attestation.json to verify")
+	verifyAttestationCmd.Flags().StringVarP(&Manifest, "manifest", "", "", "path of the manifest.json the attestation covers (default: manifest.json next to the attestation)")
+
+	fileErr := verifyAttestationCmd.MarkFlagRequired("file")
+	utils.CheckError("File error: ", fileErr)
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	luminoAccounts "lumino/accounts"
+	accountsMocks "lumino/accounts/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// signTestAttestation signs the attestation of the manifest with a fresh key standing in for the
+// staker keystore, and returns it with the manifest as saved in manifest.json and the signer
+func signTestAttestation(t *testing.T, manifest types.ResultManifest) (types.ResultAttestation, []byte, common.Address) {
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	signer := crypto.PubkeyToAddress(key.PublicKey)
+	account := types.Account{Address: signer.Hex(), Password: "test"}
+
+	accountsMock := new(accountsMocks.AccountInterface)
+	pathMock := new(pathMocks.PathInterface)
+	originalAccountUtils := luminoAccounts.AccountUtilsInterface
+	originalPathUtils := path.PathUtilsInterface
+	t.Cleanup(func() {
+		luminoAccounts.AccountUtilsInterface = originalAccountUtils
+		path.PathUtilsInterface = originalPathUtils
+	})
+	luminoAccounts.AccountUtilsInterface = accountsMock
+	path.PathUtilsInterface = pathMock
+
+	pathMock.On("GetDefaultPath").Return("/home/lumino/.lumino", nil)
+	accountsMock.On("SignData", mock.Anything, account, "/home/lumino/.lumino/keystore_files").
+		Return(func(hash []byte, account types.Account, keystorePath string) ([]byte, error) {
+			return crypto.Sign(hash, key)
+		})
+
+	startedAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC)
+	attestation, err := attestJobResults(account, manifest, startedAt, completedAt, "3f1c2a9d")
+	assert.NoError(t, err)
+	data, err := marshalResultManifest(manifest)
+	assert.NoError(t, err)
+	return attestation, data, signer
+}
+
+// Tests signing the attestation of a job's results: the attestation describes the job and
+// the manifest digest, and its signature recovers to the staker
+func TestAttestJobResults(t *testing.T) {
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	manifest := types.ResultManifest{
+		JobID:     "7",
+		Creator:   creator.Hex(),
+		Files:     []types.ResultFile{{Path: "metrics.json", Size: 2, SHA256: "44136fa355b3678a1146ad16f7e8649e94fb4fc21fe77e8310c060f61caaff8a"}},
+		CreatedAt: time.Date(2026, 10, 1, 14, 30, 0, 0, time.UTC),
+	}
+
+	attestation, data, signer := signTestAttestation(t, manifest)
+	digest := sha256.Sum256(data)
+	assert.Equal(t, hex.EncodeToString(digest[:]), attestation.ManifestSHA256)
+	assert.Equal(t, "7", attestation.JobID)
+	assert.Equal(t, creator.Hex(), attestation.Creator)
+	assert.Equal(t, signer.Hex(), attestation.Assignee)
+	assert.Equal(t, core.ChainID.String(), attestation.ChainID)
+	assert.Equal(t, common.HexToAddress(core.JobManagerAddress).Hex(), attestation.JobManager)
+	assert.Equal(t, time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC), attestation.StartedAt)
+	assert.Equal(t, time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC), attestation.CompletedAt)
+	assert.Equal(t, "3f1c2a9d", attestation.PipelineVersion)
+	assert.Equal(t, core.VersionWithMeta, attestation.ClientVersion)
+	assert.Len(t, attestation.Signature, 2+2*crypto.SignatureLength)
+
+	recovered, err := recoverAttestationSigner(attestation)
+	assert.NoError(t, err)
+	assert.Equal(t, signer, recovered)
+
+	accountsMock := new(accountsMocks.AccountInterface)
+	luminoAccounts.AccountUtilsInterface = accountsMock
+	accountsMock.On("SignData", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("no keystore file found"))
+	_, err = attestJobResults(types.Account{Address: signer.Hex()}, manifest, time.Time{}, time.Time{}, "")
+	assert.EqualError(t, err, "failed to sign result attestation: no keystore file found")
+}
+
+// Tests verifying an attestation against its job with cases:
+// 1. An attestation signed by the on-chain assignee with a matching manifest is valid
+// 2. The manifest may be left out, verifying only the signer
+// 3. An attestation altered after signing recovers another signer
+// 4. A signer that is not the job's assignee is rejected
+// 5. A job that does not exist or was created by someone else is rejected
+// 6. A manifest other than the signed one is rejected
+// 7. An attestation for another JobManager or a malformed signature is rejected
+func TestVerifyResultAttestation(t *testing.T) {
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	manifest := types.ResultManifest{JobID: "7", Creator: creator.Hex(), CreatedAt: time.Date(2026, 10, 1, 14, 30, 0, 0, time.UTC)}
+	attestation, data, signer := signTestAttestation(t, manifest)
+
+	tests := []struct {
+		name        string
+		attestation func(types.ResultAttestation) types.ResultAttestation
+		manifest    []byte
+		jobDetails  types.JobContract
+		wantErr     string
+	}{
+		{
+			name:       "valid attestation",
+			manifest:   data,
+			jobDetails: types.JobContract{Creator: creator, Assignee: signer},
+		},
+		{
+			name:       "signer only",
+			jobDetails: types.JobContract{Creator: creator, Assignee: signer},
+		},
+		{
+			name: "altered attestation",
+			attestation: func(attestation types.ResultAttestation) types.ResultAttestation {
+				attestation.ManifestSHA256 = hex.EncodeToString(make([]byte, 32))
+				return attestation
+			},
+			jobDetails: types.JobContract{Creator: creator, Assignee: signer},
+			wantErr:    "not by its assignee " + signer.Hex(),
+		},
+		{
+			name:       "job assigned to another staker",
+			jobDetails: types.JobContract{Creator: creator, Assignee: common.HexToAddress("0x3")},
+			wantErr:    "attestation is signed by " + signer.Hex() + ", but job 7 is assigned to " + common.HexToAddress("0x3").Hex(),
+		},
+		{
+			name:    "job does not exist",
+			wantErr: "job 7 does not exist",
+		},
+		{
+			name:       "job of another creator",
+			jobDetails: types.JobContract{Creator: common.HexToAddress("0x4"), Assignee: signer},
+			wantErr:    "attestation names creator " + creator.Hex() + ", but job 7 was created by " + common.HexToAddress("0x4").Hex(),
+		},
+		{
+			name:       "other manifest",
+			manifest:   append(append([]byte{}, data...), '\n'),
+			jobDetails: types.JobContract{Creator: creator, Assignee: signer},
+			wantErr:    "does not match the attested " + attestation.ManifestSHA256,
+		},
+		{
+			name: "other JobManager",
+			attestation: func(attestation types.ResultAttestation) types.ResultAttestation {
+				attestation.JobManager = common.HexToAddress("0x5").Hex()
+				return attestation
+			},
+			jobDetails: types.JobContract{Creator: creator, Assignee: signer},
+			wantErr:    "attestation is for JobManager " + common.HexToAddress("0x5").Hex(),
+		},
+		{
+			name: "malformed signature",
+			attestation: func(attestation types.ResultAttestation) types.ResultAttestation {
+				attestation.Signature = "0x1234"
+				return attestation
+			},
+			jobDetails: types.JobContract{Creator: creator, Assignee: signer},
+			wantErr:    "attestation signature is not a 65-byte hex string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate := attestation
+			if tt.attestation != nil {
+				candidate = tt.attestation(candidate)
+			}
+			recovered, err := verifyResultAttestation(candidate, tt.manifest, tt.jobDetails)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, signer, recovered)
+		})
+	}
+}
+
+// Tests reading a saved attestation back, rejecting unsupported versions
+func TestReadResultAttestation(t *testing.T) {
+	setupJobDir(t)
+	jobDir, err := path.PathUtilsInterface.GetJobDirPath("7")
+	assert.NoError(t, err)
+
+	assert.NoError(t, ResultsUtils{}.SaveResultAttestation(types.ResultAttestation{Version: resultAttestationVersion, JobID: "7", Signature: "0x01"}))
+	attestation, err := readResultAttestation(filepath.Join(jobDir, "attestation.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "7", attestation.JobID)
+
+	assert.NoError(t, ResultsUtils{}.SaveResultAttestation(types.ResultAttestation{Version: 2, JobID: "7"}))
+	_, err = readResultAttestation(filepath.Join(jobDir, "attestation.json"))
+	assert.EqualError(t, err, "unsupported attestation version 2")
+}
@@ -0,0 +1,309 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	"math/big"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// activeProposer is the block proposer role of the executor, nil unless executeJob runs with --proposer
+var activeProposer *blockProposer
+
+// useBlockManager points the BlockManager bindings at the configured contract.
+// Returns error if no valid BlockManager address is configured.
+func useBlockManager(config types.Configurations) error {
+	if config.BlockManagerAddress == "" {
+		return errors.New("BlockManager address is not configured, set it with setConfig --blockManagerAddress")
+	}
+	if !common.IsHexAddress(config.BlockManagerAddress) {
+		return fmt.Errorf("invalid BlockManager address %q", config.BlockManagerAddress)
+	}
+	core.BlockManagerAddress = config.BlockManagerAddress
+	return nil
+}
+
+// concludedJobCollector gathers the IDs of the jobs concluded in an epoch from the
+// JobStatusUpdated events emitted since the epoch started. Blocks are scanned
+// incrementally, so every call only reads the blocks mined since the previous one.
+type concludedJobCollector struct {
+	epoch  uint32
+	next   uint64 // next block to scan
+	jobIds map[string]*big.Int
+}
+
+// Collect returns the IDs of the jobs that reached Completed or Failed in the epoch,
+// in ascending order. Returns error if the epoch start or the events cannot be read;
+// the blocks scanned so far are kept and the rest is scanned on the next call.
+func (c *concludedJobCollector) Collect(client *ethclient.Client, epoch uint32) ([]*big.Int, error) {
+	if c.jobIds == nil || c.epoch != epoch {
+		start, err := eventIndexUtils.GetEpochStartBlock(client, epoch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find the first block of epoch %d: %w", epoch, err)
+		}
+		c.epoch = epoch
+		c.next = start
+		c.jobIds = make(map[string]*big.Int)
+	}
+
+	latest, err := jobEventUtils.GetLatestBlockNumber(client)
+	if err != nil {
+		return nil, err
+	}
+	for from := c.next; from <= latest; from += core.EventBlockRange {
+		to := min(from+core.EventBlockRange-1, latest)
+		events, err := jobEventUtils.FilterJobEvents(client, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.Kind != types.JobEventStatusUpdated {
+				continue
+			}
+			if event.Status == types.JobStatusCompleted || event.Status == types.JobStatusFailed {
+				c.jobIds[event.JobID.String()] = event.JobID
+			}
+		}
+		c.next = to + 1
+	}
+
+	jobIds := make([]*big.Int, 0, len(c.jobIds))
+	for _, jobId := range c.jobIds {
+		jobIds = append(jobIds, jobId)
+	}
+	sort.Slice(jobIds, func(i, j int) bool {
+		return jobIds[i].Cmp(jobIds[j]) < 0
+	})
+	return jobIds, nil
+}
+
+// blockProposer is the block proposer and confirmer role of the executor daemon.
+// In the Confirm state it proposes a block of the jobs concluded in the epoch, again
+// whenever more jobs conclude, up to MAX_BLOCKS_PER_EPOCH_PER_STAKER blocks per epoch.
+// In the Assign state of the next epoch, once no more blocks can be proposed, it
+// confirms the winning block of the previous epoch if this node proposed it.
+type blockProposer struct {
+	stakerId       uint32
+	maxBlocks      *big.Int // MAX_BLOCKS_PER_EPOCH_PER_STAKER, read once
+	collector      concludedJobCollector
+	confirmedEpoch uint32 // last epoch whose confirmation has been settled
+}
+
+// newBlockProposer returns the proposer role of the staker
+func newBlockProposer(stakerId uint32) *blockProposer {
+	return &blockProposer{stakerId: stakerId}
+}
+
+// Handle runs the proposer duties of the current state. Returns error if proposing or
+// confirming fails; the duty is retried on the next tick.
+func (p *blockProposer) Handle(client *ethclient.Client, config types.Configurations, account types.Account, state types.EpochState, epoch uint32) error {
+	switch state {
+	case types.EpochStateConfirm:
+		return p.propose(client, config, account, epoch)
+	case types.EpochStateAssign:
+		if epoch == 0 {
+			return nil
+		}
+		return p.confirm(client, config, account, epoch-1)
+	default:
+		return nil
+	}
+}
+
+// propose submits a block of the jobs concluded in the epoch. This function:
+// 1. Loads the block round of the epoch and stops once the proposal limit is reached
+// 2. Collects the jobs concluded in the epoch, stopping if there are none
+// 3. Skips the proposal if the last block of the round already lists the same jobs
+// 4. Proposes the block and records it in the round
+// Returns error if the limit, the concluded jobs or the round cannot be read, or the proposal fails.
+func (p *blockProposer) propose(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32) error {
+	round, err := loadBlockRound(epoch)
+	if err != nil {
+		return fmt.Errorf("failed to load block round: %w", err)
+	}
+
+	if p.maxBlocks == nil {
+		opts := protoUtils.GetOptions()
+		p.maxBlocks, err = blockManagerUtils.GetMaxBlocksPerEpochPerStaker(client, &opts)
+		if err != nil {
+			return fmt.Errorf("failed to get the block proposal limit: %w", err)
+		}
+	}
+	if big.NewInt(int64(len(round.Proposals))).Cmp(p.maxBlocks) >= 0 {
+		log.WithFields(logrus.Fields{
+			"epoch":     epoch,
+			"proposals": len(round.Proposals),
+		}).Debug("Block proposal limit of the epoch reached")
+		return nil
+	}
+
+	jobIds, err := p.collector.Collect(client, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to collect concluded jobs: %w", err)
+	}
+	if len(jobIds) == 0 {
+		log.WithField("epoch", epoch).Debug("No concluded jobs to propose")
+		return nil
+	}
+	jobIdStrs := make([]string, len(jobIds))
+	for i, jobId := range jobIds {
+		jobIdStrs[i] = jobId.String()
+	}
+	if len(round.Proposals) > 0 && slices.Equal(round.Proposals[len(round.Proposals)-1].JobIDs, jobIdStrs) {
+		return nil
+	}
+
+	log.WithFields(logrus.Fields{
+		"epoch":  epoch,
+		"jobIds": jobIdStrs,
+	}).Info("Proposing block")
+	txnHash, err := cmdUtils.ProposeBlock(client, config, account, epoch, jobIds)
+	if err != nil {
+		return fmt.Errorf("failed to propose block: %w", err)
+	}
+
+	round.Proposals = append(round.Proposals, types.BlockProposal{
+		JobIDs:     jobIdStrs,
+		TxHash:     txnHash.Hex(),
+		ProposedAt: time.Now(),
+	})
+	if err := saveBlockRound(round); err != nil {
+		return fmt.Errorf("failed to save block round: %w", err)
+	}
+	return nil
+}
+
+// confirm confirms the winning block of the epoch. This function:
+// 1. Stops if the epoch already has a confirmed block or no proposed blocks
+// 2. Looks up the winning block through blockIndexToBeConfirmed and sortedProposedBlockIds
+// 3. Leaves the confirmation to the proposer of the winning block
+// 4. Confirms the block if this node proposed it
+// The outcome is settled once per epoch; a block left unconfirmed by its proposer can
+// be confirmed by hand with the confirmBlock command.
+// Returns error if the blocks cannot be read or the confirmation fails.
+func (p *blockProposer) confirm(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32) error {
+	if p.confirmedEpoch == epoch {
+		return nil
+	}
+	opts := protoUtils.GetOptions()
+
+	confirmedBlock, err := blockManagerUtils.GetConfirmedBlock(client, &opts, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to get confirmed block: %w", err)
+	}
+	if confirmedBlock.Valid {
+		log.WithField("epoch", epoch).Debug("Block already confirmed")
+		p.confirmedEpoch = epoch
+		return nil
+	}
+
+	numBlocks, err := blockManagerUtils.GetNumProposedBlocks(client, &opts, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to get number of proposed blocks: %w", err)
+	}
+	if numBlocks.Sign() == 0 {
+		log.WithField("epoch", epoch).Debug("No blocks proposed")
+		p.confirmedEpoch = epoch
+		return nil
+	}
+
+	index, err := blockManagerUtils.GetBlockIndexToBeConfirmed(client, &opts)
+	if err != nil {
+		return fmt.Errorf("failed to get index of block to be confirmed: %w", err)
+	}
+	if index < 0 {
+		log.WithField("epoch", epoch).Info("No valid block to confirm")
+		p.confirmedEpoch = epoch
+		return nil
+	}
+	blockId, err := blockManagerUtils.GetSortedProposedBlockId(client, &opts, epoch, big.NewInt(int64(index)))
+	if err != nil {
+		return fmt.Errorf("failed to get winning block id: %w", err)
+	}
+	block, err := blockManagerUtils.GetProposedBlock(client, &opts, epoch, blockId)
+	if err != nil {
+		return fmt.Errorf("failed to get winning block: %w", err)
+	}
+
+	logFields := logrus.Fields{
+		"epoch":      epoch,
+		"blockId":    blockId,
+		"proposerId": block.ProposerId,
+	}
+	if block.ProposerId != p.stakerId {
+		log.WithFields(logFields).Debug("Winning block proposed by another staker")
+		p.confirmedEpoch = epoch
+		return nil
+	}
+
+	log.WithFields(logFields).Info("Confirming winning block")
+	txnHash, err := cmdUtils.ConfirmBlock(client, config, account, epoch)
+	if err != nil {
+		return fmt.Errorf("failed to confirm block: %w", err)
+	}
+	log.WithFields(logFields).WithField("txHash", txnHash.Hex()).Info("Block confirmed")
+	p.confirmedEpoch = epoch
+	return nil
+}
+
+// loadBlockRound returns the block round of the epoch. A round left over from an
+// earlier epoch is discarded and a fresh round is started.
+func loadBlockRound(epoch uint32) (types.BlockRound, error) {
+	round := types.BlockRound{Epoch: epoch}
+
+	roundPath, err := path.PathUtilsInterface.GetBlockRoundFilePath()
+	if err != nil {
+		return round, err
+	}
+	data, err := path.OSUtilsInterface.ReadFile(roundPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return round, nil
+	}
+	if err != nil {
+		return round, fmt.Errorf("failed to read block round: %w", err)
+	}
+
+	var saved types.BlockRound
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return round, fmt.Errorf("failed to parse block round: %w", err)
+	}
+	if saved.Epoch != epoch {
+		return round, nil
+	}
+	return saved, nil
+}
+
+// saveBlockRound persists the block round atomically by writing to a temporary
+// file and renaming it over the existing round file.
+func saveBlockRound(round types.BlockRound) error {
+	roundPath, err := path.PathUtilsInterface.GetBlockRoundFilePath()
+	if err != nil {
+		return err
+	}
+
+	round.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(round, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal block round: %w", err)
+	}
+	tmpPath := roundPath + ".tmp"
+	if err := path.OSUtilsInterface.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write block round: %w", err)
+	}
+	if err := path.OSUtilsInterface.Rename(tmpPath, roundPath); err != nil {
+		return fmt.Errorf("failed to replace block round: %w", err)
+	}
+	return nil
+}
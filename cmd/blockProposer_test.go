@@ -0,0 +1,335 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"lumino/pkg/bindings"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// concludedEvents returns JobStatusUpdated events of jobs 3 and 7 concluding and job 5 starting
+func concludedEvents() []types.JobEvent {
+	return []types.JobEvent{
+		{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(7), Status: types.JobStatusCompleted},
+		{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(5), Status: types.JobStatusRunning},
+		{Kind: types.JobEventAssigned, JobID: big.NewInt(4)},
+		{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(3), Status: types.JobStatusFailed},
+	}
+}
+
+// Tests proposing blocks in the Confirm state with cases:
+// 1. No block is proposed without concluded jobs
+// 2. The concluded jobs of the epoch are proposed and recorded in the round
+// 3. Jobs already proposed in the epoch are not proposed again
+// 4. A block is proposed again once more jobs concluded
+// 5. No block is proposed once the proposal limit is reached
+// 6. A round left over from an earlier epoch does not count towards the limit
+// 7. A failed proposal is not recorded
+func TestBlockProposerPropose(t *testing.T) {
+	var client *ethclient.Client
+
+	tests := []struct {
+		name          string
+		round         *types.BlockRound
+		maxBlocks     int64
+		events        []types.JobEvent
+		proposeErr    error
+		wantProposal  []*big.Int
+		wantProposals [][]string
+		wantErr       bool
+	}{
+		{
+			name:      "no concluded jobs",
+			maxBlocks: 1,
+			events: []types.JobEvent{
+				{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(5), Status: types.JobStatusRunning},
+			},
+		},
+		{
+			name:          "concluded jobs",
+			maxBlocks:     1,
+			events:        concludedEvents(),
+			wantProposal:  []*big.Int{big.NewInt(3), big.NewInt(7)},
+			wantProposals: [][]string{{"3", "7"}},
+		},
+		{
+			name:          "jobs already proposed",
+			round:         &types.BlockRound{Epoch: 10, Proposals: []types.BlockProposal{{JobIDs: []string{"3", "7"}}}},
+			maxBlocks:     2,
+			events:        concludedEvents(),
+			wantProposals: [][]string{{"3", "7"}},
+		},
+		{
+			name:          "more jobs concluded",
+			round:         &types.BlockRound{Epoch: 10, Proposals: []types.BlockProposal{{JobIDs: []string{"3"}}}},
+			maxBlocks:     2,
+			events:        concludedEvents(),
+			wantProposal:  []*big.Int{big.NewInt(3), big.NewInt(7)},
+			wantProposals: [][]string{{"3"}, {"3", "7"}},
+		},
+		{
+			name:          "proposal limit reached",
+			round:         &types.BlockRound{Epoch: 10, Proposals: []types.BlockProposal{{JobIDs: []string{"3"}}}},
+			maxBlocks:     1,
+			wantProposals: [][]string{{"3"}},
+		},
+		{
+			name:          "round of an earlier epoch",
+			round:         &types.BlockRound{Epoch: 9, Proposals: []types.BlockProposal{{JobIDs: []string{"1"}}}},
+			maxBlocks:     1,
+			events:        concludedEvents(),
+			wantProposal:  []*big.Int{big.NewInt(3), big.NewInt(7)},
+			wantProposals: [][]string{{"3", "7"}},
+		},
+		{
+			name:         "proposal fails",
+			maxBlocks:    1,
+			events:       concludedEvents(),
+			proposeErr:   errors.New("execution reverted"),
+			wantProposal: []*big.Int{big.NewInt(3), big.NewInt(7)},
+			wantErr:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			blockManagerMock := new(mocks.BlockManagerInterface)
+			eventMock := new(mocks.JobEventInterface)
+			eventIndexMock := new(mocks.EventIndexInterface)
+			pathMock := new(pathMocks.PathInterface)
+
+			originalProtoUtils := protoUtils
+			originalCmdUtils := cmdUtils
+			originalBlockManagerUtils := blockManagerUtils
+			originalJobEventUtils := jobEventUtils
+			originalEventIndexUtils := eventIndexUtils
+			originalPathUtils := path.PathUtilsInterface
+			originalOSUtils := path.OSUtilsInterface
+			defer func() {
+				protoUtils = originalProtoUtils
+				cmdUtils = originalCmdUtils
+				blockManagerUtils = originalBlockManagerUtils
+				jobEventUtils = originalJobEventUtils
+				eventIndexUtils = originalEventIndexUtils
+				path.PathUtilsInterface = originalPathUtils
+				path.OSUtilsInterface = originalOSUtils
+			}()
+			protoUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			blockManagerUtils = blockManagerMock
+			jobEventUtils = eventMock
+			eventIndexUtils = eventIndexMock
+			path.PathUtilsInterface = pathMock
+			path.OSUtilsInterface = path.OSUtils{}
+
+			roundPath := filepath.Join(t.TempDir(), "block-round.json")
+			pathMock.On("GetBlockRoundFilePath").Return(roundPath, nil)
+			if tt.round != nil {
+				assert.NoError(t, saveBlockRound(*tt.round))
+			}
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			blockManagerMock.On("GetMaxBlocksPerEpochPerStaker", mock.Anything, mock.Anything).Return(big.NewInt(tt.maxBlocks), nil)
+			eventIndexMock.On("GetEpochStartBlock", mock.Anything, uint32(10)).Return(uint64(500), nil)
+			eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(520), nil)
+			eventMock.On("FilterJobEvents", mock.Anything, uint64(500), uint64(520)).Return(tt.events, nil)
+			if tt.wantProposal != nil {
+				cmdUtilsMock.On("ProposeBlock", mock.Anything, mock.Anything, mock.Anything, uint32(10), tt.wantProposal).
+					Return(common.HexToHash("0x1"), tt.proposeErr).Once()
+			}
+
+			proposer := newBlockProposer(2)
+			err := proposer.Handle(client, types.Configurations{}, types.Account{}, types.EpochStateConfirm, 10)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			cmdUtilsMock.AssertExpectations(t)
+			if tt.wantProposal == nil {
+				cmdUtilsMock.AssertNotCalled(t, "ProposeBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+
+			round, err := loadBlockRound(10)
+			assert.NoError(t, err)
+			var proposals [][]string
+			for _, proposal := range round.Proposals {
+				proposals = append(proposals, proposal.JobIDs)
+			}
+			assert.Equal(t, tt.wantProposals, proposals)
+		})
+	}
+}
+
+// Tests that the concluded job collector only scans new blocks and starts over in a new epoch
+func TestConcludedJobCollector(t *testing.T) {
+	var client *ethclient.Client
+	eventMock := new(mocks.JobEventInterface)
+	eventIndexMock := new(mocks.EventIndexInterface)
+
+	originalJobEventUtils := jobEventUtils
+	originalEventIndexUtils := eventIndexUtils
+	defer func() {
+		jobEventUtils = originalJobEventUtils
+		eventIndexUtils = originalEventIndexUtils
+	}()
+	jobEventUtils = eventMock
+	eventIndexUtils = eventIndexMock
+
+	eventIndexMock.On("GetEpochStartBlock", mock.Anything, uint32(10)).Return(uint64(500), nil).Once()
+	eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(520), nil).Once()
+	eventMock.On("FilterJobEvents", mock.Anything, uint64(500), uint64(520)).Return([]types.JobEvent{
+		{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(7), Status: types.JobStatusCompleted},
+	}, nil).Once()
+	eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(530), nil).Once()
+	eventMock.On("FilterJobEvents", mock.Anything, uint64(521), uint64(530)).Return([]types.JobEvent{
+		{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(3), Status: types.JobStatusFailed},
+	}, nil).Once()
+	eventIndexMock.On("GetEpochStartBlock", mock.Anything, uint32(11)).Return(uint64(540), nil).Once()
+	eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(545), nil).Once()
+	eventMock.On("FilterJobEvents", mock.Anything, uint64(540), uint64(545)).Return([]types.JobEvent{}, nil).Once()
+
+	var collector concludedJobCollector
+	jobIds, err := collector.Collect(client, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(7)}, jobIds)
+
+	jobIds, err = collector.Collect(client, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []*big.Int{big.NewInt(3), big.NewInt(7)}, jobIds)
+
+	jobIds, err = collector.Collect(client, 11)
+	assert.NoError(t, err)
+	assert.Empty(t, jobIds)
+	eventMock.AssertExpectations(t)
+	eventIndexMock.AssertExpectations(t)
+}
+
+// Tests confirming the winning block of the previous epoch in the Assign state with cases:
+// 1. An epoch with a confirmed block is left alone
+// 2. An epoch without proposed blocks is left alone
+// 3. No block is confirmed when no proposed block is valid
+// 4. The winning block of another staker is left to its proposer
+// 5. The winning block of this node is confirmed
+// 6. A failed confirmation is retried on the next tick
+func TestBlockProposerConfirm(t *testing.T) {
+	var client *ethclient.Client
+
+	tests := []struct {
+		name        string
+		setupMocks  func(*mocks.BlockManagerInterface, *mocks.UtilsCmdInterface)
+		wantErr     bool
+		wantSettled bool
+	}{
+		{
+			name: "already confirmed",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, cmdUtilsMock *mocks.UtilsCmdInterface) {
+				blockManagerMock.On("GetConfirmedBlock", mock.Anything, mock.Anything, uint32(10)).Return(bindings.StructsBlock{Valid: true}, nil)
+			},
+			wantSettled: true,
+		},
+		{
+			name: "no proposed blocks",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, cmdUtilsMock *mocks.UtilsCmdInterface) {
+				blockManagerMock.On("GetConfirmedBlock", mock.Anything, mock.Anything, uint32(10)).Return(bindings.StructsBlock{}, nil)
+				blockManagerMock.On("GetNumProposedBlocks", mock.Anything, mock.Anything, uint32(10)).Return(big.NewInt(0), nil)
+			},
+			wantSettled: true,
+		},
+		{
+			name: "no valid block",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, cmdUtilsMock *mocks.UtilsCmdInterface) {
+				blockManagerMock.On("GetConfirmedBlock", mock.Anything, mock.Anything, uint32(10)).Return(bindings.StructsBlock{}, nil)
+				blockManagerMock.On("GetNumProposedBlocks", mock.Anything, mock.Anything, uint32(10)).Return(big.NewInt(2), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", mock.Anything, mock.Anything).Return(int8(-1), nil)
+			},
+			wantSettled: true,
+		},
+		{
+			name: "winning block of another staker",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, cmdUtilsMock *mocks.UtilsCmdInterface) {
+				blockManagerMock.On("GetConfirmedBlock", mock.Anything, mock.Anything, uint32(10)).Return(bindings.StructsBlock{}, nil)
+				blockManagerMock.On("GetNumProposedBlocks", mock.Anything, mock.Anything, uint32(10)).Return(big.NewInt(2), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", mock.Anything, mock.Anything).Return(int8(1), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", mock.Anything, mock.Anything, uint32(10), big.NewInt(1)).Return(uint32(4), nil)
+				blockManagerMock.On("GetProposedBlock", mock.Anything, mock.Anything, uint32(10), uint32(4)).Return(bindings.StructsBlock{Valid: true, ProposerId: 3}, nil)
+			},
+			wantSettled: true,
+		},
+		{
+			name: "winning block of this node",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, cmdUtilsMock *mocks.UtilsCmdInterface) {
+				blockManagerMock.On("GetConfirmedBlock", mock.Anything, mock.Anything, uint32(10)).Return(bindings.StructsBlock{}, nil)
+				blockManagerMock.On("GetNumProposedBlocks", mock.Anything, mock.Anything, uint32(10)).Return(big.NewInt(2), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", mock.Anything, mock.Anything).Return(int8(0), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", mock.Anything, mock.Anything, uint32(10), big.NewInt(0)).Return(uint32(4), nil)
+				blockManagerMock.On("GetProposedBlock", mock.Anything, mock.Anything, uint32(10), uint32(4)).Return(bindings.StructsBlock{Valid: true, ProposerId: 2}, nil)
+				cmdUtilsMock.On("ConfirmBlock", mock.Anything, mock.Anything, mock.Anything, uint32(10)).Return(common.HexToHash("0x1"), nil).Once()
+			},
+			wantSettled: true,
+		},
+		{
+			name: "confirmation fails",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, cmdUtilsMock *mocks.UtilsCmdInterface) {
+				blockManagerMock.On("GetConfirmedBlock", mock.Anything, mock.Anything, uint32(10)).Return(bindings.StructsBlock{}, nil)
+				blockManagerMock.On("GetNumProposedBlocks", mock.Anything, mock.Anything, uint32(10)).Return(big.NewInt(1), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", mock.Anything, mock.Anything).Return(int8(0), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", mock.Anything, mock.Anything, uint32(10), big.NewInt(0)).Return(uint32(0), nil)
+				blockManagerMock.On("GetProposedBlock", mock.Anything, mock.Anything, uint32(10), uint32(0)).Return(bindings.StructsBlock{Valid: true, ProposerId: 2}, nil)
+				cmdUtilsMock.On("ConfirmBlock", mock.Anything, mock.Anything, mock.Anything, uint32(10)).Return(common.Hash{}, errors.New("execution reverted"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			cmdUtilsMock := new(mocks.UtilsCmdInterface)
+			blockManagerMock := new(mocks.BlockManagerInterface)
+
+			originalProtoUtils := protoUtils
+			originalCmdUtils := cmdUtils
+			originalBlockManagerUtils := blockManagerUtils
+			defer func() {
+				protoUtils = originalProtoUtils
+				cmdUtils = originalCmdUtils
+				blockManagerUtils = originalBlockManagerUtils
+			}()
+			protoUtils = utilsMock
+			cmdUtils = cmdUtilsMock
+			blockManagerUtils = blockManagerMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(blockManagerMock, cmdUtilsMock)
+
+			proposer := newBlockProposer(2)
+			err := proposer.Handle(client, types.Configurations{}, types.Account{}, types.EpochStateAssign, 11)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			cmdUtilsMock.AssertExpectations(t)
+
+			// A settled epoch is not looked at again
+			calls := len(blockManagerMock.Calls)
+			err = proposer.Handle(client, types.Configurations{}, types.Account{}, types.EpochStateAssign, 11)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantSettled, len(blockManagerMock.Calls) == calls)
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"lumino/core/types"
+	"lumino/logger"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// blocksCmd groups the read-only commands showing what the BlockManager recorded
+var blocksCmd = &cobra.Command{
+	Use:   "blocks",
+	Short: "Show the blocks recorded by the BlockManager",
+	Long: `Shows the blocks proposed and confirmed in an epoch, with the jobs they list decoded against
+the JobManager. Output is a table or, with --output json, JSON.
+
+Example:
+  ./lumino blocks proposed --epoch 3251
+  ./lumino blocks confirmed --output json
+  ./lumino blocks sorted`,
+}
+
+var blocksProposedCmd = &cobra.Command{
+	Use:   "proposed",
+	Short: "List the blocks proposed in an epoch, defaults to the current epoch",
+	Run:   initialiseBlocksProposed,
+}
+
+var blocksConfirmedCmd = &cobra.Command{
+	Use:   "confirmed",
+	Short: "Show the block confirmed for an epoch, defaults to the previous epoch",
+	Run:   initialiseBlocksConfirmed,
+}
+
+var blocksSortedCmd = &cobra.Command{
+	Use:   "sorted",
+	Short: "Show the ranking of the blocks proposed in an epoch, defaults to the current epoch",
+	Run:   initialiseBlocksSorted,
+}
+
+func initialiseBlocksProposed(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteBlocksProposed(cmd.Flags())
+}
+
+func initialiseBlocksConfirmed(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteBlocksConfirmed(cmd.Flags())
+}
+
+func initialiseBlocksSorted(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteBlocksSorted(cmd.Flags())
+}
+
+// ExecuteBlocksProposed lists the blocks proposed in an epoch with their decoded jobs.
+// Exits with error if the BlockManager is not configured or the blocks cannot be read.
+func (*UtilsStruct) ExecuteBlocksProposed(flagSet *pflag.FlagSet) {
+	client, epoch, format := setupBlocksCommand(flagSet, false)
+
+	blocks, err := getProposedBlocks(client, epoch)
+	utils.CheckError("Error in getting proposed blocks: ", err)
+
+	err = renderBlocks(os.Stdout, format, blocks)
+	utils.CheckError("Error in showing proposed blocks: ", err)
+}
+
+// ExecuteBlocksConfirmed shows the block confirmed for an epoch with its decoded jobs.
+// Exits with error if the BlockManager is not configured or the block cannot be read.
+func (*UtilsStruct) ExecuteBlocksConfirmed(flagSet *pflag.FlagSet) {
+	client, epoch, format := setupBlocksCommand(flagSet, true)
+
+	block, err := getConfirmedBlock(client, epoch)
+	utils.CheckError("Error in getting confirmed block: ", err)
+
+	var blocks []types.BlockInfo
+	if block.Valid {
+		blocks = append(blocks, block)
+	} else if format == outputFormatTable {
+		log.WithField("epoch", epoch).Info("No block confirmed for the epoch")
+		return
+	}
+	err = renderBlocks(os.Stdout, format, blocks)
+	utils.CheckError("Error in showing confirmed block: ", err)
+}
+
+// ExecuteBlocksSorted shows the ranking of the blocks proposed in an epoch and the
+// block that is to be confirmed.
+// Exits with error if the BlockManager is not configured or the ranking cannot be read.
+func (*UtilsStruct) ExecuteBlocksSorted(flagSet *pflag.FlagSet) {
+	client, epoch, format := setupBlocksCommand(flagSet, false)
+
+	sorted, err := getSortedBlocks(client, epoch)
+	utils.CheckError("Error in getting sorted blocks: ", err)
+
+	err = renderSortedBlocks(os.Stdout, format, sorted)
+	utils.CheckError("Error in showing sorted blocks: ", err)
+}
+
+// setupBlocksCommand prepares a blocks subcommand. This function:
+// 1. Validates the output format
+// 2. Loads the configuration and points the bindings at the configured BlockManager
+// 3. Connects to the RPC provider
+// 4. Resolves the epoch, defaulting to the current or, with previous set, the previous epoch
+// Exits with error if any step fails.
+func setupBlocksCommand(flagSet *pflag.FlagSet, previous bool) (*ethclient.Client, uint32, string) {
+	format, err := getOutputFormat(flagSet)
+	utils.CheckError("Error in getting output format: ", err)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("Blocks: Config: %+v", config)
+
+	err = useBlockManager(config)
+	utils.CheckError("Error in getting BlockManager: ", err)
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+	logger.SetLoggerParameters(client, "")
+
+	epoch, err := flagSet.GetUint32("epoch")
+	utils.CheckError("Error in getting epoch: ", err)
+	if epoch == 0 {
+		epoch, _, err = cmdUtils.GetEpochAndState(client)
+		utils.CheckError("Error in getting epoch: ", err)
+		if previous && epoch > 0 {
+			epoch--
+		}
+	}
+	return client, epoch, format
+}
+
+// getProposedBlocks returns the blocks proposed in the epoch in order of their block ID,
+// with the jobs they list decoded against the JobManager.
+// Returns error if the blocks cannot be read.
+func getProposedBlocks(client *ethclient.Client, epoch uint32) ([]types.BlockInfo, error) {
+	opts := protoUtils.GetOptions()
+	numBlocks, err := blockManagerUtils.GetNumProposedBlocks(client, &opts, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get number of proposed blocks: %w", err)
+	}
+
+	blocks := make([]types.BlockInfo, 0, numBlocks.Int64())
+	for blockId := uint32(0); int64(blockId) < numBlocks.Int64(); blockId++ {
+		block, err := blockManagerUtils.GetProposedBlock(client, &opts, epoch, blockId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get proposed block %d: %w", blockId, err)
+		}
+		info := newBlockInfo(client, &opts, epoch, block.Valid, block.ProposerId, block.Iteration, block.BiggestStake, block.JobIds)
+		info.BlockID = &blockId
+		blocks = append(blocks, info)
+	}
+	return blocks, nil
+}
+
+// getConfirmedBlock returns the block confirmed for the epoch, with the jobs it lists
+// decoded against the JobManager. The block is not valid if none was confirmed.
+// Returns error if the block cannot be read.
+func getConfirmedBlock(client *ethclient.Client, epoch uint32) (types.BlockInfo, error) {
+	opts := protoUtils.GetOptions()
+	block, err := blockManagerUtils.GetConfirmedBlock(client, &opts, epoch)
+	if err != nil {
+		return types.BlockInfo{}, fmt.Errorf("failed to get confirmed block: %w", err)
+	}
+	return newBlockInfo(client, &opts, epoch, block.Valid, block.ProposerId, block.Iteration, block.BiggestStake, block.JobIds), nil
+}
+
+// getSortedBlocks returns the ranking of the blocks proposed in the epoch from
+// sortedProposedBlockIds, marking the entry at blockIndexToBeConfirmed.
+// Returns error if the ranking cannot be read.
+func getSortedBlocks(client *ethclient.Client, epoch uint32) ([]types.SortedBlock, error) {
+	opts := protoUtils.GetOptions()
+	numBlocks, err := blockManagerUtils.GetNumProposedBlocks(client, &opts, epoch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get number of proposed blocks: %w", err)
+	}
+	toBeConfirmed, err := blockManagerUtils.GetBlockIndexToBeConfirmed(client, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get index of block to be confirmed: %w", err)
+	}
+
+	sorted := make([]types.SortedBlock, 0, numBlocks.Int64())
+	for index := 0; int64(index) < numBlocks.Int64(); index++ {
+		blockId, err := blockManagerUtils.GetSortedProposedBlockId(client, &opts, epoch, big.NewInt(int64(index)))
+		if err != nil {
+			if index == 0 {
+				return nil, fmt.Errorf("failed to get sorted block id: %w", err)
+			}
+			// Blocks rejected at proposal are left out of the ranking, reading past its end reverts
+			log.WithError(err).WithField("index", index).Debug("End of sorted blocks")
+			break
+		}
+		sorted = append(sorted, types.SortedBlock{
+			Index:         index,
+			BlockID:       blockId,
+			ToBeConfirmed: index == int(toBeConfirmed),
+		})
+	}
+	return sorted, nil
+}
+
+// newBlockInfo builds the view of a block. A job whose details cannot be read is
+// listed with the error instead of failing the whole block.
+func newBlockInfo(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, valid bool, proposerId uint32, iteration *big.Int, biggestStake *big.Int, jobIds []*big.Int) types.BlockInfo {
+	info := types.BlockInfo{
+		Epoch:        epoch,
+		Valid:        valid,
+		ProposerID:   proposerId,
+		Iteration:    iteration,
+		BiggestStake: biggestStake,
+		Jobs:         make([]types.BlockJob, 0, len(jobIds)),
+	}
+	for _, jobId := range jobIds {
+		job := types.BlockJob{JobID: jobId.String()}
+		details, err := jobsManagerUtils.GetJobDetails(client, opts, jobId)
+		if err != nil {
+			log.WithError(err).WithField("jobId", jobId.String()).Warn("Failed to get job details")
+			job.Error = err.Error()
+		} else {
+			job.Creator = details.Creator.Hex()
+			if details.Assignee != (common.Address{}) {
+				job.Assignee = details.Assignee.Hex()
+			}
+			job.ConclusionEpoch = details.ConclusionEpoch
+		}
+		info.Jobs = append(info.Jobs, job)
+	}
+	return info
+}
+
+// renderBlocks writes the blocks to out as JSON or as a table of blocks followed by
+// a table of the jobs they list
+func renderBlocks(out io.Writer, format string, blocks []types.BlockInfo) error {
+	if format == outputFormatJSON {
+		if blocks == nil {
+			blocks = []types.BlockInfo{}
+		}
+		return writeJSON(out, blocks)
+	}
+	if len(blocks) == 0 {
+		_, err := fmt.Fprintln(out, "No blocks")
+		return err
+	}
+
+	blockTable := tablewriter.NewWriter(out)
+	blockTable.SetHeader([]string{"Epoch", "Block ID", "Valid", "Proposer ID", "Iteration", "Biggest Stake", "Job IDs"})
+	jobTable := tablewriter.NewWriter(out)
+	jobTable.SetHeader([]string{"Block ID", "Job ID", "Creator", "Assignee", "Conclusion Epoch"})
+	for _, block := range blocks {
+		blockId := "-"
+		if block.BlockID != nil {
+			blockId = strconv.FormatUint(uint64(*block.BlockID), 10)
+		}
+		jobIds := make([]string, len(block.Jobs))
+		for i, job := range block.Jobs {
+			jobIds[i] = job.JobID
+			if job.Error != "" {
+				jobTable.Append([]string{blockId, job.JobID, "error: " + job.Error, "", ""})
+				continue
+			}
+			jobTable.Append([]string{blockId, job.JobID, job.Creator, orDash(job.Assignee), formatEpoch(job.ConclusionEpoch)})
+		}
+		blockTable.Append([]string{
+			strconv.FormatUint(uint64(block.Epoch), 10),
+			blockId,
+			strconv.FormatBool(block.Valid),
+			strconv.FormatUint(uint64(block.ProposerID), 10),
+			formatBigInt(block.Iteration),
+			formatBigInt(block.BiggestStake),
+			strings.Join(jobIds, ","),
+		})
+	}
+	blockTable.Render()
+	if jobTable.NumLines() > 0 {
+		jobTable.Render()
+	}
+	return nil
+}
+
+// renderSortedBlocks writes the ranking of the blocks to out as JSON or as a table
+func renderSortedBlocks(out io.Writer, format string, sorted []types.SortedBlock) error {
+	if format == outputFormatJSON {
+		return writeJSON(out, sorted)
+	}
+	if len(sorted) == 0 {
+		_, err := fmt.Fprintln(out, "No blocks")
+		return err
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Index", "Block ID", "To Be Confirmed"})
+	for _, block := range sorted {
+		table.Append([]string{
+			strconv.Itoa(block.Index),
+			strconv.FormatUint(uint64(block.BlockID), 10),
+			strconv.FormatBool(block.ToBeConfirmed),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// formatBigInt formats an optional contract value for display
+func formatBigInt(value *big.Int) string {
+	if value == nil {
+		return "-"
+	}
+	return value.String()
+}
+
+// formatEpoch formats an epoch that is 0 until reached for display
+func formatEpoch(epoch uint32) string {
+	if epoch == 0 {
+		return "-"
+	}
+	return strconv.FormatUint(uint64(epoch), 10)
+}
+
+// orDash returns "-" for an empty value
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// Initializes the blocks command group in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(blocksCmd)
+	blocksCmd.AddCommand(blocksProposedCmd, blocksConfirmedCmd, blocksSortedCmd)
+
+	var (
+		Epoch  uint32
+		Output string
+	)
+
+	blocksCmd.PersistentFlags().Uint32VarP(&Epoch, "epoch", "", 0, "epoch of the blocks")
+	blocksCmd.PersistentFlags().StringVarP(&Output, "output", "o", outputFormatTable, "output format (table, json)")
+}
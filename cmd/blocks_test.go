@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"lumino/pkg/bindings"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests listing the blocks proposed in an epoch covering:
+// 1. Blocks with their jobs decoded
+// 2. A job whose details cannot be read
+// 3. No blocks proposed
+// 4. Errors reading the number of blocks or a block
+func TestGetProposedBlocks(t *testing.T) {
+	client := &ethclient.Client{}
+	creator := common.HexToAddress("0x1")
+	assignee := common.HexToAddress("0x2")
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.BlockManagerInterface, *mocks.JobsManagerInterface)
+		expectedBlocks []types.BlockInfo
+		expectedError  bool
+	}{
+		{
+			name: "blocks with decoded jobs",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, jobsMock *mocks.JobsManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(big.NewInt(2), nil)
+				blockManagerMock.On("GetProposedBlock", client, mock.Anything, uint32(5), uint32(0)).Return(bindings.StructsBlock{
+					Valid: true, ProposerId: 1, JobIds: []*big.Int{big.NewInt(3)}, Iteration: big.NewInt(10), BiggestStake: big.NewInt(100),
+				}, nil)
+				blockManagerMock.On("GetProposedBlock", client, mock.Anything, uint32(5), uint32(1)).Return(bindings.StructsBlock{
+					Valid: false, ProposerId: 2, JobIds: []*big.Int{big.NewInt(3), big.NewInt(4)}, Iteration: big.NewInt(20), BiggestStake: big.NewInt(50),
+				}, nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(3)).Return(types.JobContract{Creator: creator, Assignee: assignee, ConclusionEpoch: 5}, nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(4)).Return(types.JobContract{}, errors.New("job not found"))
+			},
+			expectedBlocks: []types.BlockInfo{
+				{
+					Epoch: 5, BlockID: uint32Ptr(0), Valid: true, ProposerID: 1, Iteration: big.NewInt(10), BiggestStake: big.NewInt(100),
+					Jobs: []types.BlockJob{{JobID: "3", Creator: creator.Hex(), Assignee: assignee.Hex(), ConclusionEpoch: 5}},
+				},
+				{
+					Epoch: 5, BlockID: uint32Ptr(1), Valid: false, ProposerID: 2, Iteration: big.NewInt(20), BiggestStake: big.NewInt(50),
+					Jobs: []types.BlockJob{
+						{JobID: "3", Creator: creator.Hex(), Assignee: assignee.Hex(), ConclusionEpoch: 5},
+						{JobID: "4", Error: "job not found"},
+					},
+				},
+			},
+		},
+		{
+			name: "no blocks proposed",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, jobsMock *mocks.JobsManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(big.NewInt(0), nil)
+			},
+			expectedBlocks: []types.BlockInfo{},
+		},
+		{
+			name: "number of blocks cannot be read",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, jobsMock *mocks.JobsManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(nil, errors.New("rpc error"))
+			},
+			expectedError: true,
+		},
+		{
+			name: "block cannot be read",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, jobsMock *mocks.JobsManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(big.NewInt(1), nil)
+				blockManagerMock.On("GetProposedBlock", client, mock.Anything, uint32(5), uint32(0)).Return(bindings.StructsBlock{}, errors.New("rpc error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockManagerMock := new(mocks.BlockManagerInterface)
+			jobsMock := new(mocks.JobsManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+
+			originalBlockManagerUtils := blockManagerUtils
+			originalJobsManagerUtils := jobsManagerUtils
+			originalProtoUtils := protoUtils
+			defer func() {
+				blockManagerUtils = originalBlockManagerUtils
+				jobsManagerUtils = originalJobsManagerUtils
+				protoUtils = originalProtoUtils
+			}()
+			blockManagerUtils = blockManagerMock
+			jobsManagerUtils = jobsMock
+			protoUtils = utilsMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(blockManagerMock, jobsMock)
+
+			blocks, err := getProposedBlocks(client, 5)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBlocks, blocks)
+		})
+	}
+}
+
+// Tests reading the block confirmed for an epoch, including an epoch without one
+func TestGetConfirmedBlock(t *testing.T) {
+	client := &ethclient.Client{}
+	blockManagerMock := new(mocks.BlockManagerInterface)
+	jobsMock := new(mocks.JobsManagerInterface)
+	utilsMock := new(mocks.UtilsInterface)
+
+	originalBlockManagerUtils := blockManagerUtils
+	originalJobsManagerUtils := jobsManagerUtils
+	originalProtoUtils := protoUtils
+	defer func() {
+		blockManagerUtils = originalBlockManagerUtils
+		jobsManagerUtils = originalJobsManagerUtils
+		protoUtils = originalProtoUtils
+	}()
+	blockManagerUtils = blockManagerMock
+	jobsManagerUtils = jobsMock
+	protoUtils = utilsMock
+
+	utilsMock.On("GetOptions").Return(bind.CallOpts{})
+	blockManagerMock.On("GetConfirmedBlock", client, mock.Anything, uint32(4)).Return(bindings.StructsBlock{
+		Valid: true, ProposerId: 3, JobIds: []*big.Int{big.NewInt(8)}, Iteration: big.NewInt(1), BiggestStake: big.NewInt(9),
+	}, nil)
+	blockManagerMock.On("GetConfirmedBlock", client, mock.Anything, uint32(5)).Return(bindings.StructsBlock{}, nil)
+	blockManagerMock.On("GetConfirmedBlock", client, mock.Anything, uint32(6)).Return(bindings.StructsBlock{}, errors.New("rpc error"))
+	jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(8)).Return(types.JobContract{Creator: common.HexToAddress("0x1")}, nil)
+
+	block, err := getConfirmedBlock(client, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, types.BlockInfo{
+		Epoch: 4, Valid: true, ProposerID: 3, Iteration: big.NewInt(1), BiggestStake: big.NewInt(9),
+		Jobs: []types.BlockJob{{JobID: "8", Creator: common.HexToAddress("0x1").Hex()}},
+	}, block)
+
+	block, err = getConfirmedBlock(client, 5)
+	assert.NoError(t, err)
+	assert.False(t, block.Valid)
+	assert.Empty(t, block.Jobs)
+
+	_, err = getConfirmedBlock(client, 6)
+	assert.Error(t, err)
+}
+
+// Tests reading the ranking of the blocks proposed in an epoch covering:
+// 1. A full ranking with the block to be confirmed marked
+// 2. A ranking shorter than the number of blocks proposed
+// 3. A ranking that cannot be read
+func TestGetSortedBlocks(t *testing.T) {
+	client := &ethclient.Client{}
+
+	tests := []struct {
+		name           string
+		setupMocks     func(*mocks.BlockManagerInterface)
+		expectedSorted []types.SortedBlock
+		expectedError  bool
+	}{
+		{
+			name: "full ranking",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(big.NewInt(2), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", client, mock.Anything).Return(int8(0), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", client, mock.Anything, uint32(5), big.NewInt(0)).Return(uint32(1), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", client, mock.Anything, uint32(5), big.NewInt(1)).Return(uint32(0), nil)
+			},
+			expectedSorted: []types.SortedBlock{
+				{Index: 0, BlockID: 1, ToBeConfirmed: true},
+				{Index: 1, BlockID: 0},
+			},
+		},
+		{
+			name: "shorter ranking",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(big.NewInt(3), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", client, mock.Anything).Return(int8(-1), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", client, mock.Anything, uint32(5), big.NewInt(0)).Return(uint32(2), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", client, mock.Anything, uint32(5), big.NewInt(1)).Return(uint32(0), errors.New("execution reverted"))
+			},
+			expectedSorted: []types.SortedBlock{{Index: 0, BlockID: 2}},
+		},
+		{
+			name: "ranking cannot be read",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface) {
+				blockManagerMock.On("GetNumProposedBlocks", client, mock.Anything, uint32(5)).Return(big.NewInt(1), nil)
+				blockManagerMock.On("GetBlockIndexToBeConfirmed", client, mock.Anything).Return(int8(0), nil)
+				blockManagerMock.On("GetSortedProposedBlockId", client, mock.Anything, uint32(5), big.NewInt(0)).Return(uint32(0), errors.New("rpc error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockManagerMock := new(mocks.BlockManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+
+			originalBlockManagerUtils := blockManagerUtils
+			originalProtoUtils := protoUtils
+			defer func() {
+				blockManagerUtils = originalBlockManagerUtils
+				protoUtils = originalProtoUtils
+			}()
+			blockManagerUtils = blockManagerMock
+			protoUtils = utilsMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(blockManagerMock)
+
+			sorted, err := getSortedBlocks(client, 5)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSorted, sorted)
+		})
+	}
+}
+
+// Tests rendering blocks as a table and as JSON
+func TestRenderBlocks(t *testing.T) {
+	blocks := []types.BlockInfo{
+		{
+			Epoch: 5, BlockID: uint32Ptr(0), Valid: true, ProposerID: 1, Iteration: big.NewInt(10), BiggestStake: big.NewInt(100),
+			Jobs: []types.BlockJob{{JobID: "3", Creator: "0xCreator", ConclusionEpoch: 5}, {JobID: "4", Error: "job not found"}},
+		},
+	}
+
+	var table bytes.Buffer
+	assert.NoError(t, renderBlocks(&table, outputFormatTable, blocks))
+	assert.Contains(t, table.String(), "3,4")
+	assert.Contains(t, table.String(), "0xCreator")
+	assert.Contains(t, table.String(), "error: job not found")
+
+	var empty bytes.Buffer
+	assert.NoError(t, renderBlocks(&empty, outputFormatTable, nil))
+	assert.Equal(t, "No blocks\n", empty.String())
+
+	var out bytes.Buffer
+	assert.NoError(t, renderBlocks(&out, outputFormatJSON, blocks))
+	var decoded []map[string]interface{}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Len(t, decoded, 1)
+	assert.Equal(t, float64(0), decoded[0]["block_id"])
+	assert.Equal(t, float64(100), decoded[0]["biggest_stake"])
+	assert.Len(t, decoded[0]["jobs"], 2)
+
+	out.Reset()
+	assert.NoError(t, renderBlocks(&out, outputFormatJSON, nil))
+	assert.Equal(t, "[]\n", out.String())
+}
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
@@ -76,6 +76,34 @@ func (*UtilsStruct) GetConfigData() (types.Configurations, error) {
 	if err != nil {
 		return config, err
 	}
+	assignmentStrategy, err := cmdUtils.GetAssignmentStrategy()
+	if err != nil {
+		return config, err
+	}
+	shutdownTimeout, err := cmdUtils.GetShutdownTimeout()
+	if err != nil {
+		return config, err
+	}
+	blockManagerAddress, err := cmdUtils.GetBlockManagerAddress()
+	if err != nil {
+		return config, err
+	}
+	resultsStore, err := cmdUtils.GetResultsStore()
+	if err != nil {
+		return config, err
+	}
+	datasetCacheSize, err := cmdUtils.GetDatasetCacheSize()
+	if err != nil {
+		return config, err
+	}
+	datasetRoot, err := cmdUtils.GetDatasetRoot()
+	if err != nil {
+		return config, err
+	}
+	workflowsFile, err := cmdUtils.GetWorkflowsFile()
+	if err != nil {
+		return config, err
+	}
 	config.Provider = provider
 	config.GasMultiplier = gasMultiplier
 	config.BufferPercent = bufferPercent
@@ -84,6 +112,13 @@ func (*UtilsStruct) GetConfigData() (types.Configurations, error) {
 	config.LogLevel = logLevel
 	config.GasLimitMultiplier = gasLimit
 	config.RPCTimeout = rpcTimeout
+	config.AssignmentStrategy = assignmentStrategy
+	config.ShutdownTimeout = shutdownTimeout
+	config.BlockManagerAddress = blockManagerAddress
+	config.ResultsStore = resultsStore
+	config.DatasetCacheSize = datasetCacheSize
+	config.DatasetRoot = datasetRoot
+	config.WorkflowsFile = workflowsFile
 	utils.RPCTimeout = rpcTimeout
 
 	return config, nil
@@ -217,3 +252,114 @@ func (*UtilsStruct) GetRPCTimeout() (int64, error) {
 	}
 	return rpcTimeout, nil
 }
+
+// GetAssignmentStrategy retrieves the job assignment strategy used by the admin node
+// from configuration or flags. Falls back to assigning every job to the admin.
+func (*UtilsStruct) GetAssignmentStrategy() (string, error) {
+	assignmentStrategy, err := flagSetUtils.GetRootStringAssignmentStrategy()
+	if err != nil {
+		return core.DefaultAssignmentStrategy, err
+	}
+	if assignmentStrategy == "" {
+		if viper.IsSet("assignmentStrategy") {
+			assignmentStrategy = viper.GetString("assignmentStrategy")
+		} else {
+			assignmentStrategy = core.DefaultAssignmentStrategy
+			log.Debug("AssignmentStrategy is not set, taking its default value ", assignmentStrategy)
+		}
+	}
+	return assignmentStrategy, nil
+}
+
+// GetShutdownTimeout retrieves how many seconds executeJob waits for running jobs on shutdown
+// from configuration or flags. Falls back to the default timeout if not specified.
+func (*UtilsStruct) GetShutdownTimeout() (int64, error) {
+	shutdownTimeout, err := flagSetUtils.GetRootInt64ShutdownTimeout()
+	if err != nil {
+		return int64(core.DefaultShutdownTimeout), err
+	}
+	if shutdownTimeout == -1 {
+		if viper.IsSet("shutdownTimeout") {
+			shutdownTimeout = viper.GetInt64("shutdownTimeout")
+		} else {
+			shutdownTimeout = int64(core.DefaultShutdownTimeout)
+			log.Debug("ShutdownTimeout is not set, taking its default value ", shutdownTimeout)
+		}
+	}
+	return shutdownTimeout, nil
+}
+
+// GetBlockManagerAddress retrieves the address of the BlockManager contract from configuration
+// or flags. Falls back to core.BlockManagerAddress, which is empty until the contract is deployed.
+func (*UtilsStruct) GetBlockManagerAddress() (string, error) {
+	blockManagerAddress, err := flagSetUtils.GetRootStringBlockManagerAddress()
+	if err != nil {
+		return core.BlockManagerAddress, err
+	}
+	if blockManagerAddress == "" {
+		if viper.IsSet("blockManagerAddress") {
+			blockManagerAddress = viper.GetString("blockManagerAddress")
+		} else {
+			blockManagerAddress = core.BlockManagerAddress
+			log.Debug("BlockManagerAddress is not set, taking its default value ", blockManagerAddress)
+		}
+	}
+	return blockManagerAddress, nil
+}
+
+// GetResultsStore retrieves the location job results are published to from configuration or
+// flags: a directory or an s3:// URL. Empty when results are not published.
+func (*UtilsStruct) GetResultsStore() (string, error) {
+	resultsStore, err := flagSetUtils.GetRootStringResultsStore()
+	if err != nil {
+		return "", err
+	}
+	if resultsStore == "" && viper.IsSet("resultsStore") {
+		resultsStore = viper.GetString("resultsStore")
+	}
+	return resultsStore, nil
+}
+
+// GetDatasetCacheSize retrieves the size in megabytes the dataset cache is kept under from
+// configuration or flags. Falls back to the default size if not specified.
+func (*UtilsStruct) GetDatasetCacheSize() (int64, error) {
+	datasetCacheSize, err := flagSetUtils.GetRootInt64DatasetCacheSize()
+	if err != nil {
+		return int64(core.DefaultDatasetCacheSize), err
+	}
+	if datasetCacheSize == -1 {
+		if viper.IsSet("datasetCacheSize") {
+			datasetCacheSize = viper.GetInt64("datasetCacheSize")
+		} else {
+			datasetCacheSize = int64(core.DefaultDatasetCacheSize)
+			log.Debug("DatasetCacheSize is not set, taking its default value ", datasetCacheSize)
+		}
+	}
+	return datasetCacheSize, nil
+}
+
+// GetDatasetRoot retrieves the directory file:// datasets must be under from configuration or
+// flags. Empty when file:// datasets are refused.
+func (*UtilsStruct) GetDatasetRoot() (string, error) {
+	datasetRoot, err := flagSetUtils.GetRootStringDatasetRoot()
+	if err != nil {
+		return "", err
+	}
+	if datasetRoot == "" && viper.IsSet("datasetRoot") {
+		datasetRoot = viper.GetString("datasetRoot")
+	}
+	return datasetRoot, nil
+}
+
+// GetWorkflowsFile retrieves the JSON file declaring the script workflows of the node from
+// configuration or flags. Empty when only the built-in workflows are available.
+func (*UtilsStruct) GetWorkflowsFile() (string, error) {
+	workflowsFile, err := flagSetUtils.GetRootStringWorkflowsFile()
+	if err != nil {
+		return "", err
+	}
+	if workflowsFile == "" && viper.IsSet("workflowsFile") {
+		workflowsFile = viper.GetString("workflowsFile")
+	}
+	return workflowsFile, nil
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/logger"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var confirmBlockCmd = &cobra.Command{
+	Use:   "confirmBlock",
+	Short: "Confirm the winning block of an epoch",
+	Long: `Confirms the winning block among the blocks proposed to the BlockManager in an epoch.
+Without --epoch the previous epoch is used, whose proposals are closed.
+
+executeJob --proposer confirms the blocks it proposed on its own; this command does it by hand,
+for instance when the proposer of the winning block did not confirm it.
+
+Example:
+  ./lumino confirmBlock -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771
+  ./lumino confirmBlock -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --epoch 3251`,
+	Run: initialiseConfirmBlock,
+}
+
+// This function initialises the ExecuteConfirmBlock function
+func initialiseConfirmBlock(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteConfirmBlock(cmd.Flags())
+}
+
+// ExecuteConfirmBlock confirms the winning block of an epoch by hand. This function:
+// 1. Loads the configuration and points the bindings at the configured BlockManager
+// 2. Sets up blockchain connection and logging
+// 3. Uses the previous epoch unless --epoch is passed
+// 4. Confirms the block
+// Exits with error if the BlockManager is not configured or the confirmation fails.
+func (*UtilsStruct) ExecuteConfirmBlock(flagSet *pflag.FlagSet) {
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("ExecuteConfirmBlock: Config: %+v", config)
+
+	err = useBlockManager(config)
+	utils.CheckError("Error in getting BlockManager: ", err)
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	logger.SetLoggerParameters(client, address)
+	log.Debug("Checking to assign log file...")
+	protoUtils.AssignLogFile(flagSet)
+
+	log.Debug("Getting password...")
+	password := protoUtils.AssignPassword(flagSet)
+
+	epoch, err := flagSet.GetUint32("epoch")
+	utils.CheckError("Error in getting epoch: ", err)
+	if epoch == 0 {
+		currentEpoch, _, err := cmdUtils.GetEpochAndState(client)
+		utils.CheckError("Error in getting epoch: ", err)
+		if currentEpoch == 0 {
+			log.Fatal("No previous epoch to confirm")
+		}
+		epoch = currentEpoch - 1
+	}
+
+	account := types.Account{
+		Address:  address,
+		Password: password,
+	}
+
+	txnHash, err := cmdUtils.ConfirmBlock(client, config, account, epoch)
+	utils.CheckError("Error in confirming block: ", err)
+
+	log.WithFields(logrus.Fields{
+		"txHash": txnHash.Hex(),
+		"epoch":  epoch,
+	}).Info("Block confirmed successfully")
+}
+
+// ConfirmBlock confirms the winning block of the epoch. This function:
+// 1. Validates the client
+// 2. Constructs and submits the confirmBlock transaction
+// 3. Monitors transaction confirmation
+// Returns the transaction hash once the confirmation is mined.
+func (*UtilsStruct) ConfirmBlock(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32) (common.Hash, error) {
+	if client == nil {
+		log.Error("Client is nil")
+		return common.Hash{}, errors.New("client is nil")
+	}
+
+	log.WithField("epoch", epoch).Debug("Executing confirmBlock transaction")
+
+	txnArgs := types.TransactionOptions{
+		Client:          client,
+		AccountAddress:  account.Address,
+		Password:        account.Password,
+		ChainId:         core.ChainID,
+		Config:          config,
+		ContractAddress: core.BlockManagerAddress,
+		MethodName:      "confirmBlock",
+		Parameters:      []interface{}{epoch},
+		ABI:             bindings.BlockManagerABI,
+	}
+
+	txnOpts := protoUtils.GetTransactionOpts(txnArgs)
+
+	txn, err := blockManagerUtils.ConfirmBlock(client, txnOpts, epoch)
+	if err != nil {
+		log.WithError(err).WithField("epoch", epoch).Error("Failed to confirm block")
+		return common.Hash{}, err
+	}
+
+	if txn == nil {
+		log.Error("Transaction is nil")
+		return common.Hash{}, errors.New("transaction is nil")
+	}
+
+	txnHash := transactionUtils.Hash(txn)
+	log.WithFields(logrus.Fields{
+		"txHash": txnHash.Hex(),
+		"epoch":  epoch,
+	}).Info("Block confirmation transaction submitted")
+
+	err = protoUtils.WaitForBlockCompletion(client, txnHash.Hex())
+	if err != nil {
+		log.WithError(err).WithField("epoch", epoch).Error("Failed to wait for block completion")
+		return common.Hash{}, err
+	}
+
+	return txnHash, nil
+}
+
+// Initializes the confirmBlock command in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(confirmBlockCmd)
+
+	var (
+		Account  string
+		Password string
+		Epoch    uint32
+	)
+
+	confirmBlockCmd.Flags().StringVarP(&Account, "address", "a", "", "address of the confirmer")
+	confirmBlockCmd.Flags().StringVarP(&Password, "password", "", "", "password path of the confirmer to protect the keystore")
+	confirmBlockCmd.Flags().Uint32VarP(&Epoch, "epoch", "", 0, "epoch of the block, defaults to the previous epoch")
+
+	addrErr := confirmBlockCmd.MarkFlagRequired("address")
+	utils.CheckError("Address error: ", addrErr)
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests the confirmBlock command covering:
+// 1. Confirming the block of the given epoch
+// 2. Confirming the block of the previous epoch by default
+// 3. A missing BlockManager address
+func TestExecuteConfirmBlock(t *testing.T) {
+	var client *ethclient.Client
+	blockManagerAddress := "0xab110dA2064AC0B44c08D71A3D8148BBB0C3aD1F"
+
+	tests := []struct {
+		name          string
+		config        types.Configurations
+		epoch         uint32
+		setupMocks    func(*mocks.UtilsCmdInterface)
+		expectedFatal bool
+	}{
+		{
+			name:   "given epoch",
+			config: types.Configurations{BlockManagerAddress: blockManagerAddress},
+			epoch:  7,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface) {
+				cmdMock.On("ConfirmBlock", mock.Anything, mock.Anything, mock.Anything, uint32(7)).Return(common.HexToHash("0x1"), nil).Once()
+			},
+		},
+		{
+			name:   "previous epoch",
+			config: types.Configurations{BlockManagerAddress: blockManagerAddress},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface) {
+				cmdMock.On("GetEpochAndState", mock.Anything).Return(uint32(11), int64(types.EpochStateAssign), nil)
+				cmdMock.On("ConfirmBlock", mock.Anything, mock.Anything, mock.Anything, uint32(10)).Return(common.HexToHash("0x1"), nil).Once()
+			},
+		},
+		{
+			name:  "BlockManager not configured",
+			epoch: 7,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface) {
+				cmdMock.On("ConfirmBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(common.Hash{}, nil)
+			},
+			expectedFatal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			flagSetMock := new(mocks.FlagSetInterface)
+			cmdMock := new(mocks.UtilsCmdInterface)
+
+			originalProtoUtils := protoUtils
+			originalFlagSetUtils := flagSetUtils
+			originalCmdUtils := cmdUtils
+			originalBlockManagerAddress := core.BlockManagerAddress
+			defer func() {
+				protoUtils = originalProtoUtils
+				flagSetUtils = originalFlagSetUtils
+				cmdUtils = originalCmdUtils
+				core.BlockManagerAddress = originalBlockManagerAddress
+				log.ExitFunc = nil
+			}()
+			protoUtils = utilsMock
+			flagSetUtils = flagSetMock
+			cmdUtils = cmdMock
+
+			flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flagSet.Uint32("epoch", tt.epoch, "")
+
+			cmdMock.On("GetConfigData").Return(tt.config, nil)
+			utilsMock.On("ConnectToEthClient", mock.AnythingOfType("string")).Return(client)
+			flagSetMock.On("GetStringAddress", mock.Anything).Return("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771", nil)
+			utilsMock.On("AssignLogFile", mock.Anything)
+			utilsMock.On("AssignPassword", mock.Anything).Return("password")
+			tt.setupMocks(cmdMock)
+
+			var fatal bool
+			log.ExitFunc = func(int) { fatal = true }
+
+			utils := &UtilsStruct{}
+			utils.ExecuteConfirmBlock(flagSet)
+
+			assert.Equal(t, tt.expectedFatal, fatal)
+			if !tt.expectedFatal {
+				cmdMock.AssertExpectations(t)
+			}
+		})
+	}
+}
+
+// Tests submitting a block confirmation including:
+// 1. Successful confirmation
+// 2. Nil client handling
+// 3. Transaction failure scenarios
+// 4. Block completion errors
+func TestConfirmBlock(t *testing.T) {
+	client := &ethclient.Client{}
+	account := types.Account{
+		Address:  "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+		Password: "password",
+	}
+
+	tests := []struct {
+		name          string
+		client        *ethclient.Client
+		setupMocks    func(*mocks.BlockManagerInterface, *mocks.UtilsInterface, *mocks.TransactionInterface)
+		expectedError bool
+	}{
+		{
+			name:   "When the block is confirmed successfully",
+			client: client,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				utilsMock.On("GetTransactionOpts", mock.MatchedBy(func(txnArgs types.TransactionOptions) bool {
+					return txnArgs.MethodName == "confirmBlock" && txnArgs.ContractAddress == core.BlockManagerAddress
+				})).Return(nil)
+				blockManagerMock.On("ConfirmBlock", client, mock.Anything, uint32(10)).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+			},
+		},
+		{
+			name: "When ethereum client is nil",
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When there is a transaction error",
+			client: client,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				blockManagerMock.On("ConfirmBlock", client, mock.Anything, uint32(10)).Return(nil, errors.New("transaction error"))
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When block completion is not successful",
+			client: client,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				blockManagerMock.On("ConfirmBlock", client, mock.Anything, uint32(10)).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("block completion error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockManagerMock := new(mocks.BlockManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+			txMock := new(mocks.TransactionInterface)
+
+			originalBlockManagerUtils := blockManagerUtils
+			originalProtoUtils := protoUtils
+			originalTransactionUtils := transactionUtils
+			defer func() {
+				blockManagerUtils = originalBlockManagerUtils
+				protoUtils = originalProtoUtils
+				transactionUtils = originalTransactionUtils
+			}()
+			blockManagerUtils = blockManagerMock
+			protoUtils = utilsMock
+			transactionUtils = txMock
+
+			tt.setupMocks(blockManagerMock, utilsMock, txMock)
+
+			utils := &UtilsStruct{}
+			txnHash, err := utils.ConfirmBlock(tt.client, types.Configurations{}, account, 10)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, common.HexToHash("0x123"), txnHash)
+		})
+	}
+}
@@ -2,13 +2,16 @@
 package cmd
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"lumino/core"
 	"lumino/core/types"
 	"lumino/logger"
 	"lumino/pkg/bindings"
 	"lumino/utils"
 	"math/big"
+	"os"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -21,10 +24,13 @@ import (
 var createJobCmd = &cobra.Command{
 	Use:   "createJob",
 	Short: "Create a new job",
-	Long: `Create a new job by providing a job configuration file and job fee.
+	Long: `Create a new job by providing a job configuration file or a job template, and the job fee.
+Fields of the job spec can be overridden with --set key=value, and --dry-run prints the merged
+job spec without submitting it.
 
 Example:
-  ./lumino createJob -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --config /path/to/config.json --jobFee 1000000000000000000`,
+  ./lumino createJob -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --config /path/to/config.json --jobFee 1000000000000000000
+  ./lumino createJob -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --template llama3.1-8b-lora --set dataset_id=gs://bucket/train.jsonl --set lr=3e-4 --set num_epochs=2 --jobFee 1000000000000000000 --dry-run`,
 	Run: initialiseCreateJob,
 }
 
@@ -35,29 +41,34 @@ func initialiseCreateJob(cmd *cobra.Command, args []string) {
 
 // ExecuteCreateJob Orchestrates the job creation process by handling flag parsing,
 // config validation, and blockchain setup. This function:
-// 1. Validates configuration and connects to the blockchain
-// 2. Reads and parses the job configuration file
-// 3. Submits the job creation transaction
+// 1. Builds the job spec from the config file or template and the --set overrides
+// 2. Validates the job spec against the job spec schema and parses the job fee
+// 3. Prints the merged job spec and returns on --dry-run
+// 4. Connects to the blockchain and submits the job creation transaction
 // Returns early if any validation fails or if transaction submission fails.
 func (*UtilsStruct) ExecuteCreateJob(flagSet *pflag.FlagSet) {
 	config, err := cmdUtils.GetConfigData()
 	utils.CheckError("Error in getting config: ", err)
 	log.Debugf("RunCreateJob: Config: %+v", config)
 
-	client := protoUtils.ConnectToEthClient(config.Provider)
+	err = registerConfiguredWorkflows(config)
+	utils.CheckError("Error in registering workflows: ", err)
 
-	address, err := flagSetUtils.GetStringAddress(flagSet)
-	utils.CheckError("Error in getting address: ", err)
+	configPath, err := flagSet.GetString("config")
+	utils.CheckError("Error in getting config path: ", err)
 
-	logger.SetLoggerParameters(client, address)
-	log.Debug("Checking to assign log file...")
-	protoUtils.AssignLogFile(flagSet)
+	templateName, err := flagSet.GetString("template")
+	utils.CheckError("Error in getting template: ", err)
 
-	log.Debug("Getting password...")
-	password := protoUtils.AssignPassword(flagSet)
+	overrides, err := flagSet.GetStringArray("set")
+	utils.CheckError("Error in getting overrides: ", err)
 
-	configPath, err := flagSet.GetString("config")
-	utils.CheckError("Error in getting config path: ", err)
+	dryRun, err := flagSet.GetBool("dry-run")
+	utils.CheckError("Error in getting dry-run flag: ", err)
+
+	// Reject a malformed spec before spending gas on it
+	jobDetailsJSON, err := buildJobSpec(configPath, templateName, overrides)
+	utils.CheckError("Error validating job spec: ", err)
 
 	jobFeeStr, err := flagSet.GetString("jobFee")
 	utils.CheckError("Error in getting job fee: ", err)
@@ -67,9 +78,24 @@ func (*UtilsStruct) ExecuteCreateJob(flagSet *pflag.FlagSet) {
 		utils.CheckError("Error converting job fee to big.Int: ", errors.New("invalid job fee"))
 	}
 
-	// Read and parse the job configuration file
-	jobDetailsJSON, err := osUtils.ReadFile(configPath)
-	utils.CheckError("Error reading job configuration file: ", err)
+	if dryRun {
+		log.WithField("jobFee", jobFeeStr).Info("Dry run, the job is not submitted")
+		err = writeJSON(os.Stdout, json.RawMessage(jobDetailsJSON))
+		utils.CheckError("Error in printing job spec: ", err)
+		return
+	}
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	logger.SetLoggerParameters(client, address)
+	log.Debug("Checking to assign log file...")
+	protoUtils.AssignLogFile(flagSet)
+
+	log.Debug("Getting password...")
+	password := protoUtils.AssignPassword(flagSet)
 
 	// Create the job
 	log.Info("Creating job...")
@@ -82,6 +108,59 @@ func (*UtilsStruct) ExecuteCreateJob(flagSet *pflag.FlagSet) {
 	log.Info("Job created successfully. Transaction Hash: ", txnHash.Hex())
 }
 
+// buildJobSpec returns the job spec to submit. This function:
+// 1. Reads the job configuration file, or loads the template when no file is given
+// 2. Applies the --set overrides, submitting a file without overrides as written
+// 3. Validates the result against the job spec schema
+// Returns error if not exactly one source is given, the source cannot be read or the spec is malformed.
+func buildJobSpec(configPath string, templateName string, overrides []string) ([]byte, error) {
+	if (configPath == "") == (templateName == "") {
+		return nil, errors.New("exactly one of --config and --template is required")
+	}
+
+	var fields map[string]json.RawMessage
+	if configPath != "" {
+		data, err := osUtils.ReadFile(configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read job configuration file: %w", err)
+		}
+		if len(overrides) == 0 {
+			if _, err := parseJobSpec(data); err != nil {
+				return nil, err
+			}
+			return data, nil
+		}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("job spec is not a JSON object: %w", err)
+		}
+		if fields == nil {
+			fields = make(map[string]json.RawMessage)
+		}
+	} else {
+		template, err := loadJobTemplate(templateName)
+		if err != nil {
+			return nil, err
+		}
+		log.WithFields(logrus.Fields{
+			"template": template.Name,
+			"source":   template.Source,
+		}).Debug("Loaded job template")
+		fields = template.Fields
+	}
+
+	if err := applyJobSpecOverrides(fields, overrides); err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job spec: %w", err)
+	}
+	if _, err := parseJobSpec(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 // CreateJob creates a new job in the Lumino network with the provided job details and fee. This function handles
 // the blockchain interaction for job creation including transaction submission and monitoring.
 // It validates all inputs, constructs and submits the transaction, and waits for confirmation.
@@ -149,26 +228,32 @@ func (u *UtilsStruct) CreateJob(client *ethclient.Client, config types.Configura
 }
 
 // Sets up the command line interface for job creation by configuring required flags and help text.
-// Ensures critical parameters like address, config file path and job fee are marked as required.
+// Ensures critical parameters like address and job fee are marked as required, and that a job is
+// created from either a config file or a template.
 func init() {
 	rootCmd.AddCommand(createJobCmd)
 
 	var (
-		Account    string
-		Password   string
-		ConfigPath string
-		JobFee     string
+		Account      string
+		Password     string
+		ConfigPath   string
+		TemplateName string
+		Overrides    []string
+		JobFee       string
+		DryRun       bool
 	)
 
 	createJobCmd.Flags().StringVarP(&Account, "address", "a", "", "address of the job creator")
 	createJobCmd.Flags().StringVarP(&Password, "password", "", "", "password path of job creator to protect the keystore")
 	createJobCmd.Flags().StringVarP(&ConfigPath, "config", "c", "", "path to the job configuration file")
+	createJobCmd.Flags().StringVarP(&TemplateName, "template", "t", "", "name of the job template to start from, see jobTemplates list")
+	createJobCmd.Flags().StringArrayVar(&Overrides, "set", nil, "override a job spec field as key=value, can be repeated")
 	createJobCmd.Flags().StringVarP(&JobFee, "jobFee", "f", "", "job fee in wei")
+	createJobCmd.Flags().BoolVarP(&DryRun, "dry-run", "", false, "print the merged job spec without submitting the job")
 
 	AddrErr := createJobCmd.MarkFlagRequired("address")
 	utils.CheckError("Address error: ", AddrErr)
-	configPath := createJobCmd.MarkFlagRequired("config")
-	utils.CheckError("Path error : ", configPath)
+	createJobCmd.MarkFlagsMutuallyExclusive("config", "template")
 	jobFee := createJobCmd.MarkFlagRequired("jobFee")
 	utils.CheckError("JobFee error : ", jobFee)
 }
@@ -21,6 +21,7 @@ import (
 // 3. Invalid job configuration file handling
 // 4. Job fee validation errors
 // 5. Transaction submission failures
+// 6. Dry runs of a template, which must not connect or submit
 // Each test verifies proper error handling and state management.
 func TestExecuteCreateJob(t *testing.T) {
 	var flagSet *pflag.FlagSet
@@ -28,7 +29,7 @@ func TestExecuteCreateJob(t *testing.T) {
 	// Sample config path and job fee for tests
 	configPath := "/path/to/config.json"
 	jobFeeStr := "1000000000000000000" // 1 ETH in wei
-	mockConfigContent := []byte(`{"name": "test job", "description": "test description"}`)
+	mockConfigContent := []byte(testJobSpec(1))
 
 	tests := []struct {
 		name          string
@@ -42,6 +43,9 @@ func TestExecuteCreateJob(t *testing.T) {
 				flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
 				flagSet.String("config", "", "")
 				flagSet.String("jobFee", "", "")
+				flagSet.String("template", "", "")
+				flagSet.StringArray("set", nil, "")
+				flagSet.Bool("dry-run", false, "")
 				flagSet.Set("config", configPath)
 				flagSet.Set("jobFee", jobFeeStr)
 				flagSetMock.On("GetString", "config", flagSet).Return(configPath, nil)
@@ -56,13 +60,9 @@ func TestExecuteCreateJob(t *testing.T) {
 				utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
 				utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return("password")
 
-				// Mock file read operation
-				mockConfigContent := []byte(`{"name": "test job", "description": "test description"}`)
-
 				// Mock file read operation - Need to use OSUtils mock
 				osMock := new(mocks.OSInterface)
 				osUtils = osMock // Set the global variable
-				// mockConfigContent := []byte(`{"name": "test job", "description": "test description"}`)
 				osMock.On("ReadFile", configPath).Return(mockConfigContent, nil)
 
 				cmdMock.On("CreateJob",
@@ -120,8 +120,11 @@ func TestExecuteCreateJob(t *testing.T) {
 
 				// Create and set up flagset with invalid job fee
 				flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
-				flagSet.String("config", "", "")  // Add config flag
-				flagSet.String("jobFee", "", "")  // Add jobFee flag
+				flagSet.String("config", "", "") // Add config flag
+				flagSet.String("jobFee", "", "") // Add jobFee flag
+				flagSet.String("template", "", "")
+				flagSet.StringArray("set", nil, "")
+				flagSet.Bool("dry-run", false, "")
 				flagSet.Set("config", configPath) // Set config path
 				flagSet.Set("jobFee", "invalid")  // Set invalid job fee value
 				// Mock GetString responses from flagSet
@@ -151,6 +154,38 @@ func TestExecuteCreateJob(t *testing.T) {
 			},
 			expectedFatal: true,
 		},
+		{
+			name: "fails before sending a transaction when the job spec is malformed",
+			setupMocks: func(utilsMock *mocks.UtilsInterface, flagSetMock *mocks.FlagSetInterface, cmdMock *mocks.UtilsCmdInterface) {
+				config := types.Configurations{Provider: "test-provider"}
+				cmdMock.On("GetConfigData").Return(config, nil)
+				utilsMock.On("ConnectToEthClient", mock.AnythingOfType("string")).Return(client)
+				flagSetMock.On("GetStringAddress", mock.AnythingOfType("*pflag.FlagSet")).
+					Return("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771", nil)
+				utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
+				utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return("password")
+
+				flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
+				flagSet.String("config", configPath, "")
+				flagSet.String("jobFee", jobFeeStr, "")
+				flagSet.String("template", "", "")
+				flagSet.StringArray("set", nil, "")
+				flagSet.Bool("dry-run", false, "")
+
+				osMock := new(mocks.OSInterface)
+				osUtils = osMock
+				osMock.On("ReadFile", configPath).Return([]byte(`{"name": "test job", "description": "test description"}`), nil)
+
+				cmdMock.On("CreateJob",
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+					mock.Anything,
+				).Return(common.Hash{}, nil)
+			},
+			expectedFatal: true,
+		},
 		{
 			name: "fails when config file doesn't exist",
 			setupMocks: func(utilsMock *mocks.UtilsInterface, flagSetMock *mocks.FlagSetInterface, cmdMock *mocks.UtilsCmdInterface) {
@@ -166,6 +201,9 @@ func TestExecuteCreateJob(t *testing.T) {
 				flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
 				flagSet.String("config", "/nonexistent/path.json", "")
 				flagSet.String("jobFee", jobFeeStr, "")
+				flagSet.String("template", "", "")
+				flagSet.StringArray("set", nil, "")
+				flagSet.Bool("dry-run", false, "")
 
 				// Mock os operations with error for nonexistent file
 				osMock := new(mocks.OSInterface)
@@ -182,6 +220,24 @@ func TestExecuteCreateJob(t *testing.T) {
 			},
 			expectedFatal: true,
 		},
+		{
+			name: "prints the merged template on dry run without submitting",
+			setupMocks: func(utilsMock *mocks.UtilsInterface, flagSetMock *mocks.FlagSetInterface, cmdMock *mocks.UtilsCmdInterface) {
+				cmdMock.On("GetConfigData").Return(types.Configurations{Provider: "test-provider"}, nil)
+
+				flagSet = pflag.NewFlagSet("test", pflag.ContinueOnError)
+				flagSet.String("config", "", "")
+				flagSet.String("jobFee", jobFeeStr, "")
+				flagSet.String("template", "llama3.1-8b-lora", "")
+				flagSet.StringArray("set", nil, "")
+				flagSet.Bool("dry-run", true, "")
+				flagSet.Set("set", "dataset_id=gs://bucket/train.jsonl")
+				flagSet.Set("set", "num_epochs=2")
+
+				setupTemplatesDir(t, nil)
+			},
+			expectedFatal: false,
+		},
 	}
 
 	defer func() { log.ExitFunc = nil }()
@@ -350,3 +406,83 @@ func TestCreateJob(t *testing.T) {
 		})
 	}
 }
+
+// Tests building the job spec to submit covering:
+// 1. A config file without overrides submitted as written
+// 2. A config file or a template with --set overrides applied
+// 3. Missing or conflicting sources and specs left invalid by the overrides
+func TestBuildJobSpec(t *testing.T) {
+	configPath := "/path/to/config.json"
+	config := testJobSpec(1)
+
+	tests := []struct {
+		name          string
+		configPath    string
+		templateName  string
+		overrides     []string
+		want          string
+		expectedError string
+	}{
+		{
+			name:       "config file as written",
+			configPath: configPath,
+			want:       config,
+		},
+		{
+			name:       "config file with overrides",
+			configPath: configPath,
+			overrides:  []string{"num_epochs=3", "use_lora=true"},
+			want: `{"schema_version": 1, "job_config_name": "test", "dataset_id": "gs://bucket/dataset", "batch_size": 8,
+				"num_epochs": 3, "num_gpus": 1, "use_lora": true}`,
+		},
+		{
+			name:         "template with overrides",
+			templateName: "llama3.1-8b-lora",
+			overrides:    []string{"dataset_id=gs://bucket/train.jsonl", "lr=1e-4", "num_epochs=2"},
+			want: `{"schema_version": 1, "job_config_name": "llm_llama3_1_8b", "dataset_id": "gs://bucket/train.jsonl",
+				"batch_size": 2, "shuffle": true, "num_epochs": 2, "use_lora": true, "use_qlora": false, "lr": 1e-4,
+				"seed": 42, "num_gpus": 1, "min_gpu_memory": "24 GiB"}`,
+		},
+		{
+			name:          "template missing a required field",
+			templateName:  "llama3.1-8b-lora",
+			expectedError: "job spec is missing dataset_id",
+		},
+		{
+			name:          "override leaves an invalid value",
+			configPath:    configPath,
+			overrides:     []string{"batch_size=0"},
+			expectedError: "batch_size must be positive, got 0",
+		},
+		{
+			name:          "no source",
+			expectedError: "exactly one of --config and --template is required",
+		},
+		{
+			name:          "both sources",
+			configPath:    configPath,
+			templateName:  "llm_dummy",
+			expectedError: "exactly one of --config and --template is required",
+		},
+	}
+
+	originalOSUtils := osUtils
+	defer func() { osUtils = originalOSUtils }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupTemplatesDir(t, nil)
+			osMock := new(mocks.OSInterface)
+			osUtils = osMock
+			osMock.On("ReadFile", configPath).Return([]byte(config), nil)
+
+			got, err := buildJobSpec(tt.configPath, tt.templateName, tt.overrides)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
@@ -0,0 +1,779 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	"math/big"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/sirupsen/logrus"
+)
+
+// Key layout of the event index. An event is stored once under its position, the block
+// number and log index, and referenced from the job, staker and epoch indexes by the
+// position appended to the key.
+var (
+	indexCursorKey        = []byte("cursor")
+	indexBlockHashPrefix  = []byte("h") // h<block> -> hash of a recently indexed block
+	indexEventPrefix      = []byte("e") // e<position> -> event
+	indexJobPrefix        = []byte("j") // j<job ID><position>
+	indexStakerAddrPrefix = []byte("a") // a<address><position>
+	indexStakerIDPrefix   = []byte("s") // s<staker ID><position>
+	indexEpochPrefix      = []byte("p") // p<epoch><position>
+	indexStakerPrefix     = []byte("n") // n<staker ID> -> staker address from NewStaker
+)
+
+// indexPositionLength is the length of an event position: the block number and the log index
+const indexPositionLength = 12
+
+// eventIndex is the embedded store of the contract events read by the indexer
+type eventIndex struct {
+	db ethdb.KeyValueStore
+}
+
+// indexedBlock is the hash of an indexed block, kept to detect reorgs
+type indexedBlock struct {
+	number uint64
+	hash   common.Hash
+}
+
+// openEventIndex opens the event index in the Lumino directory, creating it on first use.
+// The store is locked while open, so it is closed as soon as a command is done with it.
+// Returns error if the store cannot be opened.
+func openEventIndex() (*eventIndex, error) {
+	indexerDirPath, err := path.PathUtilsInterface.GetIndexerDirPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexer directory: %w", err)
+	}
+	db, err := leveldb.New(indexerDirPath, 16, 16, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event index: %w", err)
+	}
+	return &eventIndex{db: db}, nil
+}
+
+// Close closes the store
+func (idx *eventIndex) Close() error {
+	return idx.db.Close()
+}
+
+// Cursor returns the last indexed block, or false before the first sync.
+// Returns error if the cursor cannot be read.
+func (idx *eventIndex) Cursor() (types.IndexerCursor, bool, error) {
+	var cursor types.IndexerCursor
+	found, err := idx.db.Has(indexCursorKey)
+	if err != nil || !found {
+		return cursor, false, err
+	}
+	data, err := idx.db.Get(indexCursorKey)
+	if err != nil {
+		return cursor, false, fmt.Errorf("failed to read indexer cursor: %w", err)
+	}
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, false, fmt.Errorf("failed to parse indexer cursor: %w", err)
+	}
+	return cursor, true, nil
+}
+
+// Reset deletes every indexed event and the cursor.
+// Returns error if the store cannot be cleared.
+func (idx *eventIndex) Reset() error {
+	it := idx.db.NewIterator(nil, nil)
+	defer it.Release()
+	batch := idx.db.NewBatch()
+	for it.Next() {
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("failed to read event index: %w", err)
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to reset event index: %w", err)
+	}
+	return nil
+}
+
+// Apply stores the events read up to the cursor block and moves the cursor in a single
+// batch, so a sync interrupted at any point resumes from a consistent cursor. This function:
+// 1. Fills in the assignee of JobStatusUpdated events and the address of staker events
+// from the JobAssigned and NewStaker events indexed before them
+// 2. Writes every event with its job, staker and epoch index entries
+// 3. Records the hashes of the event blocks and the cursor block, keeping the newest IndexerReorgDepth
+// Returns error if the batch cannot be written.
+func (idx *eventIndex) Apply(events []types.IndexedEvent, cursor types.IndexerCursor) error {
+	batch := idx.db.NewBatch()
+	assignees := make(map[string]string)
+	stakers := make(map[uint32]string)
+	blockHashes := map[uint64]common.Hash{cursor.BlockNumber: cursor.BlockHash}
+
+	for _, event := range events {
+		switch event.Kind {
+		case types.IndexedJobAssigned:
+			assignees[event.JobID.String()] = event.Assignee
+		case types.IndexedJobStatusUpdated:
+			assignee, ok := assignees[event.JobID.String()]
+			if !ok {
+				assignee = idx.latestAssignee(event.JobID)
+			}
+			event.Assignee = assignee
+		case types.IndexedNewStaker:
+			stakers[event.StakerID] = event.Staker
+			if err := batch.Put(stakerKey(event.StakerID), []byte(event.Staker)); err != nil {
+				return err
+			}
+		case types.IndexedStakeUpdated, types.IndexedStakerSlashed:
+			staker, ok := stakers[event.StakerID]
+			if !ok {
+				staker = idx.stakerAddress(event.StakerID)
+			}
+			event.Staker = staker
+		}
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+		position := eventPosition(event.BlockNumber, event.LogIndex)
+		if err := batch.Put(concatKey(indexEventPrefix, position), data); err != nil {
+			return err
+		}
+		for _, key := range eventIndexKeys(event, position) {
+			if err := batch.Put(key, nil); err != nil {
+				return err
+			}
+		}
+		blockHashes[event.BlockNumber] = event.BlockHash
+	}
+
+	for number, hash := range blockHashes {
+		if err := batch.Put(blockHashKey(number), hash.Bytes()); err != nil {
+			return err
+		}
+	}
+	cursor.NumEvents += uint64(len(events))
+	cursor.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return fmt.Errorf("failed to marshal indexer cursor: %w", err)
+	}
+	if err := batch.Put(indexCursorKey, data); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to write events: %w", err)
+	}
+	return idx.pruneBlockHashes()
+}
+
+// Rewind drops the events indexed after the given block, which is still part of the
+// canonical chain, and moves the cursor back to it.
+// Returns error if the store cannot be updated.
+func (idx *eventIndex) Rewind(cursor types.IndexerCursor, block indexedBlock) (types.IndexerCursor, error) {
+	it := idx.db.NewIterator(indexEventPrefix, eventPosition(block.number+1, 0))
+	defer it.Release()
+
+	batch := idx.db.NewBatch()
+	var dropped uint64
+	for it.Next() {
+		var event types.IndexedEvent
+		if err := json.Unmarshal(it.Value(), &event); err != nil {
+			return cursor, fmt.Errorf("failed to parse indexed event: %w", err)
+		}
+		position := common.CopyBytes(it.Key()[len(indexEventPrefix):])
+		keys := append(eventIndexKeys(event, position), common.CopyBytes(it.Key()))
+		if event.Kind == types.IndexedNewStaker {
+			keys = append(keys, stakerKey(event.StakerID))
+		}
+		for _, key := range keys {
+			if err := batch.Delete(key); err != nil {
+				return cursor, err
+			}
+		}
+		dropped++
+	}
+	if err := it.Error(); err != nil {
+		return cursor, fmt.Errorf("failed to read indexed events: %w", err)
+	}
+
+	hashes, err := idx.RecentBlocks()
+	if err != nil {
+		return cursor, err
+	}
+	for _, recent := range hashes {
+		if recent.number > block.number {
+			if err := batch.Delete(blockHashKey(recent.number)); err != nil {
+				return cursor, err
+			}
+		}
+	}
+
+	cursor.BlockNumber = block.number
+	cursor.BlockHash = block.hash
+	cursor.NumEvents -= min(cursor.NumEvents, dropped)
+	cursor.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return cursor, fmt.Errorf("failed to marshal indexer cursor: %w", err)
+	}
+	if err := batch.Put(indexCursorKey, data); err != nil {
+		return cursor, err
+	}
+	if err := batch.Write(); err != nil {
+		return cursor, fmt.Errorf("failed to drop reorged events: %w", err)
+	}
+	log.WithFields(logrus.Fields{
+		"block":   block.number,
+		"dropped": dropped,
+	}).Warn("Rewound event index after a reorg")
+	return cursor, nil
+}
+
+// RecentBlocks returns the recorded block hashes, newest first.
+// Returns error if they cannot be read.
+func (idx *eventIndex) RecentBlocks() ([]indexedBlock, error) {
+	it := idx.db.NewIterator(indexBlockHashPrefix, nil)
+	defer it.Release()
+	var blocks []indexedBlock
+	for it.Next() {
+		key := it.Key()[len(indexBlockHashPrefix):]
+		blocks = append(blocks, indexedBlock{
+			number: binary.BigEndian.Uint64(key),
+			hash:   common.BytesToHash(it.Value()),
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read indexed block hashes: %w", err)
+	}
+	slices.Reverse(blocks)
+	return blocks, nil
+}
+
+// JobEvents returns the events of a job in log order
+func (idx *eventIndex) JobEvents(jobId *big.Int, filter types.IndexedEventFilter) ([]types.IndexedEvent, error) {
+	return idx.lookup(filter, concatKey(indexJobPrefix, common.BigToHash(jobId).Bytes()))
+}
+
+// StakerEvents returns the events of a staker given by ID or address in log order: its
+// staking events, the jobs assigned to it with their status updates, and the blocks it proposed
+func (idx *eventIndex) StakerEvents(stakerId uint32, address common.Address, filter types.IndexedEventFilter) ([]types.IndexedEvent, error) {
+	var prefixes [][]byte
+	if stakerId != 0 {
+		prefixes = append(prefixes, concatKey(indexStakerIDPrefix, uint32Bytes(stakerId)))
+	}
+	if address != (common.Address{}) {
+		prefixes = append(prefixes, concatKey(indexStakerAddrPrefix, address.Bytes()))
+	}
+	return idx.lookup(filter, prefixes...)
+}
+
+// EpochEvents returns the events of the epochs from fromEpoch to toEpoch inclusive in log order
+func (idx *eventIndex) EpochEvents(fromEpoch uint32, toEpoch uint32, filter types.IndexedEventFilter) ([]types.IndexedEvent, error) {
+	it := idx.db.NewIterator(indexEpochPrefix, uint32Bytes(fromEpoch))
+	defer it.Release()
+	var positions [][]byte
+	for it.Next() {
+		key := it.Key()[len(indexEpochPrefix):]
+		if len(key) != 4+indexPositionLength {
+			continue
+		}
+		if binary.BigEndian.Uint32(key) > toEpoch {
+			break
+		}
+		positions = append(positions, common.CopyBytes(key[4:]))
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read event index: %w", err)
+	}
+	return idx.readEvents(positions, filter)
+}
+
+// ResolveStaker completes a staker given by ID or address with the other half from its
+// indexed NewStaker event. The half not found is left empty.
+func (idx *eventIndex) ResolveStaker(stakerId uint32, address common.Address) (uint32, common.Address) {
+	if stakerId != 0 && address == (common.Address{}) {
+		if staker := idx.stakerAddress(stakerId); staker != "" {
+			address = common.HexToAddress(staker)
+		}
+		return stakerId, address
+	}
+	if stakerId == 0 && address != (common.Address{}) {
+		events, err := idx.lookup(types.IndexedEventFilter{Kinds: []types.IndexedEventKind{types.IndexedNewStaker}},
+			concatKey(indexStakerAddrPrefix, address.Bytes()))
+		if err == nil && len(events) > 0 {
+			stakerId = events[len(events)-1].StakerID
+		}
+	}
+	return stakerId, address
+}
+
+// lookup returns the events referenced from the index entries under the prefixes that
+// match the filter, without duplicates and in log order.
+// Returns error if an entry or event cannot be read.
+func (idx *eventIndex) lookup(filter types.IndexedEventFilter, prefixes ...[]byte) ([]types.IndexedEvent, error) {
+	seen := make(map[string]bool)
+	var positions [][]byte
+	for _, prefix := range prefixes {
+		it := idx.db.NewIterator(prefix, nil)
+		for it.Next() {
+			key := it.Key()
+			if len(key) != len(prefix)+indexPositionLength {
+				continue
+			}
+			position := common.CopyBytes(key[len(prefix):])
+			if !seen[string(position)] {
+				seen[string(position)] = true
+				positions = append(positions, position)
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event index: %w", err)
+		}
+	}
+	return idx.readEvents(positions, filter)
+}
+
+// readEvents returns the events at the positions that match the filter, in log order.
+// Returns error if an event cannot be read.
+func (idx *eventIndex) readEvents(positions [][]byte, filter types.IndexedEventFilter) ([]types.IndexedEvent, error) {
+	sort.Slice(positions, func(i, j int) bool { return bytes.Compare(positions[i], positions[j]) < 0 })
+
+	events := make([]types.IndexedEvent, 0, len(positions))
+	for _, position := range positions {
+		data, err := idx.db.Get(concatKey(indexEventPrefix, position))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read indexed event: %w", err)
+		}
+		var event types.IndexedEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse indexed event: %w", err)
+		}
+		if matchesIndexedEventFilter(event, filter) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// latestAssignee returns the address the job was last assigned to, or "" if no assignment was indexed
+func (idx *eventIndex) latestAssignee(jobId *big.Int) string {
+	events, err := idx.JobEvents(jobId, types.IndexedEventFilter{Kinds: []types.IndexedEventKind{types.IndexedJobAssigned}})
+	if err != nil || len(events) == 0 {
+		return ""
+	}
+	return events[len(events)-1].Assignee
+}
+
+// stakerAddress returns the address of the staker from its indexed NewStaker event, or ""
+func (idx *eventIndex) stakerAddress(stakerId uint32) string {
+	data, err := idx.db.Get(stakerKey(stakerId))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// pruneBlockHashes keeps the newest IndexerReorgDepth block hashes
+func (idx *eventIndex) pruneBlockHashes() error {
+	blocks, err := idx.RecentBlocks()
+	if err != nil {
+		return err
+	}
+	if len(blocks) <= core.IndexerReorgDepth {
+		return nil
+	}
+	batch := idx.db.NewBatch()
+	for _, block := range blocks[core.IndexerReorgDepth:] {
+		if err := batch.Delete(blockHashKey(block.number)); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to prune indexed block hashes: %w", err)
+	}
+	return nil
+}
+
+// matchesIndexedEventFilter reports whether the event has one of the kinds and lies in the epoch range of the filter
+func matchesIndexedEventFilter(event types.IndexedEvent, filter types.IndexedEventFilter) bool {
+	if len(filter.Kinds) > 0 && !slices.Contains(filter.Kinds, event.Kind) {
+		return false
+	}
+	if event.Epoch < filter.FromEpoch {
+		return false
+	}
+	return filter.ToEpoch == 0 || event.Epoch <= filter.ToEpoch
+}
+
+// eventIndexKeys returns the job, staker and epoch index entries of an event
+func eventIndexKeys(event types.IndexedEvent, position []byte) [][]byte {
+	keys := [][]byte{concatKey(indexEpochPrefix, uint32Bytes(event.Epoch), position)}
+	if event.JobID != nil {
+		keys = append(keys, concatKey(indexJobPrefix, common.BigToHash(event.JobID).Bytes(), position))
+	}
+	if event.StakerID != 0 {
+		keys = append(keys, concatKey(indexStakerIDPrefix, uint32Bytes(event.StakerID), position))
+	}
+	addresses := make(map[common.Address]bool)
+	for _, address := range []string{event.Assignee, event.Staker, event.Proposer} {
+		if address != "" {
+			addresses[common.HexToAddress(address)] = true
+		}
+	}
+	for address := range addresses {
+		keys = append(keys, concatKey(indexStakerAddrPrefix, address.Bytes(), position))
+	}
+	return keys
+}
+
+// eventPosition encodes the position of an event so that positions sort in log order
+func eventPosition(blockNumber uint64, logIndex uint) []byte {
+	position := make([]byte, indexPositionLength)
+	binary.BigEndian.PutUint64(position, blockNumber)
+	binary.BigEndian.PutUint32(position[8:], uint32(logIndex))
+	return position
+}
+
+func blockHashKey(blockNumber uint64) []byte {
+	return concatKey(indexBlockHashPrefix, binary.BigEndian.AppendUint64(nil, blockNumber))
+}
+
+func stakerKey(stakerId uint32) []byte {
+	return concatKey(indexStakerPrefix, uint32Bytes(stakerId))
+}
+
+func uint32Bytes(value uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, value)
+}
+
+// concatKey joins the parts of a store key into a new slice
+func concatKey(parts ...[]byte) []byte {
+	var key []byte
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+	return key
+}
+
+// syncEventIndex indexes the contract events up to the latest block. This function:
+// 1. Resumes after the cursor, or starts at startBlock on the first sync
+// 2. Checks the cursor block is still canonical and rewinds past a reorg
+// 3. Reads the events in ranges of EventBlockRange blocks, storing each range with the
+// cursor moved to its last block
+// Returns the cursor reached, and error if the chain cannot be read or the store updated.
+func syncEventIndex(client *ethclient.Client, idx *eventIndex, startBlock uint64) (types.IndexerCursor, error) {
+	cursor, found, err := idx.Cursor()
+	if err != nil {
+		return cursor, err
+	}
+	latest, err := jobEventUtils.GetLatestBlockNumber(client)
+	if err != nil {
+		return cursor, err
+	}
+
+	from := startBlock
+	if found {
+		cursor, err = checkIndexerReorg(client, idx, cursor, latest)
+		if err != nil {
+			return cursor, err
+		}
+		from = cursor.BlockNumber + 1
+	} else {
+		cursor.StartBlock = startBlock
+	}
+
+	for ; from <= latest; from += core.EventBlockRange {
+		to := min(from+core.EventBlockRange-1, latest)
+		events, err := eventIndexUtils.FilterIndexedEvents(client, from, to)
+		if err != nil {
+			return cursor, err
+		}
+		hash, err := eventIndexUtils.GetBlockHash(client, to)
+		if err != nil {
+			return cursor, err
+		}
+		next := cursor
+		next.BlockNumber = to
+		next.BlockHash = hash
+		if err := idx.Apply(events, next); err != nil {
+			return cursor, err
+		}
+		cursor, _, err = idx.Cursor()
+		if err != nil {
+			return cursor, err
+		}
+		log.WithFields(logrus.Fields{
+			"fromBlock": from,
+			"toBlock":   to,
+			"events":    len(events),
+		}).Debug("Indexed blocks")
+	}
+	return cursor, nil
+}
+
+// checkIndexerReorg returns the cursor unchanged while its block is canonical. Otherwise the
+// newest recorded block still on the chain is looked up and the index is rewound to it.
+// Returns error if the fork is older than every recorded block or the chain cannot be read.
+func checkIndexerReorg(client *ethclient.Client, idx *eventIndex, cursor types.IndexerCursor, latest uint64) (types.IndexerCursor, error) {
+	if cursor.BlockNumber <= latest {
+		hash, err := eventIndexUtils.GetBlockHash(client, cursor.BlockNumber)
+		if err != nil {
+			return cursor, err
+		}
+		if hash == cursor.BlockHash {
+			return cursor, nil
+		}
+	}
+	log.WithField("block", cursor.BlockNumber).Warn("Indexed block is no longer canonical, looking for the fork")
+
+	blocks, err := idx.RecentBlocks()
+	if err != nil {
+		return cursor, err
+	}
+	for _, block := range blocks {
+		if block.number > latest {
+			continue
+		}
+		hash, err := eventIndexUtils.GetBlockHash(client, block.number)
+		if err != nil {
+			return cursor, err
+		}
+		if hash == block.hash {
+			return idx.Rewind(cursor, block)
+		}
+	}
+	return cursor, errors.New("reorg is older than every recorded block, rebuild the index with indexer sync --reset")
+}
+
+// GetEpochStartBlock returns the number of the first block of the epoch. Epochs start
+// every EpochLength seconds of block time, so the block is found by a binary search over
+// block timestamps. Blocks are at least a second apart, which bounds the search to the
+// blocks mined since the epoch started.
+// Returns error if a block header cannot be read.
+func (EventIndexUtils) GetEpochStartBlock(client *ethclient.Client, epoch uint32) (uint64, error) {
+	latest, err := utils.ClientInterface.HeaderByNumber(client, context.Background(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if latest == nil || latest.Number == nil {
+		return 0, errors.New("latest block header is empty")
+	}
+	epochStart := uint64(epoch) * uint64(core.EpochLength)
+	if latest.Time < epochStart {
+		return 0, fmt.Errorf("epoch %d has not started yet", epoch)
+	}
+
+	hi := latest.Number.Uint64()
+	lo := hi - min(hi, latest.Time-epochStart)
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		header, err := utils.ClientInterface.HeaderByNumber(client, context.Background(), new(big.Int).SetUint64(mid))
+		if err != nil {
+			return 0, fmt.Errorf("failed to get block %d: %w", mid, err)
+		}
+		if header.Time >= epochStart {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo, nil
+}
+
+// GetBlockHash returns the hash of the block with the given number.
+// Returns error if the block header cannot be read.
+func (EventIndexUtils) GetBlockHash(client *ethclient.Client, blockNumber uint64) (common.Hash, error) {
+	header, err := utils.ClientInterface.HeaderByNumber(client, context.Background(), new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get block %d: %w", blockNumber, err)
+	}
+	if header == nil {
+		return common.Hash{}, fmt.Errorf("block %d header is empty", blockNumber)
+	}
+	return header.Hash(), nil
+}
+
+// FilterIndexedEvents returns the events stored by the indexer that the JobManager, the StakeManager
+// and, if configured, the BlockManager emitted between fromBlock and toBlock inclusive, in log order.
+// This function:
+// 1. Fetches the logs of the three contracts in a single eth_getLogs call
+// 2. Decodes every log with the bindings of its contract
+// 3. Reads the header of every block with events for its time, from which the epoch of
+// events not carrying one is derived
+// Returns error if the logs cannot be fetched or decoded, or a block changed while being read.
+func (EventIndexUtils) FilterIndexedEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.IndexedEvent, error) {
+	jobManagerABI, err := bindings.JobManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JobManager ABI: %w", err)
+	}
+	stakeManagerABI, err := bindings.StakeManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse StakeManager ABI: %w", err)
+	}
+	blockManagerABI, err := bindings.BlockManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BlockManager ABI: %w", err)
+	}
+	jobCreatedID := jobManagerABI.Events[string(types.IndexedJobCreated)].ID
+	jobAssignedID := jobManagerABI.Events[string(types.IndexedJobAssigned)].ID
+	jobStatusUpdatedID := jobManagerABI.Events[string(types.IndexedJobStatusUpdated)].ID
+	newStakerID := stakeManagerABI.Events[string(types.IndexedNewStaker)].ID
+	stakeUpdatedID := stakeManagerABI.Events[string(types.IndexedStakeUpdated)].ID
+	stakerSlashedID := stakeManagerABI.Events[string(types.IndexedStakerSlashed)].ID
+	blockProposedID := blockManagerABI.Events[string(types.IndexedBlockProposed)].ID
+	blockConfirmedID := blockManagerABI.Events[string(types.IndexedBlockConfirmed)].ID
+
+	jobManagerAddress := common.HexToAddress(core.JobManagerAddress)
+	stakeManagerAddress := common.HexToAddress(core.StakeManagerAddress)
+	addresses := []common.Address{jobManagerAddress, stakeManagerAddress}
+	topics := []common.Hash{jobCreatedID, jobAssignedID, jobStatusUpdatedID, newStakerID, stakeUpdatedID, stakerSlashedID}
+	if core.BlockManagerAddress != "" {
+		addresses = append(addresses, common.HexToAddress(core.BlockManagerAddress))
+		topics = append(topics, blockProposedID, blockConfirmedID)
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: addresses,
+		Topics:    [][]common.Hash{topics},
+	}
+	logs, err := utils.ClientInterface.FilterLogs(client, context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter contract logs: %w", err)
+	}
+
+	jobFilterer, err := bindings.NewJobManagerFilterer(jobManagerAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JobManager filterer: %w", err)
+	}
+	stakeFilterer, err := bindings.NewStakeManagerFilterer(stakeManagerAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create StakeManager filterer: %w", err)
+	}
+	blockFilterer, err := bindings.NewBlockManagerFilterer(common.HexToAddress(core.BlockManagerAddress), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BlockManager filterer: %w", err)
+	}
+
+	headers := make(map[uint64]*Types.Header)
+	events := make([]types.IndexedEvent, 0, len(logs))
+	for _, vLog := range logs {
+		if vLog.Removed || len(vLog.Topics) == 0 {
+			continue
+		}
+		header, ok := headers[vLog.BlockNumber]
+		if !ok {
+			header, err = utils.ClientInterface.HeaderByNumber(client, context.Background(), new(big.Int).SetUint64(vLog.BlockNumber))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get block %d: %w", vLog.BlockNumber, err)
+			}
+			headers[vLog.BlockNumber] = header
+		}
+		if header.Hash() != vLog.BlockHash {
+			return nil, fmt.Errorf("block %d changed while being indexed", vLog.BlockNumber)
+		}
+
+		event := types.IndexedEvent{
+			BlockNumber: vLog.BlockNumber,
+			BlockHash:   vLog.BlockHash,
+			BlockTime:   time.Unix(int64(header.Time), 0).UTC(),
+			TxHash:      vLog.TxHash,
+			LogIndex:    vLog.Index,
+			Epoch:       uint32(int64(header.Time) / core.EpochLength),
+		}
+		switch vLog.Topics[0] {
+		case jobCreatedID:
+			created, err := jobFilterer.ParseJobCreated(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JobCreated: %w", err)
+			}
+			event.Kind = types.IndexedJobCreated
+			event.JobID = created.JobId
+			event.Creator = created.Creator.Hex()
+			event.Epoch = created.Epoch
+		case jobAssignedID:
+			assigned, err := jobFilterer.ParseJobAssigned(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JobAssigned: %w", err)
+			}
+			event.Kind = types.IndexedJobAssigned
+			event.JobID = assigned.JobId
+			event.Assignee = assigned.AssigneeAddress.Hex()
+		case jobStatusUpdatedID:
+			updated, err := jobFilterer.ParseJobStatusUpdated(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JobStatusUpdated: %w", err)
+			}
+			event.Kind = types.IndexedJobStatusUpdated
+			event.JobID = updated.JobId
+			event.Status = types.JobStatus(updated.NewStatus).String()
+		case newStakerID:
+			staker, err := stakeFilterer.ParseNewStaker(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode NewStaker: %w", err)
+			}
+			event.Kind = types.IndexedNewStaker
+			event.StakerID = staker.StakerId
+			event.Staker = staker.StakerAddress.Hex()
+		case stakeUpdatedID:
+			updated, err := stakeFilterer.ParseStakeUpdated(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode StakeUpdated: %w", err)
+			}
+			event.Kind = types.IndexedStakeUpdated
+			event.StakerID = updated.StakerId
+			event.Amount = updated.NewStake
+		case stakerSlashedID:
+			slashed, err := stakeFilterer.ParseStakerSlashed(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode StakerSlashed: %w", err)
+			}
+			event.Kind = types.IndexedStakerSlashed
+			event.StakerID = slashed.StakerId
+			event.Amount = slashed.SlashedAmount
+		case blockProposedID:
+			proposed, err := blockFilterer.ParseBlockProposed(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode BlockProposed: %w", err)
+			}
+			event.Kind = types.IndexedBlockProposed
+			event.Epoch = proposed.Epoch
+			event.BlockID = &proposed.BlockId
+			event.Proposer = proposed.Proposer.Hex()
+		case blockConfirmedID:
+			confirmed, err := blockFilterer.ParseBlockConfirmed(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode BlockConfirmed: %w", err)
+			}
+			event.Kind = types.IndexedBlockConfirmed
+			event.Epoch = confirmed.Epoch
+			event.BlockID = &confirmed.BlockId
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
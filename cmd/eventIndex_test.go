@@ -0,0 +1,391 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	mocks2 "lumino/utils/mocks"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testIndexedEvents returns the events of staker 4 registering, taking job 7 created in
+// epoch 3 and completing it in epoch 5, with a second job 8 that stays unassigned
+func testIndexedEvents() []types.IndexedEvent {
+	staker := common.HexToAddress("0x2000000000000000000000000000000000000002").Hex()
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001").Hex()
+	return []types.IndexedEvent{
+		{Kind: types.IndexedNewStaker, BlockNumber: 10, Epoch: 3, StakerID: 4, Staker: staker},
+		{Kind: types.IndexedJobCreated, BlockNumber: 11, Epoch: 3, JobID: big.NewInt(7), Creator: creator},
+		{Kind: types.IndexedJobCreated, BlockNumber: 11, LogIndex: 1, Epoch: 3, JobID: big.NewInt(8), Creator: creator},
+		{Kind: types.IndexedJobAssigned, BlockNumber: 12, Epoch: 4, JobID: big.NewInt(7), Assignee: staker},
+		{Kind: types.IndexedStakeUpdated, BlockNumber: 12, LogIndex: 1, Epoch: 4, StakerID: 4, Amount: big.NewInt(500)},
+		{Kind: types.IndexedJobStatusUpdated, BlockNumber: 13, Epoch: 5, JobID: big.NewInt(7), Status: "Completed"},
+	}
+}
+
+// Tests querying the event index covering:
+// 1. Status updates take the assignee and stake events the address indexed before them,
+// within a batch and across batches
+// 2. Events of a job, of a staker by ID or address, and of a range of epochs in log order
+// 3. Filtering by kind and epoch
+// 4. Resolving a staker ID to its address and back
+func TestEventIndexQueries(t *testing.T) {
+	idx := &eventIndex{db: memorydb.New()}
+	staker := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	events := testIndexedEvents()
+
+	assert.NoError(t, idx.Apply(events[:4], types.IndexerCursor{BlockNumber: 12}))
+	assert.NoError(t, idx.Apply(events[4:], types.IndexerCursor{BlockNumber: 13, NumEvents: 4}))
+
+	cursor, found, err := idx.Cursor()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, uint64(13), cursor.BlockNumber)
+	assert.Equal(t, uint64(6), cursor.NumEvents)
+
+	jobEvents, err := idx.JobEvents(big.NewInt(7), types.IndexedEventFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.IndexedEventKind{types.IndexedJobCreated, types.IndexedJobAssigned, types.IndexedJobStatusUpdated}, indexedKinds(jobEvents))
+	assert.Equal(t, staker.Hex(), jobEvents[2].Assignee)
+
+	byId, err := idx.StakerEvents(4, common.Address{}, types.IndexedEventFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.IndexedEventKind{types.IndexedNewStaker, types.IndexedStakeUpdated}, indexedKinds(byId))
+	assert.Equal(t, staker.Hex(), byId[1].Staker)
+
+	stakerId, address := idx.ResolveStaker(4, common.Address{})
+	assert.Equal(t, staker, address)
+	byBoth, err := idx.StakerEvents(stakerId, address, types.IndexedEventFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.IndexedEventKind{types.IndexedNewStaker, types.IndexedJobAssigned, types.IndexedStakeUpdated, types.IndexedJobStatusUpdated}, indexedKinds(byBoth))
+
+	stakerId, _ = idx.ResolveStaker(0, staker)
+	assert.Equal(t, uint32(4), stakerId)
+	stakerId, address = idx.ResolveStaker(9, common.Address{})
+	assert.Equal(t, uint32(9), stakerId)
+	assert.Equal(t, common.Address{}, address)
+
+	filtered, err := idx.StakerEvents(4, staker, types.IndexedEventFilter{Kinds: []types.IndexedEventKind{types.IndexedJobAssigned, types.IndexedJobStatusUpdated}, ToEpoch: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.IndexedEventKind{types.IndexedJobAssigned}, indexedKinds(filtered))
+
+	epochEvents, err := idx.EpochEvents(3, 4, types.IndexedEventFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, epochEvents, 5)
+	assert.Equal(t, big.NewInt(8), epochEvents[2].JobID)
+
+	epochEvents, err = idx.EpochEvents(5, 9, types.IndexedEventFilter{Kinds: []types.IndexedEventKind{types.IndexedJobCreated}})
+	assert.NoError(t, err)
+	assert.Empty(t, epochEvents)
+
+	assert.NoError(t, idx.Reset())
+	_, found, err = idx.Cursor()
+	assert.NoError(t, err)
+	assert.False(t, found)
+	jobEvents, err = idx.JobEvents(big.NewInt(7), types.IndexedEventFilter{})
+	assert.NoError(t, err)
+	assert.Empty(t, jobEvents)
+}
+
+// Tests syncing the event index with cases:
+// 1. The first sync starts at the start block and reads the chain in ranges
+// 2. A later sync resumes after the cursor
+// 3. A reorg drops the events after the newest block still canonical and reindexes from it
+// 4. A reorg older than every recorded block fails
+// 5. Failing to read events keeps the ranges already indexed
+func TestSyncEventIndex(t *testing.T) {
+	var client *ethclient.Client
+	hash := func(block uint64) common.Hash { return common.BigToHash(new(big.Int).SetUint64(block)) }
+	canonical := func(_ *ethclient.Client, block uint64) common.Hash { return hash(block) }
+	forked := func(_ *ethclient.Client, block uint64) common.Hash { return hash(block + 1000) }
+	events := testIndexedEvents()
+	for i := range events {
+		events[i].BlockHash = hash(events[i].BlockNumber)
+	}
+
+	tests := []struct {
+		name          string
+		setupIndex    func(*eventIndex)
+		setupMocks    func(*mocks.JobEventInterface, *mocks.EventIndexInterface)
+		expectedBlock uint64
+		expectedStart uint64
+		expectedJobs  int
+		expectedError string
+	}{
+		{
+			name: "first sync in ranges",
+			setupMocks: func(eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(14), nil)
+				eventIndexMock.On("FilterIndexedEvents", mock.Anything, uint64(5), uint64(9)).Return([]types.IndexedEvent{}, nil)
+				eventIndexMock.On("FilterIndexedEvents", mock.Anything, uint64(10), uint64(14)).Return(events, nil)
+				eventIndexMock.On("GetBlockHash", mock.Anything, mock.Anything).Return(canonical, nil)
+			},
+			expectedBlock: 14,
+			expectedStart: 5,
+			expectedJobs:  3,
+		},
+		{
+			name: "resume after the cursor",
+			setupIndex: func(idx *eventIndex) {
+				assert.NoError(t, idx.Apply(events[:2], types.IndexerCursor{StartBlock: 1, BlockNumber: 11, BlockHash: hash(11)}))
+			},
+			setupMocks: func(eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(14), nil)
+				eventIndexMock.On("FilterIndexedEvents", mock.Anything, uint64(12), uint64(14)).Return(events[3:], nil)
+				eventIndexMock.On("GetBlockHash", mock.Anything, mock.Anything).Return(canonical, nil)
+			},
+			expectedBlock: 14,
+			expectedStart: 1,
+			expectedJobs:  3,
+		},
+		{
+			name: "reorg rewinds to the fork",
+			setupIndex: func(idx *eventIndex) {
+				assert.NoError(t, idx.Apply(events, types.IndexerCursor{StartBlock: 1, BlockNumber: 13, BlockHash: hash(13)}))
+			},
+			setupMocks: func(eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(13), nil)
+				eventIndexMock.On("GetBlockHash", mock.Anything, mock.Anything).Return(func(_ *ethclient.Client, block uint64) common.Hash {
+					if block > 11 {
+						return forked(nil, block)
+					}
+					return hash(block)
+				}, nil)
+				eventIndexMock.On("FilterIndexedEvents", mock.Anything, uint64(12), uint64(13)).Return([]types.IndexedEvent{}, nil)
+			},
+			expectedBlock: 13,
+			expectedStart: 1,
+			expectedJobs:  1,
+		},
+		{
+			name: "reorg older than every recorded block",
+			setupIndex: func(idx *eventIndex) {
+				assert.NoError(t, idx.Apply(events, types.IndexerCursor{StartBlock: 1, BlockNumber: 13, BlockHash: hash(13)}))
+			},
+			setupMocks: func(eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(13), nil)
+				eventIndexMock.On("GetBlockHash", mock.Anything, mock.Anything).Return(forked, nil)
+			},
+			expectedError: "reorg is older than every recorded block, rebuild the index with indexer sync --reset",
+		},
+		{
+			name: "events cannot be read",
+			setupMocks: func(eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(14), nil)
+				eventIndexMock.On("FilterIndexedEvents", mock.Anything, uint64(5), uint64(9)).Return([]types.IndexedEvent{}, nil)
+				eventIndexMock.On("FilterIndexedEvents", mock.Anything, uint64(10), uint64(14)).Return(nil, errors.New("rpc error"))
+				eventIndexMock.On("GetBlockHash", mock.Anything, mock.Anything).Return(canonical, nil)
+			},
+			expectedBlock: 9,
+			expectedStart: 5,
+			expectedError: "rpc error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventMock := new(mocks.JobEventInterface)
+			eventIndexMock := new(mocks.EventIndexInterface)
+			originalJobEventUtils := jobEventUtils
+			originalEventIndexUtils := eventIndexUtils
+			originalEventBlockRange := core.EventBlockRange
+			defer func() {
+				jobEventUtils = originalJobEventUtils
+				eventIndexUtils = originalEventIndexUtils
+				core.EventBlockRange = originalEventBlockRange
+			}()
+			jobEventUtils = eventMock
+			eventIndexUtils = eventIndexMock
+			core.EventBlockRange = 5
+
+			idx := &eventIndex{db: memorydb.New()}
+			if tt.setupIndex != nil {
+				tt.setupIndex(idx)
+			}
+			tt.setupMocks(eventMock, eventIndexMock)
+
+			_, err := syncEventIndex(client, idx, 5)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectedBlock == 0 {
+				return
+			}
+
+			cursor, found, err := idx.Cursor()
+			assert.NoError(t, err)
+			assert.True(t, found)
+			assert.Equal(t, tt.expectedBlock, cursor.BlockNumber)
+			assert.Equal(t, tt.expectedStart, cursor.StartBlock)
+			jobEvents, err := idx.JobEvents(big.NewInt(7), types.IndexedEventFilter{})
+			assert.NoError(t, err)
+			assert.Len(t, jobEvents, tt.expectedJobs)
+		})
+	}
+}
+
+func indexedKinds(events []types.IndexedEvent) []types.IndexedEventKind {
+	kinds := make([]types.IndexedEventKind, 0, len(events))
+	for _, event := range events {
+		kinds = append(kinds, event.Kind)
+	}
+	return kinds
+}
+
+// Tests finding the first block of an epoch from block timestamps with cases:
+// 1. A chain with a block every two seconds
+// 2. An epoch that starts with the latest block
+// 3. An epoch that has not started yet
+func TestGetEpochStartBlock(t *testing.T) {
+	var client *ethclient.Client
+
+	tests := []struct {
+		name      string
+		epoch     uint32
+		wantBlock uint64
+		wantErr   bool
+	}{
+		{name: "block every two seconds", epoch: 2, wantBlock: 10},
+		{name: "epoch starts with the latest block", epoch: 3, wantBlock: 20},
+		{name: "epoch not started", epoch: 4, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientMock := new(mocks2.ClientUtils)
+			originalClientInterface := utils.ClientInterface
+			originalEpochLength := core.EpochLength
+			defer func() {
+				utils.ClientInterface = originalClientInterface
+				core.EpochLength = originalEpochLength
+			}()
+			utils.ClientInterface = clientMock
+			core.EpochLength = 540
+
+			// Block n is mined at 1060 + 2n, epoch 2 starts at 1080 and epoch 3 at 1620
+			blockTime := func(n uint64) uint64 {
+				if n == 20 {
+					return 1620
+				}
+				return 1060 + 2*n
+			}
+			clientMock.On("HeaderByNumber", mock.Anything, mock.Anything, mock.Anything).Return(
+				func(_ *ethclient.Client, _ context.Context, number *big.Int) *Types.Header {
+					n := uint64(20)
+					if number != nil {
+						n = number.Uint64()
+					}
+					return &Types.Header{Number: new(big.Int).SetUint64(n), Time: blockTime(n)}
+				}, nil)
+
+			block, err := EventIndexUtils{}.GetEpochStartBlock(client, tt.epoch)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBlock, block)
+		})
+	}
+}
+
+// Tests decoding the logs stored by the indexer:
+// 1. JobManager and StakeManager events are decoded in log order with the time and epoch of their block
+// 2. JobCreated keeps the epoch it carries
+// 3. A log whose block no longer matches the header read fails the range
+func TestFilterIndexedEvents(t *testing.T) {
+	var client *ethclient.Client
+
+	jobManagerABI, err := bindings.JobManagerMetaData.GetAbi()
+	assert.NoError(t, err)
+	stakeManagerABI, err := bindings.StakeManagerMetaData.GetAbi()
+	assert.NoError(t, err)
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	staker := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	jobTopic := common.BigToHash(big.NewInt(7))
+	stakerTopic := common.BigToHash(big.NewInt(4))
+
+	createdData, err := jobManagerABI.Events["JobCreated"].Inputs.NonIndexed().Pack(uint32(3))
+	assert.NoError(t, err)
+	stakeData, err := stakeManagerABI.Events["StakeUpdated"].Inputs.NonIndexed().Pack(big.NewInt(500))
+	assert.NoError(t, err)
+
+	header10 := &Types.Header{Number: big.NewInt(10), Time: uint64(100 * core.EpochLength)}
+	header12 := &Types.Header{Number: big.NewInt(12), Time: uint64(101 * core.EpochLength)}
+	logs := []Types.Log{
+		{
+			Topics:      []common.Hash{jobManagerABI.Events["JobCreated"].ID, jobTopic, common.BytesToHash(creator.Bytes())},
+			Data:        createdData,
+			BlockNumber: 10,
+			BlockHash:   header10.Hash(),
+		},
+		{
+			Topics:      []common.Hash{stakeManagerABI.Events["NewStaker"].ID, stakerTopic, common.BytesToHash(staker.Bytes())},
+			BlockNumber: 12,
+			BlockHash:   header12.Hash(),
+			Index:       1,
+		},
+		{
+			Topics:      []common.Hash{stakeManagerABI.Events["StakeUpdated"].ID, stakerTopic},
+			Data:        stakeData,
+			BlockNumber: 12,
+			BlockHash:   header12.Hash(),
+			Index:       2,
+		},
+		{
+			Topics:      []common.Hash{stakeManagerABI.Events["StakeUpdated"].ID, stakerTopic},
+			Data:        stakeData,
+			BlockNumber: 12,
+			Index:       3,
+			Removed:     true,
+		},
+	}
+
+	clientMock := new(mocks2.ClientUtils)
+	originalClientInterface := utils.ClientInterface
+	originalBlockManagerAddress := core.BlockManagerAddress
+	defer func() {
+		utils.ClientInterface = originalClientInterface
+		core.BlockManagerAddress = originalBlockManagerAddress
+	}()
+	utils.ClientInterface = clientMock
+	core.BlockManagerAddress = ""
+
+	clientMock.On("FilterLogs", mock.Anything, mock.Anything, mock.MatchedBy(func(query ethereum.FilterQuery) bool {
+		return query.FromBlock.Uint64() == 10 && query.ToBlock.Uint64() == 12 &&
+			len(query.Addresses) == 2 && len(query.Topics[0]) == 6
+	})).Return(logs, nil)
+	clientMock.On("HeaderByNumber", mock.Anything, mock.Anything, big.NewInt(10)).Return(header10, nil)
+	clientMock.On("HeaderByNumber", mock.Anything, mock.Anything, big.NewInt(12)).Return(header12, nil).Once()
+
+	events, err := EventIndexUtils{}.FilterIndexedEvents(client, 10, 12)
+	assert.NoError(t, err)
+	blockTime10 := time.Unix(int64(header10.Time), 0).UTC()
+	blockTime12 := time.Unix(int64(header12.Time), 0).UTC()
+	assert.Equal(t, []types.IndexedEvent{
+		{Kind: types.IndexedJobCreated, BlockNumber: 10, BlockHash: header10.Hash(), BlockTime: blockTime10, Epoch: 3, JobID: big.NewInt(7), Creator: creator.Hex()},
+		{Kind: types.IndexedNewStaker, BlockNumber: 12, BlockHash: header12.Hash(), BlockTime: blockTime12, LogIndex: 1, Epoch: 101, StakerID: 4, Staker: staker.Hex()},
+		{Kind: types.IndexedStakeUpdated, BlockNumber: 12, BlockHash: header12.Hash(), BlockTime: blockTime12, LogIndex: 2, Epoch: 101, StakerID: 4, Amount: big.NewInt(500)},
+	}, events)
+
+	reorged := &Types.Header{Number: big.NewInt(12), Time: uint64(102 * core.EpochLength)}
+	clientMock.On("HeaderByNumber", mock.Anything, mock.Anything, big.NewInt(12)).Return(reorged, nil)
+	_, err = EventIndexUtils{}.FilterIndexedEvents(client, 10, 12)
+	assert.EqualError(t, err, "block 12 changed while being indexed")
+}
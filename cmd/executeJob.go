@@ -4,14 +4,18 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"lumino/cmd/systemspecs"
 	"lumino/core"
 	"lumino/core/types"
 	"lumino/logger"
+	"lumino/path"
+	pipeline_zen "lumino/pipeline-zen"
 	"lumino/pkg/bindings"
 	"lumino/utils"
 	"math/big"
-	"os"
-	"os/signal"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -25,8 +29,120 @@ import (
 var (
 	executionState types.JobExecutionState
 	stateMutex     sync.RWMutex
+	// jobProcesses holds the supervised pipeline of every job started by this daemon, guarded by stateMutex
+	jobProcesses = make(map[string]pipeline_zen.Execution)
+	// jobsAwaitingStart holds the assigned jobs HandleUpdateState left Queued, guarded by stateMutex
+	jobsAwaitingStart = make(map[string]bool)
+	// pendingResultPublishes holds the results deliveries of reported jobs, running or to be retried, guarded by stateMutex
+	pendingResultPublishes = make(map[string]*pendingResultPublish)
+	// jobRuntime runs the pipelines of jobs, selected by executeJob --runtime
+	jobRuntime pipeline_zen.Runtime = pipeline_zen.ProcessRuntime{}
 )
 
+// trackJob registers a job in the execution state
+func trackJob(job *types.JobExecution) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	if executionState.Jobs == nil {
+		executionState.Jobs = make(map[string]*types.JobExecution)
+	}
+	executionState.Jobs[job.JobID.String()] = job
+}
+
+// untrackJob removes a job from the execution state and frees its GPUs
+func untrackJob(jobId *big.Int) {
+	gpuAllocator.Release(jobId.String())
+	stateMutex.Lock()
+	delete(executionState.Jobs, jobId.String())
+	stateMutex.Unlock()
+}
+
+// getTrackedJob returns a copy of a tracked job, or nil if the job is not tracked
+func getTrackedJob(jobId *big.Int) *types.JobExecution {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	job, ok := executionState.Jobs[jobId.String()]
+	if !ok {
+		return nil
+	}
+	jobCopy := *job
+	return &jobCopy
+}
+
+// getTrackedJobs returns copies of all tracked jobs ordered by job ID
+func getTrackedJobs() []types.JobExecution {
+	stateMutex.RLock()
+	jobs := make([]types.JobExecution, 0, len(executionState.Jobs))
+	for _, job := range executionState.Jobs {
+		jobs = append(jobs, *job)
+	}
+	stateMutex.RUnlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].JobID.Cmp(jobs[j].JobID) < 0
+	})
+	return jobs
+}
+
+// setJobProcess records the supervised pipeline process of a job
+func setJobProcess(jobId *big.Int, process pipeline_zen.Execution) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	jobProcesses[jobId.String()] = process
+}
+
+// getJobProcess returns the supervised pipeline process of a job, or nil if none is running
+func getJobProcess(jobId *big.Int) pipeline_zen.Execution {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	return jobProcesses[jobId.String()]
+}
+
+// setJobAwaitingStart records whether an assigned job is left Queued, waiting for GPU slots,
+// for the node to pass its preflight checks or for its Running transaction to be resent
+func setJobAwaitingStart(jobId *big.Int, waiting bool) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	if waiting {
+		jobsAwaitingStart[jobId.String()] = true
+	} else {
+		delete(jobsAwaitingStart, jobId.String())
+	}
+}
+
+// hasJobsAwaitingStart reports whether an assigned job is left Queued or a job's Running
+// transaction is still in flight, so that HandleUpdateState has to run again
+func hasJobsAwaitingStart() bool {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	if len(jobsAwaitingStart) > 0 {
+		return true
+	}
+	for _, job := range executionState.Jobs {
+		if job.Status == types.JobStatusQueued {
+			return true
+		}
+	}
+	return false
+}
+
+// removeJobProcess forgets the pipeline process of a job once it has exited
+func removeJobProcess(jobId *big.Int) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	delete(jobProcesses, jobId.String())
+}
+
+// setJobStatus updates the local status of a tracked job
+func setJobStatus(jobId *big.Int, status types.JobStatus) {
+	stateMutex.Lock()
+	defer stateMutex.Unlock()
+	if job, ok := executionState.Jobs[jobId.String()]; ok {
+		job.Status = status
+		job.LastUpdate = time.Now()
+	}
+}
+
 var executeJobCmd = &cobra.Command{
 	Use:   "executeJob",
 	Short: "[COMPUTE PROVIDER ONLY]executeJob can be used to execute an existing job",
@@ -50,16 +166,25 @@ func initialiseExecuteJob(cmd *cobra.Command, args []string) {
 
 // RunExecuteJob is the entry point for job execution that sets up the execution environment
 // and initiates job processing. This function:
-// 1. Validates all input parameters and configuration
-// 2. Sets up graceful shutdown handlers
-// 3. Initializes execution state tracking
-// 4. Launches the main execution loop
+// 1. Validates all input parameters and configuration, registers the script workflows of the
+// workflows file, and checks on chain that --isAdmin and --isRandom are only passed by an
+// account holding DEFAULT_ADMIN_ROLE on the JobManager
+// 2. Sets up graceful shutdown handlers for SIGINT and SIGTERM
+// 3. Enables the block proposer role when --proposer is passed
+// 4. Initializes execution state tracking, sizes the GPU allocator, selects the runtime running
+// the pipelines and recovers journaled jobs
+// 5. Launches the main execution loop
+// 6. Runs the shutdown sequence once a shutdown signal stopped the loop and exits with
+// ExitCodeShutdown, or ExitCodeShutdownJobsTerminated if running jobs had to be terminated
 // Returns early if validation fails or if admin checks fail.
 func (*UtilsStruct) RunExecuteJob(flagSet *pflag.FlagSet) {
 	config, err := cmdUtils.GetConfigData()
 	utils.CheckError("Error in getting config: ", err)
 	log.Debugf("RunExecuteJob: Config: %+v", config)
 
+	err = registerConfiguredWorkflows(config)
+	utils.CheckError("Error in registering workflows: ", err)
+
 	client := protoUtils.ConnectToEthClient(config.Provider)
 
 	address, err := flagSetUtils.GetStringAddress(flagSet)
@@ -81,11 +206,24 @@ func (*UtilsStruct) RunExecuteJob(flagSet *pflag.FlagSet) {
 	isRandom, err := flagSet.GetBool("isRandom")
 	utils.CheckError("Error in getting random flag: ", err)
 
-	if isAdmin && address != "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771" {
-		log.Fatal("Only Admin can pass the isAdmin Flag")
-	}
-	if isRandom && address != "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771" {
-		log.Fatal("Only Admin can pass the isRandom Flag")
+	isProposer, err := flagSet.GetBool("proposer")
+	utils.CheckError("Error in getting proposer flag: ", err)
+
+	runtimeName, err := flagSet.GetString("runtime")
+	utils.CheckError("Error in getting runtime: ", err)
+
+	image, err := flagSet.GetString("image")
+	utils.CheckError("Error in getting image: ", err)
+
+	failRefusedJobs, err = flagSet.GetBool("failRefusedJobs")
+	utils.CheckError("Error in getting failRefusedJobs flag: ", err)
+
+	if isAdmin || isRandom {
+		hasAdminRole, err := cmdUtils.HasAdminRole(client, address)
+		utils.CheckError("Error in checking admin role: ", err)
+		if !hasAdminRole {
+			log.Fatal("Only an account holding DEFAULT_ADMIN_ROLE on the JobManager can pass the isAdmin or isRandom flag")
+		}
 	}
 
 	account := types.Account{
@@ -93,47 +231,58 @@ func (*UtilsStruct) RunExecuteJob(flagSet *pflag.FlagSet) {
 		Password: password,
 	}
 
+	activeProposer = nil
+	if isProposer {
+		err = useBlockManager(config)
+		utils.CheckError("Error in enabling block proposer: ", err)
+		stakerId, err := protoUtils.GetStakerId(client, address)
+		utils.CheckError("Error in getting staker id: ", err)
+		activeProposer = newBlockProposer(stakerId)
+		log.WithField("stakerId", stakerId).Info("Block proposer enabled")
+	}
+
 	// Initialize execution state
 	executionState = types.JobExecutionState{
-		IsJobRunning: false,
-		CurrentJob:   nil,
+		Jobs: make(map[string]*types.JobExecution),
 	}
 
+	// Size the GPU slot allocator from the detected hardware
+	numGPUs, err := systemspecs.GetGPUCount()
+	if err != nil {
+		log.WithError(err).Warn("Failed to detect GPUs, running one job at a time without GPU pinning")
+	}
+	gpuAllocator = NewGPUAllocator(numGPUs, core.MaxJobsPerStaker)
+	log.WithFields(logrus.Fields{
+		"gpus":    gpuAllocator.TotalGPUs(),
+		"managed": gpuAllocator.IsManaged(),
+	}).Info("Initialized GPU slot allocator")
+
+	jobRuntime, err = newJobRuntime(runtimeName, image)
+	utils.CheckError("Error in selecting runtime: ", err)
+	log.WithFields(logrus.Fields{
+		"runtime": jobRuntime.Name(),
+		"image":   image,
+	}).Info("Selected pipeline runtime")
+
 	// Handle graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
 	handleGracefulShutdown(ctx, cancel)
 
+	// Pick up jobs that were in flight when the daemon last stopped
+	if err := cmdUtils.RecoverJobExecution(ctx, client, config, account, pipelinePath); err != nil {
+		log.WithError(err).Error("Failed to recover jobs from the execution journal")
+	}
+
 	// Start the main execution loop
 	if err := cmdUtils.ExecuteJob(ctx, client, config, account, isAdmin, isRandom, pipelinePath); err != nil {
 		log.WithError(err).Fatal("Job execution failed")
 	}
-}
-
-// Sets up signal handling for graceful shutdown of job execution. Ensures proper cleanup
-// of resources and updates job status appropriately when shutdown is triggered.
-func handleGracefulShutdown(ctx context.Context, cancel context.CancelFunc) {
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt)
 
-	go func() {
-		select {
-		case <-signalChan:
-			log.Warn("Received interrupt signal. Starting graceful shutdown...")
-			stateMutex.RLock()
-			if executionState.CurrentJob != nil {
-				log.Info("Currently executing job will be marked as failed")
-				// Handle cleanup for current job
-			}
-			stateMutex.RUnlock()
-			log.Info("Press CTRL+C again to force terminate.")
-			cancel()
-		case <-ctx.Done():
-		}
-		<-signalChan
-		os.Exit(2)
-	}()
+	if jobShutdown.IsShuttingDown() {
+		osUtils.Exit(shutdownExecutor(client, config, account, pipelinePath))
+	}
 }
 
 // UpdateJobStatus updates the on-chain status of a job by submitting a transaction with the new status.
@@ -216,13 +365,18 @@ func (*UtilsStruct) UpdateJobStatus(client *ethclient.Client, config types.Confi
 // ExecuteJob is the core job execution function that manages the job lifecycle through various network states.
 // Continuously monitors the network state and responds to changes by:
 // 1. Managing state transitions
-// 2. Handling job execution updates
-// 3. Coordinating with the blockchain for job progression
-// Uses a ticker to periodically check and update job status.
+// 2. Reading JobManager events to learn about created, assigned and updated jobs
+// 3. Handling job execution updates once an event announced them
+// 4. Coordinating with the blockchain for job progression
+// 5. Proposing and confirming blocks when the block proposer role is enabled
+// Uses a ticker to periodically check the state and events; while events cannot be read
+// every tick queries the jobs as a fallback.
 func (*UtilsStruct) ExecuteJob(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, isAdmin bool, isRandom bool, pipelinePath string) error {
 	ticker := time.NewTicker(time.Duration(core.StateCheckInterval) * time.Second)
 	defer ticker.Stop()
 
+	watcher := newJobEventWatcher(common.HexToAddress(account.Address))
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -244,10 +398,45 @@ func (*UtilsStruct) ExecuteJob(ctx context.Context, client *ethclient.Client, co
 				"epoch": epoch,
 			}).Debug("Current network state")
 
-			if err := cmdUtils.HandleStateTransition(ctx, client, config, account, types.EpochState(state), epoch, isAdmin, isRandom, pipelinePath); err != nil {
+			watcher.Refresh(client, epoch)
+
+			if activeProposer != nil {
+				if err := activeProposer.Handle(client, config, account, types.EpochState(state), epoch); err != nil {
+					log.WithError(err).Error("Error handling block proposer duties")
+				}
+			}
+
+			if !watcher.ShouldHandle(types.EpochState(state), isAdmin) {
+				continue
+			}
+
+			err = cmdUtils.HandleStateTransition(ctx, client, config, account, types.EpochState(state), epoch, isAdmin, isRandom, pipelinePath)
+			if err != nil {
 				log.WithError(err).Error("Error handling state transition")
 			}
+			watcher.Handled(types.EpochState(state), err)
+		}
+	}
+}
+
+// newJobRuntime returns the runtime running the pipelines of jobs: processes on the host, or
+// containers of image mounting the .env of the node.
+// Returns error if the runtime is unknown or the lumino directory cannot be found.
+func newJobRuntime(name string, image string) (pipeline_zen.Runtime, error) {
+	switch name {
+	case pipeline_zen.ProcessRuntimeName:
+		return pipeline_zen.ProcessRuntime{}, nil
+	case pipeline_zen.DockerRuntimeName:
+		defaultPath, err := path.PathUtilsInterface.GetDefaultPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get lumino directory: %w", err)
 		}
+		return pipeline_zen.DockerRuntime{
+			Image:   image,
+			EnvFile: filepath.Join(defaultPath, ".env"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown runtime %q, expected %s or %s", name, pipeline_zen.ProcessRuntimeName, pipeline_zen.DockerRuntimeName)
 	}
 }
 
@@ -255,18 +444,27 @@ func init() {
 	rootCmd.AddCommand(executeJobCmd)
 
 	var (
-		Account  string
-		Password string
-		ZenPath  string
-		IsAdmin  bool
-		IsRandom bool
+		Account         string
+		Password        string
+		ZenPath         string
+		IsAdmin         bool
+		IsRandom        bool
+		Proposer        bool
+		Runtime         string
+		Image           string
+		FailRefusedJobs bool
 	)
 
 	executeJobCmd.Flags().StringVarP(&Account, "address", "a", "", "address of the compute provider")
 	executeJobCmd.Flags().StringVarP(&Password, "password", "", "", "password path of compute provider to protect the keystore")
 	executeJobCmd.Flags().StringVarP(&ZenPath, "zen-path", "z", "", "path to the pipeline-zen directory")
 	executeJobCmd.Flags().BoolVarP(&IsAdmin, "isAdmin", "", false, "whether the executor is an admin")
-	executeJobCmd.Flags().BoolVarP(&IsRandom, "isRandom", "", false, "whether the job to be assigned in random manner or just to admin")
+	executeJobCmd.Flags().BoolVarP(&IsRandom, "isRandom", "", false, "assign jobs with the epoch-seeded random strategy, overriding the configured assignmentStrategy")
+
+	executeJobCmd.Flags().BoolVarP(&Proposer, "proposer", "", false, "propose blocks of the jobs concluded in each epoch and confirm the winning blocks proposed by this node")
+	executeJobCmd.Flags().StringVarP(&Runtime, "runtime", "", pipeline_zen.ProcessRuntimeName, "run job pipelines as a process on the host or in a docker container")
+	executeJobCmd.Flags().StringVarP(&Image, "image", "", pipeline_zen.DefaultContainerImage, "pipeline-zen image the containers of --runtime docker are created from")
+	executeJobCmd.Flags().BoolVarP(&FailRefusedJobs, "failRefusedJobs", "", false, "report jobs this node refuses after its preflight checks as Failed instead of leaving them Queued")
 
 	AddrErr := executeJobCmd.MarkFlagRequired("address")
 	utils.CheckError("Address error : ", AddrErr)
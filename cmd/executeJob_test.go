@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"lumino/cmd/mocks"
+	"lumino/core"
 	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	pipeline_zen "lumino/pipeline-zen"
 	"math/big"
 	"testing"
 
@@ -37,6 +41,10 @@ func TestRunExecuteJob(t *testing.T) {
 		adminErr     error
 		isRandom     bool
 		randomErr    error
+		isProposer   bool
+		stakerIdErr  error
+		hasAdminRole bool
+		adminRoleErr error
 		executeErr   error
 	}
 
@@ -139,8 +147,80 @@ func TestRunExecuteJob(t *testing.T) {
 			expectedFatal: true,
 			setupFlags:    true,
 		},
+		{
+			name: "Test 8: RunExecuteJob should enable the block proposer when the BlockManager is configured",
+			args: args{
+				config:       types.Configurations{BlockManagerAddress: "0xab110dA2064AC0B44c08D71A3D8148BBB0C3aD1F"},
+				password:     "password",
+				address:      "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+				pipelinePath: "/path/to/pipeline",
+				isProposer:   true,
+			},
+			expectedFatal: false,
+			setupFlags:    true,
+		},
+		{
+			name: "Test 9: RunExecuteJob should fail when the block proposer is enabled without a BlockManager address",
+			args: args{
+				config:       types.Configurations{},
+				password:     "password",
+				address:      "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+				pipelinePath: "/path/to/pipeline",
+				isProposer:   true,
+			},
+			expectedFatal: true,
+			setupFlags:    true,
+		},
+		{
+			name: "Test 10: RunExecuteJob should fail when the staker id of the block proposer cannot be fetched",
+			args: args{
+				config:       types.Configurations{BlockManagerAddress: "0xab110dA2064AC0B44c08D71A3D8148BBB0C3aD1F"},
+				password:     "password",
+				address:      "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+				pipelinePath: "/path/to/pipeline",
+				isProposer:   true,
+				stakerIdErr:  errors.New("staker id error"),
+			},
+			expectedFatal: true,
+			setupFlags:    true,
+		},
+		{
+			name: "Test 11: RunExecuteJob should allow the admin flags for an account holding the admin role",
+			args: args{
+				config:       types.Configurations{},
+				password:     "password",
+				address:      "0x1234567890123456789012345678901234567890",
+				pipelinePath: "/path/to/pipeline",
+				isAdmin:      true,
+				isRandom:     true,
+				hasAdminRole: true,
+			},
+			expectedFatal: false,
+			setupFlags:    true,
+		},
+		{
+			name: "Test 12: RunExecuteJob should fail when the admin role cannot be checked",
+			args: args{
+				config:       types.Configurations{},
+				password:     "password",
+				address:      "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+				pipelinePath: "/path/to/pipeline",
+				isAdmin:      true,
+				adminRoleErr: errors.New("rpc error"),
+			},
+			expectedFatal: true,
+			setupFlags:    true,
+		},
 	}
 
+	originalBlockManagerAddress := core.BlockManagerAddress
+	defer func() {
+		core.BlockManagerAddress = originalBlockManagerAddress
+		activeProposer = nil
+		jobRuntime = pipeline_zen.ProcessRuntime{}
+		failRefusedJobs = false
+	}()
+
 	defer func() { log.ExitFunc = nil }()
 	var fatal bool
 	log.ExitFunc = func(int) { fatal = true }
@@ -173,12 +253,19 @@ func TestRunExecuteJob(t *testing.T) {
 			flagSetUtilsMock.On("GetStringAddress", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.address, tt.args.addressErr)
 			utilsMock.On("AssignLogFile", mock.AnythingOfType("*pflag.FlagSet"))
 			utilsMock.On("AssignPassword", mock.AnythingOfType("*pflag.FlagSet")).Return(tt.args.password)
+			utilsMock.On("GetStakerId", mock.Anything, tt.args.address).Return(uint32(2), tt.args.stakerIdErr)
+			cmdUtilsMock.On("HasAdminRole", mock.Anything, tt.args.address).Return(tt.args.hasAdminRole, tt.args.adminRoleErr)
+
+			flagSet.String("runtime", pipeline_zen.ProcessRuntimeName, "")
+			flagSet.String("image", pipeline_zen.DefaultContainerImage, "")
+			flagSet.Bool("failRefusedJobs", false, "")
 
 			// Flag mocks and expectations
 			if tt.setupFlags {
 				flagSet.String("zen-path", tt.args.pipelinePath, "")
 				flagSet.Bool("isAdmin", tt.args.isAdmin, "")
 				flagSet.Bool("isRandom", tt.args.isRandom, "")
+				flagSet.Bool("proposer", tt.args.isProposer, "")
 
 				flagSetUtilsMock.On("GetString", "zen-path").Return(tt.args.pipelinePath, nil)
 				flagSetUtilsMock.On("GetBool", "isAdmin").Return(tt.args.isAdmin, nil)
@@ -189,6 +276,7 @@ func TestRunExecuteJob(t *testing.T) {
 					// flagSet.String("zen-path", tt.args.pipelinePath, "")
 					flagSet.Bool("isAdmin", tt.args.isAdmin, "")
 					flagSet.Bool("isRandom", tt.args.isRandom, "")
+					flagSet.Bool("proposer", false, "")
 
 					flagSetUtilsMock.On("GetString", "zen-path").Return("", tt.args.pathErr)
 					flagSetUtilsMock.On("GetBool", "isAdmin").Return(false, nil)
@@ -198,6 +286,7 @@ func TestRunExecuteJob(t *testing.T) {
 					flagSet.String("zen-path", tt.args.pipelinePath, "")
 					// flagSet.Bool("isAdmin", tt.args.isAdmin, "")
 					flagSet.Bool("isRandom", tt.args.isRandom, "")
+					flagSet.Bool("proposer", false, "")
 
 					flagSetUtilsMock.On("GetString", "zen-path").Return(tt.args.pipelinePath, nil)
 					flagSetUtilsMock.On("GetBool", "isAdmin").Return(false, tt.args.adminErr)
@@ -207,6 +296,7 @@ func TestRunExecuteJob(t *testing.T) {
 					flagSet.String("zen-path", tt.args.pipelinePath, "")
 					flagSet.Bool("isAdmin", tt.args.isAdmin, "")
 					// flagSet.Bool("isRandom", tt.args.isRandom, "")
+					flagSet.Bool("proposer", false, "")
 
 					flagSetUtilsMock.On("GetString", "zen-path").Return(tt.args.pipelinePath, nil)
 					flagSetUtilsMock.On("GetBool", "isAdmin").Return(false, nil)
@@ -219,6 +309,9 @@ func TestRunExecuteJob(t *testing.T) {
 			if tt.args.pathErr != nil {
 				executeJobPath = ""
 			}
+			cmdUtilsMock.On("RecoverJobExecution",
+				mock.Anything, mock.Anything, mock.Anything, mock.Anything, executeJobPath).
+				Return(nil)
 			cmdUtilsMock.On("ExecuteJob",
 				mock.Anything, mock.Anything, mock.Anything, mock.Anything,
 				tt.args.isAdmin, tt.args.isRandom, executeJobPath).
@@ -231,6 +324,10 @@ func TestRunExecuteJob(t *testing.T) {
 			if fatal != tt.expectedFatal {
 				t.Errorf("The RunExecuteJob function didn't execute as expected, got fatal=%v, want fatal=%v", fatal, tt.expectedFatal)
 			}
+			if tt.args.isProposer && !tt.expectedFatal {
+				assert.Equal(t, uint32(2), activeProposer.stakerId)
+				assert.Equal(t, tt.args.config.BlockManagerAddress, core.BlockManagerAddress)
+			}
 		})
 	}
 }
@@ -425,3 +522,59 @@ func TestExecuteJob(t *testing.T) {
 		})
 	}
 }
+
+// Tests selecting the runtime of job pipelines with cases:
+// 1. The process runtime
+// 2. The docker runtime with the image and the .env of the lumino directory
+// 3. The docker runtime when the lumino directory cannot be found
+// 4. An unknown runtime
+func TestNewJobRuntime(t *testing.T) {
+	tests := []struct {
+		name        string
+		runtime     string
+		pathErr     error
+		wantRuntime pipeline_zen.Runtime
+		wantErr     string
+	}{
+		{
+			name:        "process runtime",
+			runtime:     "process",
+			wantRuntime: pipeline_zen.ProcessRuntime{},
+		},
+		{
+			name:        "docker runtime",
+			runtime:     "docker",
+			wantRuntime: pipeline_zen.DockerRuntime{Image: "pipeline-zen:v2", EnvFile: "/home/node/.lumino/.env"},
+		},
+		{
+			name:    "docker runtime without lumino directory",
+			runtime: "docker",
+			pathErr: errors.New("home not set"),
+			wantErr: "failed to get lumino directory: home not set",
+		},
+		{
+			name:    "unknown runtime",
+			runtime: "podman",
+			wantErr: `unknown runtime "podman", expected process or docker`,
+		},
+	}
+
+	originalPathUtils := path.PathUtilsInterface
+	defer func() { path.PathUtilsInterface = originalPathUtils }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathMock := new(pathMocks.PathInterface)
+			pathMock.On("GetDefaultPath").Return("/home/node/.lumino", tt.pathErr)
+			path.PathUtilsInterface = pathMock
+
+			runtime, err := newJobRuntime(tt.runtime, "pipeline-zen:v2")
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRuntime, runtime)
+		})
+	}
+}
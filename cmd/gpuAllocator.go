@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"lumino/core"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gpuAllocator hands out GPU slots to the jobs executing on this node.
+// It is sized from the detected hardware when the executor starts.
+var gpuAllocator = NewGPUAllocator(0, core.MaxJobsPerStaker)
+
+// GPUAllocator tracks which GPUs of the node are reserved by which job.
+// When no GPUs were detected the allocator runs unmanaged: jobs are not
+// pinned to devices and only one job runs at a time.
+type GPUAllocator struct {
+	mu        sync.Mutex
+	totalGPUs int
+	maxJobs   int
+	inUse     map[int]string
+	jobs      map[string][]int
+}
+
+// NewGPUAllocator creates an allocator for totalGPUs devices that runs at most maxJobs jobs concurrently
+func NewGPUAllocator(totalGPUs int, maxJobs int) *GPUAllocator {
+	if totalGPUs <= 0 || maxJobs <= 0 {
+		maxJobs = 1
+	}
+	return &GPUAllocator{
+		totalGPUs: totalGPUs,
+		maxJobs:   maxJobs,
+		inUse:     make(map[int]string),
+		jobs:      make(map[string][]int),
+	}
+}
+
+// IsManaged reports whether jobs are pinned to dedicated GPUs
+func (a *GPUAllocator) IsManaged() bool {
+	return a.totalGPUs > 0
+}
+
+// TotalGPUs returns the number of GPUs managed by the allocator
+func (a *GPUAllocator) TotalGPUs() int {
+	return a.totalGPUs
+}
+
+// FreeGPUs returns the number of GPUs not reserved by any job
+func (a *GPUAllocator) FreeGPUs() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.totalGPUs - len(a.inUse)
+}
+
+// CanEverFit reports whether a job requesting numGPUs can run on this node at all.
+// A node without detected GPUs only runs jobs requesting none.
+func (a *GPUAllocator) CanEverFit(numGPUs int) bool {
+	return numGPUs <= a.totalGPUs
+}
+
+// Allocate reserves numGPUs free devices for a job, lowest indices first.
+// It returns false when the job has to stay queued until enough slots are free.
+// Allocating for a job that already holds a reservation returns that reservation.
+func (a *GPUAllocator) Allocate(jobId string, numGPUs int) ([]int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if gpus, ok := a.jobs[jobId]; ok {
+		return gpus, true
+	}
+	if len(a.jobs) >= a.maxJobs {
+		return nil, false
+	}
+	if !a.IsManaged() {
+		a.jobs[jobId] = nil
+		return nil, true
+	}
+	if numGPUs > a.totalGPUs-len(a.inUse) {
+		return nil, false
+	}
+
+	gpus := make([]int, 0, numGPUs)
+	for i := 0; i < a.totalGPUs && len(gpus) < numGPUs; i++ {
+		if _, taken := a.inUse[i]; !taken {
+			gpus = append(gpus, i)
+		}
+	}
+	for _, gpu := range gpus {
+		a.inUse[gpu] = jobId
+	}
+	a.jobs[jobId] = gpus
+	return gpus, true
+}
+
+// Reserve records an existing reservation, used when re-attaching to jobs after a restart.
+// Devices that are out of range or already held by another job are skipped.
+func (a *GPUAllocator) Reserve(jobId string, gpus []int) []int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	reserved := make([]int, 0, len(gpus))
+	for _, gpu := range gpus {
+		if gpu < 0 || gpu >= a.totalGPUs {
+			continue
+		}
+		if owner, taken := a.inUse[gpu]; taken && owner != jobId {
+			continue
+		}
+		a.inUse[gpu] = jobId
+		reserved = append(reserved, gpu)
+	}
+	sort.Ints(reserved)
+	a.jobs[jobId] = reserved
+	return reserved
+}
+
+// Release frees all GPUs held by a job
+func (a *GPUAllocator) Release(jobId string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, gpu := range a.jobs[jobId] {
+		delete(a.inUse, gpu)
+	}
+	delete(a.jobs, jobId)
+}
+
+// cudaVisibleDevices formats GPU indices for the CUDA_VISIBLE_DEVICES variable
+func cudaVisibleDevices(gpus []int) string {
+	ids := make([]string, len(gpus))
+	for i, gpu := range gpus {
+		ids[i] = strconv.Itoa(gpu)
+	}
+	return strings.Join(ids, ",")
+}
+
+// gpuEnv returns the environment pinning a job to its reserved GPUs.
+// Unmanaged jobs inherit the environment of the executor unchanged.
+func gpuEnv(gpus []int) []string {
+	if !gpuAllocator.IsManaged() {
+		return nil
+	}
+	return []string{"CUDA_VISIBLE_DEVICES=" + cudaVisibleDevices(gpus)}
+}
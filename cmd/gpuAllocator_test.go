@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests GPU slot allocation across concurrent jobs:
+// 1. Jobs receive the lowest free GPU indices
+// 2. Jobs stay queued while not enough GPUs are free
+// 3. Released GPUs are handed to the next job
+// 4. The concurrent job limit is enforced
+func TestGPUAllocator(t *testing.T) {
+	allocator := NewGPUAllocator(4, 3)
+	assert.True(t, allocator.IsManaged())
+	assert.Equal(t, 4, allocator.FreeGPUs())
+
+	gpus, ok := allocator.Allocate("1", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1}, gpus)
+
+	gpus, ok = allocator.Allocate("1", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1}, gpus, "existing reservation is returned")
+
+	gpus, ok = allocator.Allocate("2", 1)
+	assert.True(t, ok)
+	assert.Equal(t, []int{2}, gpus)
+
+	_, ok = allocator.Allocate("3", 2)
+	assert.False(t, ok, "only one GPU is free")
+	assert.Equal(t, 1, allocator.FreeGPUs())
+
+	allocator.Release("1")
+	gpus, ok = allocator.Allocate("3", 2)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 1}, gpus)
+
+	gpus, ok = allocator.Allocate("4", 0)
+	assert.True(t, ok)
+	assert.Empty(t, gpus)
+
+	_, ok = allocator.Allocate("5", 0)
+	assert.False(t, ok, "concurrent job limit reached")
+
+	assert.True(t, allocator.CanEverFit(4))
+	assert.False(t, allocator.CanEverFit(5))
+}
+
+// Tests re-reserving GPUs after a restart skips devices that are out of range or taken
+func TestGPUAllocatorReserve(t *testing.T) {
+	allocator := NewGPUAllocator(2, 2)
+
+	_, ok := allocator.Allocate("1", 1)
+	assert.True(t, ok)
+
+	assert.Equal(t, []int{1}, allocator.Reserve("2", []int{5, 1, 0}))
+	assert.Equal(t, 0, allocator.FreeGPUs())
+
+	allocator.Release("2")
+	assert.Equal(t, 1, allocator.FreeGPUs())
+}
+
+// Tests that without detected GPUs only jobs requesting no GPUs fit, one at a time and not pinned
+func TestGPUAllocatorUnmanaged(t *testing.T) {
+	original := gpuAllocator
+	defer func() { gpuAllocator = original }()
+
+	gpuAllocator = NewGPUAllocator(0, 5)
+	assert.False(t, gpuAllocator.IsManaged())
+	assert.True(t, gpuAllocator.CanEverFit(0))
+	assert.False(t, gpuAllocator.CanEverFit(8))
+
+	_, ok := gpuAllocator.Allocate("1", 8)
+	assert.True(t, ok)
+	_, ok = gpuAllocator.Allocate("2", 1)
+	assert.False(t, ok)
+	assert.Nil(t, gpuEnv(nil))
+
+	gpuAllocator = NewGPUAllocator(4, 2)
+	assert.Equal(t, []string{"CUDA_VISIBLE_DEVICES=2,3"}, gpuEnv([]int{2, 3}))
+	assert.Equal(t, []string{"CUDA_VISIBLE_DEVICES="}, gpuEnv([]int{}))
+}
@@ -0,0 +1,106 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"fmt"
+	"lumino/cmd/systemspecs"
+	"lumino/core/types"
+	"math/big"
+
+	"github.com/sirupsen/logrus"
+)
+
+// parseJobRequirements extracts the hardware requirements from the job spec.
+// num_gpus is the number of GPUs, min_gpu_memory and min_system_memory are optional
+// sizes such as "24 GiB" or "16000 MiB", bare numbers are read as GiB.
+// Returns error if the job spec is malformed.
+func parseJobRequirements(jobDetailsJSON string) (types.JobRequirements, error) {
+	spec, err := parseJobDetails([]byte(jobDetailsJSON))
+	if err != nil {
+		return types.JobRequirements{}, err
+	}
+	return jobSpecRequirements(spec)
+}
+
+// jobSpecRequirements returns the hardware requirements of a parsed job spec.
+// Returns error if a memory size of the spec is malformed.
+func jobSpecRequirements(spec types.JobSpec) (types.JobRequirements, error) {
+	var err error
+	requirements := types.JobRequirements{NumGPUs: spec.NumGPUs}
+	if spec.MinGPUMemory != "" {
+		requirements.GPUMemoryMiB, err = systemspecs.ParseMemoryMiB(spec.MinGPUMemory)
+		if err != nil {
+			return requirements, fmt.Errorf("invalid min_gpu_memory: %w", err)
+		}
+	}
+	if spec.MinSystemMemory != "" {
+		requirements.SystemMemoryMiB, err = systemspecs.ParseMemoryMiB(spec.MinSystemMemory)
+		if err != nil {
+			return requirements, fmt.Errorf("invalid min_system_memory: %w", err)
+		}
+	}
+	return requirements, nil
+}
+
+// hardwareMismatches compares the machine spec a staker published against the job
+// requirements and returns every reason the staker cannot run the job
+func hardwareMismatches(staker types.StakerContract, requirements types.JobRequirements) []string {
+	if requirements == (types.JobRequirements{}) {
+		return nil
+	}
+	if staker.MachineSpecInJSON == "" {
+		return []string{"no machine spec published"}
+	}
+
+	specs, err := systemspecs.ParseSystemSpecs(staker.MachineSpecInJSON)
+	if err != nil {
+		return []string{"machine spec is not valid JSON"}
+	}
+
+	var reasons []string
+	if specs.GPUCount() < requirements.NumGPUs {
+		reasons = append(reasons, fmt.Sprintf("has %d GPUs, job needs %d", specs.GPUCount(), requirements.NumGPUs))
+	}
+	if requirements.GPUMemoryMiB > 0 && specs.GPUCount() > 0 && specs.MinGPUMemoryMiB() < requirements.GPUMemoryMiB {
+		reasons = append(reasons, fmt.Sprintf("GPU memory %.2f MiB is below the required %.2f MiB", specs.MinGPUMemoryMiB(), requirements.GPUMemoryMiB))
+	}
+	if requirements.SystemMemoryMiB > 0 {
+		memory, err := specs.MemoryMiB()
+		if err != nil {
+			reasons = append(reasons, "system memory is not reported")
+		} else if memory < requirements.SystemMemoryMiB {
+			reasons = append(reasons, fmt.Sprintf("system memory %.2f MiB is below the required %.2f MiB", memory, requirements.SystemMemoryMiB))
+		}
+	}
+	return reasons
+}
+
+// filterStakersByHardware returns the candidates whose machine spec satisfies the job
+// requirements, logging why each rejected candidate was skipped
+func filterStakersByHardware(jobId *big.Int, requirements types.JobRequirements, candidates []types.StakerContract) []types.StakerContract {
+	eligible := make([]types.StakerContract, 0, len(candidates))
+	for _, staker := range candidates {
+		reasons := hardwareMismatches(staker, requirements)
+		if len(reasons) > 0 {
+			log.WithFields(logrus.Fields{
+				"jobId":    jobId.String(),
+				"stakerId": staker.Id,
+				"staker":   staker.Address.Hex(),
+				"reasons":  reasons,
+			}).Info("Staker does not meet job hardware requirements")
+			continue
+		}
+		eligible = append(eligible, staker)
+	}
+	return eligible
+}
+
+// hardwareEligibleStakers reads the requirements of a job from its on-chain details and
+// returns the active stakers able to run it
+func hardwareEligibleStakers(jobId *big.Int, jobDetails types.JobContract, activeStakers []types.StakerContract) ([]types.StakerContract, error) {
+	requirements, err := parseJobRequirements(jobDetails.JobDetailsInJSON)
+	if err != nil {
+		return nil, err
+	}
+	return filterStakersByHardware(jobId, requirements, activeStakers), nil
+}
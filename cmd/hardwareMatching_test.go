@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"lumino/core/types"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests parsing of job hardware requirements from the job details JSON
+func TestParseJobRequirements(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    types.JobRequirements
+		wantErr bool
+	}{
+		{
+			name: "gpu count only",
+			json: testJobSpec(2),
+			want: types.JobRequirements{NumGPUs: 2},
+		},
+		{
+			name: "all requirements with units",
+			json: `{"schema_version": 1, "job_config_name": "llm_dummy", "dataset_id": "gs://bucket/dataset", "batch_size": 8,
+				"num_epochs": 1, "num_gpus": 1, "min_gpu_memory": "24 GiB", "min_system_memory": "16000 MiB"}`,
+			want: types.JobRequirements{NumGPUs: 1, GPUMemoryMiB: 24 * 1024, SystemMemoryMiB: 16000},
+		},
+		{
+			name: "bare numbers are GiB",
+			json: `{"schema_version": 1, "job_config_name": "llm_dummy", "dataset_id": "gs://bucket/dataset", "batch_size": 8,
+				"num_epochs": 1, "num_gpus": 0, "min_system_memory": "32"}`,
+			want: types.JobRequirements{SystemMemoryMiB: 32 * 1024},
+		},
+		{
+			name:    "invalid gpu count",
+			json:    `{"schema_version": 1, "job_config_name": "llm_dummy", "dataset_id": "gs://bucket/dataset", "batch_size": 8, "num_epochs": 1, "num_gpus": "many"}`,
+			wantErr: true,
+		},
+		{
+			name: "invalid memory unit",
+			json: `{"schema_version": 1, "job_config_name": "llm_dummy", "dataset_id": "gs://bucket/dataset", "batch_size": 8,
+				"num_epochs": 1, "num_gpus": 1, "min_gpu_memory": "24 bananas"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			json:    `{`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJobRequirements(tt.json)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// Tests matching stakers against job requirements:
+// 1. Stakers meeting every requirement are kept
+// 2. Missing GPUs, small GPUs and small memory are rejected with reasons
+// 3. Missing or malformed specs are rejected when the job has requirements
+func TestFilterStakersByHardware(t *testing.T) {
+	bigBox := types.StakerContract{Id: 1, Address: common.HexToAddress("0x1"),
+		MachineSpecInJSON: `{"gpu":[{"model":"A100","memory":"81920.00 MiB"},{"model":"A100","memory":"81920.00 MiB"}],"mem":"503.00 GiB"}`}
+	smallGPU := types.StakerContract{Id: 2, Address: common.HexToAddress("0x2"),
+		MachineSpecInJSON: `{"gpu":[{"model":"T4","memory":"15360.00 MiB"},{"model":"T4","memory":"15360.00 MiB"}],"mem":"503.00 GiB"}`}
+	cpuOnly := types.StakerContract{Id: 3, Address: common.HexToAddress("0x3"), MachineSpecInJSON: `{"mem":"64.00 GiB"}`}
+	noSpec := types.StakerContract{Id: 4, Address: common.HexToAddress("0x4")}
+	badSpec := types.StakerContract{Id: 5, Address: common.HexToAddress("0x5"), MachineSpecInJSON: `not json`}
+	candidates := []types.StakerContract{bigBox, smallGPU, cpuOnly, noSpec, badSpec}
+
+	requirements := types.JobRequirements{NumGPUs: 2, GPUMemoryMiB: 40 * 1024, SystemMemoryMiB: 128 * 1024}
+	assert.Equal(t, []types.StakerContract{bigBox}, filterStakersByHardware(big.NewInt(1), requirements, candidates))
+
+	assert.Equal(t, []string{"GPU memory 15360.00 MiB is below the required 40960.00 MiB"}, hardwareMismatches(smallGPU, requirements))
+	assert.Equal(t, []string{"has 0 GPUs, job needs 2", "system memory 65536.00 MiB is below the required 131072.00 MiB"}, hardwareMismatches(cpuOnly, requirements))
+	assert.Equal(t, []string{"no machine spec published"}, hardwareMismatches(noSpec, requirements))
+	assert.Equal(t, []string{"machine spec is not valid JSON"}, hardwareMismatches(badSpec, requirements))
+
+	// A job without requirements can run anywhere
+	assert.Equal(t, candidates, filterStakersByHardware(big.NewInt(1), types.JobRequirements{}, candidates))
+}
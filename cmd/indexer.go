@@ -0,0 +1,479 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/logger"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// indexerCmd groups the commands building and querying the local event index
+var indexerCmd = &cobra.Command{
+	Use:   "indexer",
+	Short: "Index contract events locally and query the job, staker and epoch history",
+	Long: `Reads the JobCreated, JobAssigned, JobStatusUpdated, NewStaker, StakeUpdated, StakerSlashed,
+BlockProposed and BlockConfirmed events into a local store in ~/.lumino/indexer, resuming from
+where the previous sync stopped, and answers history queries from it. BlockManager events are
+only indexed once the BlockManager address is configured. Query output is a table or, with
+--output json, JSON.
+
+Example:
+  ./lumino indexer sync --fromBlock 2500000
+  ./lumino indexer sync --follow
+  ./lumino indexer job --jobId 21
+  ./lumino indexer staker --staker 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --kind JobStatusUpdated --fromEpoch 3200
+  ./lumino indexer epoch --epoch 3251 --output json`,
+}
+
+var indexerSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Index the contract events up to the latest block",
+	Run:   initialiseIndexerSync,
+}
+
+var indexerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the block the index has reached",
+	Run:   initialiseIndexerStatus,
+}
+
+var indexerJobCmd = &cobra.Command{
+	Use:   "job",
+	Short: "Show the indexed history of a job",
+	Run:   initialiseIndexerJob,
+}
+
+var indexerStakerCmd = &cobra.Command{
+	Use:   "staker",
+	Short: "Show the indexed history of a staker: stake changes, assigned jobs and proposed blocks",
+	Run:   initialiseIndexerStaker,
+}
+
+var indexerEpochCmd = &cobra.Command{
+	Use:   "epoch",
+	Short: "Show the indexed events of an epoch or a range of epochs",
+	Run:   initialiseIndexerEpoch,
+}
+
+func initialiseIndexerSync(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteIndexerSync(cmd.Flags())
+}
+
+func initialiseIndexerStatus(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteIndexerStatus(cmd.Flags())
+}
+
+func initialiseIndexerJob(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteIndexerJob(cmd.Flags())
+}
+
+func initialiseIndexerStaker(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteIndexerStaker(cmd.Flags())
+}
+
+func initialiseIndexerEpoch(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteIndexerEpoch(cmd.Flags())
+}
+
+// ExecuteIndexerSync indexes the contract events up to the latest block. This function:
+// 1. Loads the configuration and, if set, the BlockManager address
+// 2. Clears the index on --reset
+// 3. Syncs from the cursor, or from --fromBlock on the first sync
+// 4. With --follow, syncs again every IndexerPollInterval until interrupted
+// Exits with error if the index cannot be opened or a sync fails without --follow.
+func (*UtilsStruct) ExecuteIndexerSync(flagSet *pflag.FlagSet) {
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("Indexer: Config: %+v", config)
+
+	if err := useBlockManager(config); err != nil {
+		log.WithError(err).Warn("BlockManager events are not indexed")
+	}
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+	logger.SetLoggerParameters(client, "")
+
+	fromBlock, err := flagSet.GetUint64("fromBlock")
+	utils.CheckError("Error in getting fromBlock: ", err)
+	follow, err := flagSet.GetBool("follow")
+	utils.CheckError("Error in getting follow flag: ", err)
+	reset, err := flagSet.GetBool("reset")
+	utils.CheckError("Error in getting reset flag: ", err)
+
+	if reset {
+		err = withEventIndex(func(idx *eventIndex) error { return idx.Reset() })
+		utils.CheckError("Error in resetting event index: ", err)
+		log.Info("Event index cleared")
+	}
+
+	if !follow {
+		cursor, err := runIndexerSync(client, fromBlock)
+		utils.CheckError("Error in syncing event index: ", err)
+		log.WithFields(logrus.Fields{
+			"block":  cursor.BlockNumber,
+			"events": cursor.NumEvents,
+		}).Info("Event index synced")
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+	followEventIndex(ctx, client, fromBlock, time.Duration(core.IndexerPollInterval)*time.Second)
+}
+
+// ExecuteIndexerStatus shows the block the index has reached.
+// Exits with error if the index cannot be read.
+func (*UtilsStruct) ExecuteIndexerStatus(flagSet *pflag.FlagSet) {
+	format, err := getOutputFormat(flagSet)
+	utils.CheckError("Error in getting output format: ", err)
+
+	var cursor types.IndexerCursor
+	var found bool
+	err = withEventIndex(func(idx *eventIndex) error {
+		cursor, found, err = idx.Cursor()
+		return err
+	})
+	utils.CheckError("Error in reading event index: ", err)
+
+	if !found {
+		if format == outputFormatJSON {
+			err = writeJSON(os.Stdout, nil)
+			utils.CheckError("Error in showing indexer status: ", err)
+			return
+		}
+		log.Info("Nothing indexed yet, run indexer sync")
+		return
+	}
+	err = renderIndexerCursor(os.Stdout, format, cursor)
+	utils.CheckError("Error in showing indexer status: ", err)
+}
+
+// ExecuteIndexerJob shows the indexed events of a job.
+// Exits with error if the job ID is invalid or the index cannot be read.
+func (*UtilsStruct) ExecuteIndexerJob(flagSet *pflag.FlagSet) {
+	format, filter := getIndexerQueryFlags(flagSet)
+
+	jobIdStr, err := flagSet.GetString("jobId")
+	utils.CheckError("Error in getting jobId: ", err)
+	jobId, ok := new(big.Int).SetString(jobIdStr, 10)
+	if !ok || jobId.Sign() < 0 {
+		log.Fatalf("Invalid jobId %q", jobIdStr)
+	}
+
+	var events []types.IndexedEvent
+	err = withEventIndex(func(idx *eventIndex) error {
+		events, err = idx.JobEvents(jobId, filter)
+		return err
+	})
+	utils.CheckError("Error in querying event index: ", err)
+
+	err = renderIndexedEvents(os.Stdout, format, events)
+	utils.CheckError("Error in showing job history: ", err)
+}
+
+// ExecuteIndexerStaker shows the indexed events of a staker given by ID or address.
+// Exits with error if the staker is invalid or the index cannot be read.
+func (*UtilsStruct) ExecuteIndexerStaker(flagSet *pflag.FlagSet) {
+	format, filter := getIndexerQueryFlags(flagSet)
+
+	staker, err := flagSet.GetString("staker")
+	utils.CheckError("Error in getting staker: ", err)
+	stakerId, address, err := parseStaker(staker)
+	utils.CheckError("Error in parsing staker: ", err)
+
+	var events []types.IndexedEvent
+	err = withEventIndex(func(idx *eventIndex) error {
+		stakerId, address = idx.ResolveStaker(stakerId, address)
+		events, err = idx.StakerEvents(stakerId, address, filter)
+		return err
+	})
+	utils.CheckError("Error in querying event index: ", err)
+
+	err = renderIndexedEvents(os.Stdout, format, events)
+	utils.CheckError("Error in showing staker history: ", err)
+}
+
+// ExecuteIndexerEpoch shows the indexed events of an epoch, or of the epochs up to --toEpoch.
+// Exits with error if the range is invalid or the index cannot be read.
+func (*UtilsStruct) ExecuteIndexerEpoch(flagSet *pflag.FlagSet) {
+	format, filter := getIndexerQueryFlags(flagSet)
+
+	epoch, err := flagSet.GetUint32("epoch")
+	utils.CheckError("Error in getting epoch: ", err)
+	toEpoch, err := flagSet.GetUint32("toEpoch")
+	utils.CheckError("Error in getting toEpoch: ", err)
+	if toEpoch == 0 {
+		toEpoch = epoch
+	}
+	if toEpoch < epoch {
+		log.Fatalf("toEpoch %d is before epoch %d", toEpoch, epoch)
+	}
+
+	var events []types.IndexedEvent
+	err = withEventIndex(func(idx *eventIndex) error {
+		events, err = idx.EpochEvents(epoch, toEpoch, filter)
+		return err
+	})
+	utils.CheckError("Error in querying event index: ", err)
+
+	err = renderIndexedEvents(os.Stdout, format, events)
+	utils.CheckError("Error in showing epoch history: ", err)
+}
+
+// withEventIndex opens the event index for the duration of fn
+func withEventIndex(fn func(idx *eventIndex) error) error {
+	idx, err := openEventIndex()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := idx.Close(); err != nil {
+			log.WithError(err).Warn("Failed to close event index")
+		}
+	}()
+	return fn(idx)
+}
+
+// runIndexerSync opens the event index and syncs it up to the latest block
+func runIndexerSync(client *ethclient.Client, fromBlock uint64) (types.IndexerCursor, error) {
+	var cursor types.IndexerCursor
+	err := withEventIndex(func(idx *eventIndex) error {
+		var err error
+		cursor, err = syncEventIndex(client, idx, fromBlock)
+		return err
+	})
+	return cursor, err
+}
+
+// followEventIndex syncs the event index every interval until ctx is canceled. The index is
+// only open during a sync, so queries can run in between. Failed syncs are retried.
+func followEventIndex(ctx context.Context, client *ethclient.Client, fromBlock uint64, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		cursor, err := runIndexerSync(client, fromBlock)
+		if err != nil {
+			log.WithError(err).Error("Failed to sync event index")
+		} else {
+			log.WithFields(logrus.Fields{
+				"block":  cursor.BlockNumber,
+				"events": cursor.NumEvents,
+			}).Debug("Event index synced")
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Stopped following the chain")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// getIndexerQueryFlags reads the output format and the --kind, --fromEpoch and --toEpoch filters.
+// Exits with error if a flag is invalid.
+func getIndexerQueryFlags(flagSet *pflag.FlagSet) (string, types.IndexedEventFilter) {
+	format, err := getOutputFormat(flagSet)
+	utils.CheckError("Error in getting output format: ", err)
+
+	kinds, err := flagSet.GetStringSlice("kind")
+	utils.CheckError("Error in getting kind: ", err)
+	var filter types.IndexedEventFilter
+	filter.Kinds, err = parseIndexedEventKinds(kinds)
+	utils.CheckError("Error in parsing kind: ", err)
+
+	if flagSet.Lookup("fromEpoch") != nil {
+		filter.FromEpoch, err = flagSet.GetUint32("fromEpoch")
+		utils.CheckError("Error in getting fromEpoch: ", err)
+		filter.ToEpoch, err = flagSet.GetUint32("toEpoch")
+		utils.CheckError("Error in getting toEpoch: ", err)
+	}
+	return format, filter
+}
+
+// indexedEventKinds are the events stored by the indexer
+var indexedEventKinds = []types.IndexedEventKind{
+	types.IndexedJobCreated,
+	types.IndexedJobAssigned,
+	types.IndexedJobStatusUpdated,
+	types.IndexedNewStaker,
+	types.IndexedStakeUpdated,
+	types.IndexedStakerSlashed,
+	types.IndexedBlockProposed,
+	types.IndexedBlockConfirmed,
+}
+
+// parseIndexedEventKinds parses event names, matched case-insensitively.
+// Returns error if a name is not an indexed event.
+func parseIndexedEventKinds(names []string) ([]types.IndexedEventKind, error) {
+	var kinds []types.IndexedEventKind
+	for _, name := range names {
+		var matched bool
+		for _, kind := range indexedEventKinds {
+			if strings.EqualFold(name, string(kind)) {
+				kinds = append(kinds, kind)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("unknown event %q", name)
+		}
+	}
+	return kinds, nil
+}
+
+// parseStaker parses a staker given by numeric ID or by address.
+// Returns error if the value is neither.
+func parseStaker(value string) (uint32, common.Address, error) {
+	if common.IsHexAddress(value) {
+		return 0, common.HexToAddress(value), nil
+	}
+	stakerId, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || stakerId == 0 {
+		return 0, common.Address{}, fmt.Errorf("invalid staker %q, expected a staker ID or an address", value)
+	}
+	return uint32(stakerId), common.Address{}, nil
+}
+
+// renderIndexerCursor writes the indexer cursor to out as JSON or as a table
+func renderIndexerCursor(out io.Writer, format string, cursor types.IndexerCursor) error {
+	if format == outputFormatJSON {
+		return writeJSON(out, cursor)
+	}
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Start Block", "Indexed To Block", "Block Hash", "Events", "Updated"})
+	table.Append([]string{
+		strconv.FormatUint(cursor.StartBlock, 10),
+		strconv.FormatUint(cursor.BlockNumber, 10),
+		cursor.BlockHash.Hex(),
+		strconv.FormatUint(cursor.NumEvents, 10),
+		formatTime(cursor.UpdatedAt),
+	})
+	table.Render()
+	return nil
+}
+
+// renderIndexedEvents writes the events to out as JSON or as a table
+func renderIndexedEvents(out io.Writer, format string, events []types.IndexedEvent) error {
+	if format == outputFormatJSON {
+		if events == nil {
+			events = []types.IndexedEvent{}
+		}
+		return writeJSON(out, events)
+	}
+	if len(events) == 0 {
+		_, err := fmt.Fprintln(out, "No events")
+		return err
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Block", "Time", "Epoch", "Event", "Job ID", "Staker", "Details"})
+	table.SetAutoWrapText(false)
+	for _, event := range events {
+		jobId := "-"
+		if event.JobID != nil {
+			jobId = event.JobID.String()
+		}
+		staker := event.Staker
+		if event.StakerID != 0 {
+			staker = strings.TrimSpace(fmt.Sprintf("%d %s", event.StakerID, event.Staker))
+		}
+		table.Append([]string{
+			strconv.FormatUint(event.BlockNumber, 10),
+			formatTime(event.BlockTime),
+			strconv.FormatUint(uint64(event.Epoch), 10),
+			string(event.Kind),
+			jobId,
+			orDash(staker),
+			orDash(describeIndexedEvent(event)),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// describeIndexedEvent summarizes the fields specific to the kind of event
+func describeIndexedEvent(event types.IndexedEvent) string {
+	switch event.Kind {
+	case types.IndexedJobCreated:
+		return "creator " + event.Creator
+	case types.IndexedJobAssigned:
+		return "assignee " + event.Assignee
+	case types.IndexedJobStatusUpdated:
+		if event.Assignee != "" {
+			return event.Status + " by " + event.Assignee
+		}
+		return event.Status
+	case types.IndexedStakeUpdated:
+		return "stake " + formatBigInt(event.Amount)
+	case types.IndexedStakerSlashed:
+		return "slashed " + formatBigInt(event.Amount)
+	case types.IndexedBlockProposed:
+		return fmt.Sprintf("block %d by %s", *event.BlockID, event.Proposer)
+	case types.IndexedBlockConfirmed:
+		return fmt.Sprintf("block %d", *event.BlockID)
+	}
+	return ""
+}
+
+// Initializes the indexer command group in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(indexerCmd)
+	indexerCmd.AddCommand(indexerSyncCmd, indexerStatusCmd, indexerJobCmd, indexerStakerCmd, indexerEpochCmd)
+
+	var (
+		FromBlock uint64
+		Follow    bool
+		Reset     bool
+		Output    string
+		Kinds     []string
+		JobId     string
+		Staker    string
+		Epoch     uint32
+		FromEpoch uint32
+		ToEpoch   uint32
+	)
+
+	indexerSyncCmd.Flags().Uint64VarP(&FromBlock, "fromBlock", "", 0, "block to start indexing from on the first sync, the contract deployment block covers every event")
+	indexerSyncCmd.Flags().BoolVarP(&Follow, "follow", "", false, "keep indexing new blocks until interrupted")
+	indexerSyncCmd.Flags().BoolVarP(&Reset, "reset", "", false, "clear the index and start again from --fromBlock")
+
+	indexerStatusCmd.Flags().StringVarP(&Output, "output", "o", outputFormatTable, "output format, table or json")
+	for _, queryCmd := range []*cobra.Command{indexerJobCmd, indexerStakerCmd, indexerEpochCmd} {
+		queryCmd.Flags().StringVarP(&Output, "output", "o", outputFormatTable, "output format, table or json")
+		queryCmd.Flags().StringSliceVarP(&Kinds, "kind", "", nil, "only show these events, such as JobStatusUpdated")
+	}
+	for _, queryCmd := range []*cobra.Command{indexerJobCmd, indexerStakerCmd} {
+		queryCmd.Flags().Uint32VarP(&FromEpoch, "fromEpoch", "", 0, "only show events from this epoch")
+		queryCmd.Flags().Uint32VarP(&ToEpoch, "toEpoch", "", 0, "only show events up to this epoch")
+	}
+	indexerJobCmd.Flags().StringVarP(&JobId, "jobId", "", "", "ID of the job")
+	indexerStakerCmd.Flags().StringVarP(&Staker, "staker", "", "", "ID or address of the staker")
+	indexerEpochCmd.Flags().Uint32VarP(&Epoch, "epoch", "", 0, "epoch to show")
+	indexerEpochCmd.Flags().Uint32VarP(&ToEpoch, "toEpoch", "", 0, "show the epochs from --epoch up to this epoch")
+
+	jobIdErr := indexerJobCmd.MarkFlagRequired("jobId")
+	utils.CheckError("JobId error: ", jobIdErr)
+	stakerErr := indexerStakerCmd.MarkFlagRequired("staker")
+	utils.CheckError("Staker error: ", stakerErr)
+	epochErr := indexerEpochCmd.MarkFlagRequired("epoch")
+	utils.CheckError("Epoch error: ", epochErr)
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"lumino/core/types"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests parsing a staker given by ID or address
+func TestParseStaker(t *testing.T) {
+	stakerId, address, err := parseStaker("4")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(4), stakerId)
+	assert.Equal(t, common.Address{}, address)
+
+	stakerId, address, err = parseStaker("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771")
+	assert.NoError(t, err)
+	assert.Zero(t, stakerId)
+	assert.Equal(t, common.HexToAddress("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771"), address)
+
+	for _, value := range []string{"0", "-1", "0x123", "staker"} {
+		_, _, err = parseStaker(value)
+		assert.EqualError(t, err, `invalid staker "`+value+`", expected a staker ID or an address`)
+	}
+}
+
+// Tests parsing the --kind filter case-insensitively
+func TestParseIndexedEventKinds(t *testing.T) {
+	kinds, err := parseIndexedEventKinds([]string{"jobcreated", "StakeUpdated"})
+	assert.NoError(t, err)
+	assert.Equal(t, []types.IndexedEventKind{types.IndexedJobCreated, types.IndexedStakeUpdated}, kinds)
+
+	kinds, err = parseIndexedEventKinds(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, kinds)
+
+	_, err = parseIndexedEventKinds([]string{"Transfer"})
+	assert.EqualError(t, err, `unknown event "Transfer"`)
+}
+
+// Tests rendering the cursor and indexed events as tables and as JSON
+func TestRenderIndexedEvents(t *testing.T) {
+	blockId := uint32(2)
+	proposer := common.HexToAddress("0x3").Hex()
+	events := append(testIndexedEvents(), types.IndexedEvent{Kind: types.IndexedBlockProposed, BlockNumber: 14, Epoch: 5, BlockID: &blockId, Proposer: proposer})
+	events[5].Assignee = events[3].Assignee
+	events[4].Staker = events[0].Staker
+
+	var out bytes.Buffer
+	assert.NoError(t, renderIndexedEvents(&out, outputFormatTable, events))
+	assert.Regexp(t, `\| +13 +\| +- +\| +5 +\| +JobStatusUpdated +\| +7 +\| +- +\| +Completed by `+events[3].Assignee+` +\|`, out.String())
+	assert.Regexp(t, `\| +12 +\| +- +\| +4 +\| +StakeUpdated +\| +- +\| +4 `+events[0].Staker+` +\| +stake 500 +\|`, out.String())
+	assert.Regexp(t, `\| +BlockProposed +\| +- +\| +- +\| +block 2 by `+proposer+` +\|`, out.String())
+
+	out.Reset()
+	assert.NoError(t, renderIndexedEvents(&out, outputFormatJSON, events[:1]))
+	var decoded []types.IndexedEvent
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, events[:1], decoded)
+
+	out.Reset()
+	assert.NoError(t, renderIndexedEvents(&out, outputFormatJSON, nil))
+	assert.Equal(t, "[]\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, renderIndexedEvents(&out, outputFormatTable, nil))
+	assert.Equal(t, "No events\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, renderIndexerCursor(&out, outputFormatTable, types.IndexerCursor{StartBlock: 5, BlockNumber: 14, NumEvents: 7}))
+	assert.Regexp(t, `\| +5 +\| +14 +\| +0x0+ +\| +7 +\| +- +\|`, out.String())
+}
@@ -11,6 +11,7 @@ import (
 	Accounts "lumino/accounts"
 	"lumino/core/types"
 	"lumino/path"
+	pipeline_zen "lumino/pipeline-zen"
 	"lumino/pkg/bindings"
 	"math/big"
 	"os"
@@ -34,6 +35,7 @@ var cmdUtils UtilsCmdInterface
 var stateManagerUtils StateManagerInterface
 var stakeManagerUtils StakeManagerInterface
 var jobsManagerUtils JobsManagerInterface
+var blockManagerUtils BlockManagerInterface
 var transactionUtils TransactionInterface
 var abiUtils AbiInterface
 var keystoreUtils KeystoreInterface
@@ -41,6 +43,13 @@ var cryptoUtils CryptoInterface
 var viperUtils ViperInterface
 var timeUtils TimeInterface
 var osUtils OSInterface
+var jobJournalUtils JobJournalInterface
+var assignmentRoundUtils AssignmentRoundInterface
+var jobEventUtils JobEventInterface
+var roleEventUtils RoleEventInterface
+var eventIndexUtils EventIndexInterface
+var resultsUtils ResultsInterface
+var preflightUtils PreflightInterface
 
 // Primary interface for utility functions used throughout the system.
 // Provides core functionality for blockchain interaction, transaction management,
@@ -88,6 +97,20 @@ type FlagSetInterface interface {
 	GetRootStringLogLevel() (string, error)
 	GetRootFloat32GasLimit() (float32, error)
 	GetRootInt64RPCTimeout() (int64, error)
+	GetStringAssignmentStrategy(flagSet *pflag.FlagSet) (string, error)
+	GetRootStringAssignmentStrategy() (string, error)
+	GetInt64ShutdownTimeout(flagSet *pflag.FlagSet) (int64, error)
+	GetRootInt64ShutdownTimeout() (int64, error)
+	GetStringBlockManagerAddress(flagSet *pflag.FlagSet) (string, error)
+	GetRootStringBlockManagerAddress() (string, error)
+	GetStringResultsStore(flagSet *pflag.FlagSet) (string, error)
+	GetRootStringResultsStore() (string, error)
+	GetInt64DatasetCacheSize(flagSet *pflag.FlagSet) (int64, error)
+	GetRootInt64DatasetCacheSize() (int64, error)
+	GetStringDatasetRoot(flagSet *pflag.FlagSet) (string, error)
+	GetRootStringDatasetRoot() (string, error)
+	GetStringWorkflowsFile(flagSet *pflag.FlagSet) (string, error)
+	GetRootStringWorkflowsFile() (string, error)
 	GetStringAddress(flagSet *pflag.FlagSet) (string, error)
 	GetStringValue(flagSet *pflag.FlagSet) (string, error)
 	GetBoolWeiLumino(flagSet *pflag.FlagSet) (bool, error)
@@ -112,6 +135,7 @@ type StakeManagerInterface interface {
 	Withdraw(client *ethclient.Client, opts *bind.TransactOpts, stakerId uint32) (*Types.Transaction, error)
 	GetNumStakers(client *ethclient.Client, opts *bind.CallOpts) (uint32, error)
 	GetStakerStructFromId(client *ethclient.Client, opts *bind.CallOpts, stakerId uint32) (types.StakerContract, error)
+	GetMinStake(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error)
 }
 
 // Interface for managing job-related operations on the blockchain.
@@ -124,6 +148,26 @@ type JobsManagerInterface interface {
 	GetJobForStaker(client *ethclient.Client, opts *bind.CallOpts, stakerAddress common.Address) (*big.Int, error)
 	GetJobStatus(client *ethclient.Client, opts *bind.CallOpts, jobId *big.Int) (uint8, error)
 	GetJobDetails(client *ethclient.Client, opts *bind.CallOpts, jobId *big.Int) (types.JobContract, error)
+	GetJobIdCounter(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error)
+	HasRole(client *ethclient.Client, opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error)
+	GetRoleAdmin(client *ethclient.Client, opts *bind.CallOpts, role [32]byte) ([32]byte, error)
+	GrantRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*Types.Transaction, error)
+	RevokeRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*Types.Transaction, error)
+	RenounceRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*Types.Transaction, error)
+}
+
+// Interface for proposing and confirming blocks on the BlockManager.
+// A block lists the jobs concluded in an epoch; the proposed blocks are
+// ranked on chain and the winning one is confirmed after the epoch.
+type BlockManagerInterface interface {
+	Propose(client *ethclient.Client, opts *bind.TransactOpts, epoch uint32, jobIds []*big.Int) (*Types.Transaction, error)
+	ConfirmBlock(client *ethclient.Client, opts *bind.TransactOpts, epoch uint32) (*Types.Transaction, error)
+	GetNumProposedBlocks(client *ethclient.Client, opts *bind.CallOpts, epoch uint32) (*big.Int, error)
+	GetProposedBlock(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, blockId uint32) (bindings.StructsBlock, error)
+	GetConfirmedBlock(client *ethclient.Client, opts *bind.CallOpts, epoch uint32) (bindings.StructsBlock, error)
+	GetSortedProposedBlockId(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, index *big.Int) (uint32, error)
+	GetBlockIndexToBeConfirmed(client *ethclient.Client, opts *bind.CallOpts) (int8, error)
+	GetMaxBlocksPerEpochPerStaker(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error)
 }
 
 type TransactionInterface interface {
@@ -139,6 +183,13 @@ type UtilsCmdInterface interface {
 	GetLogLevel() (string, error)
 	GetGasLimit() (float32, error)
 	GetRPCTimeout() (int64, error)
+	GetAssignmentStrategy() (string, error)
+	GetShutdownTimeout() (int64, error)
+	GetBlockManagerAddress() (string, error)
+	GetResultsStore() (string, error)
+	GetDatasetCacheSize() (int64, error)
+	GetDatasetRoot() (string, error)
+	GetWorkflowsFile() (string, error)
 	GetEpochAndState(client *ethclient.Client) (uint32, int64, error)
 	GetConfigData() (types.Configurations, error)
 	GetRPCProvider() (string, error)
@@ -158,6 +209,32 @@ type UtilsCmdInterface interface {
 	Withdraw(client *ethclient.Client, txnOpts *bind.TransactOpts, stakerId uint32) (common.Hash, error)
 	RunExecuteJob(flagSet *pflag.FlagSet)
 	ExecuteCreateJob(flagSet *pflag.FlagSet)
+	ExecuteListJobTemplates()
+	ExecuteShowJobTemplate(name string)
+	ExecuteJobLogs(flagSet *pflag.FlagSet)
+	ExecuteJobStatus(flagSet *pflag.FlagSet)
+	ExecuteJobShow(flagSet *pflag.FlagSet)
+	ExecuteJobList(flagSet *pflag.FlagSet)
+	ExecuteIndexerSync(flagSet *pflag.FlagSet)
+	ExecuteIndexerStatus(flagSet *pflag.FlagSet)
+	ExecuteIndexerJob(flagSet *pflag.FlagSet)
+	ExecuteIndexerStaker(flagSet *pflag.FlagSet)
+	ExecuteIndexerEpoch(flagSet *pflag.FlagSet)
+	ExecuteFetchResults(flagSet *pflag.FlagSet)
+	ExecuteVerifyAttestation(flagSet *pflag.FlagSet)
+	ExecuteProposeBlock(flagSet *pflag.FlagSet)
+	ExecuteConfirmBlock(flagSet *pflag.FlagSet)
+	ExecuteBlocksProposed(flagSet *pflag.FlagSet)
+	ExecuteBlocksConfirmed(flagSet *pflag.FlagSet)
+	ExecuteBlocksSorted(flagSet *pflag.FlagSet)
+	ExecuteGrantRole(flagSet *pflag.FlagSet)
+	ExecuteRevokeRole(flagSet *pflag.FlagSet)
+	ExecuteRenounceRole(flagSet *pflag.FlagSet)
+	ExecuteListRoles(flagSet *pflag.FlagSet)
+	UpdateRole(client *ethclient.Client, config types.Configurations, account types.Account, action types.RoleAction, role common.Hash, target common.Address) (common.Hash, error)
+	HasAdminRole(client *ethclient.Client, address string) (bool, error)
+	ProposeBlock(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, jobIds []*big.Int) (common.Hash, error)
+	ConfirmBlock(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32) (common.Hash, error)
 	ExecuteJob(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, isAdmin bool, isRandom bool, pipelinePath string) error
 	CreateJob(client *ethclient.Client, config types.Configurations, account types.Account, jobDetailsJSON string, jobFee *big.Int) (common.Hash, error)
 	UpdateJobStatus(client *ethclient.Client, config types.Configurations, account types.Account, jobId *big.Int, status types.JobStatus, buffer uint8) (common.Hash, error)
@@ -167,6 +244,8 @@ type UtilsCmdInterface interface {
 	HandleAssignState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, isRandom bool) error
 	HandleUpdateState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, pipelinePath string) error
 	HandleConfirmState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, pipelinePath string) error
+	RecoverJobExecution(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, pipelinePath string) error
+	GetActiveStakers(client *ethclient.Client, epoch uint32) ([]types.StakerContract, error)
 }
 
 type KeystoreInterface interface {
@@ -200,6 +279,68 @@ type OSInterface interface {
 	Open(name string) (*os.File, error)
 	ReadFile(path string) ([]byte, error)
 	WriteFile(name string, content []byte, perm fs.FileMode) error
+	Rename(oldpath string, newpath string) error
+	ReadDir(name string) ([]fs.DirEntry, error)
+	RemoveAll(path string) error
+}
+
+// Interface for the persistent job execution journal.
+// Records every lifecycle transition of a job so that the executor can
+// recover in-flight jobs after a restart.
+type JobJournalInterface interface {
+	RecordStage(record types.JobJournalRecord, event types.JobJournalEvent) error
+	GetRecords() ([]types.JobJournalRecord, error)
+	RemoveRecord(jobId string) error
+}
+
+// Interface for the persistent assignment round of an admin node.
+// Tracks the jobs assigned or pending in the current epoch so that every
+// job is submitted at most once per epoch.
+type AssignmentRoundInterface interface {
+	GetRound() (types.AssignmentRound, error)
+	SaveRound(round types.AssignmentRound) error
+}
+
+// Interface for reading JobManager events from the chain.
+// Lets the executor loop react to new, assigned and updated jobs instead of
+// querying every job on each tick.
+type JobEventInterface interface {
+	GetLatestBlockNumber(client *ethclient.Client) (uint64, error)
+	FilterJobEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.JobEvent, error)
+}
+
+// Interface for reading the role changes of the JobManager from the chain.
+// The admin roles commands rebuild the current role holders from them.
+type RoleEventInterface interface {
+	FilterRoleEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.RoleEvent, error)
+}
+
+// Interface for reading the events stored by the indexer from the chain.
+// Also locates blocks by number and epoch so that the indexer can detect reorgs
+// and the block proposer can scan the events of an epoch.
+type EventIndexInterface interface {
+	GetEpochStartBlock(client *ethclient.Client, epoch uint32) (uint64, error)
+	GetBlockHash(client *ethclient.Client, blockNumber uint64) (common.Hash, error)
+	FilterIndexedEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.IndexedEvent, error)
+}
+
+// Interface for delivering job results to their creators.
+// Executors publish the results archive of a completed job with its manifest
+// and signed attestation to a results store, from which the creator fetches and verifies them.
+type ResultsInterface interface {
+	PackageJobResults(manifest types.ResultManifest, resultsDir string, withArchive bool) (types.ResultManifest, error)
+	SaveResultAttestation(attestation types.ResultAttestation) error
+	PublishJobResults(location string, manifest types.ResultManifest) error
+	FetchJobResults(location string, creator common.Address, jobId *big.Int, outputDir string) (types.ResultManifest, error)
+}
+
+// Interface for inspecting the resources of the node.
+// The executor checks them before accepting a job so that a job the node
+// cannot run is refused instead of failing once it is Running.
+type PreflightInterface interface {
+	GetFreeDiskSpace(dir string) (uint64, error)
+	GetMemory() (total uint64, available uint64, err error)
+	CheckPipelineEnvironment(runtime pipeline_zen.Runtime, pipelinePath string) error
 }
 
 type Utils struct{}
@@ -208,6 +349,7 @@ type UtilsStruct struct{}
 type StateManagerUtils struct{}
 type StakeManagerUtils struct{}
 type JobsManagerUtils struct{}
+type BlockManagerUtils struct{}
 type TransactionUtils struct{}
 type KeystoreUtils struct{}
 type CryptoUtils struct{}
@@ -215,6 +357,13 @@ type ViperUtils struct{}
 type TimeUtils struct{}
 type AbiUtils struct{}
 type OSUtils struct{}
+type JobJournalUtils struct{}
+type AssignmentRoundUtils struct{}
+type JobEventUtils struct{}
+type RoleEventUtils struct{}
+type EventIndexUtils struct{}
+type ResultsUtils struct{}
+type PreflightUtils struct{}
 
 // Initializes all interface implementations with their concrete types.
 // This is the central point for dependency injection and system setup.
@@ -225,6 +374,7 @@ func InitializeInterfaces() {
 	stateManagerUtils = &StateManagerUtils{}
 	stakeManagerUtils = &StakeManagerUtils{}
 	jobsManagerUtils = &JobsManagerUtils{}
+	blockManagerUtils = &BlockManagerUtils{}
 	transactionUtils = TransactionUtils{}
 	keystoreUtils = KeystoreUtils{}
 	cryptoUtils = CryptoUtils{}
@@ -232,6 +382,13 @@ func InitializeInterfaces() {
 	abiUtils = AbiUtils{}
 	timeUtils = TimeUtils{}
 	osUtils = OSUtils{}
+	jobJournalUtils = JobJournalUtils{}
+	assignmentRoundUtils = AssignmentRoundUtils{}
+	jobEventUtils = JobEventUtils{}
+	roleEventUtils = RoleEventUtils{}
+	eventIndexUtils = EventIndexUtils{}
+	resultsUtils = ResultsUtils{}
+	preflightUtils = PreflightUtils{}
 
 	Accounts.AccountUtilsInterface = Accounts.AccountUtils{}
 	path.PathUtilsInterface = path.PathUtils{}
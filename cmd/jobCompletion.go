@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pipeline_zen "lumino/pipeline-zen"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// jobCompletionFile holds the outcome of a job and its reason inside the job directory
+	jobCompletionFile = "completion.json"
+	// jobRunnerExitFile holds how the pipeline runner of a job exited inside the job directory
+	jobRunnerExitFile = "exit.json"
+)
+
+// jobCompletionSignals are the observations the outcome of a job is decided from
+type jobCompletionSignals struct {
+	Exit          *types.JobRunnerExit // nil while the runner runs or when it was not supervised by this node
+	Finished      bool                 // the workflow marked its end
+	FailureMarker string               // name of the failure marker found in the results directory
+	FailureReason string               // content of the failure marker
+	HasResults    bool                 // the results directory holds a file other than the markers
+	MetricsError  string               // why the metrics file is unusable or reports a failure
+	LastHeartbeat time.Time            // latest output, progress or results write, zero if unknown
+	RunningEpochs uint32               // epochs since the Running status landed
+}
+
+// decideJobOutcome draws the outcome of a job from its signals. This function:
+// 1. Fails a job whose pipeline wrote a failure marker or whose runner exited unsuccessfully
+// 2. Completes a finished job with usable metrics and results, and fails it otherwise
+// 3. Fails an unfinished job running longer than MaxJobRuntimeEpochs
+// 4. Considers an unfinished job without a heartbeat for JobHeartbeatTimeout stalled
+// Returns the outcome with its reason, or an empty outcome while the job is still running.
+func decideJobOutcome(signals jobCompletionSignals, now time.Time) (types.JobOutcome, string) {
+	switch {
+	case signals.FailureMarker != "":
+		reason := "pipeline wrote " + signals.FailureMarker
+		if signals.FailureReason != "" {
+			reason += ": " + signals.FailureReason
+		}
+		return types.JobOutcomeFailed, reason
+	case signals.Exit != nil && !signals.Exit.Succeeded():
+		return types.JobOutcomeFailed, "runner " + signals.Exit.Reason
+	case signals.Exit != nil || signals.Finished:
+		if signals.MetricsError != "" {
+			return types.JobOutcomeFailed, signals.MetricsError
+		}
+		if !signals.HasResults {
+			return types.JobOutcomeFailed, "pipeline finished without writing any results"
+		}
+		return types.JobOutcomeCompleted, "pipeline finished and wrote its results"
+	case core.MaxJobRuntimeEpochs > 0 && signals.RunningEpochs > core.MaxJobRuntimeEpochs:
+		return types.JobOutcomeFailed, fmt.Sprintf("running for %d epochs, exceeding the maximum runtime of %d epochs",
+			signals.RunningEpochs, core.MaxJobRuntimeEpochs)
+	case core.JobHeartbeatTimeout > 0 && !signals.LastHeartbeat.IsZero():
+		timeout := time.Duration(core.JobHeartbeatTimeout) * time.Second
+		if silence := now.Sub(signals.LastHeartbeat); silence > timeout {
+			return types.JobOutcomeStalled, fmt.Sprintf("no heartbeat for %s, last at %s",
+				silence.Round(time.Second), signals.LastHeartbeat.UTC().Format(time.RFC3339))
+		}
+	}
+	return "", ""
+}
+
+// gatherJobCompletionSignals observes a job through its job directory, the pipeline-zen
+// results directory and the chain. The markers and metrics in the results directory are
+// read by the workflow of the job. A clean exit recorded only in the local status of the
+// job counts as a successful runner exit. The heartbeat of a job is the latest write to
+// its logs, progress.json or the root of its results directory, and its start otherwise.
+func gatherJobCompletionSignals(job types.JobExecution, executionEpoch uint32, epoch uint32, resultsDir string) jobCompletionSignals {
+	var signals jobCompletionSignals
+	heartbeat := func(modTime time.Time) {
+		if modTime.After(signals.LastHeartbeat) {
+			signals.LastHeartbeat = modTime
+		}
+	}
+	heartbeat(job.StartTime)
+
+	workflow := defaultJobWorkflow()
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(job.JobID.String())
+	if err != nil {
+		log.WithError(err).WithField("jobId", job.JobID.String()).Warn("Failed to get job directory, deciding the outcome from the results only")
+	} else {
+		workflow = jobWorkflow(job.JobID.String(), filepath.Join(jobDirPath, "config.json"))
+		if exit, err := readJobRunnerExit(jobDirPath); err == nil {
+			signals.Exit = &exit
+		} else if !errors.Is(err, os.ErrNotExist) {
+			log.WithError(err).WithField("jobId", job.JobID.String()).Warn("Failed to read runner exit")
+		}
+		for _, name := range []string{jobStdoutLogFile, jobStderrLogFile, jobProgressFile} {
+			if info, err := path.OSUtilsInterface.Stat(filepath.Join(jobDirPath, name)); err == nil {
+				heartbeat(info.ModTime())
+			}
+		}
+	}
+	if signals.Exit == nil && job.Status == types.JobStatusCompleted {
+		signals.Exit = &types.JobRunnerExit{}
+	}
+
+	state := workflow.DetectCompletion(resultsDir)
+	signals.Finished = state.Finished
+	signals.FailureMarker = state.FailureMarker
+	signals.FailureReason = state.FailureReason
+	signals.HasResults = state.HasResults
+	signals.MetricsError = state.MetricsError
+	if entries, err := os.ReadDir(resultsDir); err == nil {
+		for _, entry := range entries {
+			if info, err := entry.Info(); err == nil {
+				heartbeat(info.ModTime())
+			}
+		}
+	}
+
+	if executionEpoch > 0 && epoch > executionEpoch {
+		signals.RunningEpochs = epoch - executionEpoch
+	}
+	return signals
+}
+
+// jobWorkflow returns the workflow selected by the job config at configPath. A job whose
+// config cannot be read or names an unknown workflow is observed as a torchtunewrapper job.
+func jobWorkflow(jobId string, configPath string) pipeline_zen.Workflow {
+	jobConfig, err := readJobConfig(configPath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.WithError(err).WithField("jobId", jobId).Warn("Failed to read job config, observing the job as a torchtunewrapper job")
+		}
+		return defaultJobWorkflow()
+	}
+	workflow, err := pipeline_zen.WorkflowFor(jobConfig.Workflow)
+	if err != nil {
+		log.WithError(err).WithField("jobId", jobId).Warn("Observing the job as a torchtunewrapper job")
+		return defaultJobWorkflow()
+	}
+	return workflow
+}
+
+// defaultJobWorkflow returns the workflow of the job configs that do not select one
+func defaultJobWorkflow() pipeline_zen.Workflow {
+	workflow, _ := pipeline_zen.WorkflowFor(pipeline_zen.DefaultWorkflow)
+	return workflow
+}
+
+// saveJobCompletion writes the outcome of a job to completion.json in its job directory
+func saveJobCompletion(completion types.JobCompletion) error {
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(completion.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job directory: %w", err)
+	}
+	data, err := json.MarshalIndent(completion, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job completion: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(jobDirPath, jobCompletionFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to save job completion: %w", err)
+	}
+	return nil
+}
+
+// readJobCompletion reads the outcome of a job saved by saveJobCompletion.
+// Returns an error wrapping os.ErrNotExist if no outcome was decided yet.
+func readJobCompletion(jobId string) (types.JobCompletion, error) {
+	var completion types.JobCompletion
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(jobId)
+	if err != nil {
+		return completion, fmt.Errorf("failed to get job directory: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(jobDirPath, jobCompletionFile))
+	if err != nil {
+		return completion, err
+	}
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return completion, fmt.Errorf("failed to parse %s: %w", jobCompletionFile, err)
+	}
+	return completion, nil
+}
+
+// saveJobRunnerExit writes how the runner of a job exited to exit.json in the job directory
+func saveJobRunnerExit(jobDirPath string, exit types.JobRunnerExit) error {
+	data, err := json.MarshalIndent(exit, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal runner exit: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(jobDirPath, jobRunnerExitFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to save runner exit: %w", err)
+	}
+	return nil
+}
+
+// readJobRunnerExit reads the runner exit saved by saveJobRunnerExit.
+// Returns an error wrapping os.ErrNotExist if the runner has not exited.
+func readJobRunnerExit(jobDirPath string) (types.JobRunnerExit, error) {
+	var exit types.JobRunnerExit
+	data, err := os.ReadFile(filepath.Join(jobDirPath, jobRunnerExitFile))
+	if err != nil {
+		return exit, err
+	}
+	if err := json.Unmarshal(data, &exit); err != nil {
+		return exit, fmt.Errorf("failed to parse %s: %w", jobRunnerExitFile, err)
+	}
+	return exit, nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests deciding the outcome of a job with cases:
+// 1. Failure markers and unsuccessful runner exits fail the job, before anything else
+// 2. A finished job completes only with usable metrics and results
+// 3. An unfinished job fails past the maximum runtime and stalls without a heartbeat
+// 4. An unfinished job with a recent heartbeat is still running
+func TestDecideJobOutcome(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Minute)
+	silent := now.Add(-time.Duration(core.JobHeartbeatTimeout+60) * time.Second)
+
+	tests := []struct {
+		name        string
+		signals     jobCompletionSignals
+		wantOutcome types.JobOutcome
+		wantReason  string
+	}{
+		{
+			name:        "failure marker",
+			signals:     jobCompletionSignals{Finished: true, HasResults: true, FailureMarker: ".failed", FailureReason: "CUDA out of memory"},
+			wantOutcome: types.JobOutcomeFailed,
+			wantReason:  "pipeline wrote .failed: CUDA out of memory",
+		},
+		{
+			name:        "empty failure marker",
+			signals:     jobCompletionSignals{FailureMarker: ".error", LastHeartbeat: recent},
+			wantOutcome: types.JobOutcomeFailed,
+			wantReason:  "pipeline wrote .error",
+		},
+		{
+			name:        "runner exited non-zero",
+			signals:     jobCompletionSignals{Exit: &types.JobRunnerExit{ExitCode: 2, Reason: "exited with code 2"}, Finished: true, HasResults: true},
+			wantOutcome: types.JobOutcomeFailed,
+			wantReason:  "runner exited with code 2",
+		},
+		{
+			name:        "finished with results",
+			signals:     jobCompletionSignals{Finished: true, HasResults: true, LastHeartbeat: silent},
+			wantOutcome: types.JobOutcomeCompleted,
+			wantReason:  "pipeline finished and wrote its results",
+		},
+		{
+			name:        "runner exited cleanly with results",
+			signals:     jobCompletionSignals{Exit: &types.JobRunnerExit{}, HasResults: true},
+			wantOutcome: types.JobOutcomeCompleted,
+			wantReason:  "pipeline finished and wrote its results",
+		},
+		{
+			name:        "finished without results",
+			signals:     jobCompletionSignals{Finished: true},
+			wantOutcome: types.JobOutcomeFailed,
+			wantReason:  "pipeline finished without writing any results",
+		},
+		{
+			name:        "finished with failed metrics",
+			signals:     jobCompletionSignals{Finished: true, HasResults: true, MetricsError: `pipeline reported status "failed" in metrics.json`},
+			wantOutcome: types.JobOutcomeFailed,
+			wantReason:  `pipeline reported status "failed" in metrics.json`,
+		},
+		{
+			name:        "maximum runtime exceeded",
+			signals:     jobCompletionSignals{LastHeartbeat: recent, RunningEpochs: core.MaxJobRuntimeEpochs + 1},
+			wantOutcome: types.JobOutcomeFailed,
+			wantReason:  "exceeding the maximum runtime",
+		},
+		{
+			name:        "no heartbeat",
+			signals:     jobCompletionSignals{LastHeartbeat: silent},
+			wantOutcome: types.JobOutcomeStalled,
+			wantReason:  "no heartbeat for 1h1m0s, last at 2026-10-01T10:59:00Z",
+		},
+		{
+			name:    "recent heartbeat",
+			signals: jobCompletionSignals{LastHeartbeat: recent, RunningEpochs: core.MaxJobRuntimeEpochs},
+		},
+		{
+			name:    "nothing known yet",
+			signals: jobCompletionSignals{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, reason := decideJobOutcome(tt.signals, now)
+			assert.Equal(t, tt.wantOutcome, outcome)
+			assert.Contains(t, reason, tt.wantReason)
+		})
+	}
+}
+
+// Tests observing a job through its job and results directories with cases:
+// 1. The runner exit, markers, results and metrics are read
+// 2. The heartbeat is the latest write to the logs, progress or results
+// 3. A clean exit known only locally counts as a successful runner exit
+// 4. The runtime is counted from the epoch the Running status landed in
+func TestGatherJobCompletionSignals(t *testing.T) {
+	jobDir := t.TempDir()
+	resultsDir := t.TempDir()
+	pathMock := new(pathMocks.PathInterface)
+	originalPathUtils := path.PathUtilsInterface
+	originalOSUtils := path.OSUtilsInterface
+	defer func() {
+		path.PathUtilsInterface = originalPathUtils
+		path.OSUtilsInterface = originalOSUtils
+	}()
+	path.PathUtilsInterface = pathMock
+	path.OSUtilsInterface = path.OSUtils{}
+	pathMock.On("GetJobDirPath", "7").Return(jobDir, nil)
+
+	startTime := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+	job := types.JobExecution{JobID: big.NewInt(7), Status: types.JobStatusRunning, StartTime: startTime}
+
+	signals := gatherJobCompletionSignals(job, 10, 12, resultsDir)
+	assert.Equal(t, jobCompletionSignals{LastHeartbeat: startTime, RunningEpochs: 2}, signals)
+
+	stdoutTime := startTime.Add(time.Hour)
+	assert.NoError(t, os.WriteFile(filepath.Join(jobDir, jobStdoutLogFile), []byte("step 1\n"), 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(jobDir, jobStdoutLogFile), stdoutTime, stdoutTime))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".started"), nil, 0644))
+	assert.NoError(t, os.Chtimes(filepath.Join(resultsDir, ".started"), startTime, startTime))
+	signals = gatherJobCompletionSignals(job, 0, 12, resultsDir)
+	assert.Equal(t, stdoutTime, signals.LastHeartbeat)
+	assert.False(t, signals.HasResults)
+	assert.Zero(t, signals.RunningEpochs)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(resultsDir, "checkpoints"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "checkpoints", "adapter.safetensors"), []byte("weights"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "metrics.json"), []byte(`{"status": "failed"}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".finished"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".error"), []byte("dataset not found\n"), 0644))
+	assert.NoError(t, saveJobRunnerExit(jobDir, types.JobRunnerExit{ExitCode: 1, Reason: "exited with code 1"}))
+	signals = gatherJobCompletionSignals(job, 10, 12, resultsDir)
+	assert.Equal(t, &types.JobRunnerExit{ExitCode: 1, Reason: "exited with code 1"}, signals.Exit)
+	assert.True(t, signals.Finished)
+	assert.True(t, signals.HasResults)
+	assert.Equal(t, ".error", signals.FailureMarker)
+	assert.Equal(t, "dataset not found", signals.FailureReason)
+	assert.Equal(t, `pipeline reported status "failed" in metrics.json`, signals.MetricsError)
+	assert.True(t, signals.LastHeartbeat.After(stdoutTime))
+
+	assert.NoError(t, os.Remove(filepath.Join(jobDir, jobRunnerExitFile)))
+	job.Status = types.JobStatusCompleted
+	signals = gatherJobCompletionSignals(job, 10, 12, resultsDir)
+	assert.Equal(t, &types.JobRunnerExit{}, signals.Exit)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"lumino/core/types"
+	"lumino/path"
+	"lumino/storage"
+	"net/url"
+	pathPackage "path"
+	"path/filepath"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jobDatasetDir is the directory of a job holding the local copy of its dataset
+const jobDatasetDir = "dataset"
+
+var (
+	datasetCacheMutex sync.Mutex
+	// datasetCache is the cache of the datasets prefetched for jobs, shared by the jobs of the executor
+	datasetCache *storage.DatasetCache
+)
+
+// getDatasetCache returns the dataset cache under the lumino directory, opened on first use
+// with the size of the datasetCacheSize setting. It only reads file:// datasets under the
+// datasetRoot setting and refuses hosts of private networks.
+// Returns error if the cache directory cannot be created.
+func getDatasetCache(config types.Configurations) (*storage.DatasetCache, error) {
+	datasetCacheMutex.Lock()
+	defer datasetCacheMutex.Unlock()
+	if datasetCache == nil {
+		cacheDir, err := path.PathUtilsInterface.GetDatasetCacheDirPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get dataset cache directory: %w", err)
+		}
+		datasetCache = storage.NewDatasetCache(cacheDir, config.DatasetCacheSize*1024*1024, storage.URIPolicy{FileRoot: config.DatasetRoot})
+	}
+	return datasetCache, nil
+}
+
+// prefetchJobDataset makes the dataset of a job available locally before the job is set to
+// Running, so that a dataset that cannot be fetched fails the job before it starts. This function:
+// 1. Skips jobs without a dataset and datasets other than file://, http(s):// and s3:// URIs,
+// such as gs:// datasets, which the pipeline downloads itself
+// 2. Fetches the dataset through the dataset cache, downloading it only if it is not cached
+// 3. Links the cached dataset into the dataset directory of the job
+// Returns the path of the local copy, "" if the dataset is not prefetched, or error if the
+// dataset cannot be fetched.
+func prefetchJobDataset(ctx context.Context, config types.Configurations, jobId string, jobDir string, datasetId string) (string, error) {
+	if datasetId == "" || !storage.CanOpenURI(datasetId) {
+		return "", nil
+	}
+
+	cache, err := getDatasetCache(config)
+	if err != nil {
+		return "", err
+	}
+	entry, cached, err := cache.Fetch(ctx, datasetId)
+	if err != nil {
+		return "", err
+	}
+
+	datasetPath := filepath.Join(jobDir, jobDatasetDir, datasetFileName(datasetId))
+	if err := cache.Link(entry, datasetPath); err != nil {
+		return "", err
+	}
+	log.WithFields(logrus.Fields{
+		"jobId":   jobId,
+		"dataset": datasetId,
+		"digest":  entry.Digest,
+		"size":    entry.Size,
+		"cached":  cached,
+	}).Info("Prefetched job dataset")
+	return datasetPath, nil
+}
+
+// removeJobDataset removes the local copy of the dataset of a concluded job. The copy is a hard
+// link to the cached content, which keeps the content on disk after the cache evicted it until
+// the link is removed too.
+func removeJobDataset(jobId string) {
+	jobDir, err := path.PathUtilsInterface.GetJobDirPath(jobId)
+	if err == nil {
+		err = path.OSUtilsInterface.RemoveAll(filepath.Join(jobDir, jobDatasetDir))
+	}
+	if err != nil {
+		log.WithError(err).WithField("jobId", jobId).Warn("Failed to remove the dataset of a concluded job")
+	}
+}
+
+// datasetFileName returns the file name of the local copy of a dataset, the last element of its URI
+func datasetFileName(datasetId string) string {
+	name := "dataset"
+	if u, err := url.Parse(datasetId); err == nil {
+		if base := pathPackage.Base(u.Path); base != "." && base != "/" {
+			name = base
+		}
+	}
+	return name
+}
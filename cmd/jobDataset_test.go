@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests prefetching job datasets with cases:
+// 1. A file:// dataset is cached and linked into the job directory
+// 2. The dataset of a second job is served from the cache
+// 3. Jobs without a dataset and gs:// datasets are left to the pipeline
+// 4. A missing dataset fails the prefetch
+// 5. A file:// dataset outside the dataset root is refused
+func TestPrefetchJobDataset(t *testing.T) {
+	datasetDir := t.TempDir()
+	datasetFile := filepath.Join(datasetDir, "text2sql.jsonl")
+	assert.NoError(t, os.WriteFile(datasetFile, []byte(`{"question": "q", "answer": "a"}`+"\n"), 0644))
+	cacheDir := t.TempDir()
+	outsideFile := filepath.Join(t.TempDir(), "secret.key")
+	assert.NoError(t, os.WriteFile(outsideFile, []byte("secret"), 0600))
+
+	originalPathUtils := path.PathUtilsInterface
+	defer func() {
+		path.PathUtilsInterface = originalPathUtils
+		datasetCache = nil
+	}()
+	pathMock := new(pathMocks.PathInterface)
+	pathMock.On("GetDatasetCacheDirPath").Return(cacheDir, nil)
+	path.PathUtilsInterface = pathMock
+	datasetCache = nil
+
+	tests := []struct {
+		name      string
+		datasetId string
+		wantPath  bool
+		wantErr   string
+	}{
+		{name: "file dataset", datasetId: "file://" + filepath.ToSlash(datasetFile), wantPath: true},
+		{name: "cached dataset", datasetId: "file://" + filepath.ToSlash(datasetFile), wantPath: true},
+		{name: "no dataset"},
+		{name: "gs dataset", datasetId: "gs://bucket/text2sqljsonl"},
+		{name: "missing dataset", datasetId: "file://" + filepath.ToSlash(filepath.Join(datasetDir, "missing.jsonl")), wantErr: "object not found"},
+		{name: "dataset outside the dataset root", datasetId: "file://" + filepath.ToSlash(outsideFile), wantErr: "is outside the dataset root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobDir := t.TempDir()
+			datasetPath, err := prefetchJobDataset(context.Background(), types.Configurations{DatasetCacheSize: 1, DatasetRoot: datasetDir}, "7", jobDir, tt.datasetId)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			if !tt.wantPath {
+				assert.Empty(t, datasetPath)
+				return
+			}
+			assert.Equal(t, filepath.Join(jobDir, "dataset", "text2sql.jsonl"), datasetPath)
+			data, err := os.ReadFile(datasetPath)
+			assert.NoError(t, err)
+			assert.Equal(t, `{"question": "q", "answer": "a"}`+"\n", string(data))
+		})
+	}
+
+	entries, err := os.ReadDir(filepath.Join(cacheDir, "sha256"))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+// Tests that removing the dataset of a concluded job frees the disk space of a dataset the
+// cache evicted while the hard link of the job kept it on disk
+func TestRemoveJobDataset(t *testing.T) {
+	datasetDir := t.TempDir()
+	const datasetSize = 600 << 10
+	for _, name := range []string{"a", "b"} {
+		data := bytes.Repeat([]byte(name), datasetSize)
+		assert.NoError(t, os.WriteFile(filepath.Join(datasetDir, name+".jsonl"), data, 0644))
+	}
+	cacheDir := t.TempDir()
+	jobsDir := t.TempDir()
+
+	originalPathUtils := path.PathUtilsInterface
+	originalOSUtils := path.OSUtilsInterface
+	defer func() {
+		path.PathUtilsInterface = originalPathUtils
+		path.OSUtilsInterface = originalOSUtils
+		datasetCache = nil
+	}()
+	pathMock := new(pathMocks.PathInterface)
+	pathMock.On("GetDatasetCacheDirPath").Return(cacheDir, nil)
+	pathMock.On("GetJobDirPath", "7").Return(filepath.Join(jobsDir, "7"), nil)
+	path.PathUtilsInterface = pathMock
+	path.OSUtilsInterface = path.OSUtils{}
+	datasetCache = nil
+
+	// The cache is kept under 1 MiB, so fetching the dataset of job 8 evicts the one of job 7
+	config := types.Configurations{DatasetCacheSize: 1, DatasetRoot: datasetDir}
+	ctx := context.Background()
+	_, err := prefetchJobDataset(ctx, config, "7", filepath.Join(jobsDir, "7"), "file://"+filepath.ToSlash(filepath.Join(datasetDir, "a.jsonl")))
+	assert.NoError(t, err)
+	_, err = prefetchJobDataset(ctx, config, "8", filepath.Join(jobsDir, "8"), "file://"+filepath.ToSlash(filepath.Join(datasetDir, "b.jsonl")))
+	assert.NoError(t, err)
+	// The evicted dataset of job 7 is still on disk next to the one of job 8 and the cache index
+	usage := diskUsage(t, cacheDir, jobsDir)
+	assert.GreaterOrEqual(t, usage, int64(2*datasetSize))
+
+	removeJobDataset("7")
+	assert.NoDirExists(t, filepath.Join(jobsDir, "7", jobDatasetDir))
+	assert.FileExists(t, filepath.Join(jobsDir, "8", jobDatasetDir, "b.jsonl"))
+	assert.Equal(t, usage-datasetSize, diskUsage(t, cacheDir, jobsDir))
+}
+
+// diskUsage returns the size of the regular files under dirs, counting hard-linked files once
+func diskUsage(t *testing.T, dirs ...string) int64 {
+	t.Helper()
+	var files []os.FileInfo
+	var usage int64
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil || !entry.Type().IsRegular() {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				if os.SameFile(file, info) {
+					return nil
+				}
+			}
+			files = append(files, info)
+			usage += info.Size()
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+	return usage
+}
@@ -0,0 +1,239 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// GetLatestBlockNumber returns the number of the latest block
+func (JobEventUtils) GetLatestBlockNumber(client *ethclient.Client) (uint64, error) {
+	header, err := utils.ClientInterface.HeaderByNumber(client, context.Background(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if header == nil || header.Number == nil {
+		return 0, errors.New("latest block header is empty")
+	}
+	return header.Number.Uint64(), nil
+}
+
+// FilterJobEvents returns the JobCreated, JobAssigned and JobStatusUpdated events
+// emitted by the JobManager between fromBlock and toBlock inclusive, in log order.
+// Returns error if the logs cannot be fetched or decoded.
+func (JobEventUtils) FilterJobEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.JobEvent, error) {
+	jobManagerABI, err := bindings.JobManagerMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JobManager ABI: %w", err)
+	}
+	createdID := jobManagerABI.Events[string(types.JobEventCreated)].ID
+	assignedID := jobManagerABI.Events[string(types.JobEventAssigned)].ID
+	statusUpdatedID := jobManagerABI.Events[string(types.JobEventStatusUpdated)].ID
+
+	jobManagerAddress := common.HexToAddress(core.JobManagerAddress)
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{jobManagerAddress},
+		Topics:    [][]common.Hash{{createdID, assignedID, statusUpdatedID}},
+	}
+	logs, err := utils.ClientInterface.FilterLogs(client, context.Background(), query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter JobManager logs: %w", err)
+	}
+
+	filterer, err := bindings.NewJobManagerFilterer(jobManagerAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JobManager filterer: %w", err)
+	}
+
+	events := make([]types.JobEvent, 0, len(logs))
+	for _, vLog := range logs {
+		if vLog.Removed || len(vLog.Topics) == 0 {
+			continue
+		}
+		event := types.JobEvent{
+			BlockNumber: vLog.BlockNumber,
+			TxHash:      vLog.TxHash,
+			LogIndex:    vLog.Index,
+		}
+		switch vLog.Topics[0] {
+		case createdID:
+			created, err := filterer.ParseJobCreated(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JobCreated: %w", err)
+			}
+			event.Kind = types.JobEventCreated
+			event.JobID = created.JobId
+			event.Creator = created.Creator
+			event.Epoch = created.Epoch
+		case assignedID:
+			assigned, err := filterer.ParseJobAssigned(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JobAssigned: %w", err)
+			}
+			event.Kind = types.JobEventAssigned
+			event.JobID = assigned.JobId
+			event.Assignee = assigned.AssigneeAddress
+		case statusUpdatedID:
+			updated, err := filterer.ParseJobStatusUpdated(vLog)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode JobStatusUpdated: %w", err)
+			}
+			event.Kind = types.JobEventStatusUpdated
+			event.JobID = updated.JobId
+			event.Status = types.JobStatus(updated.NewStatus)
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// jobEventWatcher decides from JobManager events when the executor loop has to query
+// the chain for jobs. The Assign and Update handlers only run once an event announced
+// work for them; Confirm and the other states run on every tick as before.
+// The watcher falls back to running every handler on every tick while events cannot
+// be fetched, and forces a full resync every JobEventResyncInterval to cover missed events.
+type jobEventWatcher struct {
+	staker        common.Address
+	cursor        uint64 // last block scanned for events, 0 before the first refresh
+	epoch         uint32
+	pendingAssign bool
+	pendingUpdate bool
+	polling       bool // events unavailable, every tick runs every handler
+	lastResync    time.Time
+}
+
+// newJobEventWatcher returns a watcher for the staker. Both handlers start pending so
+// that the jobs created and assigned before startup are picked up.
+func newJobEventWatcher(staker common.Address) *jobEventWatcher {
+	return &jobEventWatcher{
+		staker:        staker,
+		pendingAssign: true,
+		pendingUpdate: true,
+		lastResync:    time.Now(),
+	}
+}
+
+// Refresh scans the blocks since the last refresh for JobManager events. This function:
+// 1. Marks assignment pending on a new epoch, which starts a new assignment round
+// 2. Marks both handlers pending once the resync interval has passed
+// 3. Reads events up to the latest block in chunks of EventBlockRange blocks
+// 4. Marks the handlers the events concern as pending
+// On errors the cursor stays put, so the missed blocks are scanned again on the next refresh.
+func (w *jobEventWatcher) Refresh(client *ethclient.Client, epoch uint32) {
+	if epoch != w.epoch {
+		w.epoch = epoch
+		w.pendingAssign = true
+	}
+	if time.Since(w.lastResync) >= time.Duration(core.JobEventResyncInterval)*time.Second {
+		log.Debug("Resyncing jobs with the chain")
+		w.pendingAssign = true
+		w.pendingUpdate = true
+		w.lastResync = time.Now()
+	}
+
+	latest, err := jobEventUtils.GetLatestBlockNumber(client)
+	if err != nil {
+		w.fallBackToPolling(err)
+		return
+	}
+	if w.cursor == 0 {
+		// Jobs from before startup are covered by the pending handlers
+		w.cursor = latest
+	}
+
+	for from := w.cursor + 1; from <= latest; from += core.EventBlockRange {
+		to := min(from+core.EventBlockRange-1, latest)
+		events, err := jobEventUtils.FilterJobEvents(client, from, to)
+		if err != nil {
+			w.fallBackToPolling(err)
+			return
+		}
+		for _, event := range events {
+			w.apply(event)
+		}
+		w.cursor = to
+	}
+
+	if w.polling {
+		log.WithField("block", w.cursor).Info("JobManager events available again, leaving polling mode")
+		w.polling = false
+	}
+}
+
+// fallBackToPolling switches to running every handler on every tick until events can be read again
+func (w *jobEventWatcher) fallBackToPolling(err error) {
+	if !w.polling {
+		log.WithError(err).Warn("Failed to read JobManager events, falling back to polling")
+	}
+	w.polling = true
+	w.pendingAssign = true
+	w.pendingUpdate = true
+}
+
+// apply marks the handlers concerned by an event as pending
+func (w *jobEventWatcher) apply(event types.JobEvent) {
+	log.WithFields(logrus.Fields{
+		"event":  event.Kind,
+		"jobId":  event.JobID,
+		"block":  event.BlockNumber,
+		"txHash": event.TxHash.Hex(),
+	}).Debug("Received JobManager event")
+
+	switch event.Kind {
+	case types.JobEventCreated:
+		w.pendingAssign = true
+	case types.JobEventAssigned:
+		if event.Assignee == w.staker {
+			log.WithField("jobId", event.JobID).Info("Job assigned to this node")
+			w.pendingUpdate = true
+		}
+	case types.JobEventStatusUpdated:
+		// A queued job may be ready to start and a concluded job may have freed GPU slots
+		if event.Status == types.JobStatusQueued || event.Status == types.JobStatusCompleted || event.Status == types.JobStatusFailed {
+			w.pendingUpdate = true
+		}
+	}
+}
+
+// ShouldHandle reports whether the handler of state has work to do
+func (w *jobEventWatcher) ShouldHandle(state types.EpochState, isAdmin bool) bool {
+	switch state {
+	case types.EpochStateAssign:
+		return !isAdmin || w.pendingAssign
+	case types.EpochStateUpdate:
+		return w.pendingUpdate
+	default:
+		return true
+	}
+}
+
+// Handled clears the pending flag of state once its handler succeeded. A failed
+// handler stays pending and runs again on the next tick, and so does the Update handler
+// while assigned jobs are left Queued or their Running transaction is in flight.
+func (w *jobEventWatcher) Handled(state types.EpochState, err error) {
+	if err != nil {
+		return
+	}
+	switch state {
+	case types.EpochStateAssign:
+		w.pendingAssign = false
+	case types.EpochStateUpdate:
+		w.pendingUpdate = hasJobsAwaitingStart()
+	}
+}
@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	mocks2 "lumino/utils/mocks"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests decoding JobManager logs into job events:
+// 1. JobCreated, JobAssigned and JobStatusUpdated are decoded in log order
+// 2. Logs removed by a reorg are skipped
+func TestFilterJobEvents(t *testing.T) {
+	var client *ethclient.Client
+
+	jobManagerABI, err := bindings.JobManagerMetaData.GetAbi()
+	assert.NoError(t, err)
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	assignee := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	jobTopic := common.BigToHash(big.NewInt(7))
+
+	createdData, err := jobManagerABI.Events["JobCreated"].Inputs.NonIndexed().Pack(uint32(3))
+	assert.NoError(t, err)
+	statusData, err := jobManagerABI.Events["JobStatusUpdated"].Inputs.NonIndexed().Pack(uint8(types.JobStatusQueued))
+	assert.NoError(t, err)
+
+	logs := []Types.Log{
+		{
+			Topics:      []common.Hash{jobManagerABI.Events["JobCreated"].ID, jobTopic, common.BytesToHash(creator.Bytes())},
+			Data:        createdData,
+			BlockNumber: 10,
+			Index:       0,
+		},
+		{
+			Topics:      []common.Hash{jobManagerABI.Events["JobAssigned"].ID, jobTopic, common.BytesToHash(assignee.Bytes())},
+			BlockNumber: 11,
+			Index:       1,
+			Removed:     true,
+		},
+		{
+			Topics:      []common.Hash{jobManagerABI.Events["JobAssigned"].ID, jobTopic, common.BytesToHash(assignee.Bytes())},
+			BlockNumber: 12,
+			Index:       0,
+		},
+		{
+			Topics:      []common.Hash{jobManagerABI.Events["JobStatusUpdated"].ID, jobTopic},
+			Data:        statusData,
+			BlockNumber: 12,
+			Index:       1,
+		},
+	}
+
+	clientMock := new(mocks2.ClientUtils)
+	originalClientInterface := utils.ClientInterface
+	defer func() {
+		utils.ClientInterface = originalClientInterface
+	}()
+	utils.ClientInterface = clientMock
+
+	clientMock.On("FilterLogs", mock.Anything, mock.Anything, mock.MatchedBy(func(query ethereum.FilterQuery) bool {
+		return query.FromBlock.Uint64() == 10 && query.ToBlock.Uint64() == 12 &&
+			query.Addresses[0] == common.HexToAddress(core.JobManagerAddress) && len(query.Topics[0]) == 3
+	})).Return(logs, nil)
+
+	events, err := JobEventUtils{}.FilterJobEvents(client, 10, 12)
+	assert.NoError(t, err)
+	assert.Equal(t, []types.JobEvent{
+		{Kind: types.JobEventCreated, JobID: big.NewInt(7), Creator: creator, Epoch: 3, BlockNumber: 10},
+		{Kind: types.JobEventAssigned, JobID: big.NewInt(7), Assignee: assignee, BlockNumber: 12},
+		{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(7), Status: types.JobStatusQueued, BlockNumber: 12, LogIndex: 1},
+	}, events)
+}
+
+// Tests how the event watcher schedules the Assign and Update handlers with cases:
+// 1. Blocks without events leave both handlers idle
+// 2. A job created on chain schedules assignment
+// 3. A job assigned to this node schedules the update handler, one assigned elsewhere does not
+// 4. Queued and concluded jobs schedule the update handler, running jobs do not
+// 5. A new epoch schedules assignment
+// 6. Large block ranges are scanned in chunks
+// 7. Failing to read events falls back to polling and rescans the blocks later
+// 8. The resync interval schedules both handlers
+func TestJobEventWatcher(t *testing.T) {
+	var client *ethclient.Client
+	staker := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	other := common.HexToAddress("0x3000000000000000000000000000000000000003")
+
+	tests := []struct {
+		name        string
+		epoch       uint32
+		lastResync  time.Duration // age of the last resync
+		setupMocks  func(*mocks.JobEventInterface)
+		wantAssign  bool
+		wantUpdate  bool
+		wantCursor  uint64
+		wantPolling bool
+	}{
+		{
+			name:  "no events",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(105)).Return([]types.JobEvent{}, nil)
+			},
+			wantCursor: 105,
+		},
+		{
+			name:  "job created",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(105)).Return([]types.JobEvent{
+					{Kind: types.JobEventCreated, JobID: big.NewInt(1)},
+				}, nil)
+			},
+			wantAssign: true,
+			wantCursor: 105,
+		},
+		{
+			name:  "job assigned to this node",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(105)).Return([]types.JobEvent{
+					{Kind: types.JobEventAssigned, JobID: big.NewInt(1), Assignee: staker},
+				}, nil)
+			},
+			wantUpdate: true,
+			wantCursor: 105,
+		},
+		{
+			name:  "job assigned to another node",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(105)).Return([]types.JobEvent{
+					{Kind: types.JobEventAssigned, JobID: big.NewInt(1), Assignee: other},
+					{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(1), Status: types.JobStatusRunning},
+				}, nil)
+			},
+			wantCursor: 105,
+		},
+		{
+			name:  "job concluded",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(105)).Return([]types.JobEvent{
+					{Kind: types.JobEventStatusUpdated, JobID: big.NewInt(1), Status: types.JobStatusCompleted},
+				}, nil)
+			},
+			wantUpdate: true,
+			wantCursor: 105,
+		},
+		{
+			name:  "new epoch",
+			epoch: 2,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+			},
+			wantAssign: true,
+			wantCursor: 100,
+		},
+		{
+			name:  "chunked scan",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(125), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(110)).Return([]types.JobEvent{}, nil).Once()
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(111), uint64(120)).Return([]types.JobEvent{}, nil).Once()
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(121), uint64(125)).Return([]types.JobEvent{}, nil).Once()
+			},
+			wantCursor: 125,
+		},
+		{
+			name:  "events unavailable",
+			epoch: 1,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(125), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(101), uint64(110)).Return([]types.JobEvent{}, nil).Once()
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(111), uint64(120)).Return(nil, errors.New("eth_getLogs not supported")).Once()
+			},
+			wantAssign:  true,
+			wantUpdate:  true,
+			wantCursor:  110,
+			wantPolling: true,
+		},
+		{
+			name:       "resync",
+			epoch:      1,
+			lastResync: time.Hour,
+			setupMocks: func(eventMock *mocks.JobEventInterface) {
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+			},
+			wantAssign: true,
+			wantUpdate: true,
+			wantCursor: 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventMock := new(mocks.JobEventInterface)
+
+			originalJobEventUtils := jobEventUtils
+			originalBlockRange := core.EventBlockRange
+			defer func() {
+				jobEventUtils = originalJobEventUtils
+				core.EventBlockRange = originalBlockRange
+			}()
+			jobEventUtils = eventMock
+			core.EventBlockRange = 10
+
+			tt.setupMocks(eventMock)
+
+			// A watcher that has scanned up to block 100 in epoch 1 and handled everything
+			watcher := newJobEventWatcher(staker)
+			watcher.cursor = 100
+			watcher.epoch = 1
+			watcher.Handled(types.EpochStateAssign, nil)
+			watcher.Handled(types.EpochStateUpdate, nil)
+			watcher.lastResync = time.Now().Add(-tt.lastResync)
+
+			watcher.Refresh(client, tt.epoch)
+
+			assert.Equal(t, tt.wantAssign, watcher.ShouldHandle(types.EpochStateAssign, true))
+			assert.Equal(t, tt.wantUpdate, watcher.ShouldHandle(types.EpochStateUpdate, false))
+			assert.True(t, watcher.ShouldHandle(types.EpochStateConfirm, false))
+			assert.Equal(t, tt.wantCursor, watcher.cursor)
+			assert.Equal(t, tt.wantPolling, watcher.polling)
+			eventMock.AssertExpectations(t)
+		})
+	}
+}
+
+// Tests that a fresh watcher starts at the latest block with both handlers pending,
+// that a failed handler stays pending and that the Update handler stays pending while
+// jobs await their start
+func TestJobEventWatcherStartup(t *testing.T) {
+	var client *ethclient.Client
+	eventMock := new(mocks.JobEventInterface)
+
+	originalJobEventUtils := jobEventUtils
+	defer func() {
+		jobEventUtils = originalJobEventUtils
+		stateMutex.Lock()
+		executionState = types.JobExecutionState{}
+		jobsAwaitingStart = make(map[string]bool)
+		stateMutex.Unlock()
+	}()
+	jobEventUtils = eventMock
+
+	eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(5000), nil)
+
+	watcher := newJobEventWatcher(common.Address{})
+	watcher.Refresh(client, 1)
+	assert.Equal(t, uint64(5000), watcher.cursor)
+	assert.True(t, watcher.ShouldHandle(types.EpochStateAssign, true))
+	assert.True(t, watcher.ShouldHandle(types.EpochStateUpdate, false))
+
+	watcher.Handled(types.EpochStateUpdate, errors.New("rpc error"))
+	assert.True(t, watcher.ShouldHandle(types.EpochStateUpdate, false))
+	watcher.Handled(types.EpochStateUpdate, nil)
+	assert.False(t, watcher.ShouldHandle(types.EpochStateUpdate, false))
+
+	// Jobs left Queued, or whose Running transaction is in flight, keep the Update handler pending
+	setJobAwaitingStart(big.NewInt(3), true)
+	watcher.Handled(types.EpochStateUpdate, nil)
+	assert.True(t, watcher.ShouldHandle(types.EpochStateUpdate, false))
+	setJobAwaitingStart(big.NewInt(3), false)
+	trackJob(&types.JobExecution{JobID: big.NewInt(3), Status: types.JobStatusQueued})
+	watcher.Handled(types.EpochStateUpdate, nil)
+	assert.True(t, watcher.ShouldHandle(types.EpochStateUpdate, false))
+	setJobStatus(big.NewInt(3), types.JobStatusRunning)
+	watcher.Handled(types.EpochStateUpdate, nil)
+	assert.False(t, watcher.ShouldHandle(types.EpochStateUpdate, false))
+	eventMock.AssertNotCalled(t, "FilterJobEvents", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,319 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"lumino/core/types"
+	"lumino/path"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// journalMutex serialises read-modify-write cycles on the journal file
+var journalMutex sync.Mutex
+
+// readJournal loads all journal records keyed by job ID. A missing journal file
+// is treated as an empty journal.
+func readJournal(journalPath string) (map[string]types.JobJournalRecord, error) {
+	records := make(map[string]types.JobJournalRecord)
+	if _, err := path.OSUtilsInterface.Stat(journalPath); path.OSUtilsInterface.IsNotExist(err) {
+		return records, nil
+	}
+
+	data, err := path.OSUtilsInterface.ReadFile(journalPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job journal: %w", err)
+	}
+	if len(data) == 0 {
+		return records, nil
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("failed to parse job journal: %w", err)
+	}
+	return records, nil
+}
+
+// writeJournal persists the journal atomically by writing to a temporary file
+// and renaming it over the existing journal, so a crash mid-write never leaves
+// a truncated journal behind.
+func writeJournal(journalPath string, records map[string]types.JobJournalRecord) error {
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job journal: %w", err)
+	}
+	tmpPath := journalPath + ".tmp"
+	if err := path.OSUtilsInterface.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write job journal: %w", err)
+	}
+	if err := path.OSUtilsInterface.Rename(tmpPath, journalPath); err != nil {
+		return fmt.Errorf("failed to replace job journal: %w", err)
+	}
+	return nil
+}
+
+// RecordStage appends a lifecycle event to the journal record of a job, creating
+// the record if needed. Non-empty fields of the passed record overwrite the
+// stored values so callers only need to pass what they know at that stage.
+func (JobJournalUtils) RecordStage(record types.JobJournalRecord, event types.JobJournalEvent) error {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+
+	journalPath, err := path.PathUtilsInterface.GetJobJournalFilePath()
+	if err != nil {
+		return err
+	}
+	records, err := readJournal(journalPath)
+	if err != nil {
+		return err
+	}
+
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	stored, ok := records[record.JobID]
+	if !ok {
+		stored = types.JobJournalRecord{JobID: record.JobID, StartTime: event.Timestamp}
+	}
+	if record.Creator != "" {
+		stored.Creator = record.Creator
+	}
+	if record.Executor != "" {
+		stored.Executor = record.Executor
+	}
+	if record.ConfigPath != "" {
+		stored.ConfigPath = record.ConfigPath
+	}
+	if record.PipelinePath != "" {
+		stored.PipelinePath = record.PipelinePath
+	}
+	if len(record.GPUs) > 0 {
+		stored.GPUs = record.GPUs
+	}
+	if record.FinalStatus != 0 {
+		stored.FinalStatus = record.FinalStatus
+	}
+	stored.Stage = event.Stage
+	stored.UpdatedAt = event.Timestamp
+	stored.History = append(stored.History, event)
+	records[record.JobID] = stored
+
+	return writeJournal(journalPath, records)
+}
+
+// GetRecords returns all journal records ordered by job ID
+func (JobJournalUtils) GetRecords() ([]types.JobJournalRecord, error) {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+
+	journalPath, err := path.PathUtilsInterface.GetJobJournalFilePath()
+	if err != nil {
+		return nil, err
+	}
+	records, err := readJournal(journalPath)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.JobJournalRecord, 0, len(records))
+	for _, record := range records {
+		result = append(result, record)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].JobID < result[j].JobID
+	})
+	return result, nil
+}
+
+// RemoveRecord deletes the journal record of a job
+func (JobJournalUtils) RemoveRecord(jobId string) error {
+	journalMutex.Lock()
+	defer journalMutex.Unlock()
+
+	journalPath, err := path.PathUtilsInterface.GetJobJournalFilePath()
+	if err != nil {
+		return err
+	}
+	records, err := readJournal(journalPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := records[jobId]; !ok {
+		return nil
+	}
+	delete(records, jobId)
+	return writeJournal(journalPath, records)
+}
+
+// recordJobStage journals a lifecycle transition of a job. Journal failures are
+// logged rather than returned so that they never block on-chain progress.
+func recordJobStage(record types.JobJournalRecord, event types.JobJournalEvent) {
+	if err := jobJournalUtils.RecordStage(record, event); err != nil {
+		log.WithError(err).WithFields(logrus.Fields{
+			"jobId": record.JobID,
+			"stage": event.Stage,
+		}).Warn("Failed to record job stage in journal")
+	}
+}
+
+// RecoverJobExecution reconciles the local job journal with the chain and the
+// result markers of the job workflows after a restart of the executor. For every job
+// that has not yet been concluded it:
+// 1. Drops the record if the chain already holds a final status or the job is no longer ours.
+// A job reported Completed whose results were not delivered before the restart has its delivery resumed
+// A job whose status or details cannot be read is skipped, leaving its record for the next restart
+// 2. Re-attaches to a running or finished pipeline so HandleConfirmState can report it,
+// re-reserving the GPUs the job was pinned to
+// 3. Resumes the pipeline if the Running transaction landed but the pipeline never started
+// 4. Restores a job that failed, or whose outcome was decided as failed or stalled, so
+// its Failed status gets reported
+// Returns error if the journal cannot be read.
+func (*UtilsStruct) RecoverJobExecution(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, pipelinePath string) error {
+	records, err := jobJournalUtils.GetRecords()
+	if err != nil {
+		return fmt.Errorf("failed to read job journal: %w", err)
+	}
+	if len(records) == 0 {
+		log.Debug("No journaled jobs to recover")
+		return nil
+	}
+
+	opts := protoUtils.GetOptions()
+
+	for _, record := range records {
+		if record.IsConcluded() {
+			removeJobDataset(record.JobID)
+			if record.FinalStatus == types.JobStatusCompleted && resultsAwaitingDelivery(config, record.JobID) {
+				log.WithField("jobId", record.JobID).Info("Resuming delivery of the results of a completed job")
+				resumeResultDelivery(config, account, record, pipelinePath)
+				continue
+			}
+			if err := jobJournalUtils.RemoveRecord(record.JobID); err != nil {
+				log.WithError(err).WithField("jobId", record.JobID).Warn("Failed to prune concluded job from journal")
+			}
+			continue
+		}
+
+		jobId, ok := new(big.Int).SetString(record.JobID, 10)
+		if !ok {
+			log.WithField("jobId", record.JobID).Warn("Skipping journal record with invalid job ID")
+			continue
+		}
+
+		status, err := jobsManagerUtils.GetJobStatus(client, &opts, jobId)
+		if err != nil {
+			log.WithError(err).WithField("jobId", record.JobID).Error("Failed to get job status, recovering it on the next restart")
+			continue
+		}
+
+		logFields := logrus.Fields{
+			"jobId":         record.JobID,
+			"journalStage":  record.Stage,
+			"onChainStatus": status,
+		}
+
+		switch types.JobStatus(status) {
+		case types.JobStatusCompleted, types.JobStatusFailed:
+			log.WithFields(logFields).Info("Job already concluded on chain, closing journal record")
+			recordJobStage(types.JobJournalRecord{JobID: record.JobID, FinalStatus: types.JobStatus(status)},
+				types.JobJournalEvent{Stage: types.JobStageStatusReported})
+			removeJobDataset(record.JobID)
+			continue
+		case types.JobStatusRunning:
+		default:
+			// The Running transaction never landed, HandleUpdateState picks the job up again
+			log.WithFields(logFields).Info("Job not running on chain, discarding journal record")
+			if err := jobJournalUtils.RemoveRecord(record.JobID); err != nil {
+				log.WithError(err).WithField("jobId", record.JobID).Warn("Failed to remove job from journal")
+			}
+			continue
+		}
+
+		jobDetails, err := jobsManagerUtils.GetJobDetails(client, &opts, jobId)
+		if err != nil {
+			log.WithError(err).WithField("jobId", record.JobID).Error("Failed to get job details, recovering it on the next restart")
+			continue
+		}
+		if jobDetails.Assignee != common.HexToAddress(account.Address) {
+			log.WithFields(logFields).Warn("Journaled job is no longer assigned to this staker, discarding journal record")
+			if err := jobJournalUtils.RemoveRecord(record.JobID); err != nil {
+				log.WithError(err).WithField("jobId", record.JobID).Warn("Failed to remove job from journal")
+			}
+			continue
+		}
+
+		// Hold on to the GPUs the job was pinned to before the restart
+		gpus := gpuAllocator.Reserve(record.JobID, record.GPUs)
+		recoveredJob := &types.JobExecution{
+			JobID:     jobId,
+			Status:    types.JobStatusRunning,
+			StartTime: record.StartTime,
+			Executor:  account.Address,
+			GPUs:      gpus,
+		}
+
+		completion, completionErr := readJobCompletion(record.JobID)
+		if record.Stage == types.JobStagePipelineFailed || (completionErr == nil && completion.Outcome.Status() == types.JobStatusFailed) {
+			log.WithFields(logFields).Info("Recovered failed job, failure will be reported in confirm state")
+			recoveredJob.Status = types.JobStatusFailed
+		} else {
+			resultsPath := filepath.Join(pipelinePath, ".results", record.Creator, record.JobID)
+			state := jobWorkflow(record.JobID, record.ConfigPath).DetectCompletion(resultsPath)
+
+			switch {
+			case state.Finished:
+				log.WithFields(logFields).Info("Recovered finished job, completion will be reported in confirm state")
+			case state.Started:
+				log.WithFields(logFields).Info("Re-attaching to running pipeline")
+			default:
+				log.WithFields(logFields).Info("Pipeline never started, resuming job")
+				trackJob(recoveredJob)
+				configPath := record.ConfigPath
+				jobShutdown.Go(func() {
+					runJobPipeline(jobShutdown.JobContext(), client, config, account, jobId, configPath, pipelinePath, gpus)
+				})
+				continue
+			}
+		}
+
+		trackJob(recoveredJob)
+	}
+
+	return nil
+}
+
+// resumeResultDelivery queues the delivery of the results of a job reported Completed before a
+// restart. The job completed when its runner exited, or else when its outcome was decided.
+func resumeResultDelivery(config types.Configurations, account types.Account, record types.JobJournalRecord, pipelinePath string) {
+	jobId, ok := new(big.Int).SetString(record.JobID, 10)
+	if !ok {
+		return
+	}
+	completedAt := record.UpdatedAt
+	if completion, err := readJobCompletion(record.JobID); err == nil {
+		completedAt = completion.DecidedAt
+	}
+	if jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(record.JobID); err == nil {
+		if exit, err := readJobRunnerExit(jobDirPath); err == nil {
+			completedAt = exit.ExitedAt
+		}
+	}
+	publishJobResults(&pendingResultPublish{
+		config:       config,
+		account:      account,
+		job:          types.JobExecution{JobID: jobId, Status: types.JobStatusCompleted, StartTime: record.StartTime, Executor: account.Address},
+		creator:      common.HexToAddress(record.Creator),
+		completedAt:  completedAt,
+		zenPath:      filepath.Join(pipelinePath, ".results", record.Creator, record.JobID),
+		pipelinePath: pipelinePath,
+	})
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests the journal file round trip:
+// 1. Recording stages creates and updates a record
+// 2. Known fields are kept when later stages omit them
+// 3. Removing a record drops it from the journal
+func TestJobJournal(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), "job-journal.json")
+
+	pathMock := new(pathMocks.PathInterface)
+	pathMock.On("GetJobJournalFilePath").Return(journalPath, nil)
+
+	originalPathUtils := path.PathUtilsInterface
+	originalOSUtils := path.OSUtilsInterface
+	defer func() {
+		path.PathUtilsInterface = originalPathUtils
+		path.OSUtilsInterface = originalOSUtils
+	}()
+	path.PathUtilsInterface = pathMock
+	path.OSUtilsInterface = path.OSUtils{}
+
+	journal := JobJournalUtils{}
+
+	records, err := journal.GetRecords()
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	err = journal.RecordStage(types.JobJournalRecord{
+		JobID:      "7",
+		Creator:    "0x123",
+		ConfigPath: ".jobs/7/config.json",
+	}, types.JobJournalEvent{Stage: types.JobStageConfigWritten})
+	assert.NoError(t, err)
+
+	err = journal.RecordStage(types.JobJournalRecord{JobID: "7"},
+		types.JobJournalEvent{Stage: types.JobStageRunningTxSent, TxHash: "0xabc"})
+	assert.NoError(t, err)
+
+	records, err = journal.GetRecords()
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, types.JobStageRunningTxSent, records[0].Stage)
+	assert.Equal(t, "0x123", records[0].Creator)
+	assert.Equal(t, ".jobs/7/config.json", records[0].ConfigPath)
+	assert.Len(t, records[0].History, 2)
+	assert.Equal(t, "0xabc", records[0].History[1].TxHash)
+	assert.False(t, records[0].IsConcluded())
+
+	_, err = os.Stat(journalPath + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, journal.RemoveRecord("7"))
+	records, err = journal.GetRecords()
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+// Tests crash recovery of journaled jobs:
+// 1. Concluded records are pruned, unless the results of the completed job were not
+// delivered, which resumes their delivery
+// 2. Jobs concluded on chain are closed
+// 3. Jobs no longer running on chain are discarded
+// 4. Finished pipelines are re-attached for confirmation with their GPUs
+// 5. Failed pipelines, and pipelines stopped as stalled, are restored as failed
+// 6. Jobs reassigned to another staker are discarded
+// 7. A job whose status cannot be read is skipped without stopping the recovery
+// 8. Journal read errors are returned
+func TestRecoverJobExecution(t *testing.T) {
+	var client *ethclient.Client
+	var config types.Configurations
+	account := types.Account{Address: "0x000000000000000000000000000000000000dEaD"}
+	assignedToUs := types.JobContract{Assignee: common.HexToAddress(account.Address)}
+
+	pipelinePath := t.TempDir()
+	finishedResults := filepath.Join(pipelinePath, ".results", "0x123", "2")
+	assert.NoError(t, os.MkdirAll(finishedResults, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(finishedResults, ".started"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(finishedResults, ".finished"), nil, 0644))
+
+	// Job 5 was stopped as stalled by HandleConfirmState before its pipeline concluded
+	jobDirs := t.TempDir()
+	pathMock := new(pathMocks.PathInterface)
+	pathMock.On("GetJobDirPath", mock.Anything).Return(func(jobId string) (string, error) {
+		jobDir := filepath.Join(jobDirs, jobId)
+		return jobDir, os.MkdirAll(jobDir, 0755)
+	})
+	assert.NoError(t, os.MkdirAll(filepath.Join(jobDirs, "5"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(jobDirs, "5", "completion.json"),
+		[]byte(`{"job_id": "5", "outcome": "stalled", "reason": "no heartbeat for 1h0m1s"}`), 0644))
+	// Job 7 was reported Completed and its results attested before the restart
+	assert.NoError(t, os.MkdirAll(filepath.Join(jobDirs, "7"), 0755))
+	writeAttestedResults(t, filepath.Join(jobDirs, "7"), types.ResultManifest{JobID: "7"})
+
+	tests := []struct {
+		name       string
+		records    []types.JobJournalRecord
+		recordsErr error
+		setupMocks func(*mocks.JobsManagerInterface, *mocks.JobJournalInterface)
+		wantJobs   []types.JobExecution
+		wantQueued []string // jobs whose results delivery is resumed
+		wantErr    bool
+	}{
+		{
+			name:    "concluded records are pruned from the journal",
+			records: []types.JobJournalRecord{{JobID: "1", Stage: types.JobStageStatusReported}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				journalMock.On("RemoveRecord", "1").Return(nil)
+			},
+		},
+		{
+			name:    "completed job with delivered results is pruned from the journal",
+			records: []types.JobJournalRecord{{JobID: "7", Creator: "0x123", Stage: types.JobStageStatusReported, FinalStatus: types.JobStatusCompleted}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				journalMock.On("RemoveRecord", "7").Return(nil)
+			},
+		},
+		{
+			name:    "completed job whose results were not delivered resumes the delivery",
+			records: []types.JobJournalRecord{{JobID: "8", Creator: "0x123", Stage: types.JobStageStatusReported, FinalStatus: types.JobStatusCompleted}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				// The record is kept until the results are delivered
+			},
+			wantQueued: []string{"8"},
+		},
+		{
+			name:    "job already completed on chain is closed in the journal",
+			records: []types.JobJournalRecord{{JobID: "1", Stage: types.JobStagePipelineExited}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).Return(uint8(types.JobStatusCompleted), nil)
+				journalMock.On("RecordStage",
+					types.JobJournalRecord{JobID: "1", FinalStatus: types.JobStatusCompleted},
+					mock.MatchedBy(func(event types.JobJournalEvent) bool { return event.Stage == types.JobStageStatusReported }),
+				).Return(nil)
+			},
+		},
+		{
+			name:    "job still queued on chain is discarded",
+			records: []types.JobJournalRecord{{JobID: "1", Stage: types.JobStageConfigWritten}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).Return(uint8(types.JobStatusQueued), nil)
+				journalMock.On("RemoveRecord", "1").Return(nil)
+			},
+		},
+		{
+			name:    "finished pipeline is re-attached for confirmation",
+			records: []types.JobJournalRecord{{JobID: "2", Creator: "0x123", Stage: types.JobStagePipelineExited, GPUs: []int{1}}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(2)).Return(uint8(types.JobStatusRunning), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(2)).Return(assignedToUs, nil)
+			},
+			wantJobs: []types.JobExecution{{JobID: big.NewInt(2), Status: types.JobStatusRunning, Executor: account.Address, GPUs: []int{1}}},
+		},
+		{
+			name:    "failed pipeline is restored as failed",
+			records: []types.JobJournalRecord{{JobID: "3", Creator: "0x123", Stage: types.JobStagePipelineFailed}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(3)).Return(uint8(types.JobStatusRunning), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(3)).Return(assignedToUs, nil)
+			},
+			wantJobs: []types.JobExecution{{JobID: big.NewInt(3), Status: types.JobStatusFailed, Executor: account.Address, GPUs: []int{}}},
+		},
+		{
+			name:    "pipeline stopped as stalled is restored as failed",
+			records: []types.JobJournalRecord{{JobID: "5", Creator: "0x123", Stage: types.JobStagePipelineStarted}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(5)).Return(uint8(types.JobStatusRunning), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(5)).Return(assignedToUs, nil)
+			},
+			wantJobs: []types.JobExecution{{JobID: big.NewInt(5), Status: types.JobStatusFailed, Executor: account.Address, GPUs: []int{}}},
+		},
+		{
+			name:    "job reassigned to another staker is not re-attached",
+			records: []types.JobJournalRecord{{JobID: "4", Creator: "0x123", Stage: types.JobStagePipelineExited}},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(4)).Return(uint8(types.JobStatusRunning), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(4)).
+					Return(types.JobContract{Assignee: common.HexToAddress("0x456")}, nil)
+				journalMock.On("RemoveRecord", "4").Return(nil).Once()
+			},
+		},
+		{
+			name: "job whose status cannot be read does not stop the recovery of the others",
+			records: []types.JobJournalRecord{
+				{JobID: "6", Creator: "0x123", Stage: types.JobStagePipelineExited},
+				{JobID: "2", Creator: "0x123", Stage: types.JobStagePipelineExited, GPUs: []int{1}},
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(6)).Return(uint8(0), errors.New("rpc timeout"))
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(2)).Return(uint8(types.JobStatusRunning), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(2)).Return(assignedToUs, nil)
+			},
+			wantJobs: []types.JobExecution{{JobID: big.NewInt(2), Status: types.JobStatusRunning, Executor: account.Address, GPUs: []int{1}}},
+		},
+		{
+			name:       "journal read error is returned",
+			recordsErr: errors.New("corrupt journal"),
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, journalMock *mocks.JobJournalInterface) {},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateMutex.Lock()
+			executionState = types.JobExecutionState{}
+			stateMutex.Unlock()
+
+			originalGPUAllocator := gpuAllocator
+			gpuAllocator = NewGPUAllocator(2, 2)
+			defer func() { gpuAllocator = originalGPUAllocator }()
+
+			jobsMock := new(mocks.JobsManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+			journalMock := new(mocks.JobJournalInterface)
+			resultsMock := new(mocks.ResultsInterface)
+
+			originalResultsUtils := resultsUtils
+			originalJobsManagerUtils := jobsManagerUtils
+			originalProtoUtils := protoUtils
+			originalJobJournalUtils := jobJournalUtils
+			originalOSUtils := path.OSUtilsInterface
+			originalPathUtils := path.PathUtilsInterface
+			defer func() {
+				resultsUtils = originalResultsUtils
+				jobsManagerUtils = originalJobsManagerUtils
+				protoUtils = originalProtoUtils
+				jobJournalUtils = originalJobJournalUtils
+				path.OSUtilsInterface = originalOSUtils
+				path.PathUtilsInterface = originalPathUtils
+				stateMutex.Lock()
+				pendingResultPublishes = make(map[string]*pendingResultPublish)
+				stateMutex.Unlock()
+			}()
+
+			resultsUtils = resultsMock
+			jobsManagerUtils = jobsMock
+			protoUtils = utilsMock
+			jobJournalUtils = journalMock
+			path.OSUtilsInterface = path.OSUtils{}
+			path.PathUtilsInterface = pathMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			journalMock.On("GetRecords").Return(tt.records, tt.recordsErr)
+			resultsMock.On("PackageJobResults", mock.Anything, mock.Anything, false).
+				Return(types.ResultManifest{}, errors.New("results directory not found")).Maybe()
+			tt.setupMocks(jobsMock, journalMock)
+
+			utils := &UtilsStruct{}
+			err := utils.RecoverJobExecution(context.Background(), client, config, account, pipelinePath)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Eventually(t, resultPublishesIdle, time.Second, 10*time.Millisecond)
+			journalMock.AssertExpectations(t)
+
+			stateMutex.RLock()
+			queued := make([]string, 0, len(pendingResultPublishes))
+			for jobId := range pendingResultPublishes {
+				queued = append(queued, jobId)
+			}
+			stateMutex.RUnlock()
+			assert.ElementsMatch(t, tt.wantQueued, queued)
+
+			jobs := getTrackedJobs()
+			if assert.Len(t, jobs, len(tt.wantJobs)) {
+				for i, want := range tt.wantJobs {
+					assert.Equal(t, want.JobID, jobs[i].JobID)
+					assert.Equal(t, want.Status, jobs[i].Status)
+					assert.Equal(t, want.Executor, jobs[i].Executor)
+					assert.Equal(t, want.GPUs, jobs[i].GPUs)
+				}
+			}
+
+			jobsMock.AssertExpectations(t)
+			journalMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"lumino/core"
+	"lumino/path"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// jobStdoutLogFile receives the stdout of a job pipeline inside the job directory
+	jobStdoutLogFile = "stdout.log"
+	// jobStderrLogFile receives the stderr of a job pipeline inside the job directory
+	jobStderrLogFile = "stderr.log"
+)
+
+// jobLogsCmd prints the pipeline output captured for a job executed on this node.
+var jobLogsCmd = &cobra.Command{
+	Use:   "jobLogs",
+	Short: "Show the pipeline logs of a job",
+	Long: `Prints the stdout (or stderr) of a job pipeline run by executeJob on this node.
+The logs are kept in ~/.lumino/.jobs/<jobId>/ and rotated by size; only the current file is shown.
+
+Example:
+  ./lumino jobLogs --jobId 21 --tail 100 --follow
+  ./lumino jobLogs --jobId 21 --stderr`,
+	Run: initialiseJobLogs,
+}
+
+func initialiseJobLogs(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteJobLogs(cmd.Flags())
+}
+
+// ExecuteJobLogs prints the captured logs of a job. This function:
+// 1. Resolves the stdout or stderr log file of the job
+// 2. Prints the whole file or its last lines
+// 3. Keeps printing new output with --follow until interrupted
+// Exits with error if the job ID is invalid or the job has no logs.
+func (*UtilsStruct) ExecuteJobLogs(flagSet *pflag.FlagSet) {
+	jobIdStr, err := flagSet.GetString("jobId")
+	utils.CheckError("Error in getting jobId: ", err)
+	jobId, ok := new(big.Int).SetString(jobIdStr, 10)
+	if !ok || jobId.Sign() < 0 {
+		log.Fatalf("Invalid jobId %q", jobIdStr)
+	}
+
+	follow, err := flagSet.GetBool("follow")
+	utils.CheckError("Error in getting follow flag: ", err)
+	tail, err := flagSet.GetInt("tail")
+	utils.CheckError("Error in getting tail: ", err)
+	showStderr, err := flagSet.GetBool("stderr")
+	utils.CheckError("Error in getting stderr flag: ", err)
+
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(jobId.String())
+	utils.CheckError("Error in getting job directory: ", err)
+	logPath := filepath.Join(jobDirPath, jobStdoutLogFile)
+	if showStderr {
+		logPath = filepath.Join(jobDirPath, jobStderrLogFile)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), shutdownSignals...)
+	defer stop()
+
+	err = showJobLogs(ctx, os.Stdout, logPath, tail, follow)
+	utils.CheckError("Error in showing job logs: ", err)
+}
+
+// openJobLogs returns the rotating stdout and stderr log files in a job directory
+func openJobLogs(jobDirPath string) (*lumberjack.Logger, *lumberjack.Logger) {
+	return newJobLogFile(filepath.Join(jobDirPath, jobStdoutLogFile)),
+		newJobLogFile(filepath.Join(jobDirPath, jobStderrLogFile))
+}
+
+// newJobLogFile returns a log file rotated once it reaches JobLogMaxSize
+func newJobLogFile(logPath string) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   logPath,
+		MaxSize:    core.JobLogMaxSize,
+		MaxBackups: core.JobLogMaxBackups,
+	}
+}
+
+// showJobLogs writes a job log file to out. This function:
+// 1. Writes the last tail lines of the file, or the whole file if tail is negative
+// 2. With follow, keeps writing appended output until ctx is canceled
+// 3. Switches to the new file when the log is rotated while following
+// Returns error if the log file does not exist or cannot be read.
+func showJobLogs(ctx context.Context, out io.Writer, logPath string, tail int, follow bool) error {
+	file, err := os.Open(logPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no logs found at %s", logPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open job log: %w", err)
+	}
+	defer func() {
+		file.Close()
+	}()
+
+	if tail >= 0 {
+		offset, err := tailOffset(file, tail)
+		if err != nil {
+			return fmt.Errorf("failed to find the last %d lines: %w", tail, err)
+		}
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("failed to seek job log: %w", err)
+		}
+	}
+	if _, err := io.Copy(out, file); err != nil {
+		return fmt.Errorf("failed to read job log: %w", err)
+	}
+	if !follow {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(core.JobLogFollowInterval) * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if _, err := io.Copy(out, file); err != nil {
+			return fmt.Errorf("failed to read job log: %w", err)
+		}
+
+		current, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to stat job log: %w", err)
+		}
+		latest, err := os.Stat(logPath)
+		if err != nil {
+			// The file is briefly missing while it is being rotated
+			continue
+		}
+
+		if !os.SameFile(current, latest) {
+			// Rotated: drain the renamed backup before switching to the new file
+			if _, err := io.Copy(out, file); err != nil {
+				return fmt.Errorf("failed to read job log: %w", err)
+			}
+			rotated, err := os.Open(logPath)
+			if err != nil {
+				continue
+			}
+			file.Close()
+			file = rotated
+			continue
+		}
+
+		position, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return fmt.Errorf("failed to seek job log: %w", err)
+		}
+		if latest.Size() < position {
+			// Truncated in place, start over
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return fmt.Errorf("failed to seek job log: %w", err)
+			}
+		}
+	}
+}
+
+// tailOffset returns the offset at which the last n lines of file start.
+// A trailing newline ends the last line instead of starting an empty one.
+func tailOffset(file *os.File, n int) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	if n == 0 {
+		return size, nil
+	}
+
+	buf := make([]byte, 64*1024)
+	lines := 0
+	for position := size; position > 0; {
+		chunk := min(int64(len(buf)), position)
+		position -= chunk
+		if _, err := file.ReadAt(buf[:chunk], position); err != nil && err != io.EOF {
+			return 0, err
+		}
+		for i := chunk - 1; i >= 0; i-- {
+			if buf[i] != '\n' || position+i == size-1 {
+				continue
+			}
+			lines++
+			if lines == n {
+				return position + i + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// Initializes the jobLogs command in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(jobLogsCmd)
+
+	var (
+		JobId  string
+		Follow bool
+		Tail   int
+		Stderr bool
+	)
+
+	jobLogsCmd.Flags().StringVarP(&JobId, "jobId", "", "", "ID of the job")
+	jobLogsCmd.Flags().BoolVarP(&Follow, "follow", "f", false, "keep printing new output until interrupted")
+	jobLogsCmd.Flags().IntVarP(&Tail, "tail", "n", -1, "number of lines to show from the end of the log, all lines if negative")
+	jobLogsCmd.Flags().BoolVarP(&Stderr, "stderr", "", false, "show stderr.log instead of stdout.log")
+
+	jobIdErr := jobLogsCmd.MarkFlagRequired("jobId")
+	utils.CheckError("JobId error: ", jobIdErr)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"lumino/core"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests printing job logs with cases:
+// 1. The whole file without --tail
+// 2. The last lines with --tail
+// 3. Nothing with --tail 0
+// 4. The whole file when --tail exceeds its lines
+// 5. A last line without trailing newline
+// 6. A job without logs
+func TestShowJobLogs(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		noLogs    bool
+		tail      int
+		want      string
+		wantError string
+	}{
+		{
+			name:    "whole file",
+			content: "one\ntwo\nthree\n",
+			tail:    -1,
+			want:    "one\ntwo\nthree\n",
+		},
+		{
+			name:    "last lines",
+			content: "one\ntwo\nthree\n",
+			tail:    2,
+			want:    "two\nthree\n",
+		},
+		{
+			name:    "no lines",
+			content: "one\ntwo\nthree\n",
+			tail:    0,
+			want:    "",
+		},
+		{
+			name:    "tail exceeds lines",
+			content: "one\ntwo\n",
+			tail:    10,
+			want:    "one\ntwo\n",
+		},
+		{
+			name:    "no trailing newline",
+			content: "one\ntwo\nthree",
+			tail:    1,
+			want:    "three",
+		},
+		{
+			name:      "no logs",
+			noLogs:    true,
+			tail:      -1,
+			wantError: "no logs found at",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logPath := filepath.Join(t.TempDir(), jobStdoutLogFile)
+			if !tt.noLogs {
+				assert.NoError(t, os.WriteFile(logPath, []byte(tt.content), 0600))
+			}
+
+			var out bytes.Buffer
+			err := showJobLogs(context.Background(), &out, logPath, tt.tail, false)
+			if tt.wantError != "" {
+				assert.ErrorContains(t, err, tt.wantError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+// Tests that following job logs prints appended output across a rotation
+func TestShowJobLogsFollow(t *testing.T) {
+	originalInterval := core.JobLogFollowInterval
+	defer func() {
+		core.JobLogFollowInterval = originalInterval
+	}()
+	core.JobLogFollowInterval = 10
+
+	logFile := newJobLogFile(filepath.Join(t.TempDir(), jobStdoutLogFile))
+	defer logFile.Close()
+	_, err := logFile.Write([]byte("old\nbefore\n"))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- showJobLogs(ctx, out, logFile.Filename, 1, true)
+	}()
+
+	assert.Eventually(t, func() bool { return out.String() == "before\n" }, 5*time.Second, 10*time.Millisecond)
+
+	_, err = logFile.Write([]byte("appended\n"))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return out.String() == "before\nappended\n" }, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, logFile.Rotate())
+	_, err = logFile.Write([]byte("rotated\n"))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool { return out.String() == "before\nappended\nrotated\n" }, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"fmt"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pipeline_zen "lumino/pipeline-zen"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/sirupsen/logrus"
+)
+
+// failRefusedJobs reports jobs refused by the preflight checks as Failed, set by executeJob --failRefusedJobs
+var failRefusedJobs bool
+
+// GetFreeDiskSpace returns the bytes available to the executor on the filesystem of dir, or of
+// its closest existing parent if dir does not exist yet.
+// Returns error if the disk usage cannot be read.
+func (PreflightUtils) GetFreeDiskSpace(dir string) (uint64, error) {
+	for {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	usage, err := disk.Usage(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get disk usage of %s: %w", dir, err)
+	}
+	return usage.Free, nil
+}
+
+// GetMemory returns the total and the available memory of the node in bytes.
+// Returns error if the memory statistics cannot be read.
+func (PreflightUtils) GetMemory() (uint64, uint64, error) {
+	memory, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get memory: %w", err)
+	}
+	return memory.Total, memory.Available, nil
+}
+
+// CheckPipelineEnvironment checks that runtime can run workflows from the pipeline-zen directory at pipelinePath
+func (PreflightUtils) CheckPipelineEnvironment(runtime pipeline_zen.Runtime, pipelinePath string) error {
+	return runtime.CheckEnvironment(pipelinePath)
+}
+
+// preflightJob checks that the node can run a job before it is accepted. This function:
+// 1. Checks the free disk space of the lumino directory holding the job directories and of the
+// results directory of the pipeline-zen checkout against MinJobFreeDiskSpace
+// 2. Checks the requested GPUs against the GPUs of the node, so that a node without detected GPUs
+// only accepts jobs requesting none. GPUs held by running jobs are not counted against the job:
+// it fits once they are released, so it stays queued until GPU allocation succeeds instead
+// 3. Checks the total memory against the min_system_memory of the job and the available
+// memory against MinJobFreeMemory
+// 4. Checks that the runtime finds pipeline-zen and its Python environment
+// Returns every reason the node cannot run the job, nil if it can, or error if the disk space or
+// memory cannot be read, in which case the checks are retried on the next state update.
+func preflightJob(requirements types.JobRequirements, pipelinePath string) ([]string, error) {
+	var reasons []string
+
+	minFreeDiskSpace := uint64(core.MinJobFreeDiskSpace) * 1024 * 1024
+	jobsDir, err := path.PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lumino directory: %w", err)
+	}
+	for _, dir := range []string{jobsDir, filepath.Join(pipelinePath, ".results")} {
+		free, err := preflightUtils.GetFreeDiskSpace(dir)
+		if err != nil {
+			return nil, err
+		}
+		if free < minFreeDiskSpace {
+			reasons = append(reasons, fmt.Sprintf("%d MiB free in %s, job needs %d MiB", free/1024/1024, dir, core.MinJobFreeDiskSpace))
+		}
+	}
+
+	if !gpuAllocator.CanEverFit(requirements.NumGPUs) {
+		reasons = append(reasons, fmt.Sprintf("has %d GPUs, job needs %d", gpuAllocator.TotalGPUs(), requirements.NumGPUs))
+	}
+
+	total, available, err := preflightUtils.GetMemory()
+	if err != nil {
+		return nil, err
+	}
+	totalMiB := float64(total) / 1024 / 1024
+	if requirements.SystemMemoryMiB > 0 && totalMiB < requirements.SystemMemoryMiB {
+		reasons = append(reasons, fmt.Sprintf("system memory %.2f MiB is below the required %.2f MiB", totalMiB, requirements.SystemMemoryMiB))
+	}
+	if available < uint64(core.MinJobFreeMemory)*1024*1024 {
+		reasons = append(reasons, fmt.Sprintf("%d MiB memory available, job needs %d MiB", available/1024/1024, core.MinJobFreeMemory))
+	}
+
+	if err := preflightUtils.CheckPipelineEnvironment(jobRuntime, pipelinePath); err != nil {
+		reasons = append(reasons, err.Error())
+	}
+	return reasons, nil
+}
+
+// refuseJob refuses a Queued job the node failed the preflight checks of. The job stays
+// Queued, and is checked again on the next state update, unless executeJob --failRefusedJobs
+// is passed: the job is then reported Failed with its refused outcome saved, so that its
+// creator is not left waiting for a job the node will not run.
+// Returns error if the Failed status cannot be reported.
+func refuseJob(client *ethclient.Client, config types.Configurations, account types.Account, jobId *big.Int, reasons []string) error {
+	reason := strings.Join(reasons, "; ")
+	log.WithFields(logrus.Fields{
+		"jobId":   jobId.String(),
+		"reason":  reason,
+		"failJob": failRefusedJobs,
+	}).Warn("Refused job, node failed its preflight checks")
+	if !failRefusedJobs {
+		return nil
+	}
+
+	return failQueuedJob(client, config, account, jobId, types.JobCompletion{
+		JobID:   jobId.String(),
+		Outcome: types.JobOutcomeRefused,
+		Reason:  reason,
+	})
+}
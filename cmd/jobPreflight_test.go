@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests the preflight checks of jobs with cases:
+// 1. A node with enough disk space, memory and GPUs and a pipeline environment accepts the job
+// 2. Too little free disk space in the results directory refuses the job
+// 3. More GPUs than the node has refuses the job, also on nodes without detected GPUs, which
+// only accept jobs requesting none
+// 4. GPUs held by running jobs do not refuse a job that fits once they are released
+// 5. Too little total memory for min_system_memory or too little available memory refuses the job
+// 6. A missing pipeline environment refuses the job
+// 7. Every failed check is reported
+// 8. Disk space or memory that cannot be read fails the checks without refusing the job
+func TestPreflightJob(t *testing.T) {
+	luminoDir := t.TempDir()
+	pipelinePath := t.TempDir()
+	resultsDir := filepath.Join(pipelinePath, ".results")
+
+	originalPathUtils := path.PathUtilsInterface
+	originalPreflightUtils := preflightUtils
+	originalGPUAllocator := gpuAllocator
+	defer func() {
+		path.PathUtilsInterface = originalPathUtils
+		preflightUtils = originalPreflightUtils
+		gpuAllocator = originalGPUAllocator
+	}()
+
+	const gib = uint64(1) << 30
+
+	tests := []struct {
+		name         string
+		requirements types.JobRequirements
+		gpus         int
+		busyGPUs     int
+		resultsFree  uint64
+		diskErr      error
+		total        uint64
+		available    uint64
+		memoryErr    error
+		environment  error
+		wantReasons  []string
+		wantErr      string
+	}{
+		{
+			name:         "node can run the job",
+			requirements: types.JobRequirements{NumGPUs: 2, SystemMemoryMiB: 16 * 1024},
+			gpus:         2,
+			resultsFree:  100 * gib,
+			total:        32 * gib,
+			available:    16 * gib,
+		},
+		{
+			name:         "low disk space",
+			requirements: types.JobRequirements{NumGPUs: 1},
+			gpus:         1,
+			resultsFree:  gib,
+			total:        32 * gib,
+			available:    16 * gib,
+			wantReasons:  []string{"1024 MiB free in " + resultsDir + ", job needs 20480 MiB"},
+		},
+		{
+			name:         "too many GPUs",
+			requirements: types.JobRequirements{NumGPUs: 4},
+			gpus:         2,
+			resultsFree:  100 * gib,
+			total:        32 * gib,
+			available:    16 * gib,
+			wantReasons:  []string{"has 2 GPUs, job needs 4"},
+		},
+		{
+			name:         "no detected GPUs",
+			requirements: types.JobRequirements{NumGPUs: 4},
+			resultsFree:  100 * gib,
+			total:        32 * gib,
+			available:    16 * gib,
+			wantReasons:  []string{"has 0 GPUs, job needs 4"},
+		},
+		{
+			name:        "CPU job without detected GPUs",
+			resultsFree: 100 * gib,
+			total:       32 * gib,
+			available:   16 * gib,
+		},
+		{
+			name:         "GPUs held by running jobs",
+			requirements: types.JobRequirements{NumGPUs: 2},
+			gpus:         2,
+			busyGPUs:     2,
+			resultsFree:  100 * gib,
+			total:        32 * gib,
+			available:    16 * gib,
+		},
+		{
+			name:         "low memory",
+			requirements: types.JobRequirements{SystemMemoryMiB: 64 * 1024},
+			resultsFree:  100 * gib,
+			total:        32 * gib,
+			available:    gib,
+			wantReasons: []string{
+				"system memory 32768.00 MiB is below the required 65536.00 MiB",
+				"1024 MiB memory available, job needs 2048 MiB",
+			},
+		},
+		{
+			name:         "missing pipeline environment",
+			requirements: types.JobRequirements{NumGPUs: 1},
+			gpus:         1,
+			resultsFree:  100 * gib,
+			total:        32 * gib,
+			available:    16 * gib,
+			environment:  errors.New("torch is not installed for /usr/bin/python3"),
+			wantReasons:  []string{"torch is not installed for /usr/bin/python3"},
+		},
+		{
+			name:         "every failed check",
+			requirements: types.JobRequirements{NumGPUs: 4},
+			gpus:         2,
+			resultsFree:  gib,
+			total:        32 * gib,
+			available:    gib,
+			environment:  errors.New("pipeline-zen directory not found"),
+			wantReasons: []string{
+				"1024 MiB free in " + resultsDir + ", job needs 20480 MiB",
+				"has 2 GPUs, job needs 4",
+				"1024 MiB memory available, job needs 2048 MiB",
+				"pipeline-zen directory not found",
+			},
+		},
+		{
+			name:         "disk space unreadable",
+			requirements: types.JobRequirements{NumGPUs: 1},
+			gpus:         1,
+			diskErr:      errors.New("failed to get disk usage"),
+			total:        32 * gib,
+			available:    16 * gib,
+			wantErr:      "failed to get disk usage",
+		},
+		{
+			name:         "memory unreadable",
+			requirements: types.JobRequirements{NumGPUs: 1},
+			gpus:         1,
+			resultsFree:  100 * gib,
+			memoryErr:    errors.New("failed to get memory"),
+			wantErr:      "failed to get memory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathMock := new(pathMocks.PathInterface)
+			preflightMock := new(mocks.PreflightInterface)
+			path.PathUtilsInterface = pathMock
+			preflightUtils = preflightMock
+			gpuAllocator = NewGPUAllocator(tt.gpus, 2)
+			if tt.busyGPUs > 0 {
+				_, ok := gpuAllocator.Allocate("9", tt.busyGPUs)
+				assert.True(t, ok)
+			}
+
+			pathMock.On("GetDefaultPath").Return(luminoDir, nil)
+			preflightMock.On("GetFreeDiskSpace", luminoDir).Return(100*gib, nil)
+			preflightMock.On("GetFreeDiskSpace", resultsDir).Return(tt.resultsFree, tt.diskErr)
+			preflightMock.On("GetMemory").Return(tt.total, tt.available, tt.memoryErr)
+			preflightMock.On("CheckPipelineEnvironment", mock.Anything, pipelinePath).Return(tt.environment)
+
+			reasons, err := preflightJob(tt.requirements, pipelinePath)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Nil(t, reasons)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantReasons, reasons)
+		})
+	}
+}
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pipeline_zen "lumino/pipeline-zen"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// jobProgressFile holds the latest training progress inside the job directory
+const jobProgressFile = "progress.json"
+
+// jobStatusCmd shows the local execution state and training progress of a job.
+var jobStatusCmd = &cobra.Command{
+	Use:   "jobStatus",
+	Short: "Show the execution stage and training progress of a job",
+	Long: `Shows the execution stage of a job run by executeJob on this node, together with the
+latest epoch, step, loss, learning rate and throughput parsed from its pipeline output.
+Output is a table or, with --output json, JSON. The JobManager view of the job is shown by jobShow.
+
+Example:
+  ./lumino jobStatus --jobId 21
+  ./lumino jobStatus --jobId 21 --output json`,
+	Run: initialiseJobStatus,
+}
+
+func initialiseJobStatus(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteJobStatus(cmd.Flags())
+}
+
+// ExecuteJobStatus prints the local status of a job. This function:
+// 1. Looks up the execution stage of the job in the journal
+// 2. Reads the training progress from the job's progress.json
+// 3. Displays both in a table or, with --output json, as JSON
+// Exits with error if the job ID is invalid or this node holds no record of the job.
+func (*UtilsStruct) ExecuteJobStatus(flagSet *pflag.FlagSet) {
+	format, err := getOutputFormat(flagSet)
+	utils.CheckError("Error in getting output format: ", err)
+
+	jobIdStr, err := flagSet.GetString("jobId")
+	utils.CheckError("Error in getting jobId: ", err)
+	jobId, ok := new(big.Int).SetString(jobIdStr, 10)
+	if !ok || jobId.Sign() < 0 {
+		log.Fatalf("Invalid jobId %q", jobIdStr)
+	}
+
+	err = showJobStatus(os.Stdout, format, jobId.String())
+	utils.CheckError("Error in showing job status: ", err)
+}
+
+// showJobStatus writes the journal stage and training progress of a job to out as JSON or as a table.
+// Returns error if neither is available.
+func showJobStatus(out io.Writer, format string, jobId string) error {
+	status, err := getJobLocalStatus(jobId)
+	if err != nil {
+		return err
+	}
+	if format == outputFormatJSON {
+		return writeJSON(out, status)
+	}
+
+	progress := status.Progress
+	epoch := "-"
+	if progress.Epoch > 0 {
+		epoch = strconv.Itoa(progress.Epoch)
+		if progress.TotalEpochs > 0 {
+			epoch += "/" + strconv.Itoa(progress.TotalEpochs)
+		}
+	}
+	step := "-"
+	if progress.Step > 0 {
+		step = strconv.Itoa(progress.Step)
+	}
+	updated := "-"
+	if !progress.UpdatedAt.IsZero() {
+		updated = progress.UpdatedAt.Format(time.RFC3339)
+	}
+
+	outcome := "-"
+	if status.Completion != nil {
+		outcome = string(status.Completion.Outcome)
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Job ID", "Stage", "Epoch", "Step", "Loss", "LR", "Tokens/s/GPU", "Updated", "Outcome"})
+	table.Append([]string{
+		jobId,
+		// Concluded jobs are pruned from the journal on restart while their progress is kept
+		orDash(status.Stage),
+		epoch,
+		step,
+		formatProgressValue(progress.Loss),
+		formatProgressValue(progress.LearningRate),
+		formatProgressValue(progress.Throughput),
+		updated,
+		outcome,
+	})
+	table.Render()
+	if status.Completion != nil && status.Completion.Reason != "" {
+		_, err := fmt.Fprintf(out, "Reason: %s\n", status.Completion.Reason)
+		return err
+	}
+	return nil
+}
+
+// getJobLocalStatus reads the journal stage, training progress and outcome of a job.
+// Returns error if none is available.
+func getJobLocalStatus(jobId string) (types.JobLocalStatus, error) {
+	status := types.JobLocalStatus{JobID: jobId}
+	records, err := jobJournalUtils.GetRecords()
+	if err != nil {
+		return status, fmt.Errorf("failed to read job journal: %w", err)
+	}
+	for _, record := range records {
+		if record.JobID == jobId {
+			status.Stage = string(record.Stage)
+		}
+	}
+
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(jobId)
+	if err != nil {
+		return status, fmt.Errorf("failed to get job directory: %w", err)
+	}
+	if completion, err := readJobCompletion(jobId); err == nil {
+		status.Completion = &completion
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return status, err
+	}
+	status.Progress, err = readJobProgress(filepath.Join(jobDirPath, jobProgressFile))
+	if errors.Is(err, os.ErrNotExist) {
+		if status.Stage == "" && status.Completion == nil {
+			return status, fmt.Errorf("no record of job %s on this node", jobId)
+		}
+		status.Progress = types.JobProgress{JobID: jobId}
+	} else if err != nil {
+		return status, err
+	}
+	return status, nil
+}
+
+// formatProgressValue formats an optional progress value for display
+func formatProgressValue(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.FormatFloat(*value, 'g', 6, 64)
+}
+
+// jobProgressTracker is an output sink of a job pipeline that parses training
+// progress from every line and saves it to progress.json. Saves are throttled to
+// one per JobProgressSaveInterval; Flush saves the latest values.
+type jobProgressTracker struct {
+	mu           sync.Mutex
+	parser       pipeline_zen.ProgressParser
+	progressPath string
+	progress     types.JobProgress
+	buf          []byte
+	dirty        bool
+	lastSave     time.Time
+}
+
+// newJobProgressTracker returns a tracker saving to the job directory, using the
+// progress parser registered for the job's workflow.
+func newJobProgressTracker(jobDirPath string, jobId string, jobConfig types.JobConfig) *jobProgressTracker {
+	progress := types.JobProgress{
+		JobID:         jobId,
+		JobConfigName: jobConfig.JobConfigName,
+		StartedAt:     time.Now(),
+	}
+	progress.TotalEpochs, _ = strconv.Atoi(jobConfig.NumEpochs)
+	// Workflows other than torchtunewrapper have no job_config_name and register their parser by workflow
+	parserName := jobConfig.JobConfigName
+	if parserName == "" {
+		parserName = jobConfig.Workflow
+	}
+	return &jobProgressTracker{
+		parser:       pipeline_zen.ProgressParserFor(parserName),
+		progressPath: filepath.Join(jobDirPath, jobProgressFile),
+		progress:     progress,
+	}
+}
+
+// Write parses every complete line in data. Progress bars redraw with carriage
+// returns, so each carriage-return separated segment is parsed on its own.
+func (t *jobProgressTracker) Write(data []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buf = append(t.buf, data...)
+	i := strings.LastIndexByte(string(t.buf), '\n')
+	if i < 0 {
+		return len(data), nil
+	}
+	lines := string(t.buf[:i])
+	t.buf = append(t.buf[:0], t.buf[i+1:]...)
+
+	for _, line := range strings.FieldsFunc(lines, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if t.parser.ParseLine(line, &t.progress) {
+			t.dirty = true
+		}
+	}
+	if t.dirty && time.Since(t.lastSave) >= time.Duration(core.JobProgressSaveInterval)*time.Second {
+		t.save()
+	}
+	return len(data), nil
+}
+
+// Flush saves progress parsed since the last save
+func (t *jobProgressTracker) Flush() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.dirty {
+		t.save()
+	}
+}
+
+// save writes the progress, logging instead of failing the pipeline on errors
+func (t *jobProgressTracker) save() {
+	t.progress.UpdatedAt = time.Now()
+	if err := saveJobProgress(t.progressPath, t.progress); err != nil {
+		log.WithError(err).WithField("jobId", t.progress.JobID).Warn("Failed to save job progress")
+		return
+	}
+	t.dirty = false
+	t.lastSave = time.Now()
+}
+
+// saveJobProgress persists the progress of a job atomically by writing to a
+// temporary file and renaming it over progress.json.
+func saveJobProgress(progressPath string, progress types.JobProgress) error {
+	data, err := json.MarshalIndent(progress, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job progress: %w", err)
+	}
+	tmpPath := progressPath + ".tmp"
+	if err := path.OSUtilsInterface.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write job progress: %w", err)
+	}
+	if err := path.OSUtilsInterface.Rename(tmpPath, progressPath); err != nil {
+		return fmt.Errorf("failed to replace job progress: %w", err)
+	}
+	return nil
+}
+
+// readJobProgress loads the progress of a job. Returns an error wrapping
+// os.ErrNotExist if no progress was saved yet.
+func readJobProgress(progressPath string) (types.JobProgress, error) {
+	var progress types.JobProgress
+	data, err := path.OSUtilsInterface.ReadFile(progressPath)
+	if err != nil {
+		return progress, fmt.Errorf("failed to read job progress: %w", err)
+	}
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return progress, fmt.Errorf("failed to parse job progress: %w", err)
+	}
+	return progress, nil
+}
+
+// readJobConfig loads the job config written for the pipeline by HandleUpdateState
+func readJobConfig(configPath string) (types.JobConfig, error) {
+	var jobConfig types.JobConfig
+	data, err := path.OSUtilsInterface.ReadFile(configPath)
+	if err != nil {
+		return jobConfig, fmt.Errorf("failed to read job config: %w", err)
+	}
+	if err := json.Unmarshal(data, &jobConfig); err != nil {
+		return jobConfig, fmt.Errorf("failed to parse job config: %w", err)
+	}
+	return jobConfig, nil
+}
+
+// Initializes the jobStatus command in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(jobStatusCmd)
+
+	var (
+		JobId  string
+		Output string
+	)
+
+	jobStatusCmd.Flags().StringVarP(&JobId, "jobId", "", "", "ID of the job")
+	jobStatusCmd.Flags().StringVarP(&Output, "output", "o", outputFormatTable, "output format, table or json")
+
+	jobIdErr := jobStatusCmd.MarkFlagRequired("jobId")
+	utils.CheckError("JobId error: ", jobIdErr)
+}
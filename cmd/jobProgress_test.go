@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests that the progress tracker parses pipeline output into progress.json:
+// 1. Nothing is saved before any progress is parsed
+// 2. Carriage-return separated progress bar updates are parsed one by one
+// 3. Flush saves the latest values
+func TestJobProgressTracker(t *testing.T) {
+	originalOSUtils := path.OSUtilsInterface
+	originalInterval := core.JobProgressSaveInterval
+	defer func() {
+		path.OSUtilsInterface = originalOSUtils
+		core.JobProgressSaveInterval = originalInterval
+	}()
+	path.OSUtilsInterface = path.OSUtils{}
+	core.JobProgressSaveInterval = 3600
+
+	jobDirPath := t.TempDir()
+	progressPath := filepath.Join(jobDirPath, jobProgressFile)
+	tracker := newJobProgressTracker(jobDirPath, "7", types.JobConfig{JobConfigName: "llm_dummy", NumEpochs: "3"})
+
+	_, err := tracker.Write([]byte("Loading checkpoint\n"))
+	assert.NoError(t, err)
+	tracker.Flush()
+	_, err = readJobProgress(progressPath)
+	assert.Error(t, err)
+
+	// The first save happens right away, later ones wait for the save interval
+	_, err = tracker.Write([]byte("1|1|Loss: 2.5:   1%|\r1|2|Loss: 2.25:   2%|\n"))
+	assert.NoError(t, err)
+	progress, err := readJobProgress(progressPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, progress.Step)
+
+	_, err = tracker.Write([]byte("Step 3 | loss:2.0 lr:1e-05"))
+	assert.NoError(t, err)
+	_, err = tracker.Write([]byte(" tokens_per_second_per_gpu:900\n"))
+	assert.NoError(t, err)
+	progress, err = readJobProgress(progressPath)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, progress.Step)
+
+	tracker.Flush()
+	progress, err = readJobProgress(progressPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", progress.JobID)
+	assert.Equal(t, "llm_dummy", progress.JobConfigName)
+	assert.Equal(t, 1, progress.Epoch)
+	assert.Equal(t, 3, progress.TotalEpochs)
+	assert.Equal(t, 3, progress.Step)
+	assert.Equal(t, 2.0, *progress.Loss)
+	assert.Equal(t, 1e-05, *progress.LearningRate)
+	assert.Equal(t, 900.0, *progress.Throughput)
+	assert.False(t, progress.UpdatedAt.IsZero())
+}
+
+// Tests showing the status of a job with cases:
+// 1. A job with journal stage and progress
+// 2. A job that has not reported progress yet
+// 3. A pruned job whose progress is kept
+// 4. A concluded job with its outcome and reason
+// 5. A job unknown to this node
+func TestShowJobStatus(t *testing.T) {
+	loss := 0.5
+	tests := []struct {
+		name       string
+		records    []types.JobJournalRecord
+		progress   *types.JobProgress
+		completion *types.JobCompletion
+		wantRow    []string
+		wantReason string
+		wantError  string
+	}{
+		{
+			name:     "stage and progress",
+			records:  []types.JobJournalRecord{{JobID: "7", Stage: types.JobStagePipelineStarted}},
+			progress: &types.JobProgress{JobID: "7", Epoch: 1, TotalEpochs: 2, Step: 10, Loss: &loss},
+			wantRow:  []string{"7", "pipeline_started", "1/2", "10", "0.5"},
+		},
+		{
+			name:    "no progress yet",
+			records: []types.JobJournalRecord{{JobID: "7", Stage: types.JobStageRunningTxSent}},
+			wantRow: []string{"7", "running_tx_sent", "-"},
+		},
+		{
+			name:     "pruned from the journal",
+			progress: &types.JobProgress{JobID: "7", Step: 10},
+			wantRow:  []string{"7", "-", "-", "10"},
+		},
+		{
+			name:       "stalled job",
+			records:    []types.JobJournalRecord{{JobID: "7", Stage: types.JobStagePipelineFailed}},
+			progress:   &types.JobProgress{JobID: "7", Step: 10},
+			completion: &types.JobCompletion{JobID: "7", Outcome: types.JobOutcomeStalled, Reason: "no heartbeat for 1h0m1s"},
+			wantRow:    []string{"7", "pipeline_failed", "-", "10", "-", "-", "-", "-", "stalled"},
+			wantReason: "Reason: no heartbeat for 1h0m1s\n",
+		},
+		{
+			name:      "unknown job",
+			records:   []types.JobJournalRecord{{JobID: "8", Stage: types.JobStagePipelineStarted}},
+			wantError: "no record of job 7 on this node",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journalMock := new(mocks.JobJournalInterface)
+			pathMock := new(pathMocks.PathInterface)
+
+			originalJobJournalUtils := jobJournalUtils
+			originalPathUtils := path.PathUtilsInterface
+			originalOSUtils := path.OSUtilsInterface
+			defer func() {
+				jobJournalUtils = originalJobJournalUtils
+				path.PathUtilsInterface = originalPathUtils
+				path.OSUtilsInterface = originalOSUtils
+			}()
+			jobJournalUtils = journalMock
+			path.PathUtilsInterface = pathMock
+			path.OSUtilsInterface = path.OSUtils{}
+
+			jobDirPath := t.TempDir()
+			journalMock.On("GetRecords").Return(tt.records, nil)
+			pathMock.On("GetJobDirPath", "7").Return(jobDirPath, nil)
+			if tt.progress != nil {
+				assert.NoError(t, saveJobProgress(filepath.Join(jobDirPath, jobProgressFile), *tt.progress))
+			}
+			if tt.completion != nil {
+				assert.NoError(t, saveJobCompletion(*tt.completion))
+			}
+
+			var out bytes.Buffer
+			err := showJobStatus(&out, outputFormatTable, "7")
+			if tt.wantError != "" {
+				assert.EqualError(t, err, tt.wantError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Regexp(t, "\\| +"+joinTableCells(tt.wantRow)+" +\\|", out.String())
+			if tt.wantReason != "" {
+				assert.True(t, strings.HasSuffix(out.String(), tt.wantReason))
+			}
+
+			out.Reset()
+			assert.NoError(t, showJobStatus(&out, outputFormatJSON, "7"))
+			var status types.JobLocalStatus
+			assert.NoError(t, json.Unmarshal(out.Bytes(), &status))
+			assert.Equal(t, "7", status.JobID)
+			assert.Equal(t, tt.wantRow[1], orDash(status.Stage))
+			assert.Equal(t, tt.completion, status.Completion)
+		})
+	}
+}
+
+// joinTableCells returns a pattern matching consecutive cells of a rendered table row
+func joinTableCells(cells []string) string {
+	quoted := make([]string, len(cells))
+	for i, cell := range cells {
+		quoted[i] = regexp.QuoteMeta(cell)
+	}
+	return strings.Join(quoted, ` +\| +`)
+}
@@ -0,0 +1,380 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"lumino/core/types"
+	"lumino/logger"
+	pipeline_zen "lumino/pipeline-zen"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// jobShowCmd shows a job as recorded by the JobManager
+var jobShowCmd = &cobra.Command{
+	Use:   "jobShow",
+	Short: "Show the JobManager details and decoded job spec of a job",
+	Long: `Shows a job as recorded by the JobManager: its status, creator, assignee, the epoch it reached
+every status in, its fee and its decoded job spec. Output is a table or, with --output json, JSON.
+
+Example:
+  ./lumino jobShow --jobId 21
+  ./lumino jobShow --jobId 21 --output json`,
+	Run: initialiseJobShow,
+}
+
+// jobListCmd lists jobs recorded by the JobManager
+var jobListCmd = &cobra.Command{
+	Use:   "jobList",
+	Short: "List the active jobs, or the jobs created by or assigned to an address",
+	Long: `Lists the active jobs of the JobManager. With --creator or --assignee, every job is read and
+the jobs created by or assigned to the address are listed; --all lists every job. Output is a
+table or, with --output json, JSON.
+
+Example:
+  ./lumino jobList
+  ./lumino jobList --creator 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --output json
+  ./lumino jobList --assignee 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771`,
+	Run: initialiseJobList,
+}
+
+func initialiseJobShow(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteJobShow(cmd.Flags())
+}
+
+func initialiseJobList(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteJobList(cmd.Flags())
+}
+
+// ExecuteJobShow prints a job as recorded by the JobManager.
+// Exits with error if the job ID is invalid, the job does not exist or its details cannot be read.
+func (*UtilsStruct) ExecuteJobShow(flagSet *pflag.FlagSet) {
+	client, format := setupJobQueryCommand(flagSet)
+
+	jobIdStr, err := flagSet.GetString("jobId")
+	utils.CheckError("Error in getting jobId: ", err)
+	jobId, ok := new(big.Int).SetString(jobIdStr, 10)
+	if !ok || jobId.Sign() < 0 {
+		log.Fatalf("Invalid jobId %q", jobIdStr)
+	}
+
+	opts := protoUtils.GetOptions()
+	job, err := getJobInfo(client, &opts, jobId)
+	utils.CheckError("Error in getting job: ", err)
+
+	err = renderJobInfo(os.Stdout, format, job)
+	utils.CheckError("Error in showing job: ", err)
+}
+
+// ExecuteJobList lists the jobs selected by the filters. This function:
+// 1. Parses the --creator and --assignee filters
+// 2. Reads the active jobs or, with a filter or --all, every job
+// 3. Displays the matching jobs in a table or, with --output json, as JSON
+// Exits with error if a filter is not an address or the jobs cannot be read.
+func (*UtilsStruct) ExecuteJobList(flagSet *pflag.FlagSet) {
+	client, format := setupJobQueryCommand(flagSet)
+
+	var filter types.JobFilter
+	creator, err := flagSet.GetString("creator")
+	utils.CheckError("Error in getting creator: ", err)
+	filter.Creator, err = parseOptionalAddress("creator", creator)
+	utils.CheckError("Error in parsing creator: ", err)
+
+	assignee, err := flagSet.GetString("assignee")
+	utils.CheckError("Error in getting assignee: ", err)
+	filter.Assignee, err = parseOptionalAddress("assignee", assignee)
+	utils.CheckError("Error in parsing assignee: ", err)
+
+	filter.All, err = flagSet.GetBool("all")
+	utils.CheckError("Error in getting all flag: ", err)
+
+	jobs, err := listJobs(client, filter)
+	utils.CheckError("Error in listing jobs: ", err)
+
+	err = renderJobList(os.Stdout, format, jobs)
+	utils.CheckError("Error in showing jobs: ", err)
+}
+
+// setupJobQueryCommand validates the output format, loads the configuration and connects to the RPC provider.
+// Exits with error if any step fails.
+func setupJobQueryCommand(flagSet *pflag.FlagSet) (*ethclient.Client, string) {
+	format, err := getOutputFormat(flagSet)
+	utils.CheckError("Error in getting output format: ", err)
+
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("Job query: Config: %+v", config)
+
+	err = registerConfiguredWorkflows(config)
+	utils.CheckError("Error in registering workflows: ", err)
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+	logger.SetLoggerParameters(client, "")
+	return client, format
+}
+
+// parseOptionalAddress parses an address flag that may be left empty.
+// Returns error if the value is set but not a hex address.
+func parseOptionalAddress(name string, value string) (*common.Address, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if !common.IsHexAddress(value) {
+		return nil, fmt.Errorf("invalid %s address %q", name, value)
+	}
+	address := common.HexToAddress(value)
+	return &address, nil
+}
+
+// listJobs returns the jobs matching the filter in order of their job ID. This function:
+// 1. Reads the active job IDs, or every job ID up to jobIdCounter if the filter needs all jobs
+// 2. Reads the details and status of every job
+// 3. Keeps the jobs created by and assigned to the addresses of the filter
+// Returns error if the job IDs or a job cannot be read.
+func listJobs(client *ethclient.Client, filter types.JobFilter) ([]types.JobInfo, error) {
+	opts := protoUtils.GetOptions()
+
+	var jobIds []*big.Int
+	if filter.All || filter.Creator != nil || filter.Assignee != nil {
+		counter, err := jobsManagerUtils.GetJobIdCounter(client, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job ID counter: %w", err)
+		}
+		// Job IDs are read from 0 to the counter inclusive, slots never written hold no creator
+		for id := int64(0); id <= counter.Int64(); id++ {
+			jobIds = append(jobIds, big.NewInt(id))
+		}
+	} else {
+		activeJobs, err := jobsManagerUtils.GetActiveJobs(client, &opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get active jobs: %w", err)
+		}
+		jobIds = append(jobIds, activeJobs...)
+		sort.Slice(jobIds, func(i, j int) bool { return jobIds[i].Cmp(jobIds[j]) < 0 })
+	}
+
+	jobs := make([]types.JobInfo, 0, len(jobIds))
+	for _, jobId := range jobIds {
+		details, err := jobsManagerUtils.GetJobDetails(client, &opts, jobId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get details of job %s: %w", jobId, err)
+		}
+		if details.Creator == (common.Address{}) {
+			continue
+		}
+		if filter.Creator != nil && details.Creator != *filter.Creator {
+			continue
+		}
+		if filter.Assignee != nil && details.Assignee != *filter.Assignee {
+			continue
+		}
+		status, err := jobsManagerUtils.GetJobStatus(client, &opts, jobId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get status of job %s: %w", jobId, err)
+		}
+		jobs = append(jobs, newJobInfo(jobId, details, types.JobStatus(status)))
+	}
+	log.WithField("count", len(jobs)).Debug("Listed jobs")
+	return jobs, nil
+}
+
+// getJobInfo reads the details and status of a job.
+// Returns error if they cannot be read or the job does not exist.
+func getJobInfo(client *ethclient.Client, opts *bind.CallOpts, jobId *big.Int) (types.JobInfo, error) {
+	details, err := jobsManagerUtils.GetJobDetails(client, opts, jobId)
+	if err != nil {
+		return types.JobInfo{}, fmt.Errorf("failed to get job details: %w", err)
+	}
+	if details.Creator == (common.Address{}) {
+		return types.JobInfo{}, fmt.Errorf("job %s does not exist", jobId)
+	}
+	status, err := jobsManagerUtils.GetJobStatus(client, opts, jobId)
+	if err != nil {
+		return types.JobInfo{}, fmt.Errorf("failed to get job status: %w", err)
+	}
+	return newJobInfo(jobId, details, types.JobStatus(status)), nil
+}
+
+// newJobInfo builds the view of a job, decoding its job spec. A job whose details are not a
+// valid job spec keeps them as submitted with the reason the spec was rejected.
+func newJobInfo(jobId *big.Int, details types.JobContract, status types.JobStatus) types.JobInfo {
+	job := types.JobInfo{
+		JobID:                jobId.String(),
+		Status:               status.String(),
+		Creator:              details.Creator.Hex(),
+		CreationEpoch:        details.CreationEpoch,
+		QueuedEpoch:          details.QueuedEpoch,
+		ExecutionEpoch:       details.ExecutionEpoch,
+		ProofGenerationEpoch: details.ProofGenerationEpoch,
+		ConclusionEpoch:      details.ConclusionEpoch,
+		CreatedAt:            timestampToTime(details.CreationTimestamp),
+		UpdatedAt:            timestampToTime(details.LastUpdatedAtTimestamp),
+		JobFee:               details.JobFee,
+	}
+	if details.Assignee != (common.Address{}) {
+		job.Assignee = details.Assignee.Hex()
+	}
+	spec, err := parseJobDetails([]byte(details.JobDetailsInJSON))
+	if err != nil {
+		job.SpecError = err.Error()
+		job.Details = details.JobDetailsInJSON
+	} else {
+		job.Spec = &spec
+	}
+	return job
+}
+
+// timestampToTime converts a contract timestamp in seconds, which is 0 until set, to UTC time
+func timestampToTime(timestamp *big.Int) time.Time {
+	if timestamp == nil || timestamp.Sign() == 0 {
+		return time.Time{}
+	}
+	return time.Unix(timestamp.Int64(), 0).UTC()
+}
+
+// formatTime formats an optional time for display
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// renderJobInfo writes a job to out as JSON or as a table of its fields followed by a
+// table of its job spec
+func renderJobInfo(out io.Writer, format string, job types.JobInfo) error {
+	if format == outputFormatJSON {
+		return writeJSON(out, job)
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Field", "Value"})
+	table.AppendBulk([][]string{
+		{"Job ID", job.JobID},
+		{"Status", job.Status},
+		{"Creator", job.Creator},
+		{"Assignee", orDash(job.Assignee)},
+		{"Creation Epoch", formatEpoch(job.CreationEpoch)},
+		{"Queued Epoch", formatEpoch(job.QueuedEpoch)},
+		{"Execution Epoch", formatEpoch(job.ExecutionEpoch)},
+		{"Proof Generation Epoch", formatEpoch(job.ProofGenerationEpoch)},
+		{"Conclusion Epoch", formatEpoch(job.ConclusionEpoch)},
+		{"Created At", formatTime(job.CreatedAt)},
+		{"Updated At", formatTime(job.UpdatedAt)},
+		{"Job Fee (wei)", formatBigInt(job.JobFee)},
+	})
+	if job.Spec == nil {
+		table.AppendBulk([][]string{
+			{"Spec Error", job.SpecError},
+			{"Details", job.Details},
+		})
+	}
+	table.Render()
+	if job.Spec == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(job.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job spec: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to decode job spec: %w", err)
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	specTable := tablewriter.NewWriter(out)
+	specTable.SetHeader([]string{"Spec Field", "Value"})
+	for _, name := range names {
+		value := string(fields[name])
+		var text string
+		if json.Unmarshal(fields[name], &text) == nil {
+			value = text
+		}
+		specTable.Append([]string{name, value})
+	}
+	specTable.Render()
+	return nil
+}
+
+// renderJobList writes the jobs to out as JSON or as a table
+func renderJobList(out io.Writer, format string, jobs []types.JobInfo) error {
+	if format == outputFormatJSON {
+		if jobs == nil {
+			jobs = []types.JobInfo{}
+		}
+		return writeJSON(out, jobs)
+	}
+	if len(jobs) == 0 {
+		_, err := fmt.Fprintln(out, "No jobs")
+		return err
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Job ID", "Status", "Creator", "Assignee", "Job Config Name", "GPUs", "Creation Epoch", "Conclusion Epoch", "Job Fee (wei)"})
+	for _, job := range jobs {
+		jobConfigName, numGPUs := "invalid spec", "-"
+		if job.Spec != nil {
+			jobConfigName = job.Spec.JobConfigName
+			if job.Spec.Workflow != pipeline_zen.DefaultWorkflow {
+				jobConfigName = job.Spec.Workflow
+			}
+			numGPUs = strconv.Itoa(job.Spec.NumGPUs)
+		}
+		table.Append([]string{
+			job.JobID,
+			job.Status,
+			job.Creator,
+			orDash(job.Assignee),
+			jobConfigName,
+			numGPUs,
+			formatEpoch(job.CreationEpoch),
+			formatEpoch(job.ConclusionEpoch),
+			formatBigInt(job.JobFee),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// Initializes the jobShow and jobList commands in the CLI with their flags.
+func init() {
+	rootCmd.AddCommand(jobShowCmd, jobListCmd)
+
+	var (
+		JobId    string
+		Creator  string
+		Assignee string
+		All      bool
+		Output   string
+	)
+
+	jobShowCmd.Flags().StringVarP(&JobId, "jobId", "", "", "ID of the job")
+	jobShowCmd.Flags().StringVarP(&Output, "output", "o", outputFormatTable, "output format, table or json")
+
+	jobListCmd.Flags().StringVarP(&Creator, "creator", "", "", "list the jobs created by this address")
+	jobListCmd.Flags().StringVarP(&Assignee, "assignee", "", "", "list the jobs assigned to this address")
+	jobListCmd.Flags().BoolVarP(&All, "all", "", false, "list every job instead of the active jobs")
+	jobListCmd.Flags().StringVarP(&Output, "output", "o", outputFormatTable, "output format, table or json")
+
+	jobIdErr := jobShowCmd.MarkFlagRequired("jobId")
+	utils.CheckError("JobId error: ", jobIdErr)
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// testJobContract returns the JobManager details of a job created by creator with a valid job spec
+func testJobContract(jobId int64, creator common.Address, assignee common.Address) types.JobContract {
+	return types.JobContract{
+		JobId:                  big.NewInt(jobId),
+		Creator:                creator,
+		Assignee:               assignee,
+		CreationEpoch:          10,
+		QueuedEpoch:            11,
+		CreationTimestamp:      big.NewInt(1700000000),
+		LastUpdatedAtTimestamp: big.NewInt(1700000600),
+		JobFee:                 big.NewInt(1000),
+		JobDetailsInJSON:       testJobSpec(1),
+	}
+}
+
+// Tests listing jobs covering:
+// 1. Active jobs in order of their job ID
+// 2. Every job filtered by creator or assignee, skipping unused job IDs
+// 3. Errors reading the job IDs, details or status
+func TestListJobs(t *testing.T) {
+	client := &ethclient.Client{}
+	creator := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	assignee := common.HexToAddress("0x3")
+
+	tests := []struct {
+		name          string
+		filter        types.JobFilter
+		setupMocks    func(*mocks.JobsManagerInterface)
+		expectedIds   []string
+		expectedError string
+	}{
+		{
+			name: "active jobs",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetActiveJobs", client, mock.Anything).Return([]*big.Int{big.NewInt(4), big.NewInt(2)}, nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(2)).Return(testJobContract(2, creator, common.Address{}), nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(4)).Return(testJobContract(4, other, assignee), nil)
+				jobsMock.On("GetJobStatus", client, mock.Anything, mock.Anything).Return(uint8(types.JobStatusQueued), nil)
+			},
+			expectedIds: []string{"2", "4"},
+		},
+		{
+			name:   "jobs created by an address",
+			filter: types.JobFilter{Creator: &creator},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetJobIdCounter", client, mock.Anything).Return(big.NewInt(3), nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(0)).Return(types.JobContract{}, nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(1)).Return(testJobContract(1, creator, assignee), nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(2)).Return(testJobContract(2, other, assignee), nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(3)).Return(testJobContract(3, creator, common.Address{}), nil)
+				jobsMock.On("GetJobStatus", client, mock.Anything, mock.Anything).Return(uint8(types.JobStatusCompleted), nil)
+			},
+			expectedIds: []string{"1", "3"},
+		},
+		{
+			name:   "jobs assigned to an address",
+			filter: types.JobFilter{Assignee: &assignee},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetJobIdCounter", client, mock.Anything).Return(big.NewInt(2), nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(0)).Return(types.JobContract{}, nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(1)).Return(testJobContract(1, creator, assignee), nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(2)).Return(testJobContract(2, creator, other), nil)
+				jobsMock.On("GetJobStatus", client, mock.Anything, mock.Anything).Return(uint8(types.JobStatusRunning), nil)
+			},
+			expectedIds: []string{"1"},
+		},
+		{
+			name: "active jobs cannot be read",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetActiveJobs", client, mock.Anything).Return(nil, errors.New("rpc error"))
+			},
+			expectedError: "failed to get active jobs: rpc error",
+		},
+		{
+			name:   "job ID counter cannot be read",
+			filter: types.JobFilter{All: true},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetJobIdCounter", client, mock.Anything).Return(nil, errors.New("rpc error"))
+			},
+			expectedError: "failed to get job ID counter: rpc error",
+		},
+		{
+			name: "job status cannot be read",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface) {
+				jobsMock.On("GetActiveJobs", client, mock.Anything).Return([]*big.Int{big.NewInt(2)}, nil)
+				jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(2)).Return(testJobContract(2, creator, common.Address{}), nil)
+				jobsMock.On("GetJobStatus", client, mock.Anything, big.NewInt(2)).Return(uint8(0), errors.New("rpc error"))
+			},
+			expectedError: "failed to get status of job 2: rpc error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobsMock := new(mocks.JobsManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+
+			originalJobsManagerUtils := jobsManagerUtils
+			originalProtoUtils := protoUtils
+			defer func() {
+				jobsManagerUtils = originalJobsManagerUtils
+				protoUtils = originalProtoUtils
+			}()
+			jobsManagerUtils = jobsMock
+			protoUtils = utilsMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(jobsMock)
+
+			jobs, err := listJobs(client, tt.filter)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			ids := make([]string, 0, len(jobs))
+			for _, job := range jobs {
+				ids = append(ids, job.JobID)
+			}
+			assert.Equal(t, tt.expectedIds, ids)
+		})
+	}
+}
+
+// Tests reading one job covering:
+// 1. A job with a valid job spec
+// 2. A job whose details are not a valid job spec
+// 3. A job ID that was never used
+func TestGetJobInfo(t *testing.T) {
+	client := &ethclient.Client{}
+	creator := common.HexToAddress("0x1")
+	assignee := common.HexToAddress("0x3")
+
+	invalid := testJobContract(8, creator, common.Address{})
+	invalid.JobDetailsInJSON = `{"name": "test job"}`
+
+	jobsMock := new(mocks.JobsManagerInterface)
+	originalJobsManagerUtils := jobsManagerUtils
+	defer func() { jobsManagerUtils = originalJobsManagerUtils }()
+	jobsManagerUtils = jobsMock
+
+	jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(7)).Return(testJobContract(7, creator, assignee), nil)
+	jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(8)).Return(invalid, nil)
+	jobsMock.On("GetJobDetails", client, mock.Anything, big.NewInt(9)).Return(types.JobContract{}, nil)
+	jobsMock.On("GetJobStatus", client, mock.Anything, mock.Anything).Return(uint8(types.JobStatusQueued), nil)
+
+	job, err := getJobInfo(client, &bind.CallOpts{}, big.NewInt(7))
+	assert.NoError(t, err)
+	assert.Equal(t, "7", job.JobID)
+	assert.Equal(t, "Queued", job.Status)
+	assert.Equal(t, creator.Hex(), job.Creator)
+	assert.Equal(t, assignee.Hex(), job.Assignee)
+	assert.Equal(t, uint32(11), job.QueuedEpoch)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), job.CreatedAt)
+	assert.Equal(t, big.NewInt(1000), job.JobFee)
+	if assert.NotNil(t, job.Spec) {
+		assert.Equal(t, 8, job.Spec.BatchSize)
+	}
+	assert.Empty(t, job.Details)
+
+	job, err = getJobInfo(client, &bind.CallOpts{}, big.NewInt(8))
+	assert.NoError(t, err)
+	assert.Nil(t, job.Spec)
+	assert.Empty(t, job.Assignee)
+	assert.Contains(t, job.SpecError, "job spec is missing job_config_name")
+	assert.Equal(t, invalid.JobDetailsInJSON, job.Details)
+
+	_, err = getJobInfo(client, &bind.CallOpts{}, big.NewInt(9))
+	assert.EqualError(t, err, "job 9 does not exist")
+}
+
+// Tests rendering jobs as tables and as JSON
+func TestRenderJobs(t *testing.T) {
+	creator := common.HexToAddress("0x1")
+	job := newJobInfo(big.NewInt(7), testJobContract(7, creator, common.Address{}), types.JobStatusRunning)
+
+	var out bytes.Buffer
+	assert.NoError(t, renderJobInfo(&out, outputFormatTable, job))
+	assert.Regexp(t, `\| +Status +\| +Running +\|`, out.String())
+	assert.Regexp(t, `\| +Assignee +\| +- +\|`, out.String())
+	assert.Regexp(t, `\| +dataset_id +\| +gs://bucket/dataset +\|`, out.String())
+
+	out.Reset()
+	assert.NoError(t, renderJobInfo(&out, outputFormatJSON, job))
+	var decoded types.JobInfo
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, job, decoded)
+
+	out.Reset()
+	assert.NoError(t, renderJobList(&out, outputFormatTable, []types.JobInfo{job}))
+	assert.Regexp(t, `\| +7 +\| +Running +\| +`+creator.Hex()+` +\| +- +\| +test +\| +1 +\|`, out.String())
+
+	out.Reset()
+	assert.NoError(t, renderJobList(&out, outputFormatJSON, nil))
+	assert.Equal(t, "[]\n", out.String())
+
+	out.Reset()
+	assert.NoError(t, renderJobList(&out, outputFormatTable, nil))
+	assert.Equal(t, "No jobs\n", out.String())
+}
+
+// Tests parsing the optional address filters of jobList
+func TestParseOptionalAddress(t *testing.T) {
+	address, err := parseOptionalAddress("creator", "")
+	assert.NoError(t, err)
+	assert.Nil(t, address)
+
+	address, err = parseOptionalAddress("creator", "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771")
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771"), *address)
+
+	_, err = parseOptionalAddress("assignee", "0x123")
+	assert.EqualError(t, err, `invalid assignee address "0x123"`)
+}
@@ -0,0 +1,521 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"lumino/core/types"
+	"lumino/path"
+	"lumino/storage"
+	"lumino/utils"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// resultsArchiveName is the object name of the results archive of a job
+	resultsArchiveName = "results.tar.gz"
+	// resultsManifestName is the object name of the manifest of a job's results
+	resultsManifestName = "manifest.json"
+	// resultsAttestationName is the object name of the assignee's signed attestation of a job's results
+	resultsAttestationName = "attestation.json"
+)
+
+// resultsMarkerFiles are the pipeline-zen markers of a results directory, which are not results
+var resultsMarkerFiles = map[string]bool{".started": true, ".finished": true, ".failed": true, ".error": true}
+
+// fetchResultsCmd downloads and verifies the published results of a job
+var fetchResultsCmd = &cobra.Command{
+	Use:   "fetchResults",
+	Short: "Download and verify the results of a completed job",
+	Long: `Downloads the results archive an executor published for a job, verifies it against its
+SHA-256 manifest and extracts it. The results are read from the configured results store
+(see setConfig --resultsStore) unless --store is given.
+
+Example:
+  ./lumino fetchResults --jobId 21
+  ./lumino fetchResults --jobId 21 --store s3://results/lumino?endpoint=https://minio.example.com --dir ./job-21`,
+	Run: initialiseFetchResults,
+}
+
+func initialiseFetchResults(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteFetchResults(cmd.Flags())
+}
+
+// ExecuteFetchResults downloads the results of a job. This function:
+// 1. Reads the creator of the job from the JobManager, under whose address results are published
+// 2. Downloads the manifest and the archive from the results store
+// 3. Verifies the archive and every extracted file against the manifest
+// Exits with error if the job has no published results or they fail verification.
+func (*UtilsStruct) ExecuteFetchResults(flagSet *pflag.FlagSet) {
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("ExecuteFetchResults: Config: %+v", config)
+
+	jobIdStr, err := flagSet.GetString("jobId")
+	utils.CheckError("Error in getting jobId: ", err)
+	jobId, ok := new(big.Int).SetString(jobIdStr, 10)
+	if !ok || jobId.Sign() < 0 {
+		log.Fatalf("Invalid jobId %q", jobIdStr)
+	}
+
+	location, err := flagSet.GetString("store")
+	utils.CheckError("Error in getting store: ", err)
+	if location == "" {
+		location = config.ResultsStore
+	}
+	if location == "" {
+		log.Fatal("No results store configured, pass --store or run setConfig --resultsStore")
+	}
+
+	outputDir, err := flagSet.GetString("dir")
+	utils.CheckError("Error in getting dir: ", err)
+	if outputDir == "" {
+		outputDir = "results-" + jobId.String()
+	}
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+	opts := protoUtils.GetOptions()
+	jobDetails, err := jobsManagerUtils.GetJobDetails(client, &opts, jobId)
+	utils.CheckError("Error in getting job details: ", err)
+	if jobDetails.Creator == (common.Address{}) {
+		log.Fatalf("Job %s does not exist", jobId.String())
+	}
+
+	manifest, err := resultsUtils.FetchJobResults(location, jobDetails.Creator, jobId, outputDir)
+	utils.CheckError("Error in fetching results: ", err)
+
+	log.WithFields(logrus.Fields{
+		"jobId":    jobId.String(),
+		"assignee": manifest.Assignee,
+		"files":    len(manifest.Files),
+		"dir":      outputDir,
+	}).Info("Results downloaded and verified")
+}
+
+// PackageJobResults describes the results directory of a job in its manifest and saves the
+// manifest in the job directory. This function:
+// 1. Hashes every file of the directory, without the pipeline markers, with SHA-256
+// 2. With withArchive, writes the files to a gzipped tar archive in the job directory and
+// records its key, size and SHA-256 digest in the manifest
+// 3. Writes the manifest to manifest.json in the job directory
+// Returns the manifest, and error if the directory cannot be read or the files cannot be written.
+func (ResultsUtils) PackageJobResults(manifest types.ResultManifest, resultsDir string, withArchive bool) (types.ResultManifest, error) {
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(manifest.JobID)
+	if err != nil {
+		return manifest, fmt.Errorf("failed to get job directory: %w", err)
+	}
+
+	if withArchive {
+		archive, err := os.Create(filepath.Join(jobDirPath, resultsArchiveName))
+		if err != nil {
+			return manifest, fmt.Errorf("failed to create results archive: %w", err)
+		}
+		defer archive.Close()
+		hash := sha256.New()
+		counter := &countingWriter{w: io.MultiWriter(archive, hash)}
+		if manifest.Files, err = packageResults(resultsDir, counter); err != nil {
+			return manifest, err
+		}
+		manifest.Archive = resultsObjectKey(manifest.Creator, manifest.JobID, resultsArchiveName)
+		manifest.ArchiveSize = counter.n
+		manifest.ArchiveSHA256 = hex.EncodeToString(hash.Sum(nil))
+	} else if manifest.Files, err = packageResults(resultsDir, io.Discard); err != nil {
+		return manifest, err
+	}
+	manifest.CreatedAt = time.Now().UTC()
+
+	data, err := marshalResultManifest(manifest)
+	if err != nil {
+		return manifest, err
+	}
+	if err := os.WriteFile(filepath.Join(jobDirPath, resultsManifestName), data, 0644); err != nil {
+		return manifest, fmt.Errorf("failed to save results manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// SaveResultAttestation writes the attestation of a job to attestation.json in its job directory.
+// Returns error if the attestation cannot be written.
+func (ResultsUtils) SaveResultAttestation(attestation types.ResultAttestation) error {
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(attestation.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job directory: %w", err)
+	}
+	data, err := json.MarshalIndent(attestation, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal result attestation: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(jobDirPath, resultsAttestationName), data, 0644); err != nil {
+		return fmt.Errorf("failed to save result attestation: %w", err)
+	}
+	return nil
+}
+
+// PublishJobResults uploads the results of a job packaged by PackageJobResults to the results
+// store. This function:
+// 1. Uploads the archive, then the attestation when the job directory holds one, under <creator>/<jobId>/
+// 2. Uploads the manifest last, so fetching never finds a manifest without its archive
+// 3. Removes the local archive
+// Returns error if the packaged results are missing or the upload fails.
+func (ResultsUtils) PublishJobResults(location string, manifest types.ResultManifest) error {
+	store, err := storage.Open(location)
+	if err != nil {
+		return err
+	}
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(manifest.JobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job directory: %w", err)
+	}
+	if manifest.Archive == "" {
+		return fmt.Errorf("results of job %s were packaged without an archive", manifest.JobID)
+	}
+
+	ctx := context.Background()
+	archivePath := filepath.Join(jobDirPath, resultsArchiveName)
+	if err := putResultsFile(ctx, store, archivePath, manifest.Archive); err != nil {
+		return err
+	}
+	attestationPath := filepath.Join(jobDirPath, resultsAttestationName)
+	if _, err := os.Stat(attestationPath); err == nil {
+		attestationKey := resultsObjectKey(manifest.Creator, manifest.JobID, resultsAttestationName)
+		if err := putResultsFile(ctx, store, attestationPath, attestationKey); err != nil {
+			return err
+		}
+	}
+	manifestKey := resultsObjectKey(manifest.Creator, manifest.JobID, resultsManifestName)
+	if err := putResultsFile(ctx, store, filepath.Join(jobDirPath, resultsManifestName), manifestKey); err != nil {
+		return err
+	}
+	if err := os.Remove(archivePath); err != nil {
+		log.WithError(err).WithField("archive", archivePath).Warn("Failed to remove published results archive")
+	}
+
+	log.WithFields(logrus.Fields{
+		"jobId":  manifest.JobID,
+		"store":  store.String(),
+		"files":  len(manifest.Files),
+		"size":   manifest.ArchiveSize,
+		"sha256": manifest.ArchiveSHA256,
+	}).Info("Published job results")
+	return nil
+}
+
+// FetchJobResults downloads the results of a job from the results store into outputDir. This function:
+// 1. Reads the manifest and checks it describes the job of the creator
+// 2. Downloads the archive, checking its size and SHA-256 digest
+// 3. Extracts the archive, checking every file against the manifest, and saves the manifest next to it
+// 4. Saves the assignee's attestation next to the manifest when one was published, see verifyAttestation
+// Returns the manifest, and error if the results are missing, altered or cannot be written.
+func (ResultsUtils) FetchJobResults(location string, creator common.Address, jobId *big.Int, outputDir string) (types.ResultManifest, error) {
+	var manifest types.ResultManifest
+	store, err := storage.Open(location)
+	if err != nil {
+		return manifest, err
+	}
+	ctx := context.Background()
+
+	manifestKey := resultsObjectKey(creator.Hex(), jobId.String(), resultsManifestName)
+	reader, err := store.Get(ctx, manifestKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return manifest, fmt.Errorf("no results published for job %s in %s", jobId.String(), store.String())
+	}
+	if err != nil {
+		return manifest, err
+	}
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		return manifest, fmt.Errorf("failed to read results manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("failed to parse results manifest: %w", err)
+	}
+	if manifest.JobID != jobId.String() || !strings.EqualFold(manifest.Creator, creator.Hex()) {
+		return manifest, fmt.Errorf("results manifest describes job %s of %s, expected job %s of %s",
+			manifest.JobID, manifest.Creator, jobId.String(), creator.Hex())
+	}
+
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return manifest, fmt.Errorf("failed to create %s: %w", outputDir, err)
+	}
+	archivePath := filepath.Join(outputDir, "."+resultsArchiveName+".part")
+	defer os.Remove(archivePath)
+	if err := downloadResultsArchive(ctx, store, manifest, archivePath); err != nil {
+		return manifest, err
+	}
+	if err := extractResults(archivePath, outputDir, manifest.Files); err != nil {
+		return manifest, err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, resultsManifestName), data, 0644); err != nil {
+		return manifest, fmt.Errorf("failed to save results manifest: %w", err)
+	}
+	attestationKey := resultsObjectKey(creator.Hex(), jobId.String(), resultsAttestationName)
+	if err := getResultsFile(ctx, store, attestationKey, filepath.Join(outputDir, resultsAttestationName)); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return manifest, err
+	}
+	return manifest, nil
+}
+
+// packageResults writes the regular files under resultsDir to w as a gzipped tar archive in
+// lexical order, skipping the pipeline markers at its root.
+// Returns the archived files with their digests, and error if a file cannot be read.
+func packageResults(resultsDir string, w io.Writer) ([]types.ResultFile, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	files := []types.ResultFile{}
+
+	err := filepath.WalkDir(resultsDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(resultsDir, filePath)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if entry.IsDir() || resultsMarkerFiles[rel] {
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			log.WithField("file", filePath).Warn("Skipping results entry that is not a regular file")
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    rel,
+			Mode:    0644,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		}); err != nil {
+			return err
+		}
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		hash := sha256.New()
+		written, err := io.Copy(io.MultiWriter(tw, hash), file)
+		if err != nil {
+			return err
+		}
+		if written != info.Size() {
+			return fmt.Errorf("%s changed while being archived", rel)
+		}
+		files = append(files, types.ResultFile{Path: rel, Size: written, SHA256: hex.EncodeToString(hash.Sum(nil))})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive results: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to archive results: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to archive results: %w", err)
+	}
+	return files, nil
+}
+
+// downloadResultsArchive downloads the archive of the manifest to archivePath.
+// Returns error if the archive is missing or its size or SHA-256 digest differs from the manifest.
+func downloadResultsArchive(ctx context.Context, store storage.Store, manifest types.ResultManifest, archivePath string) error {
+	reader, err := store.Get(ctx, manifest.Archive)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	file, err := os.Create(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create results archive: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), reader)
+	if err != nil {
+		return fmt.Errorf("failed to download results archive: %w", err)
+	}
+	if size != manifest.ArchiveSize {
+		return fmt.Errorf("results archive is %d bytes, manifest lists %d", size, manifest.ArchiveSize)
+	}
+	if digest := hex.EncodeToString(hash.Sum(nil)); digest != manifest.ArchiveSHA256 {
+		return fmt.Errorf("results archive SHA-256 %s does not match manifest %s", digest, manifest.ArchiveSHA256)
+	}
+	return nil
+}
+
+// extractResults extracts the archive into outputDir, writing only the files listed in the
+// manifest. Returns error if the archive holds a file the manifest does not list, a file
+// differs from its digest, or a listed file is missing.
+func extractResults(archivePath string, outputDir string, files []types.ResultFile) error {
+	expected := make(map[string]types.ResultFile, len(files))
+	for _, file := range files {
+		expected[file.Path] = file
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("failed to open results archive: %w", err)
+	}
+	defer archive.Close()
+	gz, err := gzip.NewReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to read results archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read results archive: %w", err)
+		}
+		want, ok := expected[header.Name]
+		if !ok || header.Typeflag != tar.TypeReg || !filepath.IsLocal(filepath.FromSlash(header.Name)) {
+			return fmt.Errorf("results archive holds %q, which the manifest does not list", header.Name)
+		}
+		delete(expected, header.Name)
+
+		target := filepath.Join(outputDir, filepath.FromSlash(header.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", header.Name, err)
+		}
+		if err := extractResultFile(tr, target, want); err != nil {
+			return err
+		}
+	}
+
+	if len(expected) > 0 {
+		missing := make([]string, 0, len(expected))
+		for name := range expected {
+			missing = append(missing, name)
+		}
+		sort.Strings(missing)
+		return fmt.Errorf("results archive is missing %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// extractResultFile writes one archived file to target and checks it against the manifest
+func extractResultFile(r io.Reader, target string, want types.ResultFile) error {
+	file, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", want.Path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash), io.LimitReader(r, want.Size+1))
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", want.Path, err)
+	}
+	if size != want.Size || hex.EncodeToString(hash.Sum(nil)) != want.SHA256 {
+		return fmt.Errorf("%s does not match its manifest digest", want.Path)
+	}
+	return nil
+}
+
+// marshalResultManifest encodes the manifest as saved in manifest.json. The attestation of a
+// job signs the SHA-256 digest of exactly these bytes.
+func marshalResultManifest(manifest types.ResultManifest) ([]byte, error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal results manifest: %w", err)
+	}
+	return data, nil
+}
+
+// putResultsFile uploads the file at filePath to the store under key
+func putResultsFile(ctx context.Context, store storage.Store, filePath string, key string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(filePath), err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", filepath.Base(filePath), err)
+	}
+	return store.Put(ctx, key, file, info.Size())
+}
+
+// getResultsFile downloads the object under key to filePath.
+// Returns an error wrapping storage.ErrNotFound if the store holds no such object.
+func getResultsFile(ctx context.Context, store storage.Store, key string, filePath string) error {
+	reader, err := store.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Base(filePath), err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, reader); err != nil {
+		return fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	return nil
+}
+
+// resultsObjectKey returns the key of a results object, mirroring the pipeline-zen
+// .results/<creator>/<jobId> layout
+func resultsObjectKey(creator string, jobId string, name string) string {
+	return creator + "/" + jobId + "/" + name
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Initializes the fetchResults command with its flags.
+func init() {
+	rootCmd.AddCommand(fetchResultsCmd)
+
+	var (
+		JobId     string
+		Store     string
+		OutputDir string
+	)
+
+	fetchResultsCmd.Flags().StringVarP(&JobId, "jobId", "", "", "ID of the job")
+	fetchResultsCmd.Flags().StringVarP(&Store, "store", "", "", "directory or s3:// URL to read the results from (default: configured results store)")
+	fetchResultsCmd.Flags().StringVarP(&OutputDir, "dir", "", "", "directory to extract the results to (default: results-<jobId>)")
+
+	jobIdErr := fetchResultsCmd.MarkFlagRequired("jobId")
+	utils.CheckError("JobId error: ", jobIdErr)
+}
@@ -0,0 +1,212 @@
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"lumino/storage"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupResultsDir writes the results of a job with the pipeline-zen markers into a temporary directory
+func setupResultsDir(t *testing.T) string {
+	resultsDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(resultsDir, "checkpoints"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "metrics.json"), []byte(`{"loss": 0.42}`), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "checkpoints", "adapter.safetensors"), bytes.Repeat([]byte{7}, 4096), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".started"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".finished"), nil, 0644))
+	return resultsDir
+}
+
+// setupJobDir points GetJobDirPath at a temporary directory for the duration of the test
+func setupJobDir(t *testing.T) string {
+	pathMock := new(pathMocks.PathInterface)
+	originalPathUtils := path.PathUtilsInterface
+	t.Cleanup(func() { path.PathUtilsInterface = originalPathUtils })
+	path.PathUtilsInterface = pathMock
+	jobDir := t.TempDir()
+	pathMock.On("GetJobDirPath", "7").Return(jobDir, nil)
+	return jobDir
+}
+
+// Tests publishing the results of a job and fetching them back with cases:
+// 1. The archive holds every result file without the pipeline markers, listed with their digests
+// 2. The manifest and attestation are saved in the job directory, and the archive removed once published
+// 3. Fetching extracts the files and saves the manifest and attestation
+// 4. Results are looked up under the creator, so another creator finds none
+// 5. An archive altered in the store fails the digest check
+func TestPublishAndFetchJobResults(t *testing.T) {
+	jobDir := setupJobDir(t)
+	resultsDir := setupResultsDir(t)
+	storeDir := t.TempDir()
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	assignee := common.HexToAddress("0x2000000000000000000000000000000000000002")
+
+	manifest, err := ResultsUtils{}.PackageJobResults(types.ResultManifest{
+		JobID:    "7",
+		Creator:  creator.Hex(),
+		Assignee: assignee.Hex(),
+	}, resultsDir, true)
+	assert.NoError(t, err)
+	assert.Equal(t, creator.Hex()+"/7/results.tar.gz", manifest.Archive)
+	metricsDigest := sha256.Sum256([]byte(`{"loss": 0.42}`))
+	assert.Equal(t, []types.ResultFile{
+		{Path: "checkpoints/adapter.safetensors", Size: 4096, SHA256: manifest.Files[0].SHA256},
+		{Path: "metrics.json", Size: 14, SHA256: hex.EncodeToString(metricsDigest[:])},
+	}, manifest.Files)
+	savedManifest, err := os.ReadFile(filepath.Join(jobDir, "manifest.json"))
+	assert.NoError(t, err)
+	wantManifest, err := marshalResultManifest(manifest)
+	assert.NoError(t, err)
+	assert.Equal(t, wantManifest, savedManifest)
+	assert.NoError(t, ResultsUtils{}.SaveResultAttestation(types.ResultAttestation{Version: 1, JobID: "7", Signature: "0x01"}))
+
+	assert.NoError(t, ResultsUtils{}.PublishJobResults(storeDir, manifest))
+	assert.NoFileExists(t, filepath.Join(jobDir, "results.tar.gz"))
+	archive, err := os.ReadFile(filepath.Join(storeDir, creator.Hex(), "7", "results.tar.gz"))
+	assert.NoError(t, err)
+	archiveDigest := sha256.Sum256(archive)
+	assert.Equal(t, hex.EncodeToString(archiveDigest[:]), manifest.ArchiveSHA256)
+	assert.Equal(t, int64(len(archive)), manifest.ArchiveSize)
+
+	outputDir := filepath.Join(t.TempDir(), "results-7")
+	fetched, err := ResultsUtils{}.FetchJobResults(storeDir, creator, big.NewInt(7), outputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, manifest.ArchiveSHA256, fetched.ArchiveSHA256)
+	assert.Equal(t, assignee.Hex(), fetched.Assignee)
+	metrics, err := os.ReadFile(filepath.Join(outputDir, "metrics.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"loss": 0.42}`, string(metrics))
+	assert.FileExists(t, filepath.Join(outputDir, "checkpoints", "adapter.safetensors"))
+	fetchedManifest, err := os.ReadFile(filepath.Join(outputDir, "manifest.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, savedManifest, fetchedManifest)
+	attestation, err := readResultAttestation(filepath.Join(outputDir, "attestation.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, "0x01", attestation.Signature)
+	assert.NoFileExists(t, filepath.Join(outputDir, ".started"))
+	assert.NoFileExists(t, filepath.Join(outputDir, ".results.tar.gz.part"))
+
+	_, err = ResultsUtils{}.FetchJobResults(storeDir, assignee, big.NewInt(7), t.TempDir())
+	assert.EqualError(t, err, "no results published for job 7 in "+storeDir)
+
+	archive[len(archive)-1] ^= 0xff
+	assert.NoError(t, os.WriteFile(filepath.Join(storeDir, creator.Hex(), "7", "results.tar.gz"), archive, 0644))
+	_, err = ResultsUtils{}.FetchJobResults(storeDir, creator, big.NewInt(7), t.TempDir())
+	assert.ErrorContains(t, err, "does not match manifest")
+}
+
+// Tests packaging results without a results store: the files are listed in the manifest,
+// no archive is written and nothing can be published
+func TestPackageJobResultsWithoutArchive(t *testing.T) {
+	jobDir := setupJobDir(t)
+
+	manifest, err := ResultsUtils{}.PackageJobResults(types.ResultManifest{JobID: "7"}, setupResultsDir(t), false)
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Files, 2)
+	assert.Empty(t, manifest.Archive)
+	assert.FileExists(t, filepath.Join(jobDir, "manifest.json"))
+	assert.NoFileExists(t, filepath.Join(jobDir, "results.tar.gz"))
+
+	err = ResultsUtils{}.PublishJobResults(t.TempDir(), manifest)
+	assert.EqualError(t, err, "results of job 7 were packaged without an archive")
+}
+
+// Tests the checks of a downloaded archive against its manifest with cases:
+// 1. A manifest of another job is rejected
+// 2. A file whose content differs from its listed digest is rejected
+// 3. A file the manifest does not list, such as a path escaping the output directory, is rejected
+// 4. A listed file missing from the archive is rejected
+func TestFetchJobResultsVerification(t *testing.T) {
+	creator := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	digest := func(data string) string {
+		sum := sha256.Sum256([]byte(data))
+		return hex.EncodeToString(sum[:])
+	}
+
+	tests := []struct {
+		name      string
+		jobId     string
+		archived  map[string]string
+		listed    []types.ResultFile
+		wantError string
+	}{
+		{
+			name:      "manifest of another job",
+			jobId:     "8",
+			archived:  map[string]string{"metrics.json": "{}"},
+			listed:    []types.ResultFile{{Path: "metrics.json", Size: 2, SHA256: digest("{}")}},
+			wantError: "results manifest describes job 8 of " + creator.Hex() + ", expected job 7 of " + creator.Hex(),
+		},
+		{
+			name:      "altered file",
+			jobId:     "7",
+			archived:  map[string]string{"metrics.json": `{"loss": 9}`},
+			listed:    []types.ResultFile{{Path: "metrics.json", Size: 11, SHA256: digest("{}")}},
+			wantError: "metrics.json does not match its manifest digest",
+		},
+		{
+			name:      "path escaping the output directory",
+			jobId:     "7",
+			archived:  map[string]string{"../escape": "x"},
+			listed:    []types.ResultFile{{Path: "../escape", Size: 1, SHA256: digest("x")}},
+			wantError: `results archive holds "../escape", which the manifest does not list`,
+		},
+		{
+			name:      "missing file",
+			jobId:     "7",
+			archived:  map[string]string{"metrics.json": "{}"},
+			listed:    []types.ResultFile{{Path: "metrics.json", Size: 2, SHA256: digest("{}")}, {Path: "model.bin", Size: 1, SHA256: digest("x")}},
+			wantError: "results archive is missing model.bin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var archive bytes.Buffer
+			gz := gzip.NewWriter(&archive)
+			tw := tar.NewWriter(gz)
+			for name, content := range tt.archived {
+				assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+				_, err := tw.Write([]byte(content))
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, tw.Close())
+			assert.NoError(t, gz.Close())
+
+			manifest := types.ResultManifest{
+				JobID:         tt.jobId,
+				Creator:       creator.Hex(),
+				Archive:       creator.Hex() + "/7/results.tar.gz",
+				ArchiveSize:   int64(archive.Len()),
+				ArchiveSHA256: digest(archive.String()),
+				Files:         tt.listed,
+			}
+			data, err := json.Marshal(manifest)
+			assert.NoError(t, err)
+			storeDir := t.TempDir()
+			store := storage.NewLocalStore(storeDir)
+			assert.NoError(t, store.Put(context.Background(), manifest.Archive, bytes.NewReader(archive.Bytes()), int64(archive.Len())))
+			assert.NoError(t, store.Put(context.Background(), creator.Hex()+"/7/manifest.json", bytes.NewReader(data), int64(len(data))))
+
+			outputDir := filepath.Join(t.TempDir(), "out")
+			_, err = ResultsUtils{}.FetchJobResults(storeDir, creator, big.NewInt(7), outputDir)
+			assert.EqualError(t, err, tt.wantError)
+			assert.NoFileExists(t, filepath.Join(filepath.Dir(outputDir), "escape"))
+		})
+	}
+}
@@ -0,0 +1,327 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lumino/cmd/systemspecs"
+	"lumino/core"
+	"lumino/core/types"
+	pipeline_zen "lumino/pipeline-zen"
+	"math"
+	"math/big"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// jobSpecRequiredFields are the job spec fields without a default of the torchtunewrapper workflow
+var jobSpecRequiredFields = []string{"schema_version", "job_config_name", "dataset_id", "batch_size", "num_epochs", "num_gpus"}
+
+// workflowJobSpecRequiredFields are the job spec fields without a default of the other workflows
+var workflowJobSpecRequiredFields = []string{"schema_version", "workflow", "config"}
+
+// torchTuneWrapperJobSpecFields are the job spec fields only the torchtunewrapper workflow takes
+var torchTuneWrapperJobSpecFields = []string{"job_config_name", "batch_size", "shuffle", "num_epochs", "use_lora", "use_qlora", "lr", "override_env", "seed"}
+
+// legacyJobSpecFields are the job details read by legacy executors, which ignored any other field
+var legacyJobSpecFields = []string{"job_config_name", "dataset_id", "batch_size", "shuffle", "num_epochs", "use_lora",
+	"use_qlora", "lr", "override_env", "seed", "num_gpus", "min_gpu_memory", "min_system_memory"}
+
+// legacyJobSpecIntFields and legacyJobSpecBoolFields are the fields of legacy job specs that
+// were read as strings and are typed since schema_version 1
+var (
+	legacyJobSpecIntFields  = []string{"batch_size", "num_epochs", "seed", "num_gpus"}
+	legacyJobSpecBoolFields = []string{"shuffle", "use_lora", "use_qlora"}
+)
+
+// registerConfiguredWorkflows registers the script workflows of the workflows file of the
+// node (see setConfig --workflowsFile) so that job specs can select them.
+// Returns error if the workflows file cannot be loaded.
+func registerConfiguredWorkflows(config types.Configurations) error {
+	if config.WorkflowsFile == "" {
+		return nil
+	}
+	names, err := pipeline_zen.RegisterScriptWorkflows(config.WorkflowsFile)
+	if err != nil {
+		return err
+	}
+	log.WithField("workflows", strings.Join(names, ", ")).Debugf("Registered the script workflows of %s", config.WorkflowsFile)
+	return nil
+}
+
+// parseJobDetails decodes and validates the job details of a job read from the chain. Job
+// details without a schema_version were created before job specs were versioned, and are
+// migrated from the legacy layout before being parsed like any other spec.
+// Returns error with the reason the spec is malformed.
+func parseJobDetails(data []byte) (types.JobSpec, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields["schema_version"] == nil {
+		migrated, err := migrateLegacyJobSpec(data)
+		if err != nil {
+			return types.JobSpec{}, err
+		}
+		data = migrated
+	}
+	return parseJobSpec(data)
+}
+
+// migrateLegacyJobSpec converts the job details of a legacy job, the schema_version 0 layout,
+// into a schema_version 1 spec. This function:
+// 1. Quotes the job_config_name key, which early clients wrote unquoted
+// 2. Keeps the fields of the torchtunewrapper workflow, the only one legacy jobs could run,
+// and the hardware fields, dropping the others, which legacy executors ignored
+// 3. Converts the integers, booleans and lr given as strings into typed values, and the
+// string fields given as numbers into strings. Typed fields left empty are dropped and get their default.
+// Values that cannot be converted are kept as they are, so that parseJobSpec reports them.
+// Returns error if the job details are not a JSON object.
+func migrateLegacyJobSpec(data []byte) ([]byte, error) {
+	cleaned := strings.TrimSpace(strings.Replace(string(data), "job_config_name:", `"job_config_name":`, 1))
+	var legacy map[string]interface{}
+	if err := json.Unmarshal([]byte(cleaned), &legacy); err != nil {
+		return nil, fmt.Errorf("job spec is not a JSON object: %w", err)
+	}
+
+	spec := map[string]interface{}{"schema_version": core.JobSpecSchemaVersion}
+	for _, field := range legacyJobSpecFields {
+		value, ok := legacy[field]
+		if !ok {
+			continue
+		}
+		spec[field] = value
+		switch value := value.(type) {
+		case string:
+			text := strings.TrimSpace(value)
+			typed := slices.Contains(legacyJobSpecIntFields, field) || slices.Contains(legacyJobSpecBoolFields, field) || field == "lr"
+			if typed && text == "" {
+				delete(spec, field)
+				continue
+			}
+			switch {
+			case slices.Contains(legacyJobSpecIntFields, field):
+				if number, err := strconv.Atoi(text); err == nil {
+					spec[field] = number
+				}
+			case slices.Contains(legacyJobSpecBoolFields, field):
+				if flag, err := strconv.ParseBool(text); err == nil {
+					spec[field] = flag
+				}
+			case field == "lr":
+				if rate, err := strconv.ParseFloat(text, 64); err == nil {
+					spec[field] = rate
+				}
+			}
+		case float64:
+			if !slices.Contains(legacyJobSpecIntFields, field) && field != "lr" {
+				spec[field] = strconv.FormatFloat(value, 'f', -1, 64)
+			}
+		}
+	}
+	return json.Marshal(spec)
+}
+
+// parseJobSpec decodes and validates a job spec. This function:
+// 1. Checks the spec declares the supported schema_version and a registered workflow
+// 2. Checks every field required by the workflow is present
+// 3. Decodes the typed fields, rejecting unknown fields and values of the wrong type
+// 4. Fills in the defaults of the optional fields and validates the values, checking the
+// config of workflows other than torchtunewrapper against the schema of the workflow
+// Returns error with the reason the spec is malformed.
+func parseJobSpec(data []byte) (types.JobSpec, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return types.JobSpec{}, fmt.Errorf("job spec is not a JSON object: %w", err)
+	}
+
+	rawVersion, ok := fields["schema_version"]
+	if !ok {
+		return types.JobSpec{}, fmt.Errorf("job spec has no schema_version, expected %d", core.JobSpecSchemaVersion)
+	}
+	var version int
+	if err := json.Unmarshal(rawVersion, &version); err != nil {
+		return types.JobSpec{}, fmt.Errorf("job spec schema_version %s is not an integer", rawVersion)
+	}
+	if version != core.JobSpecSchemaVersion {
+		return types.JobSpec{}, fmt.Errorf("unsupported job spec schema_version %d, expected %d", version, core.JobSpecSchemaVersion)
+	}
+
+	workflowName := pipeline_zen.DefaultWorkflow
+	if rawWorkflow, ok := fields["workflow"]; ok {
+		if err := json.Unmarshal(rawWorkflow, &workflowName); err != nil {
+			return types.JobSpec{}, fmt.Errorf("job spec workflow %s is not a string", rawWorkflow)
+		}
+	}
+	workflow, err := pipeline_zen.WorkflowFor(workflowName)
+	if err != nil {
+		return types.JobSpec{}, fmt.Errorf("invalid job spec: %w", err)
+	}
+
+	spec := types.JobSpec{
+		LearningRate: core.DefaultJobLearningRate,
+		Seed:         core.DefaultJobSeed,
+		OverrideEnv:  core.DefaultJobOverrideEnv,
+	}
+	if workflow.Name() != pipeline_zen.DefaultWorkflow {
+		spec = types.JobSpec{}
+		for _, field := range torchTuneWrapperJobSpecFields {
+			if _, ok := fields[field]; ok {
+				return types.JobSpec{}, fmt.Errorf("job spec field %s is only used by the %s workflow, pass the arguments of %s in config",
+					field, pipeline_zen.DefaultWorkflow, workflow.Name())
+			}
+		}
+	} else if _, ok := fields["config"]; ok {
+		return types.JobSpec{}, fmt.Errorf("job spec config is only used by workflows other than %s", pipeline_zen.DefaultWorkflow)
+	}
+
+	if missing := missingJobSpecFields(fields); len(missing) > 0 {
+		return types.JobSpec{}, fmt.Errorf("job spec is missing %s", strings.Join(missing, ", "))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return types.JobSpec{}, fmt.Errorf("invalid job spec: %w", describeJobSpecError(err))
+	}
+	spec.Workflow = workflow.Name()
+
+	if err := validateJobSpec(spec, workflow); err != nil {
+		return types.JobSpec{}, err
+	}
+	return spec, nil
+}
+
+// missingJobSpecFields returns the job spec fields required by the workflow of the fields
+// that they do not set
+func missingJobSpecFields(fields map[string]json.RawMessage) []string {
+	required := jobSpecRequiredFields
+	var workflowName string
+	if json.Unmarshal(fields["workflow"], &workflowName) == nil && workflowName != "" && workflowName != pipeline_zen.DefaultWorkflow {
+		required = workflowJobSpecRequiredFields
+	}
+	var missing []string
+	for _, field := range required {
+		if _, ok := fields[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	return missing
+}
+
+// describeJobSpecError rewrites a JSON type error to name the job spec field and the
+// expected type, which the encoding/json message spells in Go terms
+func describeJobSpecError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return err
+	}
+	expected := typeErr.Type.Kind().String()
+	switch expected {
+	case "int":
+		expected = "an integer"
+	case "float64":
+		expected = "a number"
+	case "bool":
+		expected = "true or false"
+	case "string":
+		expected = "a string"
+	}
+	return fmt.Errorf("%s must be %s, got %s", typeErr.Field, expected, typeErr.Value)
+}
+
+// validateJobSpec checks the values of a decoded job spec run by workflow.
+// Returns error listing every invalid field.
+func validateJobSpec(spec types.JobSpec, workflow pipeline_zen.Workflow) error {
+	var problems []string
+	torchTuneWrapper := workflow.Name() == pipeline_zen.DefaultWorkflow
+	if torchTuneWrapper && strings.TrimSpace(spec.JobConfigName) == "" {
+		problems = append(problems, "job_config_name is empty")
+	}
+	if torchTuneWrapper || spec.DatasetID != "" {
+		if datasetURI, err := url.Parse(spec.DatasetID); err != nil || datasetURI.Scheme == "" || (datasetURI.Host == "" && datasetURI.Path == "") {
+			problems = append(problems, fmt.Sprintf("dataset_id %q is not a URI such as gs://bucket/path", spec.DatasetID))
+		}
+	}
+	if torchTuneWrapper {
+		if spec.BatchSize <= 0 {
+			problems = append(problems, fmt.Sprintf("batch_size must be positive, got %d", spec.BatchSize))
+		}
+		if spec.NumEpochs <= 0 {
+			problems = append(problems, fmt.Sprintf("num_epochs must be positive, got %d", spec.NumEpochs))
+		}
+		if spec.LearningRate <= 0 || math.IsInf(spec.LearningRate, 0) {
+			problems = append(problems, fmt.Sprintf("lr must be positive, got %g", spec.LearningRate))
+		}
+		if spec.Seed < 0 {
+			problems = append(problems, fmt.Sprintf("seed must not be negative, got %d", spec.Seed))
+		}
+	} else if err := workflow.ValidateConfig(types.JobConfig{Workflow: spec.Workflow, Config: spec.Config}); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if spec.NumGPUs < 0 {
+		problems = append(problems, fmt.Sprintf("num_gpus must not be negative, got %d", spec.NumGPUs))
+	}
+	if spec.MinGPUMemory != "" {
+		if _, err := systemspecs.ParseMemoryMiB(spec.MinGPUMemory); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid min_gpu_memory: %v", err))
+		}
+	}
+	if spec.MinSystemMemory != "" {
+		if _, err := systemspecs.ParseMemoryMiB(spec.MinSystemMemory); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid min_system_memory: %v", err))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid job spec: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// newJobConfig converts a job spec into the job config written for the pipeline. Workflows
+// other than torchtunewrapper get their config object with the job and its dataset and GPUs.
+func newJobConfig(spec types.JobSpec, jobId *big.Int, creator common.Address) types.JobConfig {
+	if spec.Workflow != "" && spec.Workflow != pipeline_zen.DefaultWorkflow {
+		return types.JobConfig{
+			Workflow:  spec.Workflow,
+			JobID:     jobId.String(),
+			DatasetID: spec.DatasetID,
+			NumGPUs:   strconv.Itoa(spec.NumGPUs),
+			UserID:    creator.String(),
+			Config:    spec.Config,
+		}
+	}
+	return types.JobConfig{
+		Workflow:      pipeline_zen.DefaultWorkflow,
+		JobConfigName: spec.JobConfigName,
+		JobID:         jobId.String(),
+		DatasetID:     spec.DatasetID,
+		BatchSize:     strconv.Itoa(spec.BatchSize),
+		Shuffle:       strconv.FormatBool(spec.Shuffle),
+		NumEpochs:     strconv.Itoa(spec.NumEpochs),
+		UseLora:       strconv.FormatBool(spec.UseLora),
+		UseQlora:      strconv.FormatBool(spec.UseQlora),
+		LearningRate:  strconv.FormatFloat(spec.LearningRate, 'g', -1, 64),
+		OverrideEnv:   spec.OverrideEnv,
+		Seed:          strconv.Itoa(spec.Seed),
+		NumGPUs:       strconv.Itoa(spec.NumGPUs),
+		UserID:        creator.String(),
+	}
+}
+
+// rejectJob rejects a Queued job whose spec failed to parse and reports it Failed, so that its
+// creator sees the reason in completion.json instead of waiting for a job no node can run.
+// Returns error if the Failed status cannot be reported.
+func rejectJob(client *ethclient.Client, config types.Configurations, account types.Account, jobId *big.Int, specErr error) error {
+	log.WithError(specErr).WithField("jobId", jobId.String()).Warn("Rejected job with a malformed spec")
+	return failQueuedJob(client, config, account, jobId, types.JobCompletion{
+		JobID:   jobId.String(),
+		Outcome: types.JobOutcomeRejected,
+		Reason:  specErr.Error(),
+	})
+}
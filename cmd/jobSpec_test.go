@@ -0,0 +1,287 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"lumino/core/types"
+	pipeline_zen "lumino/pipeline-zen"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// testJobSpec returns a valid job spec requesting numGPUs GPUs
+func testJobSpec(numGPUs int) string {
+	return fmt.Sprintf(`{"schema_version": 1, "job_config_name": "test", "dataset_id": "gs://bucket/dataset", "batch_size": 8, "num_epochs": 1, "num_gpus": %d}`, numGPUs)
+}
+
+// Tests decoding and validating job specs covering:
+// 1. A complete spec and a minimal spec filled with defaults
+// 2. Missing or unsupported schema versions
+// 3. Missing required fields, unknown fields and values of the wrong type
+// 4. Values out of range
+// 5. Specs of other workflows, whose config is checked against the schema of the workflow
+func TestParseJobSpec(t *testing.T) {
+	registerTestWorkflow(t)
+	tests := []struct {
+		name          string
+		json          string
+		want          types.JobSpec
+		expectedError string
+	}{
+		{
+			name: "complete spec",
+			json: `{"schema_version": 1, "job_config_name": "llm_llama3_1_8b", "dataset_id": "s3://bucket/train.jsonl",
+				"batch_size": 4, "shuffle": true, "num_epochs": 2, "use_lora": true, "use_qlora": false, "lr": 3e-4,
+				"override_env": "dev", "seed": 7, "num_gpus": 8, "min_gpu_memory": "80 GiB"}`,
+			want: types.JobSpec{SchemaVersion: 1, Workflow: "torchtunewrapper", JobConfigName: "llm_llama3_1_8b", DatasetID: "s3://bucket/train.jsonl",
+				BatchSize: 4, Shuffle: true, NumEpochs: 2, UseLora: true, LearningRate: 3e-4, OverrideEnv: "dev", Seed: 7,
+				NumGPUs: 8, MinGPUMemory: "80 GiB"},
+		},
+		{
+			name: "defaults of optional fields",
+			json: testJobSpec(0),
+			want: types.JobSpec{SchemaVersion: 1, Workflow: "torchtunewrapper", JobConfigName: "test", DatasetID: "gs://bucket/dataset", BatchSize: 8,
+				NumEpochs: 1, LearningRate: 1e-2, OverrideEnv: "prod", Seed: 42},
+		},
+		{
+			name:          "not a JSON object",
+			json:          `{job_config_name: "llm_dummy"}`,
+			expectedError: "job spec is not a JSON object",
+		},
+		{
+			name:          "no schema version",
+			json:          `{"job_config_name": "llm_dummy", "num_gpus": "1"}`,
+			expectedError: "job spec has no schema_version, expected 1",
+		},
+		{
+			name:          "unsupported schema version",
+			json:          `{"schema_version": 2, "job_config_name": "llm_dummy"}`,
+			expectedError: "unsupported job spec schema_version 2, expected 1",
+		},
+		{
+			name:          "missing required fields",
+			json:          `{"schema_version": 1, "job_config_name": "llm_dummy", "num_gpus": 1}`,
+			expectedError: "job spec is missing dataset_id, batch_size, num_epochs",
+		},
+		{
+			name: "string instead of integer",
+			json: `{"schema_version": 1, "job_config_name": "test", "dataset_id": "gs://bucket/dataset", "batch_size": "20",
+				"num_epochs": 1, "num_gpus": 1}`,
+			expectedError: "batch_size must be an integer, got string",
+		},
+		{
+			name: "string instead of bool",
+			json: `{"schema_version": 1, "job_config_name": "test", "dataset_id": "gs://bucket/dataset", "batch_size": 8,
+				"num_epochs": 1, "num_gpus": 1, "use_lora": "true"}`,
+			expectedError: "use_lora must be true or false, got string",
+		},
+		{
+			name: "unknown field",
+			json: `{"schema_version": 1, "job_config_name": "test", "dataset_id": "gs://bucket/dataset", "batch_size": 8,
+				"num_epochs": 1, "num_gpus": 1, "num_epoch": 3}`,
+			expectedError: `unknown field "num_epoch"`,
+		},
+		{
+			name: "values out of range",
+			json: `{"schema_version": 1, "job_config_name": " ", "dataset_id": "my-dataset", "batch_size": 0,
+				"num_epochs": -1, "num_gpus": -2, "lr": 0, "seed": -1, "min_system_memory": "lots"}`,
+			expectedError: `invalid job spec: job_config_name is empty; dataset_id "my-dataset" is not a URI such as gs://bucket/path; ` +
+				`batch_size must be positive, got 0; num_epochs must be positive, got -1; lr must be positive, got 0; ` +
+				`seed must not be negative, got -1; num_gpus must not be negative, got -2; invalid min_system_memory`,
+		},
+		{
+			name: "workflow with config",
+			json: `{"schema_version": 1, "workflow": "evaluation", "dataset_id": "s3://bucket/eval.jsonl", "num_gpus": 1,
+				"config": {"model": "llama3.1-8b", "max_samples": 500}}`,
+			want: types.JobSpec{SchemaVersion: 1, Workflow: "evaluation", DatasetID: "s3://bucket/eval.jsonl", NumGPUs: 1,
+				Config: map[string]json.RawMessage{"model": json.RawMessage(`"llama3.1-8b"`), "max_samples": json.RawMessage(`500`)}},
+		},
+		{
+			name:          "unknown workflow",
+			json:          `{"schema_version": 1, "workflow": "distillation", "config": {}}`,
+			expectedError: `invalid job spec: unknown workflow "distillation", expected one of`,
+		},
+		{
+			name:          "workflow without config",
+			json:          `{"schema_version": 1, "workflow": "evaluation", "num_gpus": 1}`,
+			expectedError: "job spec is missing config",
+		},
+		{
+			name:          "torchtunewrapper field in workflow spec",
+			json:          `{"schema_version": 1, "workflow": "evaluation", "batch_size": 8, "config": {"model": "llama3.1-8b"}}`,
+			expectedError: "job spec field batch_size is only used by the torchtunewrapper workflow, pass the arguments of evaluation in config",
+		},
+		{
+			name:          "config in torchtunewrapper spec",
+			json:          `{"schema_version": 1, "workflow": "torchtunewrapper", "config": {"model": "llama3.1-8b"}}`,
+			expectedError: "job spec config is only used by workflows other than torchtunewrapper",
+		},
+		{
+			name: "config not matching the workflow schema",
+			json: `{"schema_version": 1, "workflow": "evaluation", "dataset_id": "eval", "config": {"max_samples": "all", "top_k": 5}}`,
+			expectedError: `invalid job spec: dataset_id "eval" is not a URI such as gs://bucket/path; config is missing model; ` +
+				`config field max_samples must be of type integer, got string; unknown config field "top_k"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJobSpec([]byte(tt.json))
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// Tests parsing the job details of jobs read from the chain covering:
+// 1. Versioned specs, parsed as they are
+// 2. Legacy specs without schema_version, with values given as strings, an unquoted key,
+// fields legacy executors ignored and values given as numbers
+// 3. Legacy specs that are still malformed once migrated
+func TestParseJobDetails(t *testing.T) {
+	tests := []struct {
+		name          string
+		json          string
+		want          types.JobSpec
+		expectedError string
+	}{
+		{
+			name: "versioned spec",
+			json: testJobSpec(2),
+			want: types.JobSpec{SchemaVersion: 1, Workflow: "torchtunewrapper", JobConfigName: "test", DatasetID: "gs://bucket/dataset",
+				BatchSize: 8, NumEpochs: 1, LearningRate: 1e-2, OverrideEnv: "prod", Seed: 42, NumGPUs: 2},
+		},
+		{
+			name:          "versioned spec with an unknown field",
+			json:          `{"schema_version": 1, "job_config_name": "test", "dataset_id": "gs://bucket/dataset", "batch_size": 8, "num_epochs": 1, "num_gpus": 1, "job_id": "13"}`,
+			expectedError: `invalid job spec: json: unknown field "job_id"`,
+		},
+		{
+			name: "legacy spec with string values",
+			json: `{"job_config_name": "llm_dummy", "job_id": "13", "dataset_id": "gs://bucket/dataset", "batch_size": "20",
+				"shuffle": "true", "num_epochs": "1", "use_lora": "true", "use_qlora": "false", "lr": "1e-2",
+				"override_env": "prod", "seed": "42", "num_gpus": "1", "user_id": "0x4118CFD00dD5e8CED96e0ff8061F56F2d155e83B"}`,
+			want: types.JobSpec{SchemaVersion: 1, Workflow: "torchtunewrapper", JobConfigName: "llm_dummy", DatasetID: "gs://bucket/dataset",
+				BatchSize: 20, Shuffle: true, NumEpochs: 1, UseLora: true, LearningRate: 1e-2, OverrideEnv: "prod", Seed: 42, NumGPUs: 1},
+		},
+		{
+			name: "legacy spec with an unquoted key and numbers",
+			json: `{ job_config_name: "llm_dummy", "dataset_id": "gs://bucket/dataset", "batch_size": 4, "num_epochs": 2,
+				"lr": "", "num_gpus": 1, "min_gpu_memory": 24}`,
+			want: types.JobSpec{SchemaVersion: 1, Workflow: "torchtunewrapper", JobConfigName: "llm_dummy", DatasetID: "gs://bucket/dataset",
+				BatchSize: 4, NumEpochs: 2, LearningRate: 1e-2, OverrideEnv: "prod", Seed: 42, NumGPUs: 1, MinGPUMemory: "24"},
+		},
+		{
+			name:          "legacy spec with a value of the wrong type",
+			json:          `{"job_config_name": "llm_dummy", "dataset_id": "gs://bucket/dataset", "batch_size": "many", "num_epochs": "1", "num_gpus": "1"}`,
+			expectedError: "invalid job spec: batch_size must be an integer",
+		},
+		{
+			name:          "legacy spec missing fields",
+			json:          `{"job_config_name": "llm_dummy", "num_gpus": "1"}`,
+			expectedError: "job spec is missing dataset_id, batch_size, num_epochs",
+		},
+		{
+			name:          "not a JSON object",
+			json:          `job_config_name`,
+			expectedError: "job spec is not a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJobDetails([]byte(tt.json))
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// Tests converting a job spec into the string arguments of the pipeline config, and
+// the spec of another workflow into its config object
+func TestNewJobConfig(t *testing.T) {
+	spec := types.JobSpec{SchemaVersion: 1, JobConfigName: "llm_dummy", DatasetID: "gs://bucket/dataset", BatchSize: 20,
+		Shuffle: true, NumEpochs: 1, UseLora: true, LearningRate: 1e-2, OverrideEnv: "prod", Seed: 42, NumGPUs: 1}
+	creator := common.HexToAddress("0x4118CFD00dD5e8CED96e0ff8061F56F2d155e83B")
+
+	assert.Equal(t, types.JobConfig{
+		Workflow:      "torchtunewrapper",
+		JobConfigName: "llm_dummy",
+		JobID:         "13",
+		DatasetID:     "gs://bucket/dataset",
+		BatchSize:     "20",
+		Shuffle:       "true",
+		NumEpochs:     "1",
+		UseLora:       "true",
+		UseQlora:      "false",
+		LearningRate:  "0.01",
+		OverrideEnv:   "prod",
+		Seed:          "42",
+		NumGPUs:       "1",
+		UserID:        creator.String(),
+	}, newJobConfig(spec, big.NewInt(13), creator))
+
+	evaluation := types.JobSpec{SchemaVersion: 1, Workflow: "evaluation", DatasetID: "s3://bucket/eval.jsonl", NumGPUs: 2,
+		Config: map[string]json.RawMessage{"model": json.RawMessage(`"llama3.1-8b"`)}}
+	assert.Equal(t, types.JobConfig{
+		Workflow:  "evaluation",
+		JobID:     "14",
+		DatasetID: "s3://bucket/eval.jsonl",
+		NumGPUs:   "2",
+		UserID:    creator.String(),
+		Config:    map[string]json.RawMessage{"model": json.RawMessage(`"llama3.1-8b"`)},
+	}, newJobConfig(evaluation, big.NewInt(14), creator))
+}
+
+// registerTestWorkflow registers the evaluation script workflow used by the job spec tests
+// through a workflows file, like setConfig --workflowsFile
+func registerTestWorkflow(t *testing.T) {
+	workflowsFile := filepath.Join(t.TempDir(), "workflows.json")
+	assert.NoError(t, os.WriteFile(workflowsFile, []byte(`[{
+		"name": "evaluation",
+		"script": "scripts/runners/evaluate.sh",
+		"schema": {"required": ["model"], "fields": {"model": "string", "max_samples": "integer"}}
+	}]`), 0644))
+	assert.NoError(t, registerConfiguredWorkflows(types.Configurations{WorkflowsFile: workflowsFile}))
+}
+
+// Tests registering the script workflows of the configured workflows file with cases:
+// 1. Without a workflows file only the built-in workflows are available
+// 2. The workflows of the file become selectable by job specs with their schema
+// 3. An invalid workflows file is rejected
+func TestRegisterConfiguredWorkflows(t *testing.T) {
+	assert.NoError(t, registerConfiguredWorkflows(types.Configurations{}))
+
+	registerTestWorkflow(t)
+	workflow, err := pipeline_zen.WorkflowFor("evaluation")
+	assert.NoError(t, err)
+	assert.Equal(t, pipeline_zen.ScriptWorkflow{
+		WorkflowName: "evaluation",
+		Script:       "scripts/runners/evaluate.sh",
+		Schema: pipeline_zen.WorkflowSchema{
+			Required: []string{"model"},
+			Fields:   map[string]string{"model": "string", "max_samples": "integer"},
+		},
+	}, workflow)
+	spec, err := parseJobSpec([]byte(`{"schema_version": 1, "workflow": "evaluation", "dataset_id": "s3://bucket/eval.jsonl", "config": {"model": "llama3.1-8b"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "evaluation", spec.Workflow)
+	_, err = parseJobSpec([]byte(`{"schema_version": 1, "workflow": "evaluation", "config": {"max_samples": 5}}`))
+	assert.ErrorContains(t, err, "config is missing model")
+
+	err = registerConfiguredWorkflows(types.Configurations{WorkflowsFile: filepath.Join(t.TempDir(), "workflows.json")})
+	assert.ErrorContains(t, err, "failed to read workflows file")
+}
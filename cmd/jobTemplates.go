@@ -0,0 +1,278 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"lumino/core/types"
+	"lumino/path"
+	"lumino/utils"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+// builtInJobTemplates holds the job templates shipped with the binary
+//
+//go:embed templates/*.json
+var builtInJobTemplates embed.FS
+
+// jobTemplateNamePattern matches template names, which are also their file names
+var jobTemplateNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+var jobTemplatesCmd = &cobra.Command{
+	Use:   "jobTemplates",
+	Short: "List and show the job templates used by createJob --template",
+	Long: `Lists and shows the job templates that createJob --template starts from. Built-in templates are
+embedded in the binary; user templates are the <name>.json files in ~/.lumino/templates and take
+precedence over built-in templates of the same name.
+
+Example:
+  ./lumino jobTemplates list
+  ./lumino jobTemplates show llama3.1-8b-lora`,
+}
+
+var jobTemplatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the built-in and user job templates",
+	Run:   initialiseListJobTemplates,
+}
+
+var jobTemplatesShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show the job spec of a template",
+	Args:  cobra.ExactArgs(1),
+	Run:   initialiseShowJobTemplate,
+}
+
+func initialiseListJobTemplates(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteListJobTemplates()
+}
+
+func initialiseShowJobTemplate(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteShowJobTemplate(args[0])
+}
+
+// ExecuteListJobTemplates lists the job templates with the required fields each leaves to --set.
+// Exits with error if the templates cannot be read.
+func (*UtilsStruct) ExecuteListJobTemplates() {
+	templates, err := listJobTemplates()
+	utils.CheckError("Error in listing job templates: ", err)
+
+	err = renderJobTemplates(os.Stdout, templates)
+	utils.CheckError("Error in showing job templates: ", err)
+}
+
+// ExecuteShowJobTemplate prints the job spec of a template.
+// Exits with error if the template does not exist or cannot be read.
+func (*UtilsStruct) ExecuteShowJobTemplate(name string) {
+	template, err := loadJobTemplate(name)
+	utils.CheckError("Error in loading job template: ", err)
+
+	log.WithField("source", template.Source).Debug("Loaded job template")
+	err = writeJSON(os.Stdout, template.Fields)
+	utils.CheckError("Error in showing job template: ", err)
+}
+
+// loadJobTemplate returns the template with the given name, looking it up in the user
+// templates directory first and in the built-in templates second.
+// Returns error if the name is invalid, no template has it or the template is not a JSON object.
+func loadJobTemplate(name string) (types.JobTemplate, error) {
+	if !jobTemplateNamePattern.MatchString(name) {
+		return types.JobTemplate{}, fmt.Errorf("invalid template name %q", name)
+	}
+
+	templatesDir, err := path.PathUtilsInterface.GetTemplatesDirPath()
+	if err != nil {
+		return types.JobTemplate{}, fmt.Errorf("failed to get templates directory: %w", err)
+	}
+	userPath := templatesDir + "/" + name + ".json"
+	data, err := path.OSUtilsInterface.ReadFile(userPath)
+	if err == nil {
+		return newJobTemplate(name, userPath, data)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return types.JobTemplate{}, fmt.Errorf("failed to read template %s: %w", userPath, err)
+	}
+
+	data, err = builtInJobTemplates.ReadFile("templates/" + name + ".json")
+	if err != nil {
+		return types.JobTemplate{}, fmt.Errorf("unknown job template %q, see jobTemplates list", name)
+	}
+	return newJobTemplate(name, types.JobTemplateSourceBuiltIn, data)
+}
+
+// listJobTemplates returns every template sorted by name, a user template hiding the
+// built-in template of the same name.
+// Returns error if a template cannot be read.
+func listJobTemplates() ([]types.JobTemplate, error) {
+	byName := make(map[string]types.JobTemplate)
+
+	entries, err := builtInJobTemplates.ReadDir("templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in templates: %w", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		data, err := builtInJobTemplates.ReadFile("templates/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in template %s: %w", name, err)
+		}
+		template, err := newJobTemplate(name, types.JobTemplateSourceBuiltIn, data)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = template
+	}
+
+	templatesDir, err := path.PathUtilsInterface.GetTemplatesDirPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get templates directory: %w", err)
+	}
+	userEntries, err := path.OSUtilsInterface.ReadDir(templatesDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+	for _, entry := range userEntries {
+		name, isTemplate := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !isTemplate || !jobTemplateNamePattern.MatchString(name) {
+			continue
+		}
+		userPath := templatesDir + "/" + entry.Name()
+		data, err := path.OSUtilsInterface.ReadFile(userPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", userPath, err)
+		}
+		template, err := newJobTemplate(name, userPath, data)
+		if err != nil {
+			return nil, err
+		}
+		byName[name] = template
+	}
+
+	templates := make([]types.JobTemplate, 0, len(byName))
+	for _, template := range byName {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates, nil
+}
+
+// newJobTemplate decodes the fields of a template.
+// Returns error if the template is not a JSON object.
+func newJobTemplate(name string, source string, data []byte) (types.JobTemplate, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return types.JobTemplate{}, fmt.Errorf("template %s is not a JSON object: %w", source, err)
+	}
+	if fields == nil {
+		fields = make(map[string]json.RawMessage)
+	}
+	return types.JobTemplate{Name: name, Source: source, Fields: fields}, nil
+}
+
+// applyJobSpecOverrides sets the job spec fields given as key=value. Values are converted
+// to the type of the field, so --set num_epochs=2 sets a number, --set shuffle=true a bool and
+// --set config='{"model": "llama3.1-8b"}' an object.
+// Returns error if an override is malformed, names an unknown field or has a value of the wrong type.
+func applyJobSpecOverrides(fields map[string]json.RawMessage, overrides []string) error {
+	kinds := jobSpecFieldKinds()
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --set %q, expected key=value", override)
+		}
+		kind, known := kinds[key]
+		if !known {
+			return fmt.Errorf("unknown job spec field %q in --set", key)
+		}
+
+		var typed interface{}
+		var err error
+		switch kind {
+		case reflect.Int:
+			typed, err = strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid --set %s=%s, expected an integer", key, value)
+			}
+		case reflect.Float64:
+			typed, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("invalid --set %s=%s, expected a number", key, value)
+			}
+		case reflect.Bool:
+			typed, err = strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid --set %s=%s, expected true or false", key, value)
+			}
+		case reflect.Map:
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(value), &object); err != nil {
+				return fmt.Errorf("invalid --set %s=%s, expected a JSON object", key, value)
+			}
+			typed = object
+		default:
+			typed = value
+		}
+
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return fmt.Errorf("failed to encode --set %s: %w", key, err)
+		}
+		fields[key] = encoded
+	}
+	return nil
+}
+
+// jobSpecFieldKinds maps the JSON name of every job spec field to its kind
+func jobSpecFieldKinds() map[string]reflect.Kind {
+	specType := reflect.TypeOf(types.JobSpec{})
+	kinds := make(map[string]reflect.Kind, specType.NumField())
+	for i := 0; i < specType.NumField(); i++ {
+		field := specType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		kinds[name] = field.Type.Kind()
+	}
+	return kinds
+}
+
+// renderJobTemplates writes the templates to out as a table
+func renderJobTemplates(out io.Writer, templates []types.JobTemplate) error {
+	if len(templates) == 0 {
+		_, err := fmt.Fprintln(out, "No job templates")
+		return err
+	}
+
+	table := tablewriter.NewWriter(out)
+	table.SetHeader([]string{"Name", "Job Config Name", "GPUs", "Needs --set", "Source"})
+	for _, template := range templates {
+		var jobConfigName string
+		_ = json.Unmarshal(template.Fields["job_config_name"], &jobConfigName)
+		table.Append([]string{
+			template.Name,
+			orDash(jobConfigName),
+			orDash(string(template.Fields["num_gpus"])),
+			orDash(strings.Join(missingJobSpecFields(template.Fields), ", ")),
+			template.Source,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// Initializes the jobTemplates command group in the CLI.
+func init() {
+	rootCmd.AddCommand(jobTemplatesCmd)
+	jobTemplatesCmd.AddCommand(jobTemplatesListCmd, jobTemplatesShowCmd)
+}
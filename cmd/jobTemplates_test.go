@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupTemplatesDir points the user templates directory at a temporary directory holding the given templates
+func setupTemplatesDir(t *testing.T, templates map[string]string) string {
+	templatesDir := filepath.Join(t.TempDir(), "templates")
+	if templates != nil {
+		assert.NoError(t, os.Mkdir(templatesDir, 0700))
+	}
+	for name, content := range templates {
+		assert.NoError(t, os.WriteFile(filepath.Join(templatesDir, name), []byte(content), 0600))
+	}
+
+	pathMock := new(pathMocks.PathInterface)
+	pathMock.On("GetTemplatesDirPath").Return(templatesDir, nil)
+
+	originalPathUtils := path.PathUtilsInterface
+	originalOSUtils := path.OSUtilsInterface
+	t.Cleanup(func() {
+		path.PathUtilsInterface = originalPathUtils
+		path.OSUtilsInterface = originalOSUtils
+	})
+	path.PathUtilsInterface = pathMock
+	path.OSUtilsInterface = path.OSUtils{}
+	return templatesDir
+}
+
+// Tests loading templates covering:
+// 1. Built-in templates when there is no user templates directory
+// 2. User templates shadowing built-in templates of the same name
+// 3. Unknown, invalid and malformed templates
+func TestLoadJobTemplate(t *testing.T) {
+	t.Run("built-in template", func(t *testing.T) {
+		setupTemplatesDir(t, nil)
+
+		template, err := loadJobTemplate("llm_dummy")
+		assert.NoError(t, err)
+		assert.Equal(t, types.JobTemplateSourceBuiltIn, template.Source)
+		assert.JSONEq(t, `"llm_dummy"`, string(template.Fields["job_config_name"]))
+	})
+
+	t.Run("user template shadows built-in template", func(t *testing.T) {
+		templatesDir := setupTemplatesDir(t, map[string]string{"llm_dummy.json": `{"job_config_name": "custom"}`})
+
+		template, err := loadJobTemplate("llm_dummy")
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(templatesDir, "llm_dummy.json"), template.Source)
+		assert.JSONEq(t, `"custom"`, string(template.Fields["job_config_name"]))
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		setupTemplatesDir(t, map[string]string{})
+
+		_, err := loadJobTemplate("missing")
+		assert.EqualError(t, err, `unknown job template "missing", see jobTemplates list`)
+	})
+
+	t.Run("name escaping the templates directory", func(t *testing.T) {
+		setupTemplatesDir(t, nil)
+
+		_, err := loadJobTemplate("../config")
+		assert.EqualError(t, err, `invalid template name "../config"`)
+	})
+
+	t.Run("template is not an object", func(t *testing.T) {
+		setupTemplatesDir(t, map[string]string{"broken.json": `[1, 2]`})
+
+		_, err := loadJobTemplate("broken")
+		assert.ErrorContains(t, err, "is not a JSON object")
+	})
+}
+
+// Tests listing templates merges built-in and user templates by name and skips other files
+func TestListJobTemplates(t *testing.T) {
+	templatesDir := setupTemplatesDir(t, map[string]string{
+		"llm_dummy.json": `{"job_config_name": "custom"}`,
+		"mistral.json":   `{"job_config_name": "llm_mistral_7b"}`,
+		"notes.txt":      `not a template`,
+	})
+
+	templates, err := listJobTemplates()
+	assert.NoError(t, err)
+
+	var names, sources []string
+	for _, template := range templates {
+		names = append(names, template.Name)
+		sources = append(sources, template.Source)
+	}
+	assert.Equal(t, []string{"llama3.1-8b-lora", "llm_dummy", "mistral"}, names)
+	assert.Equal(t, []string{
+		types.JobTemplateSourceBuiltIn,
+		filepath.Join(templatesDir, "llm_dummy.json"),
+		filepath.Join(templatesDir, "mistral.json"),
+	}, sources)
+}
+
+// Tests the built-in templates are valid job specs once their missing fields are set
+func TestBuiltInJobTemplates(t *testing.T) {
+	setupTemplatesDir(t, nil)
+
+	templates, err := listJobTemplates()
+	assert.NoError(t, err)
+	for _, template := range templates {
+		t.Run(template.Name, func(t *testing.T) {
+			var overrides []string
+			for _, field := range missingJobSpecFields(template.Fields) {
+				assert.Equal(t, "dataset_id", field)
+				overrides = append(overrides, "dataset_id=gs://bucket/train.jsonl")
+			}
+			assert.NoError(t, applyJobSpecOverrides(template.Fields, overrides))
+
+			data, err := json.Marshal(template.Fields)
+			assert.NoError(t, err)
+			_, err = parseJobSpec(data)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// Tests applying --set overrides covering:
+// 1. Values converted to the type of each field
+// 2. Malformed overrides, unknown fields and values of the wrong type
+func TestApplyJobSpecOverrides(t *testing.T) {
+	tests := []struct {
+		name          string
+		overrides     []string
+		want          map[string]string
+		expectedError string
+	}{
+		{
+			name:      "typed values",
+			overrides: []string{"lr=3e-4", "num_epochs=2", "shuffle=true", "dataset_id=s3://bucket/a=b.jsonl", "override_env=dev"},
+			want: map[string]string{
+				"lr":           `0.0003`,
+				"num_epochs":   `2`,
+				"shuffle":      `true`,
+				"dataset_id":   `"s3://bucket/a=b.jsonl"`,
+				"override_env": `"dev"`,
+			},
+		},
+		{
+			name:          "missing value",
+			overrides:     []string{"num_epochs"},
+			expectedError: `invalid --set "num_epochs", expected key=value`,
+		},
+		{
+			name:          "unknown field",
+			overrides:     []string{"epochs=2"},
+			expectedError: `unknown job spec field "epochs" in --set`,
+		},
+		{
+			name:          "integer field",
+			overrides:     []string{"batch_size=large"},
+			expectedError: "invalid --set batch_size=large, expected an integer",
+		},
+		{
+			name:          "number field",
+			overrides:     []string{"lr=fast"},
+			expectedError: "invalid --set lr=fast, expected a number",
+		},
+		{
+			name:          "bool field",
+			overrides:     []string{"use_lora=yes"},
+			expectedError: "invalid --set use_lora=yes, expected true or false",
+		},
+		{
+			name:      "workflow config",
+			overrides: []string{"workflow=evaluation", `config={"model": "llama3.1-8b"}`},
+			want: map[string]string{
+				"workflow": `"evaluation"`,
+				"config":   `{"model": "llama3.1-8b"}`,
+			},
+		},
+		{
+			name:          "object field",
+			overrides:     []string{"config=llama3.1-8b"},
+			expectedError: "invalid --set config=llama3.1-8b, expected a JSON object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := map[string]json.RawMessage{"num_epochs": json.RawMessage(`1`)}
+			err := applyJobSpecOverrides(fields, tt.overrides)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			for field, value := range tt.want {
+				assert.JSONEq(t, value, string(fields[field]), field)
+			}
+		})
+	}
+}
+
+// Tests the templates table lists the required fields each template leaves to --set
+func TestRenderJobTemplates(t *testing.T) {
+	var out bytes.Buffer
+	err := renderJobTemplates(&out, []types.JobTemplate{{
+		Name:   "llama3.1-8b-lora",
+		Source: types.JobTemplateSourceBuiltIn,
+		Fields: map[string]json.RawMessage{
+			"schema_version":  json.RawMessage(`1`),
+			"job_config_name": json.RawMessage(`"llm_llama3_1_8b"`),
+			"batch_size":      json.RawMessage(`2`),
+			"num_epochs":      json.RawMessage(`1`),
+			"num_gpus":        json.RawMessage(`1`),
+		},
+	}})
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "llm_llama3_1_8b")
+	assert.Contains(t, out.String(), "dataset_id")
+
+	out.Reset()
+	assert.NoError(t, renderJobTemplates(&out, nil))
+	assert.Equal(t, "No job templates\n", out.String())
+}
@@ -0,0 +1,74 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	types "lumino/core/types"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AssignmentRoundInterface is an autogenerated mock type for the AssignmentRoundInterface type
+type AssignmentRoundInterface struct {
+	mock.Mock
+}
+
+// GetRound provides a mock function with given fields:
+func (_m *AssignmentRoundInterface) GetRound() (types.AssignmentRound, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRound")
+	}
+
+	var r0 types.AssignmentRound
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (types.AssignmentRound, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() types.AssignmentRound); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(types.AssignmentRound)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveRound provides a mock function with given fields: round
+func (_m *AssignmentRoundInterface) SaveRound(round types.AssignmentRound) error {
+	ret := _m.Called(round)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveRound")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(types.AssignmentRound) error); ok {
+		r0 = rf(round)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAssignmentRoundInterface creates a new instance of AssignmentRoundInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAssignmentRoundInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AssignmentRoundInterface {
+	mock := &AssignmentRoundInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,267 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	bindings "lumino/pkg/bindings"
+	big "math/big"
+
+	bind "github.com/ethereum/go-ethereum/accounts/abi/bind"
+
+	ethclient "github.com/ethereum/go-ethereum/ethclient"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockManagerInterface is an autogenerated mock type for the BlockManagerInterface type
+type BlockManagerInterface struct {
+	mock.Mock
+}
+
+// ConfirmBlock provides a mock function with given fields: client, opts, epoch
+func (_m *BlockManagerInterface) ConfirmBlock(client *ethclient.Client, opts *bind.TransactOpts, epoch uint32) (*types.Transaction, error) {
+	ret := _m.Called(client, opts, epoch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmBlock")
+	}
+
+	var r0 *types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, uint32) (*types.Transaction, error)); ok {
+		return rf(client, opts, epoch)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, uint32) *types.Transaction); ok {
+		r0 = rf(client, opts, epoch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.TransactOpts, uint32) error); ok {
+		r1 = rf(client, opts, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockIndexToBeConfirmed provides a mock function with given fields: client, opts
+func (_m *BlockManagerInterface) GetBlockIndexToBeConfirmed(client *ethclient.Client, opts *bind.CallOpts) (int8, error) {
+	ret := _m.Called(client, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockIndexToBeConfirmed")
+	}
+
+	var r0 int8
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) (int8, error)); ok {
+		return rf(client, opts)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) int8); ok {
+		r0 = rf(client, opts)
+	} else {
+		r0 = ret.Get(0).(int8)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts) error); ok {
+		r1 = rf(client, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetConfirmedBlock provides a mock function with given fields: client, opts, epoch
+func (_m *BlockManagerInterface) GetConfirmedBlock(client *ethclient.Client, opts *bind.CallOpts, epoch uint32) (bindings.StructsBlock, error) {
+	ret := _m.Called(client, opts, epoch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConfirmedBlock")
+	}
+
+	var r0 bindings.StructsBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32) (bindings.StructsBlock, error)); ok {
+		return rf(client, opts, epoch)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32) bindings.StructsBlock); ok {
+		r0 = rf(client, opts, epoch)
+	} else {
+		r0 = ret.Get(0).(bindings.StructsBlock)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts, uint32) error); ok {
+		r1 = rf(client, opts, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMaxBlocksPerEpochPerStaker provides a mock function with given fields: client, opts
+func (_m *BlockManagerInterface) GetMaxBlocksPerEpochPerStaker(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error) {
+	ret := _m.Called(client, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMaxBlocksPerEpochPerStaker")
+	}
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) (*big.Int, error)); ok {
+		return rf(client, opts)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) *big.Int); ok {
+		r0 = rf(client, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts) error); ok {
+		r1 = rf(client, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNumProposedBlocks provides a mock function with given fields: client, opts, epoch
+func (_m *BlockManagerInterface) GetNumProposedBlocks(client *ethclient.Client, opts *bind.CallOpts, epoch uint32) (*big.Int, error) {
+	ret := _m.Called(client, opts, epoch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNumProposedBlocks")
+	}
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32) (*big.Int, error)); ok {
+		return rf(client, opts, epoch)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32) *big.Int); ok {
+		r0 = rf(client, opts, epoch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts, uint32) error); ok {
+		r1 = rf(client, opts, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetProposedBlock provides a mock function with given fields: client, opts, epoch, blockId
+func (_m *BlockManagerInterface) GetProposedBlock(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, blockId uint32) (bindings.StructsBlock, error) {
+	ret := _m.Called(client, opts, epoch, blockId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetProposedBlock")
+	}
+
+	var r0 bindings.StructsBlock
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32, uint32) (bindings.StructsBlock, error)); ok {
+		return rf(client, opts, epoch, blockId)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32, uint32) bindings.StructsBlock); ok {
+		r0 = rf(client, opts, epoch, blockId)
+	} else {
+		r0 = ret.Get(0).(bindings.StructsBlock)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts, uint32, uint32) error); ok {
+		r1 = rf(client, opts, epoch, blockId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSortedProposedBlockId provides a mock function with given fields: client, opts, epoch, index
+func (_m *BlockManagerInterface) GetSortedProposedBlockId(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, index *big.Int) (uint32, error) {
+	ret := _m.Called(client, opts, epoch, index)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSortedProposedBlockId")
+	}
+
+	var r0 uint32
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32, *big.Int) (uint32, error)); ok {
+		return rf(client, opts, epoch, index)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, uint32, *big.Int) uint32); ok {
+		r0 = rf(client, opts, epoch, index)
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts, uint32, *big.Int) error); ok {
+		r1 = rf(client, opts, epoch, index)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Propose provides a mock function with given fields: client, opts, epoch, jobIds
+func (_m *BlockManagerInterface) Propose(client *ethclient.Client, opts *bind.TransactOpts, epoch uint32, jobIds []*big.Int) (*types.Transaction, error) {
+	ret := _m.Called(client, opts, epoch, jobIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Propose")
+	}
+
+	var r0 *types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, uint32, []*big.Int) (*types.Transaction, error)); ok {
+		return rf(client, opts, epoch, jobIds)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, uint32, []*big.Int) *types.Transaction); ok {
+		r0 = rf(client, opts, epoch, jobIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.TransactOpts, uint32, []*big.Int) error); ok {
+		r1 = rf(client, opts, epoch, jobIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewBlockManagerInterface creates a new instance of BlockManagerInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBlockManagerInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BlockManagerInterface {
+	mock := &BlockManagerInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,119 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	common "github.com/ethereum/go-ethereum/common"
+	ethclient "github.com/ethereum/go-ethereum/ethclient"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "lumino/core/types"
+)
+
+// EventIndexInterface is an autogenerated mock type for the EventIndexInterface type
+type EventIndexInterface struct {
+	mock.Mock
+}
+
+// FilterIndexedEvents provides a mock function with given fields: client, fromBlock, toBlock
+func (_m *EventIndexInterface) FilterIndexedEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.IndexedEvent, error) {
+	ret := _m.Called(client, fromBlock, toBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FilterIndexedEvents")
+	}
+
+	var r0 []types.IndexedEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64, uint64) ([]types.IndexedEvent, error)); ok {
+		return rf(client, fromBlock, toBlock)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64, uint64) []types.IndexedEvent); ok {
+		r0 = rf(client, fromBlock, toBlock)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.IndexedEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint64, uint64) error); ok {
+		r1 = rf(client, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockHash provides a mock function with given fields: client, blockNumber
+func (_m *EventIndexInterface) GetBlockHash(client *ethclient.Client, blockNumber uint64) (common.Hash, error) {
+	ret := _m.Called(client, blockNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockHash")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64) (common.Hash, error)); ok {
+		return rf(client, blockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64) common.Hash); ok {
+		r0 = rf(client, blockNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint64) error); ok {
+		r1 = rf(client, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEpochStartBlock provides a mock function with given fields: client, epoch
+func (_m *EventIndexInterface) GetEpochStartBlock(client *ethclient.Client, epoch uint32) (uint64, error) {
+	ret := _m.Called(client, epoch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEpochStartBlock")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint32) (uint64, error)); ok {
+		return rf(client, epoch)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint32) uint64); ok {
+		r0 = rf(client, epoch)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint32) error); ok {
+		r1 = rf(client, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEventIndexInterface creates a new instance of EventIndexInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventIndexInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventIndexInterface {
+	mock := &EventIndexInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
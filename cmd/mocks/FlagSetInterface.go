@@ -180,6 +180,34 @@ func (_m *FlagSetInterface) GetInt32Wait(flagSet *pflag.FlagSet) (int32, error)
 	return r0, r1
 }
 
+// GetInt64DatasetCacheSize provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetInt64DatasetCacheSize(flagSet *pflag.FlagSet) (int64, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInt64DatasetCacheSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (int64, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) int64); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetInt64RPCTimeout provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetInt64RPCTimeout(flagSet *pflag.FlagSet) (int64, error) {
 	ret := _m.Called(flagSet)
@@ -208,6 +236,34 @@ func (_m *FlagSetInterface) GetInt64RPCTimeout(flagSet *pflag.FlagSet) (int64, e
 	return r0, r1
 }
 
+// GetInt64ShutdownTimeout provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetInt64ShutdownTimeout(flagSet *pflag.FlagSet) (int64, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInt64ShutdownTimeout")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (int64, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) int64); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRootFloat32GasLimit provides a mock function with given fields:
 func (_m *FlagSetInterface) GetRootFloat32GasLimit() (float32, error) {
 	ret := _m.Called()
@@ -348,6 +404,34 @@ func (_m *FlagSetInterface) GetRootInt32Wait() (int32, error) {
 	return r0, r1
 }
 
+// GetRootInt64DatasetCacheSize provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootInt64DatasetCacheSize() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootInt64DatasetCacheSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRootInt64RPCTimeout provides a mock function with given fields:
 func (_m *FlagSetInterface) GetRootInt64RPCTimeout() (int64, error) {
 	ret := _m.Called()
@@ -376,6 +460,118 @@ func (_m *FlagSetInterface) GetRootInt64RPCTimeout() (int64, error) {
 	return r0, r1
 }
 
+// GetRootInt64ShutdownTimeout provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootInt64ShutdownTimeout() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootInt64ShutdownTimeout")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRootStringAssignmentStrategy provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootStringAssignmentStrategy() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootStringAssignmentStrategy")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRootStringBlockManagerAddress provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootStringBlockManagerAddress() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootStringBlockManagerAddress")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRootStringDatasetRoot provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootStringDatasetRoot() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootStringDatasetRoot")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRootStringLogLevel provides a mock function with given fields:
 func (_m *FlagSetInterface) GetRootStringLogLevel() (string, error) {
 	ret := _m.Called()
@@ -432,6 +628,62 @@ func (_m *FlagSetInterface) GetRootStringProvider() (string, error) {
 	return r0, r1
 }
 
+// GetRootStringResultsStore provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootStringResultsStore() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootStringResultsStore")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRootStringWorkflowsFile provides a mock function with given fields:
+func (_m *FlagSetInterface) GetRootStringWorkflowsFile() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRootStringWorkflowsFile")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStringAddress provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetStringAddress(flagSet *pflag.FlagSet) (string, error) {
 	ret := _m.Called(flagSet)
@@ -460,6 +712,90 @@ func (_m *FlagSetInterface) GetStringAddress(flagSet *pflag.FlagSet) (string, er
 	return r0, r1
 }
 
+// GetStringAssignmentStrategy provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringAssignmentStrategy(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringAssignmentStrategy")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (string, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStringBlockManagerAddress provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringBlockManagerAddress(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringBlockManagerAddress")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (string, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetStringDatasetRoot provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringDatasetRoot(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringDatasetRoot")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (string, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStringLogLevel provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetStringLogLevel(flagSet *pflag.FlagSet) (string, error) {
 	ret := _m.Called(flagSet)
@@ -516,6 +852,34 @@ func (_m *FlagSetInterface) GetStringProvider(flagSet *pflag.FlagSet) (string, e
 	return r0, r1
 }
 
+// GetStringResultsStore provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringResultsStore(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringResultsStore")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (string, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStringValue provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetStringValue(flagSet *pflag.FlagSet) (string, error) {
 	ret := _m.Called(flagSet)
@@ -544,6 +908,34 @@ func (_m *FlagSetInterface) GetStringValue(flagSet *pflag.FlagSet) (string, erro
 	return r0, r1
 }
 
+// GetStringWorkflowsFile provides a mock function with given fields: flagSet
+func (_m *FlagSetInterface) GetStringWorkflowsFile(flagSet *pflag.FlagSet) (string, error) {
+	ret := _m.Called(flagSet)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStringWorkflowsFile")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) (string, error)); ok {
+		return rf(flagSet)
+	}
+	if rf, ok := ret.Get(0).(func(*pflag.FlagSet) string); ok {
+		r0 = rf(flagSet)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*pflag.FlagSet) error); ok {
+		r1 = rf(flagSet)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUint16JobId provides a mock function with given fields: flagSet
 func (_m *FlagSetInterface) GetUint16JobId(flagSet *pflag.FlagSet) (uint16, error) {
 	ret := _m.Called(flagSet)
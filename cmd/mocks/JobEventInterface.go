@@ -0,0 +1,87 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	ethclient "github.com/ethereum/go-ethereum/ethclient"
+	mock "github.com/stretchr/testify/mock"
+
+	types "lumino/core/types"
+)
+
+// JobEventInterface is an autogenerated mock type for the JobEventInterface type
+type JobEventInterface struct {
+	mock.Mock
+}
+
+// FilterJobEvents provides a mock function with given fields: client, fromBlock, toBlock
+func (_m *JobEventInterface) FilterJobEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.JobEvent, error) {
+	ret := _m.Called(client, fromBlock, toBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FilterJobEvents")
+	}
+
+	var r0 []types.JobEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64, uint64) ([]types.JobEvent, error)); ok {
+		return rf(client, fromBlock, toBlock)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64, uint64) []types.JobEvent); ok {
+		r0 = rf(client, fromBlock, toBlock)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.JobEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint64, uint64) error); ok {
+		r1 = rf(client, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestBlockNumber provides a mock function with given fields: client
+func (_m *JobEventInterface) GetLatestBlockNumber(client *ethclient.Client) (uint64, error) {
+	ret := _m.Called(client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestBlockNumber")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) (uint64, error)); ok {
+		return rf(client)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client) uint64); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client) error); ok {
+		r1 = rf(client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewJobEventInterface creates a new instance of JobEventInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobEventInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobEventInterface {
+	mock := &JobEventInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,94 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	types "lumino/core/types"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// JobJournalInterface is an autogenerated mock type for the JobJournalInterface type
+type JobJournalInterface struct {
+	mock.Mock
+}
+
+// GetRecords provides a mock function with given fields:
+func (_m *JobJournalInterface) GetRecords() ([]types.JobJournalRecord, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecords")
+	}
+
+	var r0 []types.JobJournalRecord
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]types.JobJournalRecord, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []types.JobJournalRecord); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.JobJournalRecord)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordStage provides a mock function with given fields: record, event
+func (_m *JobJournalInterface) RecordStage(record types.JobJournalRecord, event types.JobJournalEvent) error {
+	ret := _m.Called(record, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordStage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(types.JobJournalRecord, types.JobJournalEvent) error); ok {
+		r0 = rf(record, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveRecord provides a mock function with given fields: jobId
+func (_m *JobJournalInterface) RemoveRecord(jobId string) error {
+	ret := _m.Called(jobId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveRecord")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(jobId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewJobJournalInterface creates a new instance of JobJournalInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewJobJournalInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *JobJournalInterface {
+	mock := &JobJournalInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -171,6 +171,36 @@ func (_m *JobsManagerInterface) GetJobForStaker(client *ethclient.Client, opts *
 	return r0, r1
 }
 
+// GetJobIdCounter provides a mock function with given fields: client, opts
+func (_m *JobsManagerInterface) GetJobIdCounter(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error) {
+	ret := _m.Called(client, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobIdCounter")
+	}
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) (*big.Int, error)); ok {
+		return rf(client, opts)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) *big.Int); ok {
+		r0 = rf(client, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts) error); ok {
+		r1 = rf(client, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetJobStatus provides a mock function with given fields: client, opts, jobId
 func (_m *JobsManagerInterface) GetJobStatus(client *ethclient.Client, opts *bind.CallOpts, jobId *big.Int) (uint8, error) {
 	ret := _m.Called(client, opts, jobId)
@@ -199,6 +229,154 @@ func (_m *JobsManagerInterface) GetJobStatus(client *ethclient.Client, opts *bin
 	return r0, r1
 }
 
+// GetRoleAdmin provides a mock function with given fields: client, opts, role
+func (_m *JobsManagerInterface) GetRoleAdmin(client *ethclient.Client, opts *bind.CallOpts, role [32]byte) ([32]byte, error) {
+	ret := _m.Called(client, opts, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleAdmin")
+	}
+
+	var r0 [32]byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, [32]byte) ([32]byte, error)); ok {
+		return rf(client, opts, role)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, [32]byte) [32]byte); ok {
+		r0 = rf(client, opts, role)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([32]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts, [32]byte) error); ok {
+		r1 = rf(client, opts, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantRole provides a mock function with given fields: client, opts, role, account
+func (_m *JobsManagerInterface) GrantRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*types.Transaction, error) {
+	ret := _m.Called(client, opts, role, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantRole")
+	}
+
+	var r0 *types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) (*types.Transaction, error)); ok {
+		return rf(client, opts, role, account)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) *types.Transaction); ok {
+		r0 = rf(client, opts, role, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) error); ok {
+		r1 = rf(client, opts, role, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasRole provides a mock function with given fields: client, opts, role, account
+func (_m *JobsManagerInterface) HasRole(client *ethclient.Client, opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error) {
+	ret := _m.Called(client, opts, role, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasRole")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, [32]byte, common.Address) (bool, error)); ok {
+		return rf(client, opts, role, account)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts, [32]byte, common.Address) bool); ok {
+		r0 = rf(client, opts, role, account)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts, [32]byte, common.Address) error); ok {
+		r1 = rf(client, opts, role, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RenounceRole provides a mock function with given fields: client, opts, role, account
+func (_m *JobsManagerInterface) RenounceRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*types.Transaction, error) {
+	ret := _m.Called(client, opts, role, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenounceRole")
+	}
+
+	var r0 *types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) (*types.Transaction, error)); ok {
+		return rf(client, opts, role, account)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) *types.Transaction); ok {
+		r0 = rf(client, opts, role, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) error); ok {
+		r1 = rf(client, opts, role, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRole provides a mock function with given fields: client, opts, role, account
+func (_m *JobsManagerInterface) RevokeRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*types.Transaction, error) {
+	ret := _m.Called(client, opts, role, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 *types.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) (*types.Transaction, error)); ok {
+		return rf(client, opts, role, account)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) *types.Transaction); ok {
+		r0 = rf(client, opts, role, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.TransactOpts, [32]byte, common.Address) error); ok {
+		r1 = rf(client, opts, role, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateJobStatus provides a mock function with given fields: client, opts, jobId, status, buffer
 func (_m *JobsManagerInterface) UpdateJobStatus(client *ethclient.Client, opts *bind.TransactOpts, jobId *big.Int, status uint8, buffer uint8) (*types.Transaction, error) {
 	ret := _m.Called(client, opts, jobId, status, buffer)
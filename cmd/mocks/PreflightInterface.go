@@ -0,0 +1,109 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	pipeline_zen "lumino/pipeline-zen"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PreflightInterface is an autogenerated mock type for the PreflightInterface type
+type PreflightInterface struct {
+	mock.Mock
+}
+
+// CheckPipelineEnvironment provides a mock function with given fields: runtime, pipelinePath
+func (_m *PreflightInterface) CheckPipelineEnvironment(runtime pipeline_zen.Runtime, pipelinePath string) error {
+	ret := _m.Called(runtime, pipelinePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckPipelineEnvironment")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(pipeline_zen.Runtime, string) error); ok {
+		r0 = rf(runtime, pipelinePath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetFreeDiskSpace provides a mock function with given fields: dir
+func (_m *PreflightInterface) GetFreeDiskSpace(dir string) (uint64, error) {
+	ret := _m.Called(dir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFreeDiskSpace")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (uint64, error)); ok {
+		return rf(dir)
+	}
+	if rf, ok := ret.Get(0).(func(string) uint64); ok {
+		r0 = rf(dir)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(dir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMemory provides a mock function with given fields:
+func (_m *PreflightInterface) GetMemory() (uint64, uint64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMemory")
+	}
+
+	var r0 uint64
+	var r1 uint64
+	var r2 error
+	if rf, ok := ret.Get(0).(func() (uint64, uint64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func() uint64); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Get(1).(uint64)
+	}
+
+	if rf, ok := ret.Get(2).(func() error); ok {
+		r2 = rf()
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// NewPreflightInterface creates a new instance of PreflightInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPreflightInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PreflightInterface {
+	mock := &PreflightInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
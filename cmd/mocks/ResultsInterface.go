@@ -0,0 +1,124 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	big "math/big"
+
+	common "github.com/ethereum/go-ethereum/common"
+
+	mock "github.com/stretchr/testify/mock"
+
+	types "lumino/core/types"
+)
+
+// ResultsInterface is an autogenerated mock type for the ResultsInterface type
+type ResultsInterface struct {
+	mock.Mock
+}
+
+// FetchJobResults provides a mock function with given fields: location, creator, jobId, outputDir
+func (_m *ResultsInterface) FetchJobResults(location string, creator common.Address, jobId *big.Int, outputDir string) (types.ResultManifest, error) {
+	ret := _m.Called(location, creator, jobId, outputDir)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FetchJobResults")
+	}
+
+	var r0 types.ResultManifest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, common.Address, *big.Int, string) (types.ResultManifest, error)); ok {
+		return rf(location, creator, jobId, outputDir)
+	}
+	if rf, ok := ret.Get(0).(func(string, common.Address, *big.Int, string) types.ResultManifest); ok {
+		r0 = rf(location, creator, jobId, outputDir)
+	} else {
+		r0 = ret.Get(0).(types.ResultManifest)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, common.Address, *big.Int, string) error); ok {
+		r1 = rf(location, creator, jobId, outputDir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PackageJobResults provides a mock function with given fields: manifest, resultsDir, withArchive
+func (_m *ResultsInterface) PackageJobResults(manifest types.ResultManifest, resultsDir string, withArchive bool) (types.ResultManifest, error) {
+	ret := _m.Called(manifest, resultsDir, withArchive)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PackageJobResults")
+	}
+
+	var r0 types.ResultManifest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(types.ResultManifest, string, bool) (types.ResultManifest, error)); ok {
+		return rf(manifest, resultsDir, withArchive)
+	}
+	if rf, ok := ret.Get(0).(func(types.ResultManifest, string, bool) types.ResultManifest); ok {
+		r0 = rf(manifest, resultsDir, withArchive)
+	} else {
+		r0 = ret.Get(0).(types.ResultManifest)
+	}
+
+	if rf, ok := ret.Get(1).(func(types.ResultManifest, string, bool) error); ok {
+		r1 = rf(manifest, resultsDir, withArchive)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishJobResults provides a mock function with given fields: location, manifest
+func (_m *ResultsInterface) PublishJobResults(location string, manifest types.ResultManifest) error {
+	ret := _m.Called(location, manifest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishJobResults")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, types.ResultManifest) error); ok {
+		r0 = rf(location, manifest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveResultAttestation provides a mock function with given fields: attestation
+func (_m *ResultsInterface) SaveResultAttestation(attestation types.ResultAttestation) error {
+	ret := _m.Called(attestation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveResultAttestation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(types.ResultAttestation) error); ok {
+		r0 = rf(attestation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewResultsInterface creates a new instance of ResultsInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewResultsInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ResultsInterface {
+	mock := &ResultsInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
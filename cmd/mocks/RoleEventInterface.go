@@ -0,0 +1,59 @@
+// Code generated by mockery v2.49.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	ethclient "github.com/ethereum/go-ethereum/ethclient"
+	mock "github.com/stretchr/testify/mock"
+
+	types "lumino/core/types"
+)
+
+// RoleEventInterface is an autogenerated mock type for the RoleEventInterface type
+type RoleEventInterface struct {
+	mock.Mock
+}
+
+// FilterRoleEvents provides a mock function with given fields: client, fromBlock, toBlock
+func (_m *RoleEventInterface) FilterRoleEvents(client *ethclient.Client, fromBlock uint64, toBlock uint64) ([]types.RoleEvent, error) {
+	ret := _m.Called(client, fromBlock, toBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FilterRoleEvents")
+	}
+
+	var r0 []types.RoleEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64, uint64) ([]types.RoleEvent, error)); ok {
+		return rf(client, fromBlock, toBlock)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint64, uint64) []types.RoleEvent); ok {
+		r0 = rf(client, fromBlock, toBlock)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.RoleEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint64, uint64) error); ok {
+		r1 = rf(client, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRoleEventInterface creates a new instance of RoleEventInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleEventInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleEventInterface {
+	mock := &RoleEventInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
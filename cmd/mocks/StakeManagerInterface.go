@@ -21,6 +21,36 @@ type StakeManagerInterface struct {
 	mock.Mock
 }
 
+// GetMinStake provides a mock function with given fields: client, opts
+func (_m *StakeManagerInterface) GetMinStake(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error) {
+	ret := _m.Called(client, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMinStake")
+	}
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) (*big.Int, error)); ok {
+		return rf(client, opts)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, *bind.CallOpts) *big.Int); ok {
+		r0 = rf(client, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, *bind.CallOpts) error); ok {
+		r1 = rf(client, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNumStakers provides a mock function with given fields: client, opts
 func (_m *StakeManagerInterface) GetNumStakers(client *ethclient.Client, opts *bind.CallOpts) (uint32, error) {
 	ret := _m.Called(client, opts)
@@ -87,6 +87,36 @@ func (_m *UtilsCmdInterface) AssignJob(client *ethclient.Client, config types.Co
 	return r0, r1
 }
 
+// ConfirmBlock provides a mock function with given fields: client, config, account, epoch
+func (_m *UtilsCmdInterface) ConfirmBlock(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32) (common.Hash, error) {
+	ret := _m.Called(client, config, account, epoch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmBlock")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, uint32) (common.Hash, error)); ok {
+		return rf(client, config, account, epoch)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, uint32) common.Hash); ok {
+		r0 = rf(client, config, account, epoch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, types.Configurations, types.Account, uint32) error); ok {
+		r1 = rf(client, config, account, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: password
 func (_m *UtilsCmdInterface) Create(password string) (accounts.Account, error) {
 	ret := _m.Called(password)
@@ -150,6 +180,26 @@ func (_m *UtilsCmdInterface) ExecuteAssignJob(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteBlocksConfirmed provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteBlocksConfirmed(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteBlocksProposed provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteBlocksProposed(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteBlocksSorted provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteBlocksSorted(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteConfirmBlock provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteConfirmBlock(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteCreate provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteCreate(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -160,11 +210,46 @@ func (_m *UtilsCmdInterface) ExecuteCreateJob(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteFetchResults provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteFetchResults(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteGrantRole provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteGrantRole(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteImport provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteImport(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteIndexerEpoch provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteIndexerEpoch(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteIndexerJob provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteIndexerJob(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteIndexerStaker provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteIndexerStaker(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteIndexerStatus provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteIndexerStatus(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteIndexerSync provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteIndexerSync(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteJob provides a mock function with given fields: ctx, client, config, account, isAdmin, isRandom, pipelinePath
 func (_m *UtilsCmdInterface) ExecuteJob(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, isAdmin bool, isRandom bool, pipelinePath string) error {
 	ret := _m.Called(ctx, client, config, account, isAdmin, isRandom, pipelinePath)
@@ -183,11 +268,61 @@ func (_m *UtilsCmdInterface) ExecuteJob(ctx context.Context, client *ethclient.C
 	return r0
 }
 
+// ExecuteJobList provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteJobList(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteJobLogs provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteJobLogs(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteJobShow provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteJobShow(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteJobStatus provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteJobStatus(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteListJobTemplates provides a mock function with given fields:
+func (_m *UtilsCmdInterface) ExecuteListJobTemplates() {
+	_m.Called()
+}
+
+// ExecuteListRoles provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteListRoles(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteNetworkInfo provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteNetworkInfo(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteProposeBlock provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteProposeBlock(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteRenounceRole provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteRenounceRole(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteRevokeRole provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteRevokeRole(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
+// ExecuteShowJobTemplate provides a mock function with given fields: name
+func (_m *UtilsCmdInterface) ExecuteShowJobTemplate(name string) {
+	_m.Called(name)
+}
+
 // ExecuteStake provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteStake(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -198,11 +333,102 @@ func (_m *UtilsCmdInterface) ExecuteUnstake(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// ExecuteVerifyAttestation provides a mock function with given fields: flagSet
+func (_m *UtilsCmdInterface) ExecuteVerifyAttestation(flagSet *pflag.FlagSet) {
+	_m.Called(flagSet)
+}
+
 // ExecuteWithdraw provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) ExecuteWithdraw(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
 }
 
+// GetActiveStakers provides a mock function with given fields: client, epoch
+func (_m *UtilsCmdInterface) GetActiveStakers(client *ethclient.Client, epoch uint32) ([]types.StakerContract, error) {
+	ret := _m.Called(client, epoch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetActiveStakers")
+	}
+
+	var r0 []types.StakerContract
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint32) ([]types.StakerContract, error)); ok {
+		return rf(client, epoch)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, uint32) []types.StakerContract); ok {
+		r0 = rf(client, epoch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.StakerContract)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, uint32) error); ok {
+		r1 = rf(client, epoch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssignmentStrategy provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetAssignmentStrategy() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignmentStrategy")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockManagerAddress provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetBlockManagerAddress() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockManagerAddress")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBufferPercent provides a mock function with given fields:
 func (_m *UtilsCmdInterface) GetBufferPercent() (int32, error) {
 	ret := _m.Called()
@@ -259,6 +485,62 @@ func (_m *UtilsCmdInterface) GetConfigData() (types.Configurations, error) {
 	return r0, r1
 }
 
+// GetDatasetCacheSize provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetDatasetCacheSize() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDatasetCacheSize")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDatasetRoot provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetDatasetRoot() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDatasetRoot")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEpochAndState provides a mock function with given fields: client
 func (_m *UtilsCmdInterface) GetEpochAndState(client *ethclient.Client) (uint32, int64, error) {
 	ret := _m.Called(client)
@@ -480,6 +762,62 @@ func (_m *UtilsCmdInterface) GetRPCTimeout() (int64, error) {
 	return r0, r1
 }
 
+// GetResultsStore provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetResultsStore() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetResultsStore")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetShutdownTimeout provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetShutdownTimeout() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetShutdownTimeout")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWaitTime provides a mock function with given fields:
 func (_m *UtilsCmdInterface) GetWaitTime() (int32, error) {
 	ret := _m.Called()
@@ -508,6 +846,34 @@ func (_m *UtilsCmdInterface) GetWaitTime() (int32, error) {
 	return r0, r1
 }
 
+// GetWorkflowsFile provides a mock function with given fields:
+func (_m *UtilsCmdInterface) GetWorkflowsFile() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWorkflowsFile")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // HandleAssignState provides a mock function with given fields: ctx, client, config, account, epoch, isRandom
 func (_m *UtilsCmdInterface) HandleAssignState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, isRandom bool) error {
 	ret := _m.Called(ctx, client, config, account, epoch, isRandom)
@@ -610,6 +976,34 @@ func (_m *UtilsCmdInterface) HandleUpdateState(ctx context.Context, client *ethc
 	return r0
 }
 
+// HasAdminRole provides a mock function with given fields: client, address
+func (_m *UtilsCmdInterface) HasAdminRole(client *ethclient.Client, address string) (bool, error) {
+	ret := _m.Called(client, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasAdminRole")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, string) (bool, error)); ok {
+		return rf(client, address)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, string) bool); ok {
+		r0 = rf(client, address)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, string) error); ok {
+		r1 = rf(client, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImportAccount provides a mock function with given fields:
 func (_m *UtilsCmdInterface) ImportAccount() (accounts.Account, error) {
 	ret := _m.Called()
@@ -638,6 +1032,54 @@ func (_m *UtilsCmdInterface) ImportAccount() (accounts.Account, error) {
 	return r0, r1
 }
 
+// ProposeBlock provides a mock function with given fields: client, config, account, epoch, jobIds
+func (_m *UtilsCmdInterface) ProposeBlock(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, jobIds []*big.Int) (common.Hash, error) {
+	ret := _m.Called(client, config, account, epoch, jobIds)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProposeBlock")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, uint32, []*big.Int) (common.Hash, error)); ok {
+		return rf(client, config, account, epoch, jobIds)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, uint32, []*big.Int) common.Hash); ok {
+		r0 = rf(client, config, account, epoch, jobIds)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, types.Configurations, types.Account, uint32, []*big.Int) error); ok {
+		r1 = rf(client, config, account, epoch, jobIds)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecoverJobExecution provides a mock function with given fields: ctx, client, config, account, pipelinePath
+func (_m *UtilsCmdInterface) RecoverJobExecution(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, pipelinePath string) error {
+	ret := _m.Called(ctx, client, config, account, pipelinePath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecoverJobExecution")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *ethclient.Client, types.Configurations, types.Account, string) error); ok {
+		r0 = rf(ctx, client, config, account, pipelinePath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RunExecuteJob provides a mock function with given fields: flagSet
 func (_m *UtilsCmdInterface) RunExecuteJob(flagSet *pflag.FlagSet) {
 	_m.Called(flagSet)
@@ -751,6 +1193,36 @@ func (_m *UtilsCmdInterface) UpdateJobStatus(client *ethclient.Client, config ty
 	return r0, r1
 }
 
+// UpdateRole provides a mock function with given fields: client, config, account, action, role, target
+func (_m *UtilsCmdInterface) UpdateRole(client *ethclient.Client, config types.Configurations, account types.Account, action types.RoleAction, role common.Hash, target common.Address) (common.Hash, error) {
+	ret := _m.Called(client, config, account, action, role, target)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRole")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, types.RoleAction, common.Hash, common.Address) (common.Hash, error)); ok {
+		return rf(client, config, account, action, role, target)
+	}
+	if rf, ok := ret.Get(0).(func(*ethclient.Client, types.Configurations, types.Account, types.RoleAction, common.Hash, common.Address) common.Hash); ok {
+		r0 = rf(client, config, account, action, role, target)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*ethclient.Client, types.Configurations, types.Account, types.RoleAction, common.Hash, common.Address) error); ok {
+		r1 = rf(client, config, account, action, role, target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Withdraw provides a mock function with given fields: client, txnOpts, stakerId
 func (_m *UtilsCmdInterface) Withdraw(client *ethclient.Client, txnOpts *bind.TransactOpts, stakerId uint32) (common.Hash, error) {
 	ret := _m.Called(client, txnOpts, stakerId)
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/spf13/pflag"
+)
+
+// Output formats of the read-only commands
+const (
+	outputFormatTable = "table"
+	outputFormatJSON  = "json"
+)
+
+// getOutputFormat returns the format selected with --output.
+// Returns error if the format is not supported.
+func getOutputFormat(flagSet *pflag.FlagSet) (string, error) {
+	format, err := flagSet.GetString("output")
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case outputFormatTable, outputFormatJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected %s or %s", format, outputFormatTable, outputFormatJSON)
+	}
+}
+
+// writeJSON writes v to out as indented JSON followed by a newline
+func writeJSON(out io.Writer, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal output: %w", err)
+	}
+	_, err = out.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/logger"
+	"lumino/pkg/bindings"
+	"lumino/utils"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var proposeBlockCmd = &cobra.Command{
+	Use:   "proposeBlock",
+	Short: "Propose a block of the jobs concluded in an epoch",
+	Long: `Proposes a block listing the jobs concluded in an epoch to the BlockManager. Without --jobIds
+the block lists every job whose status was updated to Completed or Failed since the epoch started.
+Without --epoch the current epoch is used.
+
+executeJob --proposer proposes blocks on its own; this command does it by hand.
+
+Example:
+  ./lumino proposeBlock -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771
+  ./lumino proposeBlock -a 0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771 --epoch 3251 --jobIds 4,7,9`,
+	Run: initialiseProposeBlock,
+}
+
+// This function initialises the ExecuteProposeBlock function
+func initialiseProposeBlock(cmd *cobra.Command, args []string) {
+	cmdUtils.ExecuteProposeBlock(cmd.Flags())
+}
+
+// ExecuteProposeBlock proposes a block by hand. This function:
+// 1. Loads the configuration and points the bindings at the configured BlockManager
+// 2. Sets up blockchain connection and logging
+// 3. Uses the current epoch unless --epoch is passed
+// 4. Uses the jobs concluded in the epoch unless --jobIds is passed
+// 5. Proposes the block
+// Exits with error if the BlockManager is not configured, a job ID is invalid or there is nothing to propose.
+func (*UtilsStruct) ExecuteProposeBlock(flagSet *pflag.FlagSet) {
+	config, err := cmdUtils.GetConfigData()
+	utils.CheckError("Error in getting config: ", err)
+	log.Debugf("ExecuteProposeBlock: Config: %+v", config)
+
+	err = useBlockManager(config)
+	utils.CheckError("Error in getting BlockManager: ", err)
+
+	client := protoUtils.ConnectToEthClient(config.Provider)
+
+	address, err := flagSetUtils.GetStringAddress(flagSet)
+	utils.CheckError("Error in getting address: ", err)
+
+	logger.SetLoggerParameters(client, address)
+	log.Debug("Checking to assign log file...")
+	protoUtils.AssignLogFile(flagSet)
+
+	log.Debug("Getting password...")
+	password := protoUtils.AssignPassword(flagSet)
+
+	epoch, err := flagSet.GetUint32("epoch")
+	utils.CheckError("Error in getting epoch: ", err)
+	if epoch == 0 {
+		epoch, _, err = cmdUtils.GetEpochAndState(client)
+		utils.CheckError("Error in getting epoch: ", err)
+	}
+
+	jobIdStrs, err := flagSet.GetStringSlice("jobIds")
+	utils.CheckError("Error in getting jobIds: ", err)
+
+	var jobIds []*big.Int
+	for _, jobIdStr := range jobIdStrs {
+		jobId, ok := new(big.Int).SetString(jobIdStr, 10)
+		if !ok || jobId.Sign() <= 0 {
+			log.Fatalf("Invalid jobId %q", jobIdStr)
+		}
+		jobIds = append(jobIds, jobId)
+	}
+	if len(jobIds) == 0 {
+		var collector concludedJobCollector
+		jobIds, err = collector.Collect(client, epoch)
+		utils.CheckError("Error in collecting concluded jobs: ", err)
+		if len(jobIds) == 0 {
+			log.WithField("epoch", epoch).Fatal("No concluded jobs to propose")
+		}
+	}
+
+	account := types.Account{
+		Address:  address,
+		Password: password,
+	}
+
+	txnHash, err := cmdUtils.ProposeBlock(client, config, account, epoch, jobIds)
+	utils.CheckError("Error in proposing block: ", err)
+
+	log.WithFields(logrus.Fields{
+		"txHash": txnHash.Hex(),
+		"epoch":  epoch,
+		"jobIds": jobIds,
+	}).Info("Block proposed successfully")
+}
+
+// ProposeBlock proposes a block listing the jobs concluded in the epoch. This function:
+// 1. Validates the client and the job list
+// 2. Constructs and submits the propose transaction
+// 3. Monitors transaction confirmation
+// Returns the transaction hash once the proposal is confirmed.
+func (*UtilsStruct) ProposeBlock(client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, jobIds []*big.Int) (common.Hash, error) {
+	if client == nil {
+		log.Error("Client is nil")
+		return common.Hash{}, errors.New("client is nil")
+	}
+
+	if len(jobIds) == 0 {
+		log.Error("Block has no jobs")
+		return common.Hash{}, errors.New("block has no jobs")
+	}
+
+	log.WithFields(logrus.Fields{
+		"epoch":  epoch,
+		"jobIds": jobIds,
+	}).Debug("Executing propose transaction")
+
+	txnArgs := types.TransactionOptions{
+		Client:          client,
+		AccountAddress:  account.Address,
+		Password:        account.Password,
+		ChainId:         core.ChainID,
+		Config:          config,
+		ContractAddress: core.BlockManagerAddress,
+		MethodName:      "propose",
+		Parameters:      []interface{}{epoch, jobIds},
+		ABI:             bindings.BlockManagerABI,
+	}
+
+	txnOpts := protoUtils.GetTransactionOpts(txnArgs)
+
+	txn, err := blockManagerUtils.Propose(client, txnOpts, epoch, jobIds)
+	if err != nil {
+		log.WithError(err).WithField("epoch", epoch).Error("Failed to propose block")
+		return common.Hash{}, err
+	}
+
+	if txn == nil {
+		log.Error("Transaction is nil")
+		return common.Hash{}, errors.New("transaction is nil")
+	}
+
+	txnHash := transactionUtils.Hash(txn)
+	log.WithFields(logrus.Fields{
+		"txHash": txnHash.Hex(),
+		"epoch":  epoch,
+	}).Info("Block proposal transaction submitted")
+
+	err = protoUtils.WaitForBlockCompletion(client, txnHash.Hex())
+	if err != nil {
+		log.WithError(err).WithField("epoch", epoch).Error("Failed to wait for block completion")
+		return common.Hash{}, err
+	}
+
+	return txnHash, nil
+}
+
+// Initializes the proposeBlock command in the CLI with its flags.
+func init() {
+	rootCmd.AddCommand(proposeBlockCmd)
+
+	var (
+		Account  string
+		Password string
+		Epoch    uint32
+		JobIds   []string
+	)
+
+	proposeBlockCmd.Flags().StringVarP(&Account, "address", "a", "", "address of the proposer")
+	proposeBlockCmd.Flags().StringVarP(&Password, "password", "", "", "password path of the proposer to protect the keystore")
+	proposeBlockCmd.Flags().Uint32VarP(&Epoch, "epoch", "", 0, "epoch of the block, defaults to the current epoch")
+	proposeBlockCmd.Flags().StringSliceVarP(&JobIds, "jobIds", "", nil, "IDs of the jobs in the block, defaults to the jobs concluded in the epoch")
+
+	addrErr := proposeBlockCmd.MarkFlagRequired("address")
+	utils.CheckError("Address error: ", addrErr)
+}
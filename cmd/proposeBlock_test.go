@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests the proposeBlock command covering:
+// 1. Proposing the job IDs passed with --jobIds in the given epoch
+// 2. Proposing the jobs concluded in the current epoch
+// 3. Nothing concluded in the epoch
+// 4. Invalid job IDs
+// 5. A missing BlockManager address
+func TestExecuteProposeBlock(t *testing.T) {
+	var client *ethclient.Client
+	blockManagerAddress := "0xab110dA2064AC0B44c08D71A3D8148BBB0C3aD1F"
+
+	tests := []struct {
+		name          string
+		config        types.Configurations
+		epoch         uint32
+		jobIds        []string
+		setupMocks    func(*mocks.UtilsCmdInterface, *mocks.JobEventInterface, *mocks.EventIndexInterface)
+		expectedFatal bool
+	}{
+		{
+			name:   "explicit job IDs",
+			config: types.Configurations{BlockManagerAddress: blockManagerAddress},
+			epoch:  12,
+			jobIds: []string{"4", "9"},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				cmdMock.On("ProposeBlock", mock.Anything, mock.Anything, mock.Anything, uint32(12), []*big.Int{big.NewInt(4), big.NewInt(9)}).
+					Return(common.HexToHash("0x1"), nil).Once()
+			},
+		},
+		{
+			name:   "concluded jobs of the current epoch",
+			config: types.Configurations{BlockManagerAddress: blockManagerAddress},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				cmdMock.On("GetEpochAndState", mock.Anything).Return(uint32(10), int64(types.EpochStateConfirm), nil)
+				eventIndexMock.On("GetEpochStartBlock", mock.Anything, uint32(10)).Return(uint64(500), nil)
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(520), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(500), uint64(520)).Return(concludedEvents(), nil)
+				cmdMock.On("ProposeBlock", mock.Anything, mock.Anything, mock.Anything, uint32(10), []*big.Int{big.NewInt(3), big.NewInt(7)}).
+					Return(common.HexToHash("0x1"), nil).Once()
+			},
+		},
+		{
+			name:   "nothing concluded",
+			config: types.Configurations{BlockManagerAddress: blockManagerAddress},
+			epoch:  10,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				eventIndexMock.On("GetEpochStartBlock", mock.Anything, uint32(10)).Return(uint64(500), nil)
+				eventMock.On("GetLatestBlockNumber", mock.Anything).Return(uint64(520), nil)
+				eventMock.On("FilterJobEvents", mock.Anything, uint64(500), uint64(520)).Return([]types.JobEvent{}, nil)
+				cmdMock.On("ProposeBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(common.Hash{}, nil)
+			},
+			expectedFatal: true,
+		},
+		{
+			name:   "invalid job ID",
+			config: types.Configurations{BlockManagerAddress: blockManagerAddress},
+			epoch:  10,
+			jobIds: []string{"abc"},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				cmdMock.On("ProposeBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(common.Hash{}, nil)
+			},
+			expectedFatal: true,
+		},
+		{
+			name:   "BlockManager not configured",
+			epoch:  10,
+			jobIds: []string{"4"},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, eventMock *mocks.JobEventInterface, eventIndexMock *mocks.EventIndexInterface) {
+				cmdMock.On("ProposeBlock", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(common.Hash{}, nil)
+			},
+			expectedFatal: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			utilsMock := new(mocks.UtilsInterface)
+			flagSetMock := new(mocks.FlagSetInterface)
+			cmdMock := new(mocks.UtilsCmdInterface)
+			eventMock := new(mocks.JobEventInterface)
+			eventIndexMock := new(mocks.EventIndexInterface)
+
+			originalProtoUtils := protoUtils
+			originalFlagSetUtils := flagSetUtils
+			originalCmdUtils := cmdUtils
+			originalJobEventUtils := jobEventUtils
+			originalEventIndexUtils := eventIndexUtils
+			originalBlockManagerAddress := core.BlockManagerAddress
+			defer func() {
+				protoUtils = originalProtoUtils
+				flagSetUtils = originalFlagSetUtils
+				cmdUtils = originalCmdUtils
+				jobEventUtils = originalJobEventUtils
+				eventIndexUtils = originalEventIndexUtils
+				core.BlockManagerAddress = originalBlockManagerAddress
+				log.ExitFunc = nil
+			}()
+			protoUtils = utilsMock
+			flagSetUtils = flagSetMock
+			cmdUtils = cmdMock
+			jobEventUtils = eventMock
+			eventIndexUtils = eventIndexMock
+
+			flagSet := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			flagSet.Uint32("epoch", tt.epoch, "")
+			flagSet.StringSlice("jobIds", tt.jobIds, "")
+
+			cmdMock.On("GetConfigData").Return(tt.config, nil)
+			utilsMock.On("ConnectToEthClient", mock.AnythingOfType("string")).Return(client)
+			flagSetMock.On("GetStringAddress", mock.Anything).Return("0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771", nil)
+			utilsMock.On("AssignLogFile", mock.Anything)
+			utilsMock.On("AssignPassword", mock.Anything).Return("password")
+			tt.setupMocks(cmdMock, eventMock, eventIndexMock)
+
+			var fatal bool
+			log.ExitFunc = func(int) { fatal = true }
+
+			utils := &UtilsStruct{}
+			utils.ExecuteProposeBlock(flagSet)
+
+			assert.Equal(t, tt.expectedFatal, fatal)
+			if !tt.expectedFatal {
+				cmdMock.AssertExpectations(t)
+				assert.Equal(t, blockManagerAddress, core.BlockManagerAddress)
+			}
+		})
+	}
+}
+
+// Tests submitting a block proposal including:
+// 1. Successful proposal
+// 2. Nil client handling
+// 3. A block without jobs
+// 4. Transaction failure scenarios
+// 5. Block completion errors
+func TestProposeBlock(t *testing.T) {
+	client := &ethclient.Client{}
+	account := types.Account{
+		Address:  "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771",
+		Password: "password",
+	}
+	jobIds := []*big.Int{big.NewInt(3), big.NewInt(7)}
+
+	tests := []struct {
+		name          string
+		client        *ethclient.Client
+		jobIds        []*big.Int
+		setupMocks    func(*mocks.BlockManagerInterface, *mocks.UtilsInterface, *mocks.TransactionInterface)
+		expectedError bool
+	}{
+		{
+			name:   "When the block is proposed successfully",
+			client: client,
+			jobIds: jobIds,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				utilsMock.On("GetTransactionOpts", mock.MatchedBy(func(txnArgs types.TransactionOptions) bool {
+					return txnArgs.MethodName == "propose" && txnArgs.ContractAddress == core.BlockManagerAddress
+				})).Return(nil)
+				blockManagerMock.On("Propose", client, mock.Anything, uint32(10), jobIds).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+			},
+		},
+		{
+			name:   "When ethereum client is nil",
+			jobIds: jobIds,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When the block has no jobs",
+			client: client,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When there is a transaction error",
+			client: client,
+			jobIds: jobIds,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				blockManagerMock.On("Propose", client, mock.Anything, uint32(10), jobIds).Return(nil, errors.New("transaction error"))
+			},
+			expectedError: true,
+		},
+		{
+			name:   "When block completion is not successful",
+			client: client,
+			jobIds: jobIds,
+			setupMocks: func(blockManagerMock *mocks.BlockManagerInterface, utilsMock *mocks.UtilsInterface, txMock *mocks.TransactionInterface) {
+				mockTx := &ethTypes.Transaction{}
+				utilsMock.On("GetTransactionOpts", mock.Anything).Return(nil)
+				blockManagerMock.On("Propose", client, mock.Anything, uint32(10), jobIds).Return(mockTx, nil)
+				txMock.On("Hash", mockTx).Return(common.HexToHash("0x123"))
+				utilsMock.On("WaitForBlockCompletion", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("block completion error"))
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blockManagerMock := new(mocks.BlockManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+			txMock := new(mocks.TransactionInterface)
+
+			originalBlockManagerUtils := blockManagerUtils
+			originalProtoUtils := protoUtils
+			originalTransactionUtils := transactionUtils
+			defer func() {
+				blockManagerUtils = originalBlockManagerUtils
+				protoUtils = originalProtoUtils
+				transactionUtils = originalTransactionUtils
+			}()
+			blockManagerUtils = blockManagerMock
+			protoUtils = utilsMock
+			transactionUtils = txMock
+
+			tt.setupMocks(blockManagerMock, utilsMock, txMock)
+
+			utils := &UtilsStruct{}
+			txnHash, err := utils.ProposeBlock(tt.client, types.Configurations{}, account, 10, tt.jobIds)
+			if tt.expectedError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, common.HexToHash("0x123"), txnHash)
+		})
+	}
+}
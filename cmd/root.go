@@ -17,15 +17,22 @@ import (
 )
 
 var (
-	Provider           string
-	BufferPercent      int32
-	WaitTime           int32
-	GasPrice           int32
-	RPCTimeout         int64
-	LogLevel           string
-	LogFile            string
-	GasMultiplier      float32
-	GasLimitMultiplier float32
+	Provider               string
+	BufferPercent          int32
+	WaitTime               int32
+	GasPrice               int32
+	RPCTimeout             int64
+	LogLevel               string
+	LogFile                string
+	GasMultiplier          float32
+	GasLimitMultiplier     float32
+	AssignmentStrategyName string
+	ShutdownTimeout        int64
+	BlockManagerAddr       string
+	ResultsStoreLocation   string
+	DatasetCacheSize       int64
+	DatasetRoot            string
+	WorkflowsFile          string
 )
 
 // log is the package-level logger instance
@@ -75,6 +82,13 @@ func init() {
 	rootCmd.PersistentFlags().Float32VarP(&GasLimitMultiplier, "gasLimit", "", -1, "gas limit percentage increase")
 	rootCmd.PersistentFlags().StringVarP(&LogFile, "logFile", "", "", "name of log file")
 	rootCmd.PersistentFlags().Int64VarP(&RPCTimeout, "rpcTimeout", "", 0, "RPC timeout if its not responding")
+	rootCmd.PersistentFlags().StringVarP(&AssignmentStrategyName, "assignmentStrategy", "", "", "job assignment strategy of the admin node")
+	rootCmd.PersistentFlags().Int64VarP(&ShutdownTimeout, "shutdownTimeout", "", -1, "seconds to wait for running jobs on shutdown before terminating them")
+	rootCmd.PersistentFlags().StringVarP(&BlockManagerAddr, "blockManagerAddress", "", "", "address of the BlockManager contract")
+	rootCmd.PersistentFlags().StringVarP(&ResultsStoreLocation, "resultsStore", "", "", "directory or s3:// URL job results are published to")
+	rootCmd.PersistentFlags().Int64VarP(&DatasetCacheSize, "datasetCacheSize", "", -1, "size in megabytes of the dataset cache, 0 disables eviction")
+	rootCmd.PersistentFlags().StringVarP(&DatasetRoot, "datasetRoot", "", "", "directory file:// datasets must be under, file:// datasets are refused when unset")
+	rootCmd.PersistentFlags().StringVarP(&WorkflowsFile, "workflowsFile", "", "", "JSON file declaring the script workflows jobs can select")
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
@@ -160,4 +174,5 @@ func setLogLevel() {
 	log.Debugf("Log Level: %s", config.LogLevel)
 	log.Debugf("Gas Limit: %.2f", config.GasLimitMultiplier)
 	log.Debugf("RPC Timeout: %d", config.RPCTimeout)
+	log.Debugf("Assignment Strategy: %s", config.AssignmentStrategy)
 }
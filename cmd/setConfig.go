@@ -2,9 +2,14 @@
 package cmd
 
 import (
+	"fmt"
 	"lumino/core"
+	"lumino/path"
+	pipeline_zen "lumino/pipeline-zen"
+	"lumino/storage"
 	"lumino/utils"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -17,7 +22,7 @@ var setConfig = &cobra.Command{
 Setting the gas multiplier value enables the CLI to multiply the gas with that value for all the transactions
 
 Example:
-  ./lumino setConfig --provider https://holesky.drpc.org --gasmultiplier 1.5 --buffer 20 --wait 70 --gasprice 1 --logLevel debug --gasLimit 5
+  ./lumino setConfig --provider https://holesky.drpc.org --gasmultiplier 1.5 --buffer 20 --wait 70 --gasprice 1 --logLevel debug --gasLimit 5 --assignmentStrategy round-robin --shutdownTimeout 600 --blockManagerAddress 0x<address> --resultsStore s3://bucket/lumino --datasetCacheSize 102400 --datasetRoot /mnt/datasets --workflowsFile /etc/lumino/workflows.json
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		err := cmdUtils.SetConfig(cmd.Flags())
@@ -65,6 +70,65 @@ func (*UtilsStruct) SetConfig(flagSet *pflag.FlagSet) error {
 	if rpcTimeoutErr != nil {
 		return rpcTimeoutErr
 	}
+	assignmentStrategy, err := flagSetUtils.GetStringAssignmentStrategy(flagSet)
+	if err != nil {
+		return err
+	}
+	if assignmentStrategy != "" && !isKnownAssignmentStrategy(assignmentStrategy) {
+		return fmt.Errorf("unknown assignment strategy %q, expected one of %v", assignmentStrategy, assignmentStrategyNames())
+	}
+	shutdownTimeout, err := flagSetUtils.GetInt64ShutdownTimeout(flagSet)
+	if err != nil {
+		return err
+	}
+	if shutdownTimeout < -1 {
+		return fmt.Errorf("shutdown timeout must not be negative, got %d", shutdownTimeout)
+	}
+
+	blockManagerAddress, err := flagSetUtils.GetStringBlockManagerAddress(flagSet)
+	if err != nil {
+		return err
+	}
+	if blockManagerAddress != "" && !common.IsHexAddress(blockManagerAddress) {
+		return fmt.Errorf("invalid BlockManager address %q", blockManagerAddress)
+	}
+	resultsStore, err := flagSetUtils.GetStringResultsStore(flagSet)
+	if err != nil {
+		return err
+	}
+	if resultsStore != "" {
+		if _, err := storage.Open(resultsStore); err != nil {
+			return err
+		}
+	}
+
+	datasetCacheSize, err := flagSetUtils.GetInt64DatasetCacheSize(flagSet)
+	if err != nil {
+		return err
+	}
+	if datasetCacheSize < -1 {
+		return fmt.Errorf("dataset cache size must not be negative, got %d", datasetCacheSize)
+	}
+
+	datasetRoot, err := flagSetUtils.GetStringDatasetRoot(flagSet)
+	if err != nil {
+		return err
+	}
+	if datasetRoot != "" {
+		if info, err := path.OSUtilsInterface.Stat(datasetRoot); err != nil || !info.IsDir() {
+			return fmt.Errorf("dataset root %s is not a directory", datasetRoot)
+		}
+	}
+
+	workflowsFile, err := flagSetUtils.GetStringWorkflowsFile(flagSet)
+	if err != nil {
+		return err
+	}
+	if workflowsFile != "" {
+		if _, err := pipeline_zen.LoadScriptWorkflows(workflowsFile); err != nil {
+			return err
+		}
+	}
 
 	path, pathErr := protoUtils.GetConfigFilePath()
 	if pathErr != nil {
@@ -96,7 +160,28 @@ func (*UtilsStruct) SetConfig(flagSet *pflag.FlagSet) error {
 	if rpcTimeout != 0 {
 		viper.Set("rpcTimeout", rpcTimeout)
 	}
-	if provider == "" && gasMultiplier == -1 && bufferPercent == 0 && waitTime == -1 && gasPrice == -1 && logLevel == "" && gasLimit == -1 && rpcTimeout == 0 {
+	if assignmentStrategy != "" {
+		viper.Set("assignmentStrategy", assignmentStrategy)
+	}
+	if shutdownTimeout != -1 {
+		viper.Set("shutdownTimeout", shutdownTimeout)
+	}
+	if blockManagerAddress != "" {
+		viper.Set("blockManagerAddress", blockManagerAddress)
+	}
+	if resultsStore != "" {
+		viper.Set("resultsStore", resultsStore)
+	}
+	if datasetCacheSize != -1 {
+		viper.Set("datasetCacheSize", datasetCacheSize)
+	}
+	if datasetRoot != "" {
+		viper.Set("datasetRoot", datasetRoot)
+	}
+	if workflowsFile != "" {
+		viper.Set("workflowsFile", workflowsFile)
+	}
+	if provider == "" && gasMultiplier == -1 && bufferPercent == 0 && waitTime == -1 && gasPrice == -1 && logLevel == "" && gasLimit == -1 && rpcTimeout == 0 && assignmentStrategy == "" && shutdownTimeout == -1 && blockManagerAddress == "" && resultsStore == "" && datasetCacheSize == -1 && datasetRoot == "" && workflowsFile == "" {
 		viper.Set("provider", core.DefaultRPCProvider)
 		viper.Set("gasmultiplier", core.DefaultGasMultiplier)
 		viper.Set("buffer", core.DefaultBufferPercent)
@@ -105,6 +190,9 @@ func (*UtilsStruct) SetConfig(flagSet *pflag.FlagSet) error {
 		viper.Set("logLevel", core.DefaultLogLevel)
 		viper.Set("gasLimit", core.DefaultGasLimit)
 		viper.Set("rpcTimeout", core.DefaultRPCTimeout)
+		viper.Set("assignmentStrategy", core.DefaultAssignmentStrategy)
+		viper.Set("shutdownTimeout", core.DefaultShutdownTimeout)
+		viper.Set("datasetCacheSize", core.DefaultDatasetCacheSize)
 		//viper.Set("exposeMetricsPort", "")
 		log.Info("Config values set to default. Use setConfig to modify the values.")
 	}
@@ -126,6 +214,13 @@ func (*UtilsStruct) SetConfig(flagSet *pflag.FlagSet) error {
 // - logLevel: Logging verbosity level
 // - gasLimit: Transaction gas limit multiplier
 // - rpcTimeout: Timeout for RPC calls
+// - assignmentStrategy: How the admin node assigns jobs to stakers
+// - shutdownTimeout: Seconds executeJob waits for running jobs on shutdown
+// - blockManagerAddress: Address of the BlockManager contract used by block proposers
+// - resultsStore: Directory or s3:// URL job results are published to
+// - datasetCacheSize: Megabytes the dataset cache is kept under, 0 disables eviction
+// - datasetRoot: Directory file:// datasets must be under, file:// datasets are refused when unset
+// - workflowsFile: JSON file declaring the script workflows jobs can select
 // - exposeMetrics: Port for metrics exposure
 // - certFile: SSL certificate path
 // - certKey: SSL certificate key path
@@ -133,17 +228,24 @@ func init() {
 	rootCmd.AddCommand(setConfig)
 
 	var (
-		Provider           string
-		GasMultiplier      float32
-		BufferPercent      int32
-		WaitTime           int32
-		GasPrice           int32
-		LogLevel           string
-		GasLimitMultiplier float32
-		RPCTimeout         int64
-		ExposeMetrics      string
-		CertFile           string
-		CertKey            string
+		Provider               string
+		GasMultiplier          float32
+		BufferPercent          int32
+		WaitTime               int32
+		GasPrice               int32
+		LogLevel               string
+		GasLimitMultiplier     float32
+		RPCTimeout             int64
+		AssignmentStrategyName string
+		ShutdownTimeout        int64
+		BlockManagerAddr       string
+		ResultsStoreLocation   string
+		DatasetCacheSize       int64
+		DatasetRoot            string
+		WorkflowsFile          string
+		ExposeMetrics          string
+		CertFile               string
+		CertKey                string
 	)
 	setConfig.Flags().StringVarP(&Provider, "provider", "p", "", "provider name")
 	setConfig.Flags().Float32VarP(&GasMultiplier, "gasmultiplier", "g", -1, "gas multiplier value")
@@ -153,6 +255,13 @@ func init() {
 	setConfig.Flags().StringVarP(&LogLevel, "logLevel", "", "", "log level")
 	setConfig.Flags().Float32VarP(&GasLimitMultiplier, "gasLimit", "", -1, "gas limit percentage increase")
 	setConfig.Flags().Int64VarP(&RPCTimeout, "rpcTimeout", "", 0, "RPC timeout if its not responding")
+	setConfig.Flags().StringVarP(&AssignmentStrategyName, "assignmentStrategy", "", "", "job assignment strategy (admin, round-robin, least-loaded, stake-weighted, random)")
+	setConfig.Flags().Int64VarP(&ShutdownTimeout, "shutdownTimeout", "", -1, "seconds to wait for running jobs on shutdown before terminating them")
+	setConfig.Flags().StringVarP(&BlockManagerAddr, "blockManagerAddress", "", "", "address of the BlockManager contract")
+	setConfig.Flags().StringVarP(&ResultsStoreLocation, "resultsStore", "", "", "directory or s3:// URL job results are published to")
+	setConfig.Flags().Int64VarP(&DatasetCacheSize, "datasetCacheSize", "", -1, "size in megabytes of the dataset cache, 0 disables eviction")
+	setConfig.Flags().StringVarP(&DatasetRoot, "datasetRoot", "", "", "directory file:// datasets must be under, file:// datasets are refused when unset")
+	setConfig.Flags().StringVarP(&WorkflowsFile, "workflowsFile", "", "", "JSON file declaring the script workflows jobs can select")
 	setConfig.Flags().StringVarP(&ExposeMetrics, "exposeMetrics", "", "", "port number")
 	setConfig.Flags().StringVarP(&CertFile, "certFile", "", "", "ssl certificate path")
 	setConfig.Flags().StringVarP(&CertKey, "certKey", "", "", "ssl certificate key path")
@@ -3,6 +3,9 @@ package cmd
 import (
 	"errors"
 	"lumino/cmd/mocks"
+	"lumino/path"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/spf13/pflag"
@@ -16,11 +19,29 @@ import (
 // 4. Invalid parameter value handling
 // 5. Configuration file write errors
 // 6. RPC timeout configuration
+// 7. Assignment strategy validation
+// 8. Shutdown timeout validation
+// 9. BlockManager address validation
+// 10. Workflows file validation
+// 11. Dataset root validation
 // Each test validates proper config updates and error handling.
 func TestSetConfig(t *testing.T) {
 
 	var flagSet *pflag.FlagSet
 
+	workflowsFile := filepath.Join(t.TempDir(), "workflows.json")
+	if err := os.WriteFile(workflowsFile, []byte(`[{"name": "evaluation", "script": "scripts/runners/evaluate.sh"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	datasetRoot := t.TempDir()
+	originalOSUtils := path.OSUtilsInterface
+	defer func() { path.OSUtilsInterface = originalOSUtils }()
+	path.OSUtilsInterface = path.OSUtils{}
+	invalidWorkflowsFile := filepath.Join(t.TempDir(), "workflows.json")
+	if err := os.WriteFile(invalidWorkflowsFile, []byte(`[{"name": "torchtunewrapper", "script": "run.sh"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	type args struct {
 		provider              string
 		providerErr           error
@@ -41,6 +62,13 @@ func TestSetConfig(t *testing.T) {
 		gasLimitMultiplierErr error
 		rpcTimeout            int64
 		rpcTimeoutErr         error
+		assignmentStrategy    string
+		shutdownTimeout       int64
+		blockManagerAddress   string
+		resultsStore          string
+		datasetCacheSize      int64
+		datasetRoot           string
+		workflowsFile         string
 		isFlagPassed          bool
 	}
 	tests := []struct {
@@ -79,6 +107,7 @@ func TestSetConfig(t *testing.T) {
 				gasLimitMultiplier:    10,
 				gasLimitMultiplierErr: nil,
 				rpcTimeout:            0,
+				shutdownTimeout:       -1,
 			},
 			wantErr: nil,
 		},
@@ -267,6 +296,188 @@ func TestSetConfig(t *testing.T) {
 			},
 			wantErr: errors.New("rpcTimeout error"),
 		},
+		{
+			name: "Test 15: When a known assignment strategy is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				assignmentStrategy: "round-robin",
+				path:               "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 16: When an unknown assignment strategy is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				assignmentStrategy: "first-come",
+				path:               "/home/config",
+			},
+			wantErr: errors.New(`unknown assignment strategy "first-come", expected one of [admin least-loaded random round-robin stake-weighted]`),
+		},
+		{
+			name: "Test 17: When a shutdown timeout is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    600,
+				path:               "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 18: When a negative shutdown timeout is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -5,
+				path:               "/home/config",
+			},
+			wantErr: errors.New("shutdown timeout must not be negative, got -5"),
+		},
+		{
+			name: "Test 19: When a BlockManager address is passed",
+			args: args{
+				gasmultiplier:       -1,
+				waitTime:            -1,
+				gasPrice:            -1,
+				gasLimitMultiplier:  -1,
+				shutdownTimeout:     -1,
+				blockManagerAddress: "0xab110dA2064AC0B44c08D71A3D8148BBB0C3aD1F",
+				path:                "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 20: When an invalid BlockManager address is passed",
+			args: args{
+				gasmultiplier:       -1,
+				waitTime:            -1,
+				gasPrice:            -1,
+				gasLimitMultiplier:  -1,
+				shutdownTimeout:     -1,
+				blockManagerAddress: "0x123",
+				path:                "/home/config",
+			},
+			wantErr: errors.New(`invalid BlockManager address "0x123"`),
+		},
+		{
+			name: "Test 21: When a results store is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				resultsStore:       "s3://results/lumino?endpoint=http://localhost:9000",
+				path:               "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 22: When an unsupported results store is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				resultsStore:       "gs://results",
+				path:               "/home/config",
+			},
+			wantErr: errors.New(`unsupported store location "gs://results", expected a directory, file:// or s3://`),
+		},
+		{
+			name: "Test 23: When a dataset cache size is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				datasetCacheSize:   102400,
+				path:               "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 24: When a negative dataset cache size is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				datasetCacheSize:   -2,
+				path:               "/home/config",
+			},
+			wantErr: errors.New("dataset cache size must not be negative, got -2"),
+		},
+		{
+			name: "Test 25: When a workflows file is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				datasetCacheSize:   -1,
+				workflowsFile:      workflowsFile,
+				path:               "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 26: When an invalid workflows file is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				datasetCacheSize:   -1,
+				workflowsFile:      invalidWorkflowsFile,
+				path:               "/home/config",
+			},
+			wantErr: errors.New("invalid workflows file " + invalidWorkflowsFile + ": workflow torchtunewrapper is built in and cannot be replaced"),
+		},
+		{
+			name: "Test 27: When a dataset root is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				datasetCacheSize:   -1,
+				datasetRoot:        datasetRoot,
+				path:               "/home/config",
+			},
+			wantErr: nil,
+		},
+		{
+			name: "Test 28: When a dataset root that is not a directory is passed",
+			args: args{
+				gasmultiplier:      -1,
+				waitTime:           -1,
+				gasPrice:           -1,
+				gasLimitMultiplier: -1,
+				shutdownTimeout:    -1,
+				datasetCacheSize:   -1,
+				datasetRoot:        workflowsFile,
+				path:               "/home/config",
+			},
+			wantErr: errors.New("dataset root " + workflowsFile + " is not a directory"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -290,6 +501,13 @@ func TestSetConfig(t *testing.T) {
 			flagSetUtilsMock.On("GetStringLogLevel", flagSet).Return(tt.args.logLevel, tt.args.logLevelErr)
 			flagSetUtilsMock.On("GetFloat32GasLimit", flagSet).Return(tt.args.gasLimitMultiplier, tt.args.gasLimitMultiplierErr)
 			flagSetUtilsMock.On("GetInt64RPCTimeout", flagSet).Return(tt.args.rpcTimeout, tt.args.rpcTimeoutErr)
+			flagSetUtilsMock.On("GetStringAssignmentStrategy", flagSet).Return(tt.args.assignmentStrategy, nil)
+			flagSetUtilsMock.On("GetInt64ShutdownTimeout", flagSet).Return(tt.args.shutdownTimeout, nil)
+			flagSetUtilsMock.On("GetStringBlockManagerAddress", flagSet).Return(tt.args.blockManagerAddress, nil)
+			flagSetUtilsMock.On("GetStringResultsStore", flagSet).Return(tt.args.resultsStore, nil)
+			flagSetUtilsMock.On("GetInt64DatasetCacheSize", flagSet).Return(tt.args.datasetCacheSize, nil)
+			flagSetUtilsMock.On("GetStringDatasetRoot", flagSet).Return(tt.args.datasetRoot, nil)
+			flagSetUtilsMock.On("GetStringWorkflowsFile", flagSet).Return(tt.args.workflowsFile, nil)
 			utilsMock.On("IsFlagPassed", mock.Anything).Return(tt.args.isFlagPassed)
 			utilsMock.On("GetConfigFilePath").Return(tt.args.path, tt.args.pathErr)
 			viperMock.On("ViperWriteConfigAs", mock.AnythingOfType("string")).Return(tt.args.configErr)
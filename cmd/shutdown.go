@@ -0,0 +1,187 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"context"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/logger"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownSignals start a graceful shutdown: Ctrl-C, and SIGTERM as sent by Docker and systemd
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// shutdownCoordinator tracks the job goroutines of the executor so that a
+// shutdown can stop new jobs, wait for running ones and terminate the rest.
+type shutdownCoordinator struct {
+	mu           sync.Mutex
+	shuttingDown bool
+	jobsCtx      context.Context
+	cancelJobs   context.CancelFunc
+	jobs         sync.WaitGroup
+}
+
+// jobShutdown is the shutdown coordinator of the executor daemon
+var jobShutdown = newShutdownCoordinator()
+
+func newShutdownCoordinator() *shutdownCoordinator {
+	jobsCtx, cancelJobs := context.WithCancel(context.Background())
+	return &shutdownCoordinator{jobsCtx: jobsCtx, cancelJobs: cancelJobs}
+}
+
+// Begin marks the executor as shutting down, after which no new job is accepted
+func (s *shutdownCoordinator) Begin() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shuttingDown = true
+}
+
+// IsShuttingDown reports whether a shutdown has begun
+func (s *shutdownCoordinator) IsShuttingDown() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shuttingDown
+}
+
+// JobContext returns the context of job pipelines, canceled by TerminateJobs
+func (s *shutdownCoordinator) JobContext() context.Context {
+	return s.jobsCtx
+}
+
+// Go runs fn as a tracked job goroutine. It returns false without running fn once
+// a shutdown has begun.
+func (s *shutdownCoordinator) Go(fn func()) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.shuttingDown {
+		return false
+	}
+	s.jobs.Add(1)
+	go func() {
+		defer s.jobs.Done()
+		fn()
+	}()
+	return true
+}
+
+// WaitJobs waits up to timeout for every tracked job goroutine to return.
+// A timeout of zero or less waits without limit. Returns false on timeout.
+func (s *shutdownCoordinator) WaitJobs(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		s.jobs.Wait()
+		close(done)
+	}()
+
+	if timeout <= 0 {
+		<-done
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// TerminateJobs cancels the job context, which terminates every running pipeline
+func (s *shutdownCoordinator) TerminateJobs() {
+	s.cancelJobs()
+}
+
+// handleGracefulShutdown sets up signal handling for the executor. The first SIGINT or
+// SIGTERM stops the main loop by canceling ctx, after which RunExecuteJob runs the
+// shutdown sequence. A second signal aborts the sequence with ExitCodeForcedShutdown.
+func handleGracefulShutdown(ctx context.Context, cancel context.CancelFunc) {
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, shutdownSignals...)
+
+	go func() {
+		select {
+		case sig := <-signalChan:
+			log.WithField("signal", sig.String()).Warn("Received shutdown signal. Starting graceful shutdown...")
+			jobShutdown.Begin()
+			log.Info("Send the signal again to force terminate.")
+			cancel()
+		case <-ctx.Done():
+		}
+		sig := <-signalChan
+		log.WithField("signal", sig.String()).Error("Received second shutdown signal, forcing exit")
+		if err := logger.Flush(); err != nil {
+			log.WithError(err).Error("Failed to flush logs")
+		}
+		osUtils.Exit(core.ExitCodeForcedShutdown)
+	}()
+}
+
+// shutdownExecutor runs the shutdown sequence of the executor once the main loop
+// has stopped. This function:
+// 1. Waits up to the shutdown timeout for the running pipelines to exit
+// 2. Terminates the pipelines still running at the deadline, which reports them as Failed
+// 3. Reports the final status of jobs that concluded but were not reported yet, if the
+// epoch is in the Confirm state
+// 4. Flushes the logs
+// Jobs that cannot be concluded stay in the journal and are recovered on the next start.
+// Returns the exit code of the daemon.
+func shutdownExecutor(client *ethclient.Client, config types.Configurations, account types.Account, pipelinePath string) int {
+	exitCode := core.ExitCodeShutdown
+	timeout := time.Duration(config.ShutdownTimeout) * time.Second
+
+	log.WithFields(logrus.Fields{
+		"runningJobs": len(getTrackedJobs()),
+		"timeout":     timeout.String(),
+	}).Info("Stopped accepting jobs, waiting for running jobs")
+
+	// A zero timeout terminates the running jobs right away
+	if timeout <= 0 || !jobShutdown.WaitJobs(timeout) {
+		log.Warn("Shutdown deadline reached, terminating running jobs")
+		exitCode = core.ExitCodeShutdownJobsTerminated
+		jobShutdown.TerminateJobs()
+		jobShutdown.WaitJobs(0)
+	}
+
+	// Job statuses can only be reported in the Confirm state
+	epoch, state, err := cmdUtils.GetEpochAndState(client)
+	if err != nil {
+		log.WithError(err).Error("Failed to get current state and epoch")
+	}
+	canReport := err == nil && types.EpochState(state) == types.EpochStateConfirm
+
+	for _, job := range getTrackedJobs() {
+		if job.Status != types.JobStatusCompleted && job.Status != types.JobStatusFailed {
+			log.WithFields(logrus.Fields{
+				"jobId":  job.JobID.String(),
+				"status": job.Status,
+			}).Warn("Job left unconcluded, it will be recovered from the journal on restart")
+			continue
+		}
+		if !canReport {
+			log.WithFields(logrus.Fields{
+				"jobId":  job.JobID.String(),
+				"status": job.Status,
+				"state":  state,
+			}).Warn("Not in the Confirm state, job status will be reported from the journal on restart")
+			continue
+		}
+		if err := confirmJob(client, config, account, job, epoch, pipelinePath); err != nil {
+			log.WithError(err).WithField("jobId", job.JobID.String()).Error("Failed to report job status on shutdown")
+		}
+	}
+
+	log.WithField("exitCode", exitCode).Info("Shutdown complete")
+	if err := logger.Flush(); err != nil {
+		log.WithError(err).Error("Failed to flush logs")
+	}
+	return exitCode
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"errors"
+	luminoAccounts "lumino/accounts"
+	accountsMocks "lumino/accounts/mocks"
+	"lumino/cmd/mocks"
+	"lumino/core"
+	"lumino/core/types"
+	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests the shutdown coordinator:
+// 1. Job goroutines are refused once a shutdown has begun
+// 2. Waiting for jobs times out while a job is running
+func TestShutdownCoordinator(t *testing.T) {
+	coordinator := newShutdownCoordinator()
+
+	release := make(chan struct{})
+	assert.True(t, coordinator.Go(func() { <-release }))
+	assert.False(t, coordinator.WaitJobs(50*time.Millisecond))
+
+	coordinator.Begin()
+	assert.True(t, coordinator.IsShuttingDown())
+	assert.False(t, coordinator.Go(func() { t.Error("job started during shutdown") }))
+
+	close(release)
+	assert.True(t, coordinator.WaitJobs(time.Second))
+}
+
+// writeFakePipelineZen creates a pipeline-zen directory whose workflow runner
+// runs script instead of training, and returns it with a valid job config.
+func writeFakePipelineZen(t *testing.T, script string) (string, string) {
+	pipelinePath := t.TempDir()
+	runnersPath := filepath.Join(pipelinePath, "scripts", "runners")
+	assert.NoError(t, os.MkdirAll(runnersPath, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(runnersPath, "celery-wf.sh"), []byte(script), 0755))
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	config := `{"job_config_name": "llm_dummy", "job_id": "1", "dataset_id": "gs://dataset", "batch_size": "2",
+		"num_epochs": "1", "lr": "1e-2", "seed": "42", "num_gpus": "0", "user_id": "0x123"}`
+	assert.NoError(t, os.WriteFile(configPath, []byte(config), 0644))
+	return pipelinePath, configPath
+}
+
+// Tests the shutdown sequence with cases:
+// 1. A job finishing with results before the deadline is reported Completed
+// 2. A job still running at the deadline is terminated and reported Failed
+// 3. A job finishing outside the Confirm state is left tracked, for recovery to report it
+// 4. A job finishing when the state cannot be read is left tracked as well
+// The output of every pipeline is captured in the job's stdout.log.
+func TestShutdownExecutor(t *testing.T) {
+	var client *ethclient.Client
+	var account types.Account
+	finishedResults := ".results/0x0000000000000000000000000000000000000123/1"
+
+	tests := []struct {
+		name         string
+		script       string
+		timeout      int64
+		state        types.EpochState
+		stateErr     error
+		wantStatus   types.JobStatus // status reported on chain, 0 if none
+		wantTracked  int
+		wantExitCode int
+	}{
+		{
+			name:         "job concludes before the deadline",
+			script:       "echo training; sleep 0.2; mkdir -p " + finishedResults + "; echo '{}' > " + finishedResults + "/metrics.json",
+			timeout:      10,
+			state:        types.EpochStateConfirm,
+			wantStatus:   types.JobStatusCompleted,
+			wantExitCode: core.ExitCodeShutdown,
+		},
+		{
+			name:         "job terminated at the deadline",
+			script:       "echo training; sleep 30",
+			timeout:      1,
+			state:        types.EpochStateConfirm,
+			wantStatus:   types.JobStatusFailed,
+			wantExitCode: core.ExitCodeShutdownJobsTerminated,
+		},
+		{
+			name:         "job concludes outside the Confirm state",
+			script:       "echo training; mkdir -p " + finishedResults + "; echo '{}' > " + finishedResults + "/metrics.json",
+			timeout:      10,
+			state:        types.EpochStateAssign,
+			wantTracked:  1,
+			wantExitCode: core.ExitCodeShutdown,
+		},
+		{
+			name:         "job concludes when the state cannot be read",
+			script:       "echo training; mkdir -p " + finishedResults + "; echo '{}' > " + finishedResults + "/metrics.json",
+			timeout:      10,
+			state:        types.EpochStateConfirm,
+			stateErr:     errors.New("connection refused"),
+			wantTracked:  1,
+			wantExitCode: core.ExitCodeShutdown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmdMock := new(mocks.UtilsCmdInterface)
+			jobsMock := new(mocks.JobsManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+			journalMock := new(mocks.JobJournalInterface)
+			resultsMock := new(mocks.ResultsInterface)
+			accountsMock := new(accountsMocks.AccountInterface)
+
+			originalCmdUtils := cmdUtils
+			originalJobsManagerUtils := jobsManagerUtils
+			originalProtoUtils := protoUtils
+			originalJobJournalUtils := jobJournalUtils
+			originalJobShutdown := jobShutdown
+			originalOSUtils := path.OSUtilsInterface
+			originalPathUtils := path.PathUtilsInterface
+			originalResultsUtils := resultsUtils
+			originalAccountUtils := luminoAccounts.AccountUtilsInterface
+			defer func() {
+				resultsUtils = originalResultsUtils
+				luminoAccounts.AccountUtilsInterface = originalAccountUtils
+				path.PathUtilsInterface = originalPathUtils
+				path.OSUtilsInterface = originalOSUtils
+				cmdUtils = originalCmdUtils
+				jobsManagerUtils = originalJobsManagerUtils
+				protoUtils = originalProtoUtils
+				jobJournalUtils = originalJobJournalUtils
+				jobShutdown = originalJobShutdown
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				pendingResultPublishes = make(map[string]*pendingResultPublish)
+				stateMutex.Unlock()
+			}()
+
+			cmdUtils = cmdMock
+			jobsManagerUtils = jobsMock
+			protoUtils = utilsMock
+			jobJournalUtils = journalMock
+			resultsUtils = resultsMock
+			luminoAccounts.AccountUtilsInterface = accountsMock
+			jobShutdown = newShutdownCoordinator()
+			path.OSUtilsInterface = path.OSUtils{}
+			jobDirPath := t.TempDir()
+			pathMock := new(pathMocks.PathInterface)
+			pathMock.On("GetJobDirPath", "1").Return(jobDirPath, nil)
+			pathMock.On("GetDefaultPath").Return(t.TempDir(), nil).Maybe()
+			path.PathUtilsInterface = pathMock
+
+			journalMock.On("RecordStage", mock.Anything, mock.Anything).Return(nil).Maybe()
+			utilsMock.On("GetOptions").Return(bind.CallOpts{}).Maybe()
+			resultsMock.On("PackageJobResults", mock.Anything, mock.Anything, false).Return(types.ResultManifest{JobID: "1"}, nil).Maybe()
+			resultsMock.On("SaveResultAttestation", mock.Anything).Return(nil).Maybe()
+			accountsMock.On("SignData", mock.Anything, mock.Anything, mock.Anything).Return(make([]byte, 65), nil).Maybe()
+			jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+				Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil).Maybe()
+			cmdMock.On("GetEpochAndState", client).Return(uint32(1), int64(tt.state), tt.stateErr)
+			if tt.wantStatus != 0 {
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), tt.wantStatus, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			}
+
+			pipelinePath, configPath := writeFakePipelineZen(t, tt.script)
+			config := types.Configurations{ShutdownTimeout: tt.timeout}
+
+			stateMutex.Lock()
+			executionState = types.JobExecutionState{}
+			stateMutex.Unlock()
+			trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning})
+			jobShutdown.Go(func() {
+				runJobPipeline(jobShutdown.JobContext(), client, config, account, big.NewInt(1), configPath, pipelinePath, nil)
+			})
+
+			jobShutdown.Begin()
+			exitCode := shutdownExecutor(client, config, account, pipelinePath)
+			assert.Eventually(t, resultPublishesIdle, time.Second, 10*time.Millisecond)
+
+			assert.Equal(t, tt.wantExitCode, exitCode)
+			assert.Len(t, getTrackedJobs(), tt.wantTracked)
+			stdout, err := os.ReadFile(filepath.Join(jobDirPath, jobStdoutLogFile))
+			assert.NoError(t, err)
+			assert.Equal(t, "training\n", string(stdout))
+			cmdMock.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package cmd provides all functions related to command line
+package cmd
+
+import (
+	"fmt"
+	"lumino/core/types"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/sirupsen/logrus"
+)
+
+// activeStakerCache holds the staker list discovered for the most recent epoch,
+// so the assignment loop does not walk the whole StakeManager on every tick
+var activeStakerCache struct {
+	sync.Mutex
+	epoch   uint32
+	valid   bool
+	stakers []types.StakerContract
+}
+
+// GetActiveStakers returns the stakers eligible for job assignment in the given epoch. This function:
+// 1. Returns the cached list if it was already built for this epoch
+// 2. Reads the number of stakers and the minimum stake from StakeManager
+// 3. Fetches every staker and skips empty, slashed and under-staked entries
+// 4. Caches the resulting list until the epoch changes
+// Returns error if any StakeManager call fails.
+func (*UtilsStruct) GetActiveStakers(client *ethclient.Client, epoch uint32) ([]types.StakerContract, error) {
+	activeStakerCache.Lock()
+	defer activeStakerCache.Unlock()
+
+	if activeStakerCache.valid && activeStakerCache.epoch == epoch {
+		return append([]types.StakerContract(nil), activeStakerCache.stakers...), nil
+	}
+
+	opts := protoUtils.GetOptions()
+	numStakers, err := stakeManagerUtils.GetNumStakers(client, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get number of stakers: %w", err)
+	}
+
+	minStake, err := stakeManagerUtils.GetMinStake(client, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get minimum stake: %w", err)
+	}
+
+	activeStakers := make([]types.StakerContract, 0, numStakers)
+	// Staker IDs are assigned sequentially starting at 1
+	for stakerId := uint32(1); stakerId <= numStakers; stakerId++ {
+		staker, err := stakeManagerUtils.GetStakerStructFromId(client, &opts, stakerId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get staker %d: %w", stakerId, err)
+		}
+
+		if reason := stakerIneligibility(staker, minStake); reason != "" {
+			log.WithFields(logrus.Fields{
+				"stakerId": stakerId,
+				"address":  staker.Address.Hex(),
+				"reason":   reason,
+			}).Debug("Skipping staker for assignment")
+			continue
+		}
+		activeStakers = append(activeStakers, staker)
+	}
+
+	log.WithFields(logrus.Fields{
+		"epoch":         epoch,
+		"numStakers":    numStakers,
+		"activeStakers": len(activeStakers),
+	}).Info("Discovered active stakers")
+
+	activeStakerCache.epoch = epoch
+	activeStakerCache.valid = true
+	activeStakerCache.stakers = activeStakers
+
+	return append([]types.StakerContract(nil), activeStakers...), nil
+}
+
+// stakerIneligibility returns why a staker cannot receive jobs, or an empty string if it can
+func stakerIneligibility(staker types.StakerContract, minStake *big.Int) string {
+	switch {
+	case staker.Address == (common.Address{}):
+		return "staker slot is empty"
+	case staker.IsSlashed:
+		return "staker is slashed"
+	case staker.Stake == nil || staker.Stake.Sign() <= 0:
+		return "staker has no stake"
+	case minStake != nil && staker.Stake.Cmp(minStake) < 0:
+		return "stake is below minStake"
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"lumino/cmd/mocks"
+	"lumino/core/types"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Tests on-chain staker discovery with cases:
+// 1. Slashed, under-staked and empty stakers are skipped
+// 2. StakeManager errors are returned
+// Verifies the eligible staker list built for assignment.
+func TestGetActiveStakers(t *testing.T) {
+	var client *ethclient.Client
+	minStake := big.NewInt(100)
+
+	eligible := types.StakerContract{Id: 1, Address: common.HexToAddress("0x1"), Stake: big.NewInt(100)}
+	slashed := types.StakerContract{Id: 2, Address: common.HexToAddress("0x2"), Stake: big.NewInt(500), IsSlashed: true}
+	underStaked := types.StakerContract{Id: 3, Address: common.HexToAddress("0x3"), Stake: big.NewInt(99)}
+	empty := types.StakerContract{Id: 4}
+
+	tests := []struct {
+		name        string
+		setupMocks  func(*mocks.StakeManagerInterface)
+		wantStakers []types.StakerContract
+		wantErr     bool
+	}{
+		{
+			name: "only eligible stakers are returned",
+			setupMocks: func(stakeMock *mocks.StakeManagerInterface) {
+				stakeMock.On("GetNumStakers", mock.Anything, mock.Anything).Return(uint32(4), nil)
+				stakeMock.On("GetMinStake", mock.Anything, mock.Anything).Return(minStake, nil)
+				stakeMock.On("GetStakerStructFromId", mock.Anything, mock.Anything, uint32(1)).Return(eligible, nil)
+				stakeMock.On("GetStakerStructFromId", mock.Anything, mock.Anything, uint32(2)).Return(slashed, nil)
+				stakeMock.On("GetStakerStructFromId", mock.Anything, mock.Anything, uint32(3)).Return(underStaked, nil)
+				stakeMock.On("GetStakerStructFromId", mock.Anything, mock.Anything, uint32(4)).Return(empty, nil)
+			},
+			wantStakers: []types.StakerContract{eligible},
+		},
+		{
+			name: "error fetching the number of stakers",
+			setupMocks: func(stakeMock *mocks.StakeManagerInterface) {
+				stakeMock.On("GetNumStakers", mock.Anything, mock.Anything).Return(uint32(0), errors.New("rpc error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error fetching the minimum stake",
+			setupMocks: func(stakeMock *mocks.StakeManagerInterface) {
+				stakeMock.On("GetNumStakers", mock.Anything, mock.Anything).Return(uint32(1), nil)
+				stakeMock.On("GetMinStake", mock.Anything, mock.Anything).Return(nil, errors.New("rpc error"))
+			},
+			wantErr: true,
+		},
+		{
+			name: "error fetching a staker",
+			setupMocks: func(stakeMock *mocks.StakeManagerInterface) {
+				stakeMock.On("GetNumStakers", mock.Anything, mock.Anything).Return(uint32(1), nil)
+				stakeMock.On("GetMinStake", mock.Anything, mock.Anything).Return(minStake, nil)
+				stakeMock.On("GetStakerStructFromId", mock.Anything, mock.Anything, uint32(1)).
+					Return(types.StakerContract{}, errors.New("rpc error"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activeStakerCache.valid = false
+
+			stakeMock := new(mocks.StakeManagerInterface)
+			utilsMock := new(mocks.UtilsInterface)
+
+			originalStakeManagerUtils := stakeManagerUtils
+			originalProtoUtils := protoUtils
+			defer func() {
+				stakeManagerUtils = originalStakeManagerUtils
+				protoUtils = originalProtoUtils
+				activeStakerCache.valid = false
+			}()
+
+			stakeManagerUtils = stakeMock
+			protoUtils = utilsMock
+
+			utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			tt.setupMocks(stakeMock)
+
+			utils := &UtilsStruct{}
+			stakers, err := utils.GetActiveStakers(client, 1)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStakers, stakers)
+			stakeMock.AssertExpectations(t)
+		})
+	}
+}
+
+// Tests that the staker list is read from the chain once per epoch
+func TestGetActiveStakersCachedPerEpoch(t *testing.T) {
+	var client *ethclient.Client
+	staker := types.StakerContract{Id: 1, Address: common.HexToAddress("0x1"), Stake: big.NewInt(100)}
+
+	stakeMock := new(mocks.StakeManagerInterface)
+	utilsMock := new(mocks.UtilsInterface)
+
+	originalStakeManagerUtils := stakeManagerUtils
+	originalProtoUtils := protoUtils
+	defer func() {
+		stakeManagerUtils = originalStakeManagerUtils
+		protoUtils = originalProtoUtils
+		activeStakerCache.valid = false
+	}()
+
+	stakeManagerUtils = stakeMock
+	protoUtils = utilsMock
+	activeStakerCache.valid = false
+
+	utilsMock.On("GetOptions").Return(bind.CallOpts{})
+	stakeMock.On("GetNumStakers", mock.Anything, mock.Anything).Return(uint32(1), nil).Twice()
+	stakeMock.On("GetMinStake", mock.Anything, mock.Anything).Return(big.NewInt(1), nil).Twice()
+	stakeMock.On("GetStakerStructFromId", mock.Anything, mock.Anything, uint32(1)).Return(staker, nil).Twice()
+
+	utils := &UtilsStruct{}
+	for _, epoch := range []uint32{5, 5, 6} {
+		stakers, err := utils.GetActiveStakers(client, epoch)
+		assert.NoError(t, err)
+		assert.Len(t, stakers, 1)
+	}
+
+	stakeMock.AssertNumberOfCalls(t, "GetNumStakers", 2)
+	stakeMock.AssertExpectations(t)
+}
@@ -3,19 +3,23 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"lumino/core"
 	"lumino/core/types"
 	"lumino/path"
 	pipeline_zen "lumino/pipeline-zen"
 	"math/big"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
-	"golang.org/x/exp/rand"
 )
 
 // HandleStateTransition manages state transitions in the network based on current state and conditions.
@@ -46,30 +50,65 @@ func (*UtilsStruct) HandleStateTransition(ctx context.Context, client *ethclient
 	}
 }
 
-// HandleAssignState processes job assignment state transitions. This function:
-// 1. Verifies the current epoch and network state
-// 2. Retrieves and validates active jobs
-// 3. Manages random job assignment if enabled
-// 4. Executes job assignments with proper validation
-// Returns error if assignment process fails.
+// HandleAssignState processes job assignment state transitions. Assignment runs as one
+// persisted round per epoch, so repeated ticks and restarts never resubmit a job. This function:
+// 1. Selects the assignment strategy from config, --isRandom forces the random strategy
+// 2. Loads the round of the epoch, returning early once it has completed
+// 3. Resolves assignments left pending by an interrupted round against the chain
+// 4. Discovers the active stakers from StakeManager when the strategy needs them
+// 5. Retrieves active jobs, skipping jobs already handled in this round and jobs that have an
+// assignee on chain or are past Queued, such as jobs assigned in earlier epochs
+// 6. Narrows the candidates of each job to stakers whose machine spec meets its requirements
+// 7. Records each assignment as pending before executing it and as assigned or failed afterwards
+// Returns error if the strategy is unknown, the round cannot be persisted, staker discovery or the assignment process fails.
 func (*UtilsStruct) HandleAssignState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, isRandom bool) error {
 
 	log.WithFields(logrus.Fields{
 		"Current State": "Assign",
 	}).Info("Admin Node: Executing Assign State Transition")
 	opts := protoUtils.GetOptions()
-	// TODO: to be replaced by activeStaker or a better mechanism
-	// numStakers, err := stakeManagerUtils.GetNumStakers(client, &opts)
 
-	log.Debug("Num stakers : ", 3)
-
-	var activeStakers [3]string
+	strategyName := config.AssignmentStrategy
 	if isRandom {
-		activeStakers = [3]string{"0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771", "0xab110dA2064AC0B44c08D71A3D8148BBB0C3aD1F", "0x68D12CaB6c4016A0daEeBA779205727dd6031a9a"}
-	} else {
-		activeStakers = [3]string{"0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771", "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771", "0xC4481aa21AeAcAD3cCFe6252c6fe2f161A47A771"}
+		strategyName = AssignmentStrategyRandom
+	}
+	if strategyName == "" {
+		strategyName = core.DefaultAssignmentStrategy
+	}
+	strategy, err := NewAssignmentStrategy(strategyName, account)
+	if err != nil {
+		return err
+	}
+
+	round, err := loadAssignmentRound(epoch, strategy.Name())
+	if err != nil {
+		return fmt.Errorf("failed to load assignment round: %w", err)
+	}
+	if round.Completed {
+		log.WithField("epoch", epoch).Debug("Assignment round already completed for this epoch")
+		return nil
+	}
+	if err := reconcilePendingAssignments(client, &opts, &round); err != nil {
+		return err
+	}
+	if err := assignmentRoundUtils.SaveRound(round); err != nil {
+		return fmt.Errorf("failed to save assignment round: %w", err)
 	}
 
+	var activeStakers []types.StakerContract
+	if strategy.NeedsStakers() {
+		activeStakers, err = cmdUtils.GetActiveStakers(client, epoch)
+		if err != nil {
+			return fmt.Errorf("failed to get active stakers: %w", err)
+		}
+		if len(activeStakers) == 0 {
+			log.Warn("No active stakers eligible for assignment")
+			return nil
+		}
+	}
+
+	log.Debug("Num stakers : ", len(activeStakers))
+
 	// Get unassigned jobs and assign them
 	// TODO: to be moved to jobsManagerUtils in struct Utils in future
 	unassignedJobs, err := jobsManagerUtils.GetActiveJobs(client, &opts)
@@ -78,250 +117,717 @@ func (*UtilsStruct) HandleAssignState(ctx context.Context, client *ethclient.Cli
 	}
 	if len(unassignedJobs) == 0 {
 		log.Info("No active jobs to be assigned")
-	} else {
-		log.Debug("ActiveUnassignedJobs : ", unassignedJobs)
+		return nil
 	}
+	log.Debug("ActiveUnassignedJobs : ", unassignedJobs)
 
-	// TODO: make assignJob accept array input
+	requests := make([]AssignmentRequest, 0, len(unassignedJobs))
 	for _, jobId := range unassignedJobs {
-		// Pick a random staker
-		selectedStaker := activeStakers[rand.Intn(len(activeStakers))]
-
-		if jobId.Cmp(big.NewInt(0)) == 1 {
-
+		if jobId.Cmp(big.NewInt(0)) != 1 {
+			continue
+		}
+		if handled, ok := round.Assignments[jobId.String()]; ok {
 			log.WithFields(logrus.Fields{
 				"jobId":  jobId.String(),
-				"staker": selectedStaker,
-			}).Info("Assigning job")
-
-			txnHash, err := cmdUtils.AssignJob(client, config, account, selectedStaker, jobId, 0)
+				"status": handled.Status,
+			}).Debug("Job already handled in this assignment round")
+			continue
+		}
+		jobDetails, assignable, err := assignableJob(client, &opts, jobId)
+		if err != nil {
+			log.WithError(err).WithField("jobId", jobId.String()).Error("Failed to check whether job needs an assignee")
+			continue
+		}
+		if !assignable {
+			continue
+		}
+		request := AssignmentRequest{JobID: jobId}
+		if strategy.NeedsStakers() {
+			request.Candidates, err = hardwareEligibleStakers(jobId, jobDetails, activeStakers)
 			if err != nil {
-				log.WithError(err).Error("Failed to assign job")
+				log.WithError(err).WithField("jobId", jobId.String()).Error("Failed to match job against staker hardware")
 				continue
 			}
+			if len(request.Candidates) == 0 {
+				log.WithField("jobId", jobId.String()).Warn("No active staker meets the job hardware requirements, leaving job unassigned")
+				continue
+			}
+		}
+		requests = append(requests, request)
+	}
+
+	assignments, err := strategy.Assign(client, epoch, requests)
+	if err != nil {
+		return fmt.Errorf("failed to assign jobs with strategy %s: %w", strategy.Name(), err)
+	}
+
+	// TODO: make assignJob accept array input
+	for i, assignment := range assignments {
+		logAssignment(strategy, epoch, assignment, len(requests[i].Candidates))
 
+		recorded := types.RoundAssignment{
+			JobID:  assignment.JobID.String(),
+			Staker: assignment.Staker.Hex(),
+			Status: types.AssignmentStatusPending,
+		}
+		if err := recordAssignment(&round, recorded); err != nil {
+			return fmt.Errorf("failed to record pending assignment of job %s: %w", recorded.JobID, err)
+		}
+
+		txnHash, err := cmdUtils.AssignJob(client, config, account, assignment.Staker.Hex(), assignment.JobID, 0)
+		if err != nil {
+			log.WithError(err).Error("Failed to assign job")
+			recorded.Status = types.AssignmentStatusFailed
+			recorded.Error = err.Error()
+		} else {
 			log.WithField("txHash", txnHash.Hex()).Info("Job assigned successfully")
+			recorded.Status = types.AssignmentStatusAssigned
+			recorded.TxHash = txnHash.Hex()
 		}
+		if err := recordAssignment(&round, recorded); err != nil {
+			return fmt.Errorf("failed to record assignment of job %s: %w", recorded.JobID, err)
+		}
+	}
+
+	round.Completed = true
+	if err := assignmentRoundUtils.SaveRound(round); err != nil {
+		return fmt.Errorf("failed to save assignment round: %w", err)
 	}
+	log.WithFields(logrus.Fields{
+		"epoch":       epoch,
+		"assignments": len(round.Assignments),
+	}).Info("Assignment round completed")
 	return nil
 }
 
 // HandleUpdateState manages job update state processing including:
-// 1. Checking current job status and running state
-// 2. Validating job assignment to staker
-// 3. Processing job status updates
-// 4. Managing job execution state
-// Returns error if update process fails.
+// 1. Collecting every job assigned to this staker, unless the executor is shutting down
+// 2. Skipping jobs that are already executing on this node
+// 3. Reserving GPU slots for queued jobs, leaving them queued while slots are busy
+// 4. Starting the pipeline of each job that obtained its slots
+// Returns error if the assigned jobs cannot be retrieved or a job cannot be started.
 func (*UtilsStruct) HandleUpdateState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, pipelinePath string) error {
-	// Check if already running a job
-	stateMutex.RLock()
-	isJobRunning := executionState.IsJobRunning
-	stateMutex.RUnlock()
-
 	log.WithFields(logrus.Fields{
 		"Current State": "Update",
 	}).Info("Executing Update State Transition")
 
+	if jobShutdown.IsShuttingDown() {
+		log.Info("Shutting down, not accepting new jobs")
+		return nil
+	}
+
 	opts := protoUtils.GetOptions()
 
-	// Get job assigned to this staker
-	jobId, err := jobsManagerUtils.GetJobForStaker(client, &opts, common.HexToAddress(account.Address))
+	jobIds, err := getAssignedJobs(client, &opts, account)
 	if err != nil {
-		return fmt.Errorf("failed to get job for staker: %w", err)
+		return err
 	}
 
-	if jobId.Cmp(big.NewInt(0)) == 0 {
+	if len(jobIds) == 0 {
 		log.Debug("No job assigned")
 		return nil
 	}
 
-	// Get job status
-	status, err := jobsManagerUtils.GetJobStatus(client, &opts, jobId)
+	var errs []error
+	for _, jobId := range jobIds {
+		if err := startAssignedJob(client, config, account, &opts, jobId, pipelinePath); err != nil {
+			log.WithError(err).WithField("jobId", jobId.String()).Error("Failed to start assigned job")
+			errs = append(errs, fmt.Errorf("job %s: %w", jobId.String(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// getAssignedJobs returns the IDs of all jobs assigned to the staker, ordered by job ID.
+// The job reported by getJobForStaker is merged with the active jobs whose assignee is the staker,
+// so every job handed to this node is picked up even while another one is executing.
+func getAssignedJobs(client *ethclient.Client, opts *bind.CallOpts, account types.Account) ([]*big.Int, error) {
+	stakerAddress := common.HexToAddress(account.Address)
+
+	assignedJobId, err := jobsManagerUtils.GetJobForStaker(client, opts, stakerAddress)
 	if err != nil {
-		return fmt.Errorf("failed to get job status: %w", err)
+		return nil, fmt.Errorf("failed to get job for staker: %w", err)
 	}
 
-	if isJobRunning && status == uint8(types.JobStatusRunning) {
-		log.Info("Already running a job")
+	seen := make(map[string]bool)
+	var jobIds []*big.Int
+	if assignedJobId != nil && assignedJobId.Sign() > 0 {
+		seen[assignedJobId.String()] = true
+		jobIds = append(jobIds, assignedJobId)
+	}
+
+	activeJobs, err := jobsManagerUtils.GetActiveJobs(client, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active jobs: %w", err)
+	}
+
+	for _, jobId := range activeJobs {
+		if jobId == nil || jobId.Sign() <= 0 || seen[jobId.String()] {
+			continue
+		}
+		jobDetails, err := jobsManagerUtils.GetJobDetails(client, opts, jobId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job details: %w", err)
+		}
+		if jobDetails.Assignee != stakerAddress {
+			continue
+		}
+		seen[jobId.String()] = true
+		jobIds = append(jobIds, jobId)
+	}
+
+	sort.Slice(jobIds, func(i, j int) bool {
+		return jobIds[i].Cmp(jobIds[j]) < 0
+	})
+	return jobIds, nil
+}
+
+// startAssignedJob starts the execution of a single assigned job. This function:
+// 1. Skips jobs already tracked by this node and jobs that are not Queued
+// 2. Rejects a malformed job spec, reporting the job Failed with the reason
+// 3. Runs the preflight checks of disk space, GPUs, memory and the pipeline environment,
+// refusing the job if the node cannot run it
+// 4. Reserves the requested number of GPUs, leaving the job queued when not enough are free
+// 5. Writes the job config to .jobs/<jobId>/config.json
+// 6. Prefetches the dataset of the job in a job goroutine tracked by the shutdown coordinator,
+// failing the job if it cannot be fetched
+// 7. Sets the job to Running and runs the pipeline pinned to its GPUs
+// Returns error if the job details cannot be read, the preflight checks cannot run, or a rejected
+// or refused job cannot be reported Failed.
+func startAssignedJob(client *ethclient.Client, config types.Configurations, account types.Account, opts *bind.CallOpts, jobId *big.Int, pipelinePath string) error {
+	if getTrackedJob(jobId) != nil {
+		log.WithField("jobId", jobId.String()).Debug("Job already executing on this node")
 		return nil
 	}
 
+	// Get job status
+	status, err := jobsManagerUtils.GetJobStatus(client, opts, jobId)
+	if err != nil {
+		return fmt.Errorf("failed to get job status: %w", err)
+	}
+
 	if status != uint8(types.JobStatusQueued) {
 		log.WithFields(logrus.Fields{
 			"jobId":  jobId.String(),
 			"status": status,
 		}).Debug("Job not Queued yet")
+		setJobAwaitingStart(jobId, false)
 		return nil
 	}
 
 	// Get job details
-	jobDetails, err := jobsManagerUtils.GetJobDetails(client, &opts, jobId)
+	jobDetails, err := jobsManagerUtils.GetJobDetails(client, opts, jobId)
 	if err != nil {
 		return fmt.Errorf("failed to get job details: %w", err)
 	}
 
-	// Log the input for debugging
 	log.WithField("rawJSON", jobDetails.JobDetailsInJSON).Debug("Received job details JSON")
 
-	// Clean up the JSON string by removing escaped characters
-	cleanJSON := cleanJSONString(jobDetails.JobDetailsInJSON)
-	log.WithField("cleanJSON", cleanJSON).Debug("Cleaned JSON string")
-	// First unmarshal the incoming JSON into a map to handle unknown structure
-	var rawConfig map[string]interface{}
-	if err := json.Unmarshal([]byte(cleanJSON), &rawConfig); err != nil {
-		return fmt.Errorf("failed to parse job details: %w", err)
-	}
-
-	// Create the new config structure with the desired format
-	jobConfig := types.JobConfig{
-		JobConfigName: getString(rawConfig, "job_config_name"),
-		JobID:         jobId.String(),
-		DatasetID:     getString(rawConfig, "dataset_id"),
-		BatchSize:     getString(rawConfig, "batch_size"),
-		Shuffle:       getString(rawConfig, "shuffle"),
-		NumEpochs:     getString(rawConfig, "num_epochs"),
-		UseLora:       getString(rawConfig, "use_lora"),
-		UseQlora:      getString(rawConfig, "use_qlora"),
-		LearningRate:  getString(rawConfig, "lr", "1e-2"),
-		OverrideEnv:   getString(rawConfig, "override_env", "prod"), // default to "prod"
-		Seed:          getString(rawConfig, "seed", "42"),           // default to "42"
-		NumGPUs:       getString(rawConfig, "num_gpus"),
-		UserID:        jobDetails.Creator.String(),
+	spec, err := parseJobDetails([]byte(jobDetails.JobDetailsInJSON))
+	if err != nil {
+		return rejectJob(client, config, account, jobId, err)
 	}
-
-	// Create job directory in .lumino
-	jobDir := filepath.Join("./.jobs", jobId.String())
-	if err := path.OSUtilsInterface.MkdirAll(jobDir, 0755); err != nil {
-		return fmt.Errorf("failed to create job directory: %w", err)
+	requirements, err := jobSpecRequirements(spec)
+	if err != nil {
+		return rejectJob(client, config, account, jobId, err)
 	}
+	jobConfig := newJobConfig(spec, jobId, jobDetails.Creator)
 
-	// Marshal the config with proper indentation
-	configJson, err := json.MarshalIndent(jobConfig, "", "  ")
+	reasons, err := preflightJob(requirements, pipelinePath)
 	if err != nil {
-		return fmt.Errorf("failed to marshal job config: %w", err)
+		return fmt.Errorf("failed to run preflight checks: %w", err)
+	}
+	if len(reasons) > 0 {
+		setJobAwaitingStart(jobId, !failRefusedJobs)
+		return refuseJob(client, config, account, jobId, reasons)
 	}
 
-	configJson = append(configJson, '\n')
+	numGPUs := spec.NumGPUs
+
+	gpus, ok := gpuAllocator.Allocate(jobId.String(), numGPUs)
+	if !ok {
+		log.WithFields(logrus.Fields{
+			"jobId":     jobId.String(),
+			"requested": numGPUs,
+			"free":      gpuAllocator.FreeGPUs(),
+		}).Info("Not enough free GPU slots, job stays queued")
+		setJobAwaitingStart(jobId, true)
+		return nil
+	}
+	setJobAwaitingStart(jobId, false)
+
+	// Create job directory in .lumino
+	jobDir, err := path.PathUtilsInterface.GetJobDirPath(jobId.String())
+	if err != nil {
+		gpuAllocator.Release(jobId.String())
+		return fmt.Errorf("failed to create job directory: %w", err)
+	}
 
-	// Write to file
 	configPath := filepath.Join(jobDir, "config.json")
-	if err := path.OSUtilsInterface.WriteFile(configPath, configJson, 0644); err != nil {
-		return fmt.Errorf("failed to write job config: %w", err)
+	if err := writeJobConfig(configPath, jobConfig); err != nil {
+		gpuAllocator.Release(jobId.String())
+		return err
 	}
 
 	log.WithFields(logrus.Fields{
 		"jobId":      jobId.String(),
 		"configPath": configPath,
+		"gpus":       cudaVisibleDevices(gpus),
 	}).Debug("Job configuration written to file")
 
-	// TODO: Put this in a go routine
-	// start the waitgroup and wait for it in the main thread
-	// read the files for the status of the job
+	trackJob(&types.JobExecution{
+		JobID:     jobId,
+		Status:    types.JobStatusQueued,
+		StartTime: time.Now(),
+		Executor:  account.Address,
+		GPUs:      gpus,
+	})
+
+	recordJobStage(types.JobJournalRecord{
+		JobID:        jobId.String(),
+		Creator:      jobDetails.Creator.String(),
+		Executor:     account.Address,
+		ConfigPath:   configPath,
+		PipelinePath: pipelinePath,
+		GPUs:         gpus,
+	}, types.JobJournalEvent{Stage: types.JobStageConfigWritten})
+
 	// Execute job with the config from .lumino directory
 	// Start job execution in background
-	go func() {
-		// Update job status to Running
-		txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusRunning, 0)
+	started := jobShutdown.Go(func() {
+		datasetPath, err := prefetchJobDataset(jobShutdown.JobContext(), config, jobId.String(), jobDir, jobConfig.DatasetID)
+		if err == nil && datasetPath != "" {
+			jobConfig.DatasetPath = datasetPath
+			err = writeJobConfig(configPath, jobConfig)
+		}
 		if err != nil {
-			log.WithError(err).Error("Failed to update job status to running")
+			log.WithError(err).WithField("jobId", jobId.String()).Error("Failed to fetch job dataset")
+			failJobPipeline(client, config, account, types.JobCompletion{
+				JobID:   jobId.String(),
+				Outcome: types.JobOutcomeFailed,
+				Reason:  "failed to fetch dataset: " + err.Error(),
+			})
 			return
 		}
-		log.WithField("txHash", txnHash.Hex()).Info("Job status updated to Running")
+		if datasetPath != "" {
+			recordJobStage(types.JobJournalRecord{JobID: jobId.String()},
+				types.JobJournalEvent{Stage: types.JobStageDatasetFetched})
+		}
 
-		// Execute job
-		output, err := pipeline_zen.RunTorchTuneWrapper(pipelinePath, configPath)
+		// Update job status to Running
+		txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusRunning, 0)
 		if err != nil {
-			log.WithError(err).Error("Job execution failed")
-			// Update status to Failed
-			if _, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusFailed, 0); err != nil {
-				log.WithError(err).Error("Failed to update job status to failed")
-			}
+			log.WithError(err).WithField("jobId", jobId.String()).Error("Failed to update job status to running")
+			untrackJob(jobId)
+			setJobAwaitingStart(jobId, true)
 			return
 		}
-
 		log.WithFields(logrus.Fields{
 			"jobId":  jobId.String(),
-			"output": output,
-		}).Info("Job started successfully")
-
-		// Update state
-		stateMutex.Lock()
-		executionState.CurrentJob = &types.JobExecution{
-			JobID:     jobId,
-			Status:    types.JobStatusRunning,
-			StartTime: time.Now(),
-			Executor:  account.Address,
-		}
-		executionState.IsJobRunning = true
-		stateMutex.Unlock()
-	}()
+			"txHash": txnHash.Hex(),
+		}).Info("Job status updated to Running")
+		setJobStatus(jobId, types.JobStatusRunning)
+		recordJobStage(types.JobJournalRecord{JobID: jobId.String()},
+			types.JobJournalEvent{Stage: types.JobStageRunningTxSent, TxHash: txnHash.Hex()})
+
+		runJobPipeline(jobShutdown.JobContext(), client, config, account, jobId, configPath, pipelinePath, gpus)
+	})
+	if !started {
+		log.WithField("jobId", jobId.String()).Info("Shutting down, job not started")
+		untrackJob(jobId)
+	}
 
 	return nil
 }
 
-// getString safely extracts a string value from the map, with optional default value
-func getString(m map[string]interface{}, key string, defaultValue ...string) string {
-	if val, ok := m[key]; ok {
-		// Convert the value to string regardless of its original type
-		switch v := val.(type) {
-		case string:
-			return v
-		case bool:
-			return fmt.Sprintf("%v", v)
-		case float64:
-			// Handle integers vs decimals differently
-			if v == float64(int(v)) {
-				return fmt.Sprintf("%.0f", v)
-			}
-			return fmt.Sprintf("%g", v)
-		default:
-			return fmt.Sprintf("%v", v)
+// writeJobConfig writes the job config for the pipeline to configPath.
+// Returns error if the config cannot be marshaled or written.
+func writeJobConfig(configPath string, jobConfig types.JobConfig) error {
+	// Marshal the config with proper indentation
+	configJson, err := json.MarshalIndent(jobConfig, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal job config: %w", err)
+	}
+
+	configJson = append(configJson, '\n')
+
+	if err := path.OSUtilsInterface.WriteFile(configPath, configJson, 0644); err != nil {
+		return fmt.Errorf("failed to write job config: %w", err)
+	}
+	return nil
+}
+
+// runJobPipeline runs the pipeline-zen workflow for a job whose Running status is
+// already on chain, pinned to the GPUs reserved for it, and classifies how it ended.
+// Every step is journaled and the runner exit saved to the job's exit.json so the job
+// can be recovered after a restart. A pipeline that fails to start, exits non-zero, is
+// killed, times out or is canceled through ctx is reported as Failed right away and its
+// GPUs are freed; a pipeline stopped by HandleConfirmState keeps the outcome decided
+// there. A clean exit marks the job Completed locally for HandleConfirmState to check
+// and report. The output of the pipeline is also written to the job's stdout.log and
+// stderr.log, see jobLogs, and parsed into its progress.json, see jobStatus.
+func runJobPipeline(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, jobId *big.Int, configPath string, pipelinePath string, gpus []int) {
+	if ctx.Err() != nil {
+		failJobPipeline(client, config, account, types.JobCompletion{
+			JobID:   jobId.String(),
+			Outcome: types.JobOutcomeFailed,
+			Reason:  "executor shut down before the pipeline started",
+		})
+		return
+	}
+
+	recordJobStage(types.JobJournalRecord{JobID: jobId.String()},
+		types.JobJournalEvent{Stage: types.JobStagePipelineStarted})
+
+	opts := pipeline_zen.ProcessOptions{
+		Env:         gpuEnv(gpus),
+		Timeout:     time.Duration(core.PipelineTimeout) * time.Second,
+		GracePeriod: time.Duration(core.PipelineTerminationGracePeriod) * time.Second,
+		LogFields:   logrus.Fields{"jobId": jobId.String()},
+	}
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(jobId.String())
+	if err != nil {
+		log.WithError(err).WithField("jobId", jobId.String()).Warn("Failed to get job directory, pipeline output goes to the daemon log only")
+	} else {
+		jobConfig, err := readJobConfig(configPath)
+		if err != nil {
+			log.WithError(err).WithField("jobId", jobId.String()).Warn("Failed to read job config, parsing progress as torchtune output")
+		}
+		progress := newJobProgressTracker(jobDirPath, jobId.String(), jobConfig)
+		stdoutLog, stderrLog := openJobLogs(jobDirPath)
+		defer progress.Flush()
+		defer stdoutLog.Close()
+		defer stderrLog.Close()
+		opts.Stdout = io.MultiWriter(stdoutLog, progress)
+		opts.Stderr = io.MultiWriter(stderrLog, progress)
+	}
+
+	// Execute job
+	process, err := pipeline_zen.StartWorkflow(ctx, jobRuntime, pipelinePath, configPath, opts)
+	if err != nil {
+		log.WithError(err).WithField("jobId", jobId.String()).Error("Failed to start job pipeline")
+		failJobPipeline(client, config, account, types.JobCompletion{
+			JobID:   jobId.String(),
+			Outcome: types.JobOutcomeFailed,
+			Reason:  "failed to start pipeline: " + err.Error(),
+		})
+		return
+	}
+
+	setJobProcess(jobId, process)
+	result := process.Wait()
+	removeJobProcess(jobId)
+	logFields := logrus.Fields{
+		"jobId":    jobId.String(),
+		"exitCode": result.ExitCode,
+		"signal":   result.Signal,
+		"duration": result.Duration.Round(time.Second).String(),
+		"timedOut": result.TimedOut,
+		"canceled": result.Canceled,
+	}
+	exit := types.JobRunnerExit{
+		ExitCode: result.ExitCode,
+		Signal:   result.Signal,
+		TimedOut: result.TimedOut,
+		Canceled: result.Canceled,
+		ExitedAt: time.Now().UTC(),
+	}
+	if !result.Succeeded() {
+		exit.Reason = result.Reason()
+	}
+	if jobDirPath != "" {
+		if err := saveJobRunnerExit(jobDirPath, exit); err != nil {
+			log.WithError(err).WithField("jobId", jobId.String()).Warn("Failed to save runner exit")
+		}
+	}
+	if !result.Succeeded() {
+		log.WithFields(logFields).WithField("stderrTail", strings.Join(result.StderrTail, "\n")).
+			Error("Job execution failed")
+		completion := types.JobCompletion{JobID: jobId.String(), Outcome: types.JobOutcomeFailed, Reason: "runner " + exit.Reason}
+		// A pipeline stopped by HandleConfirmState concludes with the outcome decided there
+		if stopped, err := readJobCompletion(jobId.String()); err == nil && stopped.Outcome != types.JobOutcomeCompleted {
+			completion = stopped
+		}
+		failJobPipeline(client, config, account, completion)
+		return
+	}
+
+	log.WithFields(logFields).Info("Job pipeline exited successfully")
+	recordJobStage(types.JobJournalRecord{JobID: jobId.String()},
+		types.JobJournalEvent{Stage: types.JobStagePipelineExited})
+
+	// Update state
+	setJobStatus(jobId, types.JobStatusCompleted)
+}
+
+// deliverJobResults prepares the results of a completed job for its creator. This function:
+// 1. Reuses the manifest and attestation of an earlier delivery of the job, if the job directory holds them
+// 2. Otherwise describes the results directory in a manifest, archiving it when a results store is configured
+// 3. Signs an attestation of the manifest, completed at completedAt, with the staker key and saves it in the job directory
+// 4. Uploads the archive, attestation and manifest to the results store, if configured and not uploaded yet
+// Returns error if the results cannot be packaged, signed or uploaded.
+func deliverJobResults(config types.Configurations, account types.Account, job types.JobExecution, creator common.Address, completedAt time.Time, zenPath string, pipelinePath string) error {
+	jobId := job.JobID.String()
+	manifest, err := readAttestedResults(jobId)
+	attested := err == nil
+	if attested {
+		log.WithField("jobId", jobId).Info("Job results already attested")
+	} else {
+		manifest, err = resultsUtils.PackageJobResults(types.ResultManifest{
+			JobID:    jobId,
+			Creator:  creator.String(),
+			Assignee: account.Address,
+		}, zenPath, config.ResultsStore != "")
+		if err != nil {
+			return fmt.Errorf("failed to package job results: %w", err)
+		}
+
+		attestation, err := attestJobResults(account, manifest, job.StartTime, completedAt, pipeline_zen.Version(pipelinePath))
+		if err != nil {
+			return err
 		}
+		if err := resultsUtils.SaveResultAttestation(attestation); err != nil {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"jobId":          jobId,
+			"manifestSHA256": attestation.ManifestSHA256,
+		}).Info("Job results attested")
+	}
+
+	if config.ResultsStore == "" || (attested && !resultsAwaitingUpload(jobId)) {
+		return nil
+	}
+	if err := resultsUtils.PublishJobResults(config.ResultsStore, manifest); err != nil {
+		return fmt.Errorf("failed to publish job results: %w", err)
+	}
+	recordJobStage(types.JobJournalRecord{JobID: jobId},
+		types.JobJournalEvent{Stage: types.JobStageResultsPublished})
+	log.WithFields(logrus.Fields{
+		"jobId":   jobId,
+		"archive": manifest.Archive,
+	}).Info("Job results published")
+	return nil
+}
+
+// resultsAwaitingDelivery reports whether the results of a completed job still have to be
+// delivered: they are not attested yet, or their archive awaits upload to the results store
+func resultsAwaitingDelivery(config types.Configurations, jobId string) bool {
+	if _, err := readAttestedResults(jobId); err != nil {
+		return true
+	}
+	return config.ResultsStore != "" && resultsAwaitingUpload(jobId)
+}
+
+// resultsAwaitingUpload reports whether the results archive of a job is still in its job
+// directory, which PublishJobResults removes once uploaded
+func resultsAwaitingUpload(jobId string) bool {
+	jobDirPath, err := path.PathUtilsInterface.GetJobDirPath(jobId)
+	if err != nil {
+		return false
+	}
+	_, err = path.OSUtilsInterface.Stat(filepath.Join(jobDirPath, resultsArchiveName))
+	return err == nil
+}
+
+// pendingResultPublish is the delivery of the results of a job reported Completed, see
+// deliverJobResults, running in the background or waiting to be retried
+type pendingResultPublish struct {
+	config       types.Configurations
+	account      types.Account
+	job          types.JobExecution
+	creator      common.Address
+	completedAt  time.Time
+	zenPath      string
+	pipelinePath string
+	attempts     int
+	running      bool
+}
+
+// publishJobResults queues the delivery of the results of a job reported Completed and starts
+// it in the background, so that packaging and uploading the results never hold back the
+// on-chain report of the job or the other jobs. A failed delivery is retried by retryResultPublishes.
+func publishJobResults(publish *pendingResultPublish) {
+	jobId := publish.job.JobID.String()
+	stateMutex.Lock()
+	if _, queued := pendingResultPublishes[jobId]; queued {
+		stateMutex.Unlock()
+		return
+	}
+	pendingResultPublishes[jobId] = publish
+	stateMutex.Unlock()
+	startResultPublish(publish)
+}
+
+// retryResultPublishes restarts in the background the deliveries queued by publishJobResults
+// that failed. The job was reported already, so only a published upload is journaled. After
+// core.MaxResultsPublishAttempts failed attempts the delivery is dropped with the journal
+// record of the job, leaving the results in the job directory.
+func retryResultPublishes() {
+	stateMutex.RLock()
+	pending := make([]*pendingResultPublish, 0, len(pendingResultPublishes))
+	for _, publish := range pendingResultPublishes {
+		pending = append(pending, publish)
+	}
+	stateMutex.RUnlock()
+
+	for _, publish := range pending {
+		startResultPublish(publish)
+	}
+}
+
+// startResultPublish runs a queued delivery in the background unless it is running already
+func startResultPublish(publish *pendingResultPublish) {
+	stateMutex.Lock()
+	if publish.running {
+		stateMutex.Unlock()
+		return
+	}
+	publish.running = true
+	stateMutex.Unlock()
+
+	go runResultPublish(publish)
+}
+
+// runResultPublish attempts a queued delivery once, dropping it from the queue when it succeeds
+// or has failed core.MaxResultsPublishAttempts times
+func runResultPublish(publish *pendingResultPublish) {
+	jobId := publish.job.JobID.String()
+	err := deliverJobResults(publish.config, publish.account, publish.job, publish.creator, publish.completedAt, publish.zenPath, publish.pipelinePath)
+
+	stateMutex.Lock()
+	publish.running = false
+	if err != nil {
+		publish.attempts++
+	}
+	giveUp := err != nil && publish.attempts >= core.MaxResultsPublishAttempts
+	if err == nil || giveUp {
+		delete(pendingResultPublishes, jobId)
 	}
-	if len(defaultValue) > 0 {
-		return defaultValue[0]
+	attempts := publish.attempts
+	stateMutex.Unlock()
+
+	logFields := logrus.Fields{
+		"jobId":    jobId,
+		"store":    publish.config.ResultsStore,
+		"attempts": attempts,
+	}
+	switch {
+	case err == nil:
+		log.WithFields(logFields).Debug("Job results delivered")
+	case giveUp:
+		log.WithError(err).WithFields(logFields).
+			Error("Gave up delivering job results, they stay in the job directory and cannot be fetched by the creator")
+		if err := jobJournalUtils.RemoveRecord(jobId); err != nil {
+			log.WithError(err).WithField("jobId", jobId).Warn("Failed to remove job from journal")
+		}
+	default:
+		log.WithError(err).WithFields(logFields).Warn("Failed to deliver job results, retrying in the next Confirm state")
 	}
-	return ""
 }
 
-// cleanJSONString properly formats the JSON string
-func cleanJSONString(input string) string {
-	// Handle the specific case of the unquoted job_config_name
-	input = strings.Replace(input, "job_config_name:", `"job_config_name":`, 1)
+// failJobPipeline concludes a job that failed or stalled. This function:
+// 1. Saves the outcome and its reason to the job's completion.json and journals the failure
+// 2. Marks the job Failed locally and reports it as Failed on chain
+// 3. Stops tracking the job and frees its GPUs
+// Returns error if the report cannot be sent, in which case the job stays tracked as
+// Failed so HandleConfirmState retries it.
+func failJobPipeline(client *ethclient.Client, config types.Configurations, account types.Account, completion types.JobCompletion) error {
+	jobId, _ := new(big.Int).SetString(completion.JobID, 10)
+	completion.DecidedAt = time.Now().UTC()
+	if err := saveJobCompletion(completion); err != nil {
+		log.WithError(err).WithField("jobId", completion.JobID).Warn("Failed to save job outcome")
+	}
+	recordJobStage(types.JobJournalRecord{JobID: completion.JobID},
+		types.JobJournalEvent{Stage: types.JobStagePipelineFailed, Error: string(completion.Outcome) + ": " + completion.Reason})
+	setJobStatus(jobId, types.JobStatusFailed)
+	log.WithFields(logrus.Fields{
+		"jobId":   completion.JobID,
+		"outcome": completion.Outcome,
+		"reason":  completion.Reason,
+	}).Warn("Job concluded unsuccessfully")
 
-	// Remove any whitespace between the opening brace and first key
-	input = strings.TrimSpace(input)
-	if strings.HasPrefix(input, "{") {
-		input = "{" + strings.TrimSpace(input[1:])
+	// Update status to Failed
+	txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusFailed, 0)
+	if err != nil {
+		log.WithError(err).WithField("jobId", completion.JobID).Error("Failed to update job status to failed")
+		return fmt.Errorf("failed to update job status to failed: %w", err)
 	}
+	recordJobStage(types.JobJournalRecord{JobID: completion.JobID, FinalStatus: types.JobStatusFailed},
+		types.JobJournalEvent{Stage: types.JobStageStatusReported, TxHash: txnHash.Hex()})
+	removeJobDataset(completion.JobID)
+	untrackJob(jobId)
+	return nil
+}
 
-	return input
+// failQueuedJob reports a Queued job this node does not start as Failed, saving its outcome to
+// completion.json first so that jobStatus shows the reason. Unlike failJobPipeline the job is
+// neither tracked nor journaled.
+// Returns error if the Failed status cannot be reported, leaving the job Queued to be retried.
+func failQueuedJob(client *ethclient.Client, config types.Configurations, account types.Account, jobId *big.Int, completion types.JobCompletion) error {
+	completion.DecidedAt = time.Now().UTC()
+	if err := saveJobCompletion(completion); err != nil {
+		log.WithError(err).WithField("jobId", completion.JobID).Warn("Failed to save job outcome")
+	}
+	txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusFailed, 0)
+	if err != nil {
+		return fmt.Errorf("failed to update job status to failed: %w", err)
+	}
+	log.WithFields(logrus.Fields{
+		"jobId":   completion.JobID,
+		"outcome": completion.Outcome,
+		"txHash":  txnHash.Hex(),
+	}).Info("Job status updated to Failed without running")
+	return nil
 }
 
 // HandleConfirmState processes job confirmation state transitions by:
-// 1. Validating current job execution state
-// 2. Checking job completion status
-// 3. Managing successful/failed job states
-// 4. Updating on-chain status
-// Returns error if confirmation process fails.
+// 1. Retrying the results deliveries that failed in earlier Confirm states
+// 2. Iterating over every job executing on this node
+// 3. Checking job completion status
+// 4. Managing successful/failed job states
+// 5. Updating on-chain status and freeing the GPUs of concluded jobs
+// Returns error if confirmation of any job fails.
 func (*UtilsStruct) HandleConfirmState(ctx context.Context, client *ethclient.Client, config types.Configurations, account types.Account, epoch uint32, pipelinePath string) error {
 
 	log.WithFields(logrus.Fields{
 		"Current State": "Confirm",
 	}).Info("Executing Confirm State Transition")
 
-	stateMutex.RLock()
-	currentJob := executionState.CurrentJob
-	// isJobRunning := executionState.IsJobRunning
-	stateMutex.RUnlock()
+	retryResultPublishes()
 
-	if currentJob == nil {
+	jobs := getTrackedJobs()
+	if len(jobs) == 0 {
 		log.Debug("No current job found")
 		return nil
 	}
 
-	jobId := currentJob.JobID
-	currentStatus := currentJob.Status
+	var errs []error
+	for _, job := range jobs {
+		if err := confirmJob(client, config, account, job, epoch, pipelinePath); err != nil {
+			log.WithError(err).WithField("jobId", job.JobID.String()).Error("Failed to confirm job")
+			errs = append(errs, fmt.Errorf("job %s: %w", job.JobID.String(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// confirmJob decides the outcome of a single tracked job and reports it once the job
+// has concluded, then stops tracking it. Jobs whose Running transaction has not landed
+// yet are left alone. The outcome is decided from the runner exit, the pipeline-zen
+// markers, results and metrics, the heartbeat of the job and its runtime in epochs, see
+// decideJobOutcome, and saved with its reason to the job's completion.json:
+// 1. A supervised pipeline that stalled or ran too long is terminated, runJobPipeline reports it
+// 2. A completed job is reported Completed, then its results are attested and published
+// to the results store, if configured, in the background. A failed delivery is retried in
+// the following Confirm states
+// 3. A failed or stalled job is reported as Failed
+// A failed report leaves the job to be retried in the next Confirm state.
+func confirmJob(client *ethclient.Client, config types.Configurations, account types.Account, job types.JobExecution, epoch uint32, pipelinePath string) error {
+	jobId := job.JobID
+	currentStatus := job.Status
 
 	log.WithFields(logrus.Fields{
 		"jobId":  jobId.String(),
@@ -329,24 +835,21 @@ func (*UtilsStruct) HandleConfirmState(ctx context.Context, client *ethclient.Cl
 		"epoch":  epoch,
 	}).Debug("Current job state")
 
+	if currentStatus == types.JobStatusQueued {
+		log.WithField("jobId", jobId.String()).Debug("Job is still being started")
+		return nil
+	}
+
 	opts := protoUtils.GetOptions()
 	// Get job details
 	jobDetails, err := jobsManagerUtils.GetJobDetails(client, &opts, jobId)
 	if err != nil {
 		return fmt.Errorf("failed to get job details: %w", err)
 	}
-	resultsPath := ".results/" + jobDetails.Creator.String() + "/" + currentJob.JobID.String()
+	resultsPath := ".results/" + jobDetails.Creator.String() + "/" + jobId.String()
 	zenPath := filepath.Join(pipelinePath, resultsPath)
-	startedFile := filepath.Join(zenPath, ".started")
-	finishedFile := filepath.Join(zenPath, ".finished")
-
-	log.WithFields(logrus.Fields{
-		"zenPath":      zenPath,
-		"startedFile":  startedFile,
-		"finishedFile": finishedFile,
-	}).Debug("Checking job status files")
 
-	// Handle failed status first
+	// Handle failed status first, its outcome was saved when the job failed
 	if currentStatus == types.JobStatusFailed {
 		log.WithField("jobId", jobId.String()).Info("Updating failed job status")
 		txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusFailed, 0)
@@ -354,59 +857,95 @@ func (*UtilsStruct) HandleConfirmState(ctx context.Context, client *ethclient.Cl
 			return fmt.Errorf("failed to update job status to failed: %w", err)
 		}
 		log.WithField("txHash", txnHash.Hex()).Info("Job status updated to Failed")
+		recordJobStage(types.JobJournalRecord{JobID: jobId.String(), FinalStatus: types.JobStatusFailed},
+			types.JobJournalEvent{Stage: types.JobStageStatusReported, TxHash: txnHash.Hex()})
 
 		// Clear job state
-		stateMutex.Lock()
-		executionState.CurrentJob = nil
-		executionState.IsJobRunning = false
-		stateMutex.Unlock()
+		removeJobDataset(jobId.String())
+		untrackJob(jobId)
 
 		return nil
 	}
 
-	// Check if .started file exists
-	startedExists := false
-	if _, err := path.OSUtilsInterface.Stat(startedFile); err == nil {
-		startedExists = true
+	signals := gatherJobCompletionSignals(job, jobDetails.ExecutionEpoch, epoch, zenPath)
+	outcome, reason := decideJobOutcome(signals, time.Now())
+	logFields := logrus.Fields{
+		"jobId":         jobId.String(),
+		"zenPath":       zenPath,
+		"finished":      signals.Finished,
+		"lastHeartbeat": signals.LastHeartbeat,
+		"runningEpochs": signals.RunningEpochs,
 	}
-
-	// Check if .finished file exists
-	finishedExists := false
-	if _, err := path.OSUtilsInterface.Stat(finishedFile); err == nil {
-		finishedExists = true
-	}
-
-	if !startedExists {
-		log.WithField("jobId", jobId.String()).Info("No job is running")
+	log.WithFields(logFields).Debug("Checked job completion signals")
+
+	// A pipeline supervised by this node concludes through its exit status, see runJobPipeline
+	if process := getJobProcess(jobId); currentStatus == types.JobStatusRunning && process != nil {
+		if outcome == types.JobOutcomeFailed || outcome == types.JobOutcomeStalled {
+			log.WithFields(logFields).WithFields(logrus.Fields{"outcome": outcome, "reason": reason}).
+				Warn("Terminating job pipeline")
+			if err := saveJobCompletion(types.JobCompletion{
+				JobID: jobId.String(), Outcome: outcome, Reason: reason, Epoch: epoch, DecidedAt: time.Now().UTC(),
+			}); err != nil {
+				log.WithError(err).WithField("jobId", jobId.String()).Warn("Failed to save job outcome")
+			}
+			process.Terminate()
+			return nil
+		}
+		log.WithField("jobId", jobId.String()).Info("Job pipeline is still running")
 		return nil
 	}
 
-	if startedExists && !finishedExists {
-		log.WithField("jobId", jobId.String()).Info("Job is still running")
+	switch outcome {
+	case "":
+		log.WithFields(logFields).Info("Job is still running")
 		return nil
+	case types.JobOutcomeFailed, types.JobOutcomeStalled:
+		return failJobPipeline(client, config, account, types.JobCompletion{
+			JobID: jobId.String(), Outcome: outcome, Reason: reason, Epoch: epoch,
+		})
 	}
 
-	if startedExists && finishedExists {
-
-		log.WithFields(logrus.Fields{
-			"jobId": jobId.String(),
-		}).Info("Job has concluded successfully")
+	log.WithFields(logFields).Info("Job has concluded successfully")
+	completion := types.JobCompletion{
+		JobID: jobId.String(), Outcome: outcome, Reason: reason, Epoch: epoch, DecidedAt: time.Now().UTC(),
+	}
+	// A retried report keeps the outcome saved when the job was first seen completed
+	if saved, err := readJobCompletion(jobId.String()); err == nil && saved.Outcome == types.JobOutcomeCompleted {
+		completion = saved
+	} else if err := saveJobCompletion(completion); err != nil {
+		log.WithError(err).WithField("jobId", jobId.String()).Warn("Failed to save job outcome")
+	}
+	setJobStatus(jobId, types.JobStatusCompleted)
 
-		log.WithField("jobId", jobId.String()).Info("Updating status")
+	log.WithField("jobId", jobId.String()).Info("Updating status")
 
-		// Job completed, update status
-		txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusCompleted, 0)
-		if err != nil {
-			return fmt.Errorf("failed to update job status to completed: %w", err)
-		}
-		log.WithField("txHash", txnHash.Hex()).Info("Job status updated to Completed")
-
-		// Clear job state
-		stateMutex.Lock()
-		executionState.CurrentJob = nil
-		executionState.IsJobRunning = false
-		stateMutex.Unlock()
+	// Job completed, update status
+	txnHash, err := cmdUtils.UpdateJobStatus(client, config, account, jobId, types.JobStatusCompleted, 0)
+	if err != nil {
+		return fmt.Errorf("failed to update job status to completed: %w", err)
 	}
-
+	log.WithField("txHash", txnHash.Hex()).Info("Job status updated to Completed")
+	recordJobStage(types.JobJournalRecord{JobID: jobId.String(), FinalStatus: types.JobStatusCompleted},
+		types.JobJournalEvent{Stage: types.JobStageStatusReported, TxHash: txnHash.Hex()})
+
+	// Clear job state
+	removeJobDataset(jobId.String())
+	untrackJob(jobId)
+
+	// The job completed when its runner exited, or when its outcome was decided for a
+	// pipeline this node does not supervise
+	completedAt := completion.DecidedAt
+	if signals.Exit != nil {
+		completedAt = signals.Exit.ExitedAt
+	}
+	publishJobResults(&pendingResultPublish{
+		config:       config,
+		account:      account,
+		job:          job,
+		creator:      jobDetails.Creator,
+		completedAt:  completedAt,
+		zenPath:      zenPath,
+		pipelinePath: pipelinePath,
+	})
 	return nil
 }
@@ -2,12 +2,21 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	luminoAccounts "lumino/accounts"
+	accountsMocks "lumino/accounts/mocks"
 	"lumino/cmd/mocks"
+	"lumino/core"
 	"lumino/core/types"
 	"lumino/path"
+	pathMocks "lumino/path/mocks"
+	pipeline_zen "lumino/pipeline-zen"
 	"math/big"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -125,7 +134,11 @@ func TestHandleStateTransition(t *testing.T) {
 // 1. No active jobs scenario
 // 2. Successful job assignment
 // 3. Active job retrieval failures
-// 4. Random assignment validation
+// 4. Random assignment across discovered stakers
+// 5. Staker discovery failures
+// 6. Hardware matching against staker machine specs
+// 7. Rounds that already completed or handled a job in this epoch
+// 8. Pending assignments left by an interrupted round
 // Verifies proper assignment logic and error conditions.
 func TestHandleAssignState(t *testing.T) {
 	var client *ethclient.Client
@@ -135,7 +148,10 @@ func TestHandleAssignState(t *testing.T) {
 
 	tests := []struct {
 		name       string
+		isRandom   bool
+		round      types.AssignmentRound
 		setupMocks func(*mocks.UtilsCmdInterface, *mocks.JobsManagerInterface, *mocks.UtilsInterface)
+		wantRound  map[string]types.AssignmentStatus
 		wantErr    bool
 	}{
 		{
@@ -170,6 +186,128 @@ func TestHandleAssignState(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:     "Randomly Assigns Jobs to Discovered Stakers",
+			isRandom: true,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				cmdMock.On("GetActiveStakers", mock.Anything, uint32(1)).Return([]types.StakerContract{
+					{Id: 2, Address: common.HexToAddress("0x2"), Stake: big.NewInt(1)},
+				}, nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).Return([]*big.Int{big.NewInt(1)}, nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobDetailsInJSON: testJobSpec(0)}, nil)
+				cmdMock.On("AssignJob", mock.Anything, mock.Anything, mock.Anything,
+					common.HexToAddress("0x2").Hex(), big.NewInt(1), uint8(0)).
+					Return(common.Hash{}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Leaves Job Unassigned When No Staker Meets Its Hardware Requirements",
+			isRandom: true,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				cmdMock.On("GetActiveStakers", mock.Anything, uint32(1)).Return([]types.StakerContract{
+					{Id: 2, Address: common.HexToAddress("0x2"), Stake: big.NewInt(1), MachineSpecInJSON: `{"mem":"64.00 GiB"}`},
+				}, nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).Return([]*big.Int{big.NewInt(1)}, nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobDetailsInJSON: testJobSpec(4)}, nil)
+				// AssignJob must not be called
+			},
+			wantErr: false,
+		},
+		{
+			name:  "Skips Round Already Completed This Epoch",
+			round: types.AssignmentRound{Epoch: 1, Completed: true},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+			},
+			wantErr: false,
+		},
+		{
+			name: "Skips Jobs Handled Earlier In The Round",
+			round: types.AssignmentRound{Epoch: 1, Assignments: map[string]types.RoundAssignment{
+				"1": {JobID: "1", Status: types.AssignmentStatusAssigned},
+				"2": {JobID: "2", Status: types.AssignmentStatusFailed},
+			}},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, nil)
+				cmdMock.On("AssignJob", mock.Anything, mock.Anything, mock.Anything,
+					mock.Anything, big.NewInt(3), uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			wantRound: map[string]types.AssignmentStatus{
+				"1": types.AssignmentStatusAssigned,
+				"2": types.AssignmentStatusFailed,
+				"3": types.AssignmentStatusAssigned,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Skips Jobs Assigned Or Running On Chain",
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{Assignee: common.HexToAddress("0x2"), JobDetailsInJSON: testJobSpec(0)}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(2)).
+					Return(uint8(types.JobStatusRunning), nil)
+				cmdMock.On("AssignJob", mock.Anything, mock.Anything, mock.Anything,
+					mock.Anything, big.NewInt(3), uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			wantRound: map[string]types.AssignmentStatus{
+				"3": types.AssignmentStatusAssigned,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Resolves Pending Assignments After A Restart",
+			round: types.AssignmentRound{Epoch: 1, Assignments: map[string]types.RoundAssignment{
+				"1": {JobID: "1", Staker: common.HexToAddress("0x2").Hex(), Status: types.AssignmentStatusPending},
+				"2": {JobID: "2", Staker: common.HexToAddress("0x2").Hex(), Status: types.AssignmentStatusPending},
+			}},
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{Assignee: common.HexToAddress("0x2")}, nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(2)).
+					Return(types.JobContract{}, nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{big.NewInt(1), big.NewInt(2)}, nil)
+				cmdMock.On("AssignJob", mock.Anything, mock.Anything, mock.Anything,
+					mock.Anything, big.NewInt(2), uint8(0)).
+					Return(common.Hash{}, errors.New("execution reverted")).Once()
+			},
+			wantRound: map[string]types.AssignmentStatus{
+				"1": types.AssignmentStatusAssigned,
+				"2": types.AssignmentStatusFailed,
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Skips Random Assignment Without Active Stakers",
+			isRandom: true,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				cmdMock.On("GetActiveStakers", mock.Anything, uint32(1)).Return([]types.StakerContract{}, nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:     "Error Occurs While Discovering Stakers",
+			isRandom: true,
+			setupMocks: func(cmdMock *mocks.UtilsCmdInterface, jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				cmdMock.On("GetActiveStakers", mock.Anything, uint32(1)).Return(nil, errors.New("rpc error"))
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -177,40 +315,128 @@ func TestHandleAssignState(t *testing.T) {
 			cmdMock := new(mocks.UtilsCmdInterface)
 			jobsMock := new(mocks.JobsManagerInterface)
 			utilsMock := new(mocks.UtilsInterface)
+			roundMock := new(mocks.AssignmentRoundInterface)
 
 			// Store original interfaces
 			originalCmdUtils := cmdUtils
 			originalJobsManagerUtils := jobsManagerUtils
 			originalProtoUtils := protoUtils
+			originalAssignmentRoundUtils := assignmentRoundUtils
 			defer func() {
 				cmdUtils = originalCmdUtils
 				jobsManagerUtils = originalJobsManagerUtils
 				protoUtils = originalProtoUtils
+				assignmentRoundUtils = originalAssignmentRoundUtils
 			}()
 
 			cmdUtils = cmdMock
 			jobsManagerUtils = jobsMock
 			protoUtils = utilsMock
+			assignmentRoundUtils = roundMock
 
+			round := tt.round
+			if round.Assignments == nil {
+				round.Assignments = make(map[string]types.RoundAssignment)
+			}
+			var saved types.AssignmentRound
+			roundMock.On("GetRound").Return(round, nil)
+			roundMock.On("SaveRound", mock.Anything).Run(func(args mock.Arguments) {
+				saved = args.Get(0).(types.AssignmentRound)
+			}).Return(nil)
 			tt.setupMocks(cmdMock, jobsMock, utilsMock)
+			// Jobs the case does not describe are unassigned and not started on chain
+			jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+				Return(types.JobContract{JobDetailsInJSON: testJobSpec(0)}, nil).Maybe()
+			jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, mock.Anything).
+				Return(uint8(types.JobStatusNew), nil).Maybe()
 
 			utils := &UtilsStruct{}
-			err := utils.HandleAssignState(ctx, client, config, account, 1, false)
+			err := utils.HandleAssignState(ctx, client, config, account, 1, tt.isRandom)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			if tt.wantRound != nil {
+				assert.True(t, saved.Completed)
+				got := make(map[string]types.AssignmentStatus)
+				for jobId, assignment := range saved.Assignments {
+					got[jobId] = assignment.Status
+				}
+				assert.Equal(t, tt.wantRound, got)
+				cmdMock.AssertExpectations(t)
+			}
 		})
 	}
 }
 
+// Tests that a job assigned in one epoch is not assigned again in the next epoch, when the
+// round of the new epoch no longer records it but the chain has its assignee
+func TestHandleAssignStateAcrossEpochs(t *testing.T) {
+	var client *ethclient.Client
+	var config types.Configurations
+	account := types.Account{Address: common.HexToAddress("0x2").Hex()}
+
+	cmdMock := new(mocks.UtilsCmdInterface)
+	jobsMock := new(mocks.JobsManagerInterface)
+	utilsMock := new(mocks.UtilsInterface)
+	roundMock := new(mocks.AssignmentRoundInterface)
+
+	originalCmdUtils := cmdUtils
+	originalJobsManagerUtils := jobsManagerUtils
+	originalProtoUtils := protoUtils
+	originalAssignmentRoundUtils := assignmentRoundUtils
+	defer func() {
+		cmdUtils = originalCmdUtils
+		jobsManagerUtils = originalJobsManagerUtils
+		protoUtils = originalProtoUtils
+		assignmentRoundUtils = originalAssignmentRoundUtils
+	}()
+	cmdUtils = cmdMock
+	jobsManagerUtils = jobsMock
+	protoUtils = utilsMock
+	assignmentRoundUtils = roundMock
+
+	// The round and the assignee on chain persist across epochs
+	var round types.AssignmentRound
+	var assignee common.Address
+	roundMock.On("GetRound").Return(func() (types.AssignmentRound, error) { return round, nil })
+	roundMock.On("SaveRound", mock.Anything).Run(func(args mock.Arguments) {
+		round = args.Get(0).(types.AssignmentRound)
+	}).Return(nil)
+	utilsMock.On("GetOptions").Return(bind.CallOpts{})
+	jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).Return([]*big.Int{big.NewInt(1)}, nil)
+	jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+		Return(func(*ethclient.Client, *bind.CallOpts, *big.Int) (types.JobContract, error) {
+			return types.JobContract{Assignee: assignee, JobDetailsInJSON: testJobSpec(0)}, nil
+		})
+	jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).Return(uint8(types.JobStatusNew), nil)
+	cmdMock.On("AssignJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), uint8(0)).
+		Run(func(args mock.Arguments) {
+			assignee = common.HexToAddress(args.String(3))
+		}).Return(common.Hash{}, nil).Once()
+
+	utils := &UtilsStruct{}
+	for _, epoch := range []uint32{1, 2} {
+		assert.NoError(t, utils.HandleAssignState(context.Background(), client, config, account, epoch, false))
+		assert.Equal(t, epoch, round.Epoch)
+		assert.True(t, round.Completed)
+	}
+	// The round of epoch 2 skipped the job assigned in epoch 1
+	assert.Empty(t, round.Assignments)
+	cmdMock.AssertNumberOfCalls(t, "AssignJob", 1)
+}
+
 // Tests job state updates covering:
 // 1. No assigned job scenario
 // 2. Already running job cases
-// 3. Successful state transitions
-// 4. Job status update failures
+// 3. Jobs waiting for free GPU slots
+// 4. Jobs that can never fit on the node
+// 5. Successful state transitions
+// 6. Job status update failures
+// Jobs left Queued, waiting for GPU slots, refused or whose Running transaction failed, are
+// kept awaiting start so that the Update handler runs again.
 // Validates state transition handling and error cases.
 func TestHandleUpdateState(t *testing.T) {
 	var client *ethclient.Client
@@ -218,9 +444,12 @@ func TestHandleUpdateState(t *testing.T) {
 	var account types.Account
 
 	tests := []struct {
-		name       string
-		setupMocks func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{}
-		wantErr    bool
+		name         string
+		setupMocks   func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{}
+		preflightErr error
+		failRefused  bool
+		wantErr      bool
+		wantAwaiting bool // job 1 is left Queued for HandleUpdateState to run again
 	}{
 		{
 			name: "when no job assigned is assigned to the node",
@@ -228,6 +457,8 @@ func TestHandleUpdateState(t *testing.T) {
 				utilsMock.On("GetOptions").Return(bind.CallOpts{})
 				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
 					Return(big.NewInt(0), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
 				return nil
 			},
 			wantErr: false,
@@ -238,17 +469,137 @@ func TestHandleUpdateState(t *testing.T) {
 				utilsMock.On("GetOptions").Return(bind.CallOpts{})
 				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
 					Return(big.NewInt(1), nil)
-				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, mock.Anything).
-					Return(uint8(types.JobStatusRunning), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{big.NewInt(1)}, nil)
 
-				// Set executionState
-				stateMutex.Lock()
-				executionState.IsJobRunning = true
-				stateMutex.Unlock()
+				// Track the job as executing on this node
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning})
 				return nil
 			},
 			wantErr: false,
 		},
+		{
+			name: "when not enough GPUs are free the job stays queued",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobId: big.NewInt(1), JobDetailsInJSON: testJobSpec(2)}, nil)
+
+				// Another job holds one of the two GPUs
+				gpuAllocator = NewGPUAllocator(2, 2)
+				_, ok := gpuAllocator.Allocate("9", 1)
+				assert.True(t, ok)
+				return nil
+			},
+			wantErr:      false,
+			wantAwaiting: true,
+		},
+		{
+			name: "when the job requests more GPUs than the node has the job is refused and stays queued",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobId: big.NewInt(1), JobDetailsInJSON: testJobSpec(4)}, nil)
+
+				// Neither the config is written nor the status updated
+				gpuAllocator = NewGPUAllocator(2, 2)
+				return nil
+			},
+			wantErr:      false,
+			wantAwaiting: true,
+		},
+		{
+			name: "when the pipeline environment is missing the job is refused and stays queued",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobId: big.NewInt(1), JobDetailsInJSON: testJobSpec(1)}, nil)
+				return nil
+			},
+			preflightErr: errors.New("python interpreter python3 not found"),
+			wantErr:      false,
+			wantAwaiting: true,
+		},
+		{
+			name: "when failRefusedJobs is set a refused job is reported Failed",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				done := make(chan struct{})
+
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobId: big.NewInt(1), JobDetailsInJSON: testJobSpec(1)}, nil)
+
+				// The job is never set to Running
+				cmdMock.On("UpdateJobStatus",
+					mock.AnythingOfType("*ethclient.Client"),
+					mock.AnythingOfType("types.Configurations"),
+					mock.AnythingOfType("types.Account"),
+					big.NewInt(1),
+					types.JobStatusFailed,
+					uint8(0),
+				).Run(func(args mock.Arguments) {
+					close(done)
+				}).Return(common.Hash{}, nil)
+
+				return done
+			},
+			preflightErr: errors.New("python interpreter python3 not found"),
+			failRefused:  true,
+			wantErr:      false,
+		},
+		{
+			name: "when the job spec is malformed the job is rejected",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, big.NewInt(1)).
+					Return(types.JobContract{JobId: big.NewInt(1), JobDetailsInJSON: `{job_config_name: "test", "num_gpus": "1"}`}, nil)
+
+				// The job is reported Failed without writing its config or running it
+				done := make(chan struct{})
+				cmdMock.On("UpdateJobStatus",
+					mock.AnythingOfType("*ethclient.Client"),
+					mock.AnythingOfType("types.Configurations"),
+					mock.AnythingOfType("types.Account"),
+					big.NewInt(1),
+					types.JobStatusFailed,
+					uint8(0),
+				).Run(func(args mock.Arguments) {
+					close(done)
+				}).Return(common.Hash{}, nil)
+				return done
+			},
+			wantErr: false,
+		},
 		{
 			name: "when a job is executed successfully",
 			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
@@ -258,6 +609,8 @@ func TestHandleUpdateState(t *testing.T) {
 				utilsMock.On("GetOptions").Return(bind.CallOpts{})
 				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
 					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
 				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, mock.Anything).
 					Return(uint8(types.JobStatusQueued), nil)
 
@@ -266,25 +619,23 @@ func TestHandleUpdateState(t *testing.T) {
 					JobId:   big.NewInt(1),
 					Creator: common.HexToAddress("0x123"),
 					JobDetailsInJSON: `{
+						"schema_version": 1,
 						"job_config_name": "test",
-						"dataset_id": "test_dataset",
-						"batch_size": "32",
-						"shuffle": "true",
-						"num_epochs": "1",
-						"use_lora": "true",
-						"use_qlora": "false",
-						"lr": "1e-2",
+						"dataset_id": "gs://bucket/test_dataset",
+						"batch_size": 32,
+						"shuffle": true,
+						"num_epochs": 1,
+						"use_lora": true,
+						"use_qlora": false,
+						"lr": 1e-2,
 						"override_env": "prod",
-						"seed": "42",
-						"num_gpus": "1"
+						"seed": 42,
+						"num_gpus": 1
 					}`,
 				}
 				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
 					Return(jobContract, nil)
 
-				// Mock directory creation
-				osMock.On("MkdirAll", ".jobs/1", os.FileMode(0755)).Return(nil)
-
 				// Mock file writing
 				osMock.On("WriteFile",
 					mock.AnythingOfType("string"),
@@ -317,6 +668,74 @@ func TestHandleUpdateState(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "when the Running transaction fails the job is left for the next update",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				done := make(chan struct{})
+
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, mock.Anything).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{JobId: big.NewInt(1), Creator: common.HexToAddress("0x123"), JobDetailsInJSON: testJobSpec(1)}, nil)
+				osMock.On("WriteFile", mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8"), os.FileMode(0644)).Return(nil)
+
+				cmdMock.On("UpdateJobStatus",
+					mock.AnythingOfType("*ethclient.Client"),
+					mock.AnythingOfType("types.Configurations"),
+					mock.AnythingOfType("types.Account"),
+					big.NewInt(1),
+					types.JobStatusRunning,
+					uint8(0),
+				).Run(func(args mock.Arguments) {
+					close(done)
+				}).Return(common.Hash{}, errors.New("nonce too low"))
+
+				return done
+			},
+			wantErr:      false,
+			wantAwaiting: true,
+		},
+		{
+			name: "when the dataset cannot be fetched the job fails before it runs",
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface, osMock *mocks.OSInterface) chan struct{} {
+				done := make(chan struct{})
+
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobForStaker", mock.Anything, mock.Anything, mock.Anything).
+					Return(big.NewInt(1), nil)
+				jobsMock.On("GetActiveJobs", mock.Anything, mock.Anything).
+					Return([]*big.Int{}, nil)
+				jobsMock.On("GetJobStatus", mock.Anything, mock.Anything, mock.Anything).
+					Return(uint8(types.JobStatusQueued), nil)
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{
+						JobId:            big.NewInt(1),
+						Creator:          common.HexToAddress("0x123"),
+						JobDetailsInJSON: `{"schema_version": 1, "job_config_name": "test", "dataset_id": "file:///nonexistent/text2sql.jsonl", "batch_size": 32, "num_epochs": 1, "num_gpus": 1}`,
+					}, nil)
+				osMock.On("WriteFile", mock.AnythingOfType("string"), mock.AnythingOfType("[]uint8"), os.FileMode(0644)).Return(nil)
+
+				// The job is never set to Running
+				cmdMock.On("UpdateJobStatus",
+					mock.AnythingOfType("*ethclient.Client"),
+					mock.AnythingOfType("types.Configurations"),
+					mock.AnythingOfType("types.Account"),
+					big.NewInt(1),
+					types.JobStatusFailed,
+					uint8(0),
+				).Run(func(args mock.Arguments) {
+					close(done)
+				}).Return(common.Hash{}, nil)
+
+				return done
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -324,29 +743,60 @@ func TestHandleUpdateState(t *testing.T) {
 			// Reset execution state before each test
 			stateMutex.Lock()
 			executionState = types.JobExecutionState{}
+			jobsAwaitingStart = make(map[string]bool)
 			stateMutex.Unlock()
+			datasetCache = nil
+			defer func() { datasetCache = nil }()
+
+			originalGPUAllocator := gpuAllocator
+			gpuAllocator = NewGPUAllocator(1, 1)
+			defer func() { gpuAllocator = originalGPUAllocator }()
+			failRefusedJobs = tt.failRefused
+			defer func() { failRefusedJobs = false }()
 
 			jobsMock := new(mocks.JobsManagerInterface)
 			utilsMock := new(mocks.UtilsInterface)
 			cmdMock := new(mocks.UtilsCmdInterface)
 			osMock := new(mocks.OSInterface)
+			journalMock := new(mocks.JobJournalInterface)
 
 			// Store original interfaces and restore after test
 			originalJobsManagerUtils := jobsManagerUtils
 			originalProtoUtils := protoUtils
 			originalCmdUtils := cmdUtils
 			originalPathOsUtils := path.OSUtilsInterface
+			originalPathUtils := path.PathUtilsInterface
+			originalJobJournalUtils := jobJournalUtils
+			originalPreflightUtils := preflightUtils
 			defer func() {
 				jobsManagerUtils = originalJobsManagerUtils
 				protoUtils = originalProtoUtils
 				cmdUtils = originalCmdUtils
 				path.OSUtilsInterface = originalPathOsUtils
+				path.PathUtilsInterface = originalPathUtils
+				jobJournalUtils = originalJobJournalUtils
+				preflightUtils = originalPreflightUtils
 			}()
 
+			pathMock := new(pathMocks.PathInterface)
 			jobsManagerUtils = jobsMock
 			protoUtils = utilsMock
 			cmdUtils = cmdMock
 			path.OSUtilsInterface = osMock
+			path.PathUtilsInterface = pathMock
+			jobJournalUtils = journalMock
+			preflightMock := new(mocks.PreflightInterface)
+			preflightUtils = preflightMock
+
+			journalMock.On("RecordStage", mock.Anything, mock.Anything).Return(nil).Maybe()
+			pathMock.On("GetJobDirPath", mock.Anything).Return(t.TempDir(), nil).Maybe()
+			pathMock.On("GetDefaultPath").Return(t.TempDir(), nil).Maybe()
+			preflightMock.On("GetFreeDiskSpace", mock.Anything).Return(uint64(1)<<40, nil).Maybe()
+			preflightMock.On("GetMemory").Return(uint64(64)<<30, uint64(32)<<30, nil).Maybe()
+			preflightMock.On("CheckPipelineEnvironment", mock.Anything, "/path/to/pipeline").Return(tt.preflightErr).Maybe()
+			pathMock.On("GetDatasetCacheDirPath").Return(t.TempDir(), nil).Maybe()
+			osMock.On("ReadFile", mock.Anything).Return(nil, os.ErrNotExist).Maybe()
+			osMock.On("RemoveAll", mock.Anything).Return(nil).Maybe()
 
 			// Set up mocks and get coordination channel
 			done := tt.setupMocks(jobsMock, utilsMock, cmdMock, osMock)
@@ -376,6 +826,8 @@ func TestHandleUpdateState(t *testing.T) {
 					case <-time.After(time.Second):
 						t.Fatal("Timeout waiting for main function completion")
 					}
+					// Wait for the failed job to release its GPU slots
+					assert.Eventually(t, func() bool { return len(getTrackedJobs()) == 0 }, time.Second, 10*time.Millisecond)
 				case <-ctx.Done():
 					t.Fatal("Test timed out")
 				}
@@ -399,6 +851,7 @@ func TestHandleUpdateState(t *testing.T) {
 			utilsMock.AssertExpectations(t)
 			cmdMock.AssertExpectations(t)
 			osMock.AssertExpectations(t)
+			assert.Eventually(t, func() bool { return hasJobsAwaitingStart() == tt.wantAwaiting }, time.Second, 10*time.Millisecond)
 		})
 	}
 }
@@ -408,24 +861,40 @@ func TestHandleUpdateState(t *testing.T) {
 // 2. Failed job status
 // 3. Successful completion cases
 // 4. State update error handling
+// 5. Jobs still being started
+// 6. Jobs whose supervised pipeline exited cleanly or is still running
+// 7. Results attested and published in the background once Completed is reported, queued
+// for a retry when they cannot be attested or published
+// 8. Completed, Failed and Stalled outcomes decided from the results, failure markers, runner
+// exit, heartbeat and runtime, and saved with their reason
 // Verifies proper state management and updates.
 func TestHandleConfirmState(t *testing.T) {
 	var client *ethclient.Client
-	var config types.Configurations
 	var account types.Account
+	finishedResults := map[string]string{".started": "", ".finished": "", "metrics.json": `{"loss": 0.42}`}
 	// ctx := context.Background()
 
 	tests := []struct {
-		name       string
-		setupState func()
-		setupMocks func(*mocks.JobsManagerInterface, *mocks.UtilsInterface, *mocks.UtilsCmdInterface)
-		wantErr    bool
+		name         string
+		setupState   func()
+		setupMocks   func(*mocks.JobsManagerInterface, *mocks.UtilsInterface, *mocks.UtilsCmdInterface)
+		setupResults func(*mocks.ResultsInterface)
+		signErr      error
+		config       types.Configurations
+		results      map[string]string // files pipeline-zen wrote to the results directory of job 1
+		exit         *types.JobRunnerExit
+		delivered    *types.ResultManifest // manifest attested and archived by an earlier delivery of job 1
+		epoch        uint32
+		wantErr      bool
+		wantTracked  int
+		wantPending  bool             // whether the results delivery of job 1 is queued for a retry
+		wantOutcome  types.JobOutcome // outcome saved to completion.json, empty if none
 	}{
 		{
 			name: "No Current Job Found in Confirmation State",
 			setupState: func() {
 				stateMutex.Lock()
-				executionState.CurrentJob = nil
+				executionState = types.JobExecutionState{}
 				stateMutex.Unlock()
 			},
 			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
@@ -437,11 +906,12 @@ func TestHandleConfirmState(t *testing.T) {
 			name: "Handles Job with Failed Status in Confirmation State",
 			setupState: func() {
 				stateMutex.Lock()
-				executionState.CurrentJob = &types.JobExecution{
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
 					JobID:  big.NewInt(1),
 					Status: types.JobStatusFailed,
-				}
-				stateMutex.Unlock()
+				})
 			},
 			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
 				jobDetails := types.JobContract{
@@ -467,12 +937,13 @@ func TestHandleConfirmState(t *testing.T) {
 			name: "Successfully Handles Job Completion in Confirm State",
 			setupState: func() {
 				stateMutex.Lock()
-				executionState.CurrentJob = &types.JobExecution{
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
 					JobID:     big.NewInt(1),
 					Status:    types.JobStatusRunning,
 					StartTime: time.Now(),
-				}
-				stateMutex.Unlock()
+				})
 			},
 			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
 				jobDetails := types.JobContract{
@@ -484,24 +955,365 @@ func TestHandleConfirmState(t *testing.T) {
 				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, types.JobStatusCompleted, uint8(0)).
 					Return(common.Hash{}, nil)
 			},
-			wantErr: false,
+			results:     finishedResults,
+			wantErr:     false,
+			wantOutcome: types.JobOutcomeCompleted,
 		},
 		{
-			name: "Error Occurs While Fetching Job Details in Confirmation State",
+			name: "Publishes Results After Reporting Completed",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:  big.NewInt(1),
+					Status: types.JobStatusCompleted,
+				})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusCompleted, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			setupResults: func(resultsMock *mocks.ResultsInterface) {
+				manifest := types.ResultManifest{JobID: "1", Creator: common.HexToAddress("0x123").String(), Archive: "0x123/1/results.tar.gz"}
+				resultsMock.On("PackageJobResults", mock.MatchedBy(func(manifest types.ResultManifest) bool {
+					// The results are packaged once the job is reported and no longer tracked
+					return manifest.JobID == "1" && manifest.Creator == common.HexToAddress("0x123").String() && len(getTrackedJobs()) == 0
+				}), mock.Anything, true).Return(manifest, nil).Once()
+				resultsMock.On("SaveResultAttestation", mock.MatchedBy(func(attestation types.ResultAttestation) bool {
+					return attestation.JobID == "1" && attestation.Signature != ""
+				})).Return(nil).Once()
+				resultsMock.On("PublishJobResults", "/var/lumino/results", manifest).Return(nil).Once()
+			},
+			config:      types.Configurations{ResultsStore: "/var/lumino/results"},
+			results:     finishedResults,
+			wantErr:     false,
+			wantTracked: 0,
+			wantOutcome: types.JobOutcomeCompleted,
+		},
+		{
+			name: "Reports Completed When Results Cannot Be Published",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:  big.NewInt(1),
+					Status: types.JobStatusCompleted,
+				})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusCompleted, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			setupResults: func(resultsMock *mocks.ResultsInterface) {
+				resultsMock.On("PackageJobResults", mock.Anything, mock.Anything, true).Return(types.ResultManifest{JobID: "1"}, nil)
+				resultsMock.On("SaveResultAttestation", mock.Anything).Return(nil)
+				resultsMock.On("PublishJobResults", mock.Anything, mock.Anything).Return(errors.New("403 Forbidden")).Once()
+			},
+			config:      types.Configurations{ResultsStore: "s3://results"},
+			results:     finishedResults,
+			wantErr:     false,
+			wantTracked: 0,
+			wantPending: true,
+			wantOutcome: types.JobOutcomeCompleted,
+		},
+		{
+			name: "Attests The Runner Exit Time As Completion Time",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusCompleted})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusCompleted, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			setupResults: func(resultsMock *mocks.ResultsInterface) {
+				resultsMock.On("PackageJobResults", mock.Anything, mock.Anything, false).
+					Return(types.ResultManifest{JobID: "1", CreatedAt: time.Now().UTC()}, nil).Once()
+				resultsMock.On("SaveResultAttestation", mock.MatchedBy(func(attestation types.ResultAttestation) bool {
+					return attestation.CompletedAt.Equal(time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC))
+				})).Return(nil).Once()
+			},
+			results:     finishedResults,
+			exit:        &types.JobRunnerExit{ExitedAt: time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC)},
+			wantOutcome: types.JobOutcomeCompleted,
+		},
+		{
+			name: "Reuses Results Attested By An Earlier Delivery",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusCompleted})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusCompleted, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			setupResults: func(resultsMock *mocks.ResultsInterface) {
+				// The results are neither packaged nor signed again, only the pending upload is sent
+				resultsMock.On("PublishJobResults", "s3://results", mock.MatchedBy(func(manifest types.ResultManifest) bool {
+					return manifest.JobID == "1" && manifest.Archive == "0x123/1/results.tar.gz"
+				})).Return(nil).Once()
+			},
+			config:      types.Configurations{ResultsStore: "s3://results"},
+			results:     finishedResults,
+			delivered:   &types.ResultManifest{JobID: "1", Archive: "0x123/1/results.tar.gz", CreatedAt: time.Date(2026, 10, 1, 14, 5, 0, 0, time.UTC)},
+			wantOutcome: types.JobOutcomeCompleted,
+		},
+		{
+			name: "Reports Completed When Results Cannot Be Attested",
 			setupState: func() {
 				stateMutex.Lock()
-				executionState.CurrentJob = &types.JobExecution{
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:  big.NewInt(1),
+					Status: types.JobStatusCompleted,
+				})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusCompleted, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			setupResults: func(resultsMock *mocks.ResultsInterface) {
+				resultsMock.On("PackageJobResults", mock.Anything, mock.Anything, false).Return(types.ResultManifest{JobID: "1"}, nil)
+				// Nothing is saved or published without a signature
+			},
+			signErr:     errors.New("no keystore file found"),
+			results:     finishedResults,
+			wantErr:     false,
+			wantTracked: 0,
+			wantPending: true,
+			wantOutcome: types.JobOutcomeCompleted,
+		},
+		{
+			name: "Reports Completed For Cleanly Exited Pipeline",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:  big.NewInt(1),
+					Status: types.JobStatusCompleted,
+				})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusCompleted, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			results:     map[string]string{"model.bin": "weights"},
+			wantErr:     false,
+			wantTracked: 0,
+			wantOutcome: types.JobOutcomeCompleted,
+		},
+		{
+			name: "Fails Finished Pipeline Without Results",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusFailed, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			results:     map[string]string{".started": "", ".finished": ""},
+			wantOutcome: types.JobOutcomeFailed,
+		},
+		{
+			name: "Fails Pipeline That Wrote A Failure Marker",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning, StartTime: time.Now()})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusFailed, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			results:     map[string]string{".started": "", ".failed": "CUDA out of memory\n"},
+			wantOutcome: types.JobOutcomeFailed,
+		},
+		{
+			name: "Fails Re-attached Job Whose Runner Exited Non-Zero",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning, StartTime: time.Now()})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusFailed, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			results:     map[string]string{".started": ""},
+			exit:        &types.JobRunnerExit{ExitCode: 1, Reason: "exited with code 1"},
+			wantOutcome: types.JobOutcomeFailed,
+		},
+		{
+			name: "Reports Stalled Job Without Heartbeat As Failed",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning, StartTime: time.Now().Add(-2 * time.Hour)})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusFailed, uint8(0)).
+					Return(common.Hash{}, nil).Once()
+			},
+			wantOutcome: types.JobOutcomeStalled,
+		},
+		{
+			name: "Keeps Failed Job Tracked When The Report Fails",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusRunning, StartTime: time.Now()})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				// The job ran past the maximum runtime
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123"), ExecutionEpoch: 1}, nil)
+				cmdMock.On("UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, big.NewInt(1), types.JobStatusFailed, uint8(0)).
+					Return(common.Hash{}, errors.New("nonce too low")).Once()
+			},
+			epoch:       2 + core.MaxJobRuntimeEpochs,
+			wantErr:     true,
+			wantTracked: 1,
+			wantOutcome: types.JobOutcomeFailed,
+		},
+		{
+			name: "Waits For Supervised Pipeline Still Running",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
 					JobID:  big.NewInt(1),
 					Status: types.JobStatusRunning,
+				})
+				// A process that never exits on its own stands in for the training run
+				ctx, cancel := context.WithCancel(context.Background())
+				process, err := pipeline_zen.StartProcess(ctx, "sleep", []string{"60"}, pipeline_zen.ProcessOptions{})
+				if err != nil {
+					panic(err)
+				}
+				setJobProcess(big.NewInt(1), process)
+				go func() {
+					<-time.After(time.Second)
+					cancel()
+				}()
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				// UpdateJobStatus must not be called
+			},
+			wantErr:     false,
+			wantTracked: 1,
+		},
+		{
+			name: "Terminates Supervised Pipeline Without Heartbeat",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:     big.NewInt(1),
+					Status:    types.JobStatusRunning,
+					StartTime: time.Now().Add(-2 * time.Hour),
+				})
+				process, err := pipeline_zen.StartProcess(context.Background(), "sleep", []string{"60"}, pipeline_zen.ProcessOptions{})
+				if err != nil {
+					panic(err)
 				}
+				setJobProcess(big.NewInt(1), process)
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				utilsMock.On("GetOptions").Return(bind.CallOpts{})
+				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
+					Return(types.JobContract{Creator: common.HexToAddress("0x123")}, nil)
+				// The pipeline is reported by runJobPipeline once it exits
+			},
+			wantErr:     false,
+			wantTracked: 1,
+			wantOutcome: types.JobOutcomeStalled,
+		},
+		{
+			name: "Error Occurs While Fetching Job Details in Confirmation State",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
 				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:  big.NewInt(1),
+					Status: types.JobStatusRunning,
+				})
 			},
 			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
 				utilsMock.On("GetOptions").Return(bind.CallOpts{})
 				jobsMock.On("GetJobDetails", mock.Anything, mock.Anything, mock.Anything).
 					Return(types.JobContract{}, errors.New("failed to get job details"))
 			},
-			wantErr: true,
+			wantErr:     true,
+			wantTracked: 1,
+		},
+		{
+			name: "Skips Job Whose Running Transaction Is Pending",
+			setupState: func() {
+				stateMutex.Lock()
+				executionState = types.JobExecutionState{}
+				stateMutex.Unlock()
+				trackJob(&types.JobExecution{
+					JobID:  big.NewInt(2),
+					Status: types.JobStatusQueued,
+				})
+			},
+			setupMocks: func(jobsMock *mocks.JobsManagerInterface, utilsMock *mocks.UtilsInterface, cmdMock *mocks.UtilsCmdInterface) {
+				// No mocks needed as the job is skipped
+			},
+			wantErr:     false,
+			wantTracked: 1,
 		},
 	}
 
@@ -510,26 +1322,226 @@ func TestHandleConfirmState(t *testing.T) {
 			jobsMock := new(mocks.JobsManagerInterface)
 			utilsMock := new(mocks.UtilsInterface)
 			cmdMock := new(mocks.UtilsCmdInterface)
+			journalMock := new(mocks.JobJournalInterface)
+			resultsMock := new(mocks.ResultsInterface)
+			accountsMock := new(accountsMocks.AccountInterface)
+			pathMock := new(pathMocks.PathInterface)
 
 			if tt.setupState != nil {
 				tt.setupState()
 			}
 
+			originalOSUtils := path.OSUtilsInterface
+			originalPathUtils := path.PathUtilsInterface
+			originalAccountUtils := luminoAccounts.AccountUtilsInterface
+			defer func() {
+				path.OSUtilsInterface = originalOSUtils
+				path.PathUtilsInterface = originalPathUtils
+				luminoAccounts.AccountUtilsInterface = originalAccountUtils
+			}()
+			path.PathUtilsInterface = pathMock
+			luminoAccounts.AccountUtilsInterface = accountsMock
+			defaultPath := t.TempDir()
+			jobDir := t.TempDir()
+			pathMock.On("GetDefaultPath").Return(defaultPath, nil).Maybe()
+			pathMock.On("GetJobDirPath", "1").Return(jobDir, nil).Maybe()
+			if tt.exit != nil {
+				assert.NoError(t, saveJobRunnerExit(jobDir, *tt.exit))
+			}
+			if tt.delivered != nil {
+				writeAttestedResults(t, jobDir, *tt.delivered)
+			}
+
 			jobsManagerUtils = jobsMock
 			protoUtils = utilsMock
 			cmdUtils = cmdMock
+			jobJournalUtils = journalMock
+			resultsUtils = resultsMock
+			path.OSUtilsInterface = path.OSUtils{}
+			stateMutex.Lock()
+			pendingResultPublishes = make(map[string]*pendingResultPublish)
+			stateMutex.Unlock()
+
+			journalMock.On("RecordStage", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 			if tt.setupMocks != nil {
 				tt.setupMocks(jobsMock, utilsMock, cmdMock)
 			}
+			if tt.setupResults != nil {
+				tt.setupResults(resultsMock)
+			} else {
+				resultsMock.On("PackageJobResults", mock.Anything, mock.Anything, false).
+					Return(func(manifest types.ResultManifest, resultsDir string, withArchive bool) (types.ResultManifest, error) {
+						return manifest, nil
+					}).Maybe()
+				resultsMock.On("SaveResultAttestation", mock.Anything).Return(nil).Maybe()
+			}
+			accountsMock.On("SignData", mock.Anything, account, filepath.Join(defaultPath, "keystore_files")).
+				Return(make([]byte, 65), tt.signErr).Maybe()
+
+			pipelinePath := t.TempDir()
+			resultsPath := filepath.Join(pipelinePath, ".results", common.HexToAddress("0x123").String(), "1")
+			for name, content := range tt.results {
+				assert.NoError(t, os.MkdirAll(resultsPath, 0755))
+				assert.NoError(t, os.WriteFile(filepath.Join(resultsPath, name), []byte(content), 0644))
+			}
+			epoch := tt.epoch
+			if epoch == 0 {
+				epoch = 1
+			}
 
 			utils := &UtilsStruct{}
-			err := utils.HandleConfirmState(context.Background(), client, config, account, 1, "/path/to/pipeline")
+			err := utils.HandleConfirmState(context.Background(), client, tt.config, account, epoch, pipelinePath)
 			if tt.wantErr {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
 			}
+			assert.Eventually(t, resultPublishesIdle, time.Second, 10*time.Millisecond)
+			cmdMock.AssertExpectations(t)
+			resultsMock.AssertExpectations(t)
+			assert.Len(t, getTrackedJobs(), tt.wantTracked)
+			stateMutex.RLock()
+			_, pending := pendingResultPublishes["1"]
+			stateMutex.RUnlock()
+			assert.Equal(t, tt.wantPending, pending)
+			if process := getJobProcess(big.NewInt(1)); process != nil {
+				if tt.wantOutcome != "" {
+					select {
+					case <-process.Done():
+					case <-time.After(5 * time.Second):
+						t.Error("stalled pipeline was not terminated")
+					}
+				}
+				removeJobProcess(big.NewInt(1))
+			}
+			completion, err := readJobCompletion("1")
+			if tt.wantOutcome == "" {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+			} else if assert.NoError(t, err) {
+				assert.Equal(t, tt.wantOutcome, completion.Outcome)
+				assert.NotEmpty(t, completion.Reason)
+			}
+
+			stateMutex.Lock()
+			jobProcesses = make(map[string]pipeline_zen.Execution)
+			stateMutex.Unlock()
 		})
 	}
 }
+
+// Tests retrying the results deliveries of reported jobs covering:
+// 1. A failed upload is queued and retried until it succeeds, which is journaled
+// 2. A delivery is dropped with the journal record of the job after MaxResultsPublishAttempts failed attempts
+func TestRetryResultPublishes(t *testing.T) {
+	originalResultsUtils := resultsUtils
+	originalJobJournalUtils := jobJournalUtils
+	originalPathUtils := path.PathUtilsInterface
+	originalOSUtils := path.OSUtilsInterface
+	originalMaxAttempts := core.MaxResultsPublishAttempts
+	defer func() {
+		resultsUtils = originalResultsUtils
+		jobJournalUtils = originalJobJournalUtils
+		path.PathUtilsInterface = originalPathUtils
+		path.OSUtilsInterface = originalOSUtils
+		core.MaxResultsPublishAttempts = originalMaxAttempts
+		stateMutex.Lock()
+		pendingResultPublishes = make(map[string]*pendingResultPublish)
+		stateMutex.Unlock()
+	}()
+	core.MaxResultsPublishAttempts = 3
+	path.OSUtilsInterface = path.OSUtils{}
+	manifest := types.ResultManifest{JobID: "1", Archive: "0x123/1/results.tar.gz", CreatedAt: time.Date(2026, 10, 1, 14, 5, 0, 0, time.UTC)}
+	uploadErr := errors.New("503 Service Unavailable")
+
+	tests := []struct {
+		name        string
+		results     []error // results of the upload attempts in order
+		wantPending []bool  // whether the delivery is queued after the first attempt and each retry
+		wantRemoved bool    // whether the journal record of the job is removed
+	}{
+		{
+			name:        "retried until published",
+			results:     []error{uploadErr, uploadErr, nil},
+			wantPending: []bool{true, true, false},
+		},
+		{
+			name:        "dropped after the last attempt",
+			results:     []error{uploadErr, uploadErr, uploadErr},
+			wantPending: []bool{true, true, false},
+			wantRemoved: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultsMock := new(mocks.ResultsInterface)
+			journalMock := new(mocks.JobJournalInterface)
+			pathMock := new(pathMocks.PathInterface)
+			resultsUtils = resultsMock
+			jobJournalUtils = journalMock
+			path.PathUtilsInterface = pathMock
+			stateMutex.Lock()
+			pendingResultPublishes = make(map[string]*pendingResultPublish)
+			stateMutex.Unlock()
+
+			jobDir := t.TempDir()
+			writeAttestedResults(t, jobDir, manifest)
+			pathMock.On("GetJobDirPath", "1").Return(jobDir, nil)
+			for _, err := range tt.results {
+				resultsMock.On("PublishJobResults", "s3://results", manifest).Return(err).Once()
+			}
+			if tt.wantRemoved {
+				journalMock.On("RemoveRecord", "1").Return(nil).Once()
+			} else {
+				journalMock.On("RecordStage", mock.Anything, mock.MatchedBy(func(event types.JobJournalEvent) bool {
+					return event.Stage == types.JobStageResultsPublished
+				})).Return(nil).Once()
+			}
+
+			publishJobResults(&pendingResultPublish{
+				config: types.Configurations{ResultsStore: "s3://results"},
+				job:    types.JobExecution{JobID: big.NewInt(1), Status: types.JobStatusCompleted},
+			})
+			for i, wantPending := range tt.wantPending {
+				if i > 0 {
+					retryResultPublishes()
+				}
+				assert.Eventually(t, resultPublishesIdle, time.Second, 10*time.Millisecond)
+				stateMutex.RLock()
+				_, pending := pendingResultPublishes["1"]
+				stateMutex.RUnlock()
+				assert.Equal(t, wantPending, pending, "after attempt %d", i+1)
+			}
+			resultsMock.AssertExpectations(t)
+			journalMock.AssertExpectations(t)
+		})
+	}
+}
+
+// writeAttestedResults saves the manifest, its attestation and a results archive to jobDir as
+// an earlier delivery of the results of the job would
+func writeAttestedResults(t *testing.T, jobDir string, manifest types.ResultManifest) {
+	data, err := marshalResultManifest(manifest)
+	assert.NoError(t, err)
+	digest := sha256.Sum256(data)
+	attestation, err := json.Marshal(types.ResultAttestation{
+		Version: resultAttestationVersion, JobID: manifest.JobID, ManifestSHA256: hex.EncodeToString(digest[:]),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(jobDir, resultsManifestName), data, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(jobDir, resultsAttestationName), attestation, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(jobDir, resultsArchiveName), []byte("archive"), 0644))
+}
+
+// resultPublishesIdle reports whether no results delivery is running in the background
+func resultPublishesIdle() bool {
+	stateMutex.RLock()
+	defer stateMutex.RUnlock()
+	for _, publish := range pendingResultPublishes {
+		if publish.running {
+			return false
+		}
+	}
+	return true
+}
@@ -99,6 +99,41 @@ func (FlagSetUtils FlagSetUtils) GetRootInt64RPCTimeout() (int64, error) {
 	return rootCmd.PersistentFlags().GetInt64("rpcTimeout")
 }
 
+// This function returns the assignment strategy of root in string
+func (FlagSetUtils FlagSetUtils) GetRootStringAssignmentStrategy() (string, error) {
+	return rootCmd.PersistentFlags().GetString("assignmentStrategy")
+}
+
+// This function returns the shutdown timeout of root in Int64
+func (FlagSetUtils FlagSetUtils) GetRootInt64ShutdownTimeout() (int64, error) {
+	return rootCmd.PersistentFlags().GetInt64("shutdownTimeout")
+}
+
+// This function returns the BlockManager address of root in string
+func (FlagSetUtils FlagSetUtils) GetRootStringBlockManagerAddress() (string, error) {
+	return rootCmd.PersistentFlags().GetString("blockManagerAddress")
+}
+
+// This function returns the results store of root in string
+func (FlagSetUtils FlagSetUtils) GetRootStringResultsStore() (string, error) {
+	return rootCmd.PersistentFlags().GetString("resultsStore")
+}
+
+// This function returns the dataset cache size of root in Int64
+func (FlagSetUtils FlagSetUtils) GetRootInt64DatasetCacheSize() (int64, error) {
+	return rootCmd.PersistentFlags().GetInt64("datasetCacheSize")
+}
+
+// This function returns the dataset root of root in string
+func (FlagSetUtils FlagSetUtils) GetRootStringDatasetRoot() (string, error) {
+	return rootCmd.PersistentFlags().GetString("datasetRoot")
+}
+
+// This function returns the workflows file of root in string
+func (FlagSetUtils FlagSetUtils) GetRootStringWorkflowsFile() (string, error) {
+	return rootCmd.PersistentFlags().GetString("workflowsFile")
+}
+
 // This function returns the provider in string
 func (FlagSetUtils FlagSetUtils) GetStringProvider(flagSet *pflag.FlagSet) (string, error) {
 	return flagSet.GetString("provider")
@@ -133,6 +168,41 @@ func (FlagSetUtils FlagSetUtils) GetInt64RPCTimeout(flagSet *pflag.FlagSet) (int
 	return flagSet.GetInt64("rpcTimeout")
 }
 
+// This function returns the assignment strategy in string
+func (FlagSetUtils FlagSetUtils) GetStringAssignmentStrategy(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("assignmentStrategy")
+}
+
+// This function returns the shutdown timeout in Int64
+func (FlagSetUtils FlagSetUtils) GetInt64ShutdownTimeout(flagSet *pflag.FlagSet) (int64, error) {
+	return flagSet.GetInt64("shutdownTimeout")
+}
+
+// This function returns the BlockManager address in string
+func (FlagSetUtils FlagSetUtils) GetStringBlockManagerAddress(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("blockManagerAddress")
+}
+
+// This function returns the results store in string
+func (FlagSetUtils FlagSetUtils) GetStringResultsStore(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("resultsStore")
+}
+
+// This function returns the dataset cache size in Int64
+func (FlagSetUtils FlagSetUtils) GetInt64DatasetCacheSize(flagSet *pflag.FlagSet) (int64, error) {
+	return flagSet.GetInt64("datasetCacheSize")
+}
+
+// This function returns the dataset root in string
+func (FlagSetUtils FlagSetUtils) GetStringDatasetRoot(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("datasetRoot")
+}
+
+// This function returns the workflows file in string
+func (FlagSetUtils FlagSetUtils) GetStringWorkflowsFile(flagSet *pflag.FlagSet) (string, error) {
+	return flagSet.GetString("workflowsFile")
+}
+
 // This function returns the JobId in Uint16
 func (flagSetUtils FlagSetUtils) GetUint16JobId(flagSet *pflag.FlagSet) (uint16, error) {
 	return flagSet.GetUint16("jobId")
@@ -310,6 +380,11 @@ func (stakeManagerUtils *StakeManagerUtils) GetStakerStructFromId(client *ethcli
 	return stakeManager.Stakers(opts, stakerId)
 }
 
+func (stakeManagerUtils *StakeManagerUtils) GetMinStake(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error) {
+	stakeManager := utilsInterface.GetStakeManager(client)
+	return stakeManager.MinStake(opts)
+}
+
 func (jobManagerUtils *JobsManagerUtils) CreateJob(client *ethclient.Client, opts *bind.TransactOpts, jobDetailsJSON string) (*Types.Transaction, error) {
 	jobManager := utilsInterface.GetJobManager(client)
 	return jobManager.CreateJob(opts, jobDetailsJSON)
@@ -347,6 +422,77 @@ func (jobManagerUtils *JobsManagerUtils) GetJobDetails(client *ethclient.Client,
 	return jobManager.Jobs(opts, jobId)
 }
 
+// GetJobIdCounter returns the JobManager counter of the job IDs handed out so far
+func (jobManagerUtils *JobsManagerUtils) GetJobIdCounter(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error) {
+	jobManager := utilsInterface.GetJobManager(client)
+	return jobManager.JobIdCounter(opts)
+}
+
+func (jobManagerUtils *JobsManagerUtils) HasRole(client *ethclient.Client, opts *bind.CallOpts, role [32]byte, account common.Address) (bool, error) {
+	jobManager := utilsInterface.GetJobManager(client)
+	return jobManager.HasRole(opts, role, account)
+}
+
+func (jobManagerUtils *JobsManagerUtils) GetRoleAdmin(client *ethclient.Client, opts *bind.CallOpts, role [32]byte) ([32]byte, error) {
+	jobManager := utilsInterface.GetJobManager(client)
+	return jobManager.GetRoleAdmin(opts, role)
+}
+
+func (jobManagerUtils *JobsManagerUtils) GrantRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*Types.Transaction, error) {
+	jobManager := utilsInterface.GetJobManager(client)
+	return jobManager.GrantRole(opts, role, account)
+}
+
+func (jobManagerUtils *JobsManagerUtils) RevokeRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*Types.Transaction, error) {
+	jobManager := utilsInterface.GetJobManager(client)
+	return jobManager.RevokeRole(opts, role, account)
+}
+
+func (jobManagerUtils *JobsManagerUtils) RenounceRole(client *ethclient.Client, opts *bind.TransactOpts, role [32]byte, account common.Address) (*Types.Transaction, error) {
+	jobManager := utilsInterface.GetJobManager(client)
+	return jobManager.RenounceRole(opts, role, account)
+}
+
+func (blockManagerUtils *BlockManagerUtils) Propose(client *ethclient.Client, opts *bind.TransactOpts, epoch uint32, jobIds []*big.Int) (*Types.Transaction, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.Propose(opts, epoch, jobIds)
+}
+
+func (blockManagerUtils *BlockManagerUtils) ConfirmBlock(client *ethclient.Client, opts *bind.TransactOpts, epoch uint32) (*Types.Transaction, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.ConfirmBlock(opts, epoch)
+}
+
+func (blockManagerUtils *BlockManagerUtils) GetNumProposedBlocks(client *ethclient.Client, opts *bind.CallOpts, epoch uint32) (*big.Int, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.GetNumProposedBlocks(opts, epoch)
+}
+
+func (blockManagerUtils *BlockManagerUtils) GetProposedBlock(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, blockId uint32) (bindings.StructsBlock, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.GetProposedBlock(opts, epoch, blockId)
+}
+
+func (blockManagerUtils *BlockManagerUtils) GetConfirmedBlock(client *ethclient.Client, opts *bind.CallOpts, epoch uint32) (bindings.StructsBlock, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.GetConfirmedBlock(opts, epoch)
+}
+
+func (blockManagerUtils *BlockManagerUtils) GetSortedProposedBlockId(client *ethclient.Client, opts *bind.CallOpts, epoch uint32, index *big.Int) (uint32, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.SortedProposedBlockIds(opts, epoch, index)
+}
+
+func (blockManagerUtils *BlockManagerUtils) GetBlockIndexToBeConfirmed(client *ethclient.Client, opts *bind.CallOpts) (int8, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.BlockIndexToBeConfirmed(opts)
+}
+
+func (blockManagerUtils *BlockManagerUtils) GetMaxBlocksPerEpochPerStaker(client *ethclient.Client, opts *bind.CallOpts) (*big.Int, error) {
+	blockManager := utilsInterface.GetBlockManager(client)
+	return blockManager.MAXBLOCKSPEREPOCHPERSTAKER(opts)
+}
+
 func (stateManagerUtils *StateManagerUtils) GetEpoch(client *ethclient.Client, opts *bind.CallOpts) (uint32, error) {
 	stateManager := utilsInterface.GetStateManager(client)
 	return stateManager.GetEpoch(opts)
@@ -470,3 +616,18 @@ func (o OSUtils) ReadFile(pathName string) ([]byte, error) {
 func (o OSUtils) WriteFile(name string, content []byte, perm fs.FileMode) error {
 	return path.OSUtilsInterface.WriteFile(name, content, perm)
 }
+
+// Rename moves oldpath to newpath, replacing newpath if it already exists
+func (o OSUtils) Rename(oldpath string, newpath string) error {
+	return path.OSUtilsInterface.Rename(oldpath, newpath)
+}
+
+// ReadDir reads the directory named by name and returns its entries sorted by filename
+func (o OSUtils) ReadDir(name string) ([]fs.DirEntry, error) {
+	return path.OSUtilsInterface.ReadDir(name)
+}
+
+// RemoveAll removes pathName and any children it contains
+func (o OSUtils) RemoveAll(pathName string) error {
+	return path.OSUtilsInterface.RemoveAll(pathName)
+}
@@ -7,6 +7,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
+	"unicode"
 
 	nvml "github.com/mindprince/gonvml"
 	"github.com/shirou/gopsutil/v3/cpu"
@@ -123,6 +126,16 @@ func getGPUSpec() ([]GPUSpec, error) {
 	return gpuSpecs, nil
 }
 
+// GetGPUCount returns the number of NVIDIA GPUs visible through NVML.
+// The executor sizes its GPU slot allocator from this count.
+func GetGPUCount() (int, error) {
+	gpus, err := getGPUSpec()
+	if err != nil {
+		return 0, err
+	}
+	return len(gpus), nil
+}
+
 // getCPUSpec Gathers CPU specifications including:
 // 1. Model name and architecture
 // 2. Core count and threads per core
@@ -177,3 +190,69 @@ func (s *SystemSpecs) ToJSON() (string, error) {
 	}
 	return string(jsonData), nil
 }
+
+// ParseSystemSpecs decodes the machine spec JSON a staker published when staking
+func ParseSystemSpecs(specJSON string) (*SystemSpecs, error) {
+	specs := &SystemSpecs{}
+	if err := json.Unmarshal([]byte(specJSON), specs); err != nil {
+		return nil, fmt.Errorf("failed to parse system specs: %v", err)
+	}
+	return specs, nil
+}
+
+// GPUCount returns the number of GPUs in the spec
+func (s *SystemSpecs) GPUCount() int {
+	return len(s.GPU)
+}
+
+// MinGPUMemoryMiB returns the memory of the smallest GPU in MiB, or 0 without GPUs.
+// GPUs whose memory cannot be parsed count as having no memory.
+func (s *SystemSpecs) MinGPUMemoryMiB() float64 {
+	var minMemory float64
+	for i, gpu := range s.GPU {
+		memory, err := ParseMemoryMiB(gpu.Memory)
+		if err != nil {
+			memory = 0
+		}
+		if i == 0 || memory < minMemory {
+			minMemory = memory
+		}
+	}
+	return minMemory
+}
+
+// MemoryMiB returns the system memory in MiB
+func (s *SystemSpecs) MemoryMiB() (float64, error) {
+	return ParseMemoryMiB(s.Mem)
+}
+
+// ParseMemoryMiB converts a memory size such as "24576.00 MiB" or "62.00 GiB" to MiB.
+// MB/GB/TB are treated like their binary counterparts and a bare number is read as GiB.
+func ParseMemoryMiB(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("empty memory size")
+	}
+
+	numberEnd := strings.IndexFunc(value, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+	if numberEnd == -1 {
+		numberEnd = len(value)
+	}
+	amount, err := strconv.ParseFloat(value[:numberEnd], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory size %q", value)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(value[numberEnd:])) {
+	case "mib", "mb", "m":
+		return amount, nil
+	case "", "gib", "gb", "g":
+		return amount * 1024, nil
+	case "tib", "tb", "t":
+		return amount * 1024 * 1024, nil
+	default:
+		return 0, fmt.Errorf("unknown memory unit in %q", value)
+	}
+}
@@ -25,11 +25,96 @@ var DefaultGasLimit = 2
 var DefaultRPCTimeout = 10
 var DefaultLogLevel = ""
 
+// DefaultAssignmentStrategy assigns every job to the admin node
+var DefaultAssignmentStrategy = "admin"
+
+// DefaultShutdownTimeout is the time in seconds a shutting down executor waits for running jobs before terminating them
+var DefaultShutdownTimeout = 300
+
+// DefaultDatasetCacheSize is the size in megabytes the dataset cache of an executor is kept under by evicting
+// the least recently used datasets
+var DefaultDatasetCacheSize = 50 * 1024
+
+// MinJobFreeDiskSpace is the free disk space in megabytes the job and results directories need before an executor
+// accepts a job
+var MinJobFreeDiskSpace = 20 * 1024
+
+// MaxResultsPublishAttempts is the number of attempts an executor makes to package, attest and upload the results of
+// a completed job, one after the report and one in each following Confirm state, before giving up on them
+var MaxResultsPublishAttempts = 10
+
+// MinJobFreeMemory is the available memory in megabytes an executor needs before it accepts a job
+var MinJobFreeMemory = 2 * 1024
+
 var NilHash = common.Hash{0x00}
 var BlockCompletionTimeout = 60
 
 var StateCheckInterval = 5
 
+// PipelineTimeout is the maximum runtime of a job pipeline in seconds, 0 disables the timeout
+var PipelineTimeout = 48 * 60 * 60
+
+// MaxJobRuntimeEpochs is the number of epochs a job may run after its Running status landed before
+// HandleConfirmState fails it, 0 disables the limit
+var MaxJobRuntimeEpochs uint32 = 330
+
+// JobHeartbeatTimeout is the time in seconds after which a job whose pipeline wrote no output,
+// progress or results is considered stalled, 0 disables stall detection
+var JobHeartbeatTimeout = 60 * 60
+
+// Exit codes of executeJob after a shutdown signal
+const (
+	// ExitCodeForcedShutdown is used when a second signal aborts the shutdown sequence
+	ExitCodeForcedShutdown = 2
+	// ExitCodeShutdown is used when every in-flight job concluded before the shutdown deadline
+	ExitCodeShutdown = 3
+	// ExitCodeShutdownJobsTerminated is used when jobs had to be terminated at the shutdown deadline
+	ExitCodeShutdownJobsTerminated = 4
+)
+
+// PipelineTerminationGracePeriod is the time in seconds a pipeline gets to exit after SIGTERM before it is killed
+var PipelineTerminationGracePeriod = 30
+
+// JobLogMaxSize is the size in megabytes at which a job's stdout.log or stderr.log is rotated
+var JobLogMaxSize = 100
+
+// JobLogMaxBackups is the number of rotated log files kept per job output stream
+var JobLogMaxBackups = 5
+
+// JobProgressSaveInterval is the minimum time in seconds between two writes of a job's progress.json
+var JobProgressSaveInterval = 5
+
+// JobLogFollowInterval is the time in milliseconds between checks for new output when following job logs
+var JobLogFollowInterval = 500
+
+// JobSpecSchemaVersion is the schema_version of the job specs this client creates and executes
+const JobSpecSchemaVersion = 1
+
+// Defaults of the optional job spec fields
+var (
+	DefaultJobLearningRate = 1e-2
+	DefaultJobSeed         = 42
+	DefaultJobOverrideEnv  = "prod"
+)
+
+// DefaultAdminRole is the AccessControl DEFAULT_ADMIN_ROLE of the contracts. Holding it on the JobManager
+// authorizes the executor's --isAdmin and --isRandom modes.
+var DefaultAdminRole = common.Hash{}
+
+// EventBlockRange is the maximum number of blocks scanned for JobManager events in a single eth_getLogs call
+var EventBlockRange uint64 = 1000
+
+// IndexerReorgDepth is the number of recently indexed block hashes the indexer keeps to find where a reorg forked
+// from the indexed chain
+var IndexerReorgDepth = 128
+
+// IndexerPollInterval is the time in seconds between two syncs of indexer sync --follow
+var IndexerPollInterval = 15
+
+// JobEventResyncInterval is the time in seconds after which the executor re-queries the chain for jobs even
+// if no JobManager event announced a change, guarding against missed events
+var JobEventResyncInterval = 300
+
 // EpochLength defines the duration of an epoch in seconds (20 minutes)
 var EpochLength int64 = 540
 
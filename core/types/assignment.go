@@ -0,0 +1,34 @@
+package types
+
+import "time"
+
+// AssignmentStatus is the state of a single assignJob submission within an assignment round
+type AssignmentStatus string
+
+// Assignment states recorded in the assignment round
+const (
+	AssignmentStatusPending  AssignmentStatus = "pending"
+	AssignmentStatusAssigned AssignmentStatus = "assigned"
+	AssignmentStatusFailed   AssignmentStatus = "failed"
+)
+
+// RoundAssignment is the persisted decision and submission state of one job in an assignment round
+type RoundAssignment struct {
+	JobID     string           `json:"job_id"`
+	Staker    string           `json:"staker"`
+	Status    AssignmentStatus `json:"status"`
+	TxHash    string           `json:"tx_hash,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// AssignmentRound is the persisted assignment round of an admin node for one epoch.
+// It records every job handled in the epoch so that no job is submitted twice,
+// even across restarts of the daemon.
+type AssignmentRound struct {
+	Epoch       uint32                     `json:"epoch"`
+	Strategy    string                     `json:"strategy"`
+	Completed   bool                       `json:"completed"`
+	Assignments map[string]RoundAssignment `json:"assignments"`
+	UpdatedAt   time.Time                  `json:"updated_at"`
+}
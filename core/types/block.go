@@ -1,5 +1,10 @@
 package types
 
+import (
+	"math/big"
+	"time"
+)
+
 // Block represents a block in the Lumino network
 // type Block struct {
 // 	blockNumber uint64    // Unique identifier for the block
@@ -19,3 +24,47 @@ package types
 // 	BlockStatusConfirmed
 // 	BlockStatusRejected
 // )
+
+// BlockProposal is a block submitted by this node through BlockManager.propose
+type BlockProposal struct {
+	JobIDs     []string  `json:"job_ids"`
+	TxHash     string    `json:"tx_hash"`
+	ProposedAt time.Time `json:"proposed_at"`
+}
+
+// BlockRound is the persisted proposal round of a proposer node for one epoch.
+// It records every block proposed in the epoch so that a restarted proposer
+// stays within the per-epoch proposal limit and does not propose the same jobs twice.
+type BlockRound struct {
+	Epoch     uint32          `json:"epoch"`
+	Proposals []BlockProposal `json:"proposals"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// BlockJob is a job listed in a block, decoded against its JobManager details
+type BlockJob struct {
+	JobID           string `json:"job_id"`
+	Creator         string `json:"creator,omitempty"`
+	Assignee        string `json:"assignee,omitempty"`
+	ConclusionEpoch uint32 `json:"conclusion_epoch,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// BlockInfo is a proposed or confirmed BlockManager block as shown by the blocks commands.
+// BlockID is nil for confirmed blocks, which the BlockManager stores by epoch only.
+type BlockInfo struct {
+	Epoch        uint32     `json:"epoch"`
+	BlockID      *uint32    `json:"block_id,omitempty"`
+	Valid        bool       `json:"valid"`
+	ProposerID   uint32     `json:"proposer_id"`
+	Iteration    *big.Int   `json:"iteration"`
+	BiggestStake *big.Int   `json:"biggest_stake"`
+	Jobs         []BlockJob `json:"jobs"`
+}
+
+// SortedBlock is an entry of the BlockManager ranking of the blocks proposed in an epoch
+type SortedBlock struct {
+	Index         int    `json:"index"`
+	BlockID       uint32 `json:"block_id"`
+	ToBeConfirmed bool   `json:"to_be_confirmed"`
+}
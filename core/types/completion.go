@@ -0,0 +1,50 @@
+package types
+
+import "time"
+
+// JobOutcome is the conclusion HandleConfirmState draws for a job from its pipeline
+type JobOutcome string
+
+// Outcomes of a job. A job without an outcome is still running.
+const (
+	JobOutcomeCompleted JobOutcome = "completed"
+	JobOutcomeFailed    JobOutcome = "failed"
+	JobOutcomeStalled   JobOutcome = "stalled"
+	JobOutcomeRefused   JobOutcome = "refused"  // the node failed the preflight checks of the job before accepting it
+	JobOutcomeRejected  JobOutcome = "rejected" // the job spec is malformed
+)
+
+// Status returns the on-chain status a job with the outcome is reported with.
+// A stalled, refused or rejected job is reported as Failed.
+func (o JobOutcome) Status() JobStatus {
+	if o == JobOutcomeCompleted {
+		return JobStatusCompleted
+	}
+	return JobStatusFailed
+}
+
+// JobCompletion is the outcome of a job with the reason it was reached, saved as
+// completion.json in the job directory
+type JobCompletion struct {
+	JobID     string     `json:"job_id"`
+	Outcome   JobOutcome `json:"outcome"`
+	Reason    string     `json:"reason"`
+	Epoch     uint32     `json:"epoch,omitempty"` // epoch of the decision, 0 when decided when the pipeline exited
+	DecidedAt time.Time  `json:"decided_at"`
+}
+
+// JobRunnerExit is how the pipeline runner of a job supervised by this node exited,
+// saved as exit.json in the job directory
+type JobRunnerExit struct {
+	ExitCode int       `json:"exit_code"`
+	Signal   string    `json:"signal,omitempty"`
+	TimedOut bool      `json:"timed_out,omitempty"`
+	Canceled bool      `json:"canceled,omitempty"`
+	Reason   string    `json:"reason,omitempty"` // description of an unsuccessful exit
+	ExitedAt time.Time `json:"exited_at"`
+}
+
+// Succeeded reports whether the runner exited on its own with exit code 0
+func (e JobRunnerExit) Succeeded() bool {
+	return e.Reason == "" && e.ExitCode == 0 && e.Signal == "" && !e.TimedOut && !e.Canceled
+}
@@ -1,12 +1,19 @@
 package types
 
 type Configurations struct {
-	BufferPercent      int32
-	WaitTime           int32
-	GasPrice           int32
-	RPCTimeout         int64
-	Provider           string
-	LogLevel           string
-	GasMultiplier      float32
-	GasLimitMultiplier float32
+	BufferPercent       int32
+	WaitTime            int32
+	GasPrice            int32
+	RPCTimeout          int64
+	Provider            string
+	LogLevel            string
+	GasMultiplier       float32
+	GasLimitMultiplier  float32
+	AssignmentStrategy  string
+	ShutdownTimeout     int64
+	BlockManagerAddress string
+	ResultsStore        string
+	DatasetCacheSize    int64
+	DatasetRoot         string
+	WorkflowsFile       string
 }
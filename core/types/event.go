@@ -0,0 +1,30 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// JobEventKind names a JobManager event
+type JobEventKind string
+
+// JobManager events the executor reacts to
+const (
+	JobEventCreated       JobEventKind = "JobCreated"
+	JobEventAssigned      JobEventKind = "JobAssigned"
+	JobEventStatusUpdated JobEventKind = "JobStatusUpdated"
+)
+
+// JobEvent is a decoded JobManager event. Fields that the event does not carry are left empty.
+type JobEvent struct {
+	Kind        JobEventKind
+	JobID       *big.Int
+	Creator     common.Address // JobCreated
+	Epoch       uint32         // JobCreated
+	Assignee    common.Address // JobAssigned
+	Status      JobStatus      // JobStatusUpdated
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// IndexedEventKind is the name of a contract event stored by the indexer
+type IndexedEventKind string
+
+// Contract events stored by the indexer
+const (
+	IndexedJobCreated       IndexedEventKind = "JobCreated"
+	IndexedJobAssigned      IndexedEventKind = "JobAssigned"
+	IndexedJobStatusUpdated IndexedEventKind = "JobStatusUpdated"
+	IndexedNewStaker        IndexedEventKind = "NewStaker"
+	IndexedStakeUpdated     IndexedEventKind = "StakeUpdated"
+	IndexedStakerSlashed    IndexedEventKind = "StakerSlashed"
+	IndexedBlockProposed    IndexedEventKind = "BlockProposed"
+	IndexedBlockConfirmed   IndexedEventKind = "BlockConfirmed"
+)
+
+// IndexedEvent is a JobManager, StakeManager or BlockManager event stored by the indexer.
+// Epoch is the epoch the event carries, or the epoch of its block for events without one.
+// Fields that the event does not carry are left empty.
+type IndexedEvent struct {
+	Kind        IndexedEventKind `json:"kind"`
+	BlockNumber uint64           `json:"block_number"`
+	BlockHash   common.Hash      `json:"block_hash"`
+	BlockTime   time.Time        `json:"block_time"`
+	TxHash      common.Hash      `json:"tx_hash"`
+	LogIndex    uint             `json:"log_index"`
+	Epoch       uint32           `json:"epoch"`
+	JobID       *big.Int         `json:"job_id,omitempty"`    // Job events
+	Creator     string           `json:"creator,omitempty"`   // JobCreated
+	Assignee    string           `json:"assignee,omitempty"`  // JobAssigned, and JobStatusUpdated of an assigned job
+	Status      string           `json:"status,omitempty"`    // JobStatusUpdated
+	StakerID    uint32           `json:"staker_id,omitempty"` // Staker events
+	Staker      string           `json:"staker,omitempty"`    // NewStaker, and staker events of a known staker
+	Amount      *big.Int         `json:"amount,omitempty"`    // new stake of StakeUpdated, slashed amount of StakerSlashed
+	BlockID     *uint32          `json:"block_id,omitempty"`  // Block events
+	Proposer    string           `json:"proposer,omitempty"`  // BlockProposed
+}
+
+// IndexerCursor is the last block indexed, from which the next sync resumes.
+// BlockHash detects a reorg of the indexed blocks.
+type IndexerCursor struct {
+	StartBlock  uint64      `json:"start_block"`
+	BlockNumber uint64      `json:"block_number"`
+	BlockHash   common.Hash `json:"block_hash"`
+	NumEvents   uint64      `json:"num_events"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// IndexedEventFilter narrows the events returned by an indexer query. Empty fields match every event.
+type IndexedEventFilter struct {
+	Kinds     []IndexedEventKind
+	FromEpoch uint32
+	ToEpoch   uint32
+}
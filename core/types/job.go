@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -33,21 +35,74 @@ type JobContract struct {
 	JobDetailsInJSON       string
 }
 
-// JobConfig represents the structure matching the desired output format
+// JobSpec is the job specification submitted with createJob as the job details of a job.
+// SchemaVersion selects its layout, core.JobSpecSchemaVersion is the version this client accepts.
+// Workflow selects the pipeline-zen workflow running the job. The torchtunewrapper workflow takes
+// its arguments from the fields of the spec, the other workflows from Config.
+type JobSpec struct {
+	SchemaVersion   int                        `json:"schema_version"`
+	Workflow        string                     `json:"workflow,omitempty"`
+	JobConfigName   string                     `json:"job_config_name"`
+	DatasetID       string                     `json:"dataset_id"` // URI of the dataset, such as gs://bucket/path
+	BatchSize       int                        `json:"batch_size"`
+	Shuffle         bool                       `json:"shuffle"`
+	NumEpochs       int                        `json:"num_epochs"`
+	UseLora         bool                       `json:"use_lora"`
+	UseQlora        bool                       `json:"use_qlora"`
+	LearningRate    float64                    `json:"lr"`
+	OverrideEnv     string                     `json:"override_env,omitempty"`
+	Seed            int                        `json:"seed"`
+	NumGPUs         int                        `json:"num_gpus"`
+	MinGPUMemory    string                     `json:"min_gpu_memory,omitempty"`    // per GPU, such as "24 GiB"
+	MinSystemMemory string                     `json:"min_system_memory,omitempty"` // such as "16000 MiB"
+	Config          map[string]json.RawMessage `json:"config,omitempty"`            // arguments of the workflow
+}
+
+// JobFilter selects the jobs listed by jobList. Only the active jobs are listed unless
+// All is set or an address is given, in which case every job is read.
+type JobFilter struct {
+	Creator  *common.Address
+	Assignee *common.Address
+	All      bool
+}
+
+// JobTemplate is a named job spec, possibly without some of the required fields, that
+// createJob --template starts from
+type JobTemplate struct {
+	Name   string
+	Source string                     // JobTemplateSourceBuiltIn or the path of a user template
+	Fields map[string]json.RawMessage // job spec fields set by the template
+}
+
+// JobTemplateSourceBuiltIn is the source of the templates embedded in the binary
+const JobTemplateSourceBuiltIn = "built-in"
+
+// JobConfig is the job config written for the pipeline. The arguments of the torchtunewrapper
+// workflow are all strings, the other workflows take theirs from Config as given in the job spec.
 type JobConfig struct {
-	JobConfigName string `json:"job_config_name"`
-	JobID         string `json:"job_id"`
-	DatasetID     string `json:"dataset_id"`
-	BatchSize     string `json:"batch_size"`
-	Shuffle       string `json:"shuffle"`
-	NumEpochs     string `json:"num_epochs"`
-	UseLora       string `json:"use_lora"`
-	UseQlora      string `json:"use_qlora"`
-	LearningRate  string `json:"lr"`
-	OverrideEnv   string `json:"override_env"`
-	Seed          string `json:"seed"`
-	NumGPUs       string `json:"num_gpus"`
-	UserID        string `json:"user_id"`
+	Workflow      string                     `json:"workflow,omitempty"`
+	JobConfigName string                     `json:"job_config_name,omitempty"`
+	JobID         string                     `json:"job_id"`
+	DatasetID     string                     `json:"dataset_id,omitempty"`
+	DatasetPath   string                     `json:"dataset_path,omitempty"` // local copy of the dataset prefetched by the executor, read instead of dataset_id
+	BatchSize     string                     `json:"batch_size,omitempty"`
+	Shuffle       string                     `json:"shuffle,omitempty"`
+	NumEpochs     string                     `json:"num_epochs,omitempty"`
+	UseLora       string                     `json:"use_lora,omitempty"`
+	UseQlora      string                     `json:"use_qlora,omitempty"`
+	LearningRate  string                     `json:"lr,omitempty"`
+	OverrideEnv   string                     `json:"override_env,omitempty"`
+	Seed          string                     `json:"seed,omitempty"`
+	NumGPUs       string                     `json:"num_gpus"`
+	UserID        string                     `json:"user_id"`
+	Config        map[string]json.RawMessage `json:"config,omitempty"`
+}
+
+// JobRequirements describes the hardware a job needs from the staker executing it
+type JobRequirements struct {
+	NumGPUs         int
+	GPUMemoryMiB    float64 // minimum memory of every GPU
+	SystemMemoryMiB float64
 }
 
 type ExecuteJobInput struct {
@@ -67,6 +122,45 @@ const (
 	JobStatusFailed
 )
 
+// String returns the name of the status
+func (s JobStatus) String() string {
+	switch s {
+	case JobStatusNew:
+		return "New"
+	case JobStatusQueued:
+		return "Queued"
+	case JobStatusRunning:
+		return "Running"
+	case JobStatusCompleted:
+		return "Completed"
+	case JobStatusFailed:
+		return "Failed"
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(s))
+	}
+}
+
+// JobInfo is a JobManager job as shown by jobShow and jobList. Epochs are 0 until the job
+// reaches them. Spec is nil if the job details are not a valid job spec, SpecError then
+// holds the reason and Details the job details as submitted.
+type JobInfo struct {
+	JobID                string    `json:"job_id"`
+	Status               string    `json:"status"`
+	Creator              string    `json:"creator"`
+	Assignee             string    `json:"assignee,omitempty"`
+	CreationEpoch        uint32    `json:"creation_epoch"`
+	QueuedEpoch          uint32    `json:"queued_epoch"`
+	ExecutionEpoch       uint32    `json:"execution_epoch"`
+	ProofGenerationEpoch uint32    `json:"proof_generation_epoch"`
+	ConclusionEpoch      uint32    `json:"conclusion_epoch"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	JobFee               *big.Int  `json:"job_fee"` // in wei
+	Spec                 *JobSpec  `json:"spec,omitempty"`
+	SpecError            string    `json:"spec_error,omitempty"`
+	Details              string    `json:"details,omitempty"`
+}
+
 type JobExecution struct {
 	JobID      *big.Int
 	Status     JobStatus
@@ -74,12 +168,13 @@ type JobExecution struct {
 	LastUpdate time.Time
 	Executor   string
 	PipelineID string
+	GPUs       []int // GPU indices reserved for the job, exposed through CUDA_VISIBLE_DEVICES
 }
 
+// JobExecutionState tracks every job executing on this node, keyed by job ID
 type JobExecutionState struct {
-	CurrentJob    *JobExecution
+	Jobs          map[string]*JobExecution
 	LastJobUpdate uint32
-	IsJobRunning  bool
 	CurrentEpoch  uint32
 	CurrentState  EpochState
 }
@@ -0,0 +1,48 @@
+package types
+
+import "time"
+
+// JobStage represents a step in the local execution lifecycle of a job
+type JobStage string
+
+// Job lifecycle stages recorded in the execution journal
+const (
+	JobStageConfigWritten    JobStage = "config_written"
+	JobStageDatasetFetched   JobStage = "dataset_fetched"
+	JobStageRunningTxSent    JobStage = "running_tx_sent"
+	JobStagePipelineStarted  JobStage = "pipeline_started"
+	JobStagePipelineExited   JobStage = "pipeline_exited"
+	JobStagePipelineFailed   JobStage = "pipeline_failed"
+	JobStageResultsPublished JobStage = "results_published"
+	JobStageStatusReported   JobStage = "status_reported"
+)
+
+// JobJournalEvent is a single timestamped lifecycle transition of a job
+type JobJournalEvent struct {
+	Stage     JobStage  `json:"stage"`
+	TxHash    string    `json:"tx_hash,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// JobJournalRecord is the persisted execution record of a job on this executor.
+// It holds everything needed to resume, re-attach or finish the job after a restart.
+type JobJournalRecord struct {
+	JobID        string            `json:"job_id"`
+	Creator      string            `json:"creator"`
+	Executor     string            `json:"executor"`
+	ConfigPath   string            `json:"config_path"`
+	PipelinePath string            `json:"pipeline_path"`
+	GPUs         []int             `json:"gpus,omitempty"`
+	Stage        JobStage          `json:"stage"`
+	FinalStatus  JobStatus         `json:"final_status,omitempty"`
+	StartTime    time.Time         `json:"start_time"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	History      []JobJournalEvent `json:"history"`
+}
+
+// IsConcluded reports whether the final on-chain status of the job has been reported. The
+// results of a completed job are published after the report, which keeps its FinalStatus.
+func (r JobJournalRecord) IsConcluded() bool {
+	return r.Stage == JobStageStatusReported || r.FinalStatus != 0
+}
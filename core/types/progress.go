@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// JobProgress is the latest training progress of a job, parsed from the output of its
+// pipeline and saved as progress.json in the job directory. Values a pipeline has not
+// reported yet are left empty.
+type JobProgress struct {
+	JobID         string    `json:"job_id"`
+	JobConfigName string    `json:"job_config_name,omitempty"`
+	Epoch         int       `json:"epoch,omitempty"`
+	TotalEpochs   int       `json:"total_epochs,omitempty"`
+	Step          int       `json:"step,omitempty"`
+	Loss          *float64  `json:"loss,omitempty"`
+	LearningRate  *float64  `json:"lr,omitempty"`
+	Throughput    *float64  `json:"tokens_per_second,omitempty"` // tokens per second per GPU
+	StartedAt     time.Time `json:"started_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// JobLocalStatus is the execution stage, training progress and outcome of a job on this node as shown
+// by jobStatus. Stage is empty once the job is pruned from the journal, Completion until an outcome is decided.
+type JobLocalStatus struct {
+	JobID      string         `json:"job_id"`
+	Stage      string         `json:"stage,omitempty"`
+	Progress   JobProgress    `json:"progress"`
+	Completion *JobCompletion `json:"completion,omitempty"`
+}
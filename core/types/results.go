@@ -0,0 +1,41 @@
+package types
+
+import "time"
+
+// ResultFile is a file of a results archive with its SHA-256 digest
+type ResultFile struct {
+	Path   string `json:"path"` // slash-separated path relative to the results directory
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ResultManifest describes the results archive of a job published by its assignee.
+// The archive digest covers the compressed archive as stored, the file digests its contents.
+type ResultManifest struct {
+	JobID         string       `json:"job_id"`
+	Creator       string       `json:"creator"`
+	Assignee      string       `json:"assignee"`
+	Archive       string       `json:"archive"` // key of the archive in the results store
+	ArchiveSize   int64        `json:"archive_size"`
+	ArchiveSHA256 string       `json:"archive_sha256"`
+	Files         []ResultFile `json:"files"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// ResultAttestation is the statement of an assignee that it produced the results described by
+// a manifest. Signature is the assignee's EIP-191 personal signature over the JSON encoding of
+// the attestation without its signature, so any signer can be recovered from the attestation alone.
+type ResultAttestation struct {
+	Version         int       `json:"version"`
+	ChainID         string    `json:"chain_id"`
+	JobManager      string    `json:"job_manager"` // address of the JobManager contract of the job
+	JobID           string    `json:"job_id"`
+	Creator         string    `json:"creator"`
+	Assignee        string    `json:"assignee"`
+	ManifestSHA256  string    `json:"manifest_sha256"` // digest of the manifest.json published with the results
+	StartedAt       time.Time `json:"started_at"`
+	CompletedAt     time.Time `json:"completed_at"`
+	PipelineVersion string    `json:"pipeline_version"`
+	ClientVersion   string    `json:"client_version"`
+	Signature       string    `json:"signature,omitempty"` // 0x-prefixed 65-byte signature
+}
@@ -0,0 +1,43 @@
+package types
+
+import "github.com/ethereum/go-ethereum/common"
+
+// RoleAction is an AccessControl transaction, named after its contract method
+type RoleAction string
+
+// AccessControl transactions of the admin roles commands
+const (
+	RoleActionGrant    RoleAction = "grantRole"
+	RoleActionRevoke   RoleAction = "revokeRole"
+	RoleActionRenounce RoleAction = "renounceRole"
+)
+
+// RoleEventKind names an AccessControl event
+type RoleEventKind string
+
+// AccessControl events that change the holders of a role
+const (
+	RoleEventGranted RoleEventKind = "RoleGranted"
+	RoleEventRevoked RoleEventKind = "RoleRevoked"
+)
+
+// RoleEvent is a decoded RoleGranted or RoleRevoked event of the JobManager
+type RoleEvent struct {
+	Kind        RoleEventKind
+	Role        common.Hash
+	Account     common.Address
+	Sender      common.Address
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+}
+
+// RoleHolder is an account holding a JobManager role as shown by admin roles list
+type RoleHolder struct {
+	Role      string `json:"role"`
+	RoleName  string `json:"role_name,omitempty"`
+	Account   string `json:"account"`
+	GrantedBy string `json:"granted_by"`
+	Block     uint64 `json:"block"`
+	TxHash    string `json:"tx_hash"`
+}
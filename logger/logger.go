@@ -26,6 +26,9 @@ type StandardLogger struct {
 
 var standardLogger = &StandardLogger{logrus.New()}
 
+// logFile is the rotating log file, nil when logging to the console only
+var logFile *lumberjack.Logger
+
 // Global variables for logging context
 var (
 	Address     string
@@ -78,6 +81,11 @@ func InitializeLogger(fileName string) {
 			MaxAge:     365, // Maximum number of days to retain old files
 		}
 
+		if logFile != nil {
+			_ = logFile.Close()
+		}
+		logFile = lumberJackLogger
+
 		out := os.Stderr
 		mw := io.MultiWriter(out, lumberJackLogger)
 		standardLogger.Formatter = &logrus.JSONFormatter{}
@@ -88,6 +96,15 @@ func InitializeLogger(fileName string) {
 	}
 }
 
+// Flush closes the log file so that every entry is on disk before the process exits.
+// Later entries reopen the file, so logging keeps working after a flush.
+func Flush() error {
+	if logFile == nil {
+		return nil
+	}
+	return logFile.Close()
+}
+
 // NewLogger returns a new instance of StandardLogger initialized
 // with the default configuration.
 func NewLogger() *StandardLogger {
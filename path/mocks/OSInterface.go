@@ -128,6 +128,36 @@ func (_m *OSInterface) OpenFile(name string, flag int, perm fs.FileMode) (*os.Fi
 	return r0, r1
 }
 
+// ReadDir provides a mock function with given fields: name
+func (_m *OSInterface) ReadDir(name string) ([]fs.DirEntry, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadDir")
+	}
+
+	var r0 []fs.DirEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]fs.DirEntry, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) []fs.DirEntry); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]fs.DirEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ReadFile provides a mock function with given fields: _a0
 func (_m *OSInterface) ReadFile(_a0 string) ([]byte, error) {
 	ret := _m.Called(_a0)
@@ -158,6 +188,42 @@ func (_m *OSInterface) ReadFile(_a0 string) ([]byte, error) {
 	return r0, r1
 }
 
+// RemoveAll provides a mock function with given fields: _a0
+func (_m *OSInterface) RemoveAll(_a0 string) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rename provides a mock function with given fields: oldpath, newpath
+func (_m *OSInterface) Rename(oldpath string, newpath string) error {
+	ret := _m.Called(oldpath, newpath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rename")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(oldpath, newpath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Stat provides a mock function with given fields: name
 func (_m *OSInterface) Stat(name string) (fs.FileInfo, error) {
 	ret := _m.Called(name)
@@ -9,6 +9,62 @@ type PathInterface struct {
 	mock.Mock
 }
 
+// GetAssignmentRoundFilePath provides a mock function with given fields:
+func (_m *PathInterface) GetAssignmentRoundFilePath() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAssignmentRoundFilePath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockRoundFilePath provides a mock function with given fields:
+func (_m *PathInterface) GetBlockRoundFilePath() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockRoundFilePath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetConfigFilePath provides a mock function with given fields:
 func (_m *PathInterface) GetConfigFilePath() (string, error) {
 	ret := _m.Called()
@@ -37,6 +93,34 @@ func (_m *PathInterface) GetConfigFilePath() (string, error) {
 	return r0, r1
 }
 
+// GetDatasetCacheDirPath provides a mock function with given fields:
+func (_m *PathInterface) GetDatasetCacheDirPath() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDatasetCacheDirPath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetDefaultPath provides a mock function with given fields:
 func (_m *PathInterface) GetDefaultPath() (string, error) {
 	ret := _m.Called()
@@ -65,6 +149,90 @@ func (_m *PathInterface) GetDefaultPath() (string, error) {
 	return r0, r1
 }
 
+// GetIndexerDirPath provides a mock function with given fields:
+func (_m *PathInterface) GetIndexerDirPath() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIndexerDirPath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJobDirPath provides a mock function with given fields: jobId
+func (_m *PathInterface) GetJobDirPath(jobId string) (string, error) {
+	ret := _m.Called(jobId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobDirPath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (string, error)); ok {
+		return rf(jobId)
+	}
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(jobId)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(jobId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJobJournalFilePath provides a mock function with given fields:
+func (_m *PathInterface) GetJobJournalFilePath() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJobJournalFilePath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLogFilePath provides a mock function with given fields: fileName
 func (_m *PathInterface) GetLogFilePath(fileName string) (string, error) {
 	ret := _m.Called(fileName)
@@ -93,6 +261,34 @@ func (_m *PathInterface) GetLogFilePath(fileName string) (string, error) {
 	return r0, r1
 }
 
+// GetTemplatesDirPath provides a mock function with given fields:
+func (_m *PathInterface) GetTemplatesDirPath() (string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTemplatesDirPath")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewPathInterface creates a new instance of PathInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewPathInterface(t interface {
@@ -61,3 +61,88 @@ func (PathUtils) GetConfigFilePath() (string, error) {
 	}
 	return pathPackage.Join(luminoPath, "lumino.yaml"), nil
 }
+
+// GetJobJournalFilePath returns the path to the job execution journal.
+// The journal lives in the default Lumino directory so that it survives
+// restarts of the executor daemon.
+func (PathUtils) GetJobJournalFilePath() (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return pathPackage.Join(luminoPath, "job-journal.json"), nil
+}
+
+// GetAssignmentRoundFilePath returns the path to the assignment round of an admin node.
+// The round is kept in the default Lumino directory so that a restarted admin
+// does not resubmit assignments made earlier in the same epoch.
+func (PathUtils) GetAssignmentRoundFilePath() (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return pathPackage.Join(luminoPath, "assignment-round.json"), nil
+}
+
+// GetBlockRoundFilePath returns the path to the block proposal round of a proposer node.
+// The round is kept in the default Lumino directory so that a restarted proposer
+// does not exceed the proposal limit of the epoch.
+func (PathUtils) GetBlockRoundFilePath() (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return pathPackage.Join(luminoPath, "block-round.json"), nil
+}
+
+// GetJobDirPath returns the directory holding the local files of a job, such as
+// its pipeline logs. Creates the directory if it doesn't exist.
+func (PathUtils) GetJobDirPath(jobId string) (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	jobDirPath := pathPackage.Join(luminoPath, ".jobs", jobId)
+	if err := OSUtilsInterface.MkdirAll(jobDirPath, 0700); err != nil {
+		return "", err
+	}
+	return jobDirPath, nil
+}
+
+// GetTemplatesDirPath returns the directory holding the user job templates.
+// The directory is not created, a missing directory holds no templates.
+func (PathUtils) GetTemplatesDirPath() (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return pathPackage.Join(luminoPath, "templates"), nil
+}
+
+// GetIndexerDirPath returns the directory of the local event index built by the indexer.
+// Creates the directory if it doesn't exist.
+func (PathUtils) GetIndexerDirPath() (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	indexerDirPath := pathPackage.Join(luminoPath, "indexer")
+	if err := OSUtilsInterface.MkdirAll(indexerDirPath, 0700); err != nil {
+		return "", err
+	}
+	return indexerDirPath, nil
+}
+
+// GetDatasetCacheDirPath returns the directory of the cache of the datasets downloaded for jobs.
+// Creates the directory if it doesn't exist.
+func (PathUtils) GetDatasetCacheDirPath() (string, error) {
+	luminoPath, err := PathUtilsInterface.GetDefaultPath()
+	if err != nil {
+		return "", err
+	}
+	datasetCacheDirPath := pathPackage.Join(luminoPath, ".datasets")
+	if err := OSUtilsInterface.MkdirAll(datasetCacheDirPath, 0700); err != nil {
+		return "", err
+	}
+	return datasetCacheDirPath, nil
+}
@@ -16,6 +16,13 @@ type PathInterface interface {
 	GetDefaultPath() (string, error)
 	GetLogFilePath(fileName string) (string, error)
 	GetConfigFilePath() (string, error)
+	GetJobJournalFilePath() (string, error)
+	GetAssignmentRoundFilePath() (string, error)
+	GetBlockRoundFilePath() (string, error)
+	GetJobDirPath(jobId string) (string, error)
+	GetTemplatesDirPath() (string, error)
+	GetIndexerDirPath() (string, error)
+	GetDatasetCacheDirPath() (string, error)
 }
 
 // OSInterface defines the contract for OS-level filesystem operations.
@@ -30,7 +37,10 @@ type OSInterface interface {
 	OpenFile(name string, flag int, perm fs.FileMode) (*os.File, error)
 	Open(name string) (*os.File, error)
 	ReadFile(path string) ([]byte, error)
+	ReadDir(name string) ([]fs.DirEntry, error)
 	WriteFile(name string, content []byte, perm fs.FileMode) error
+	Rename(oldpath string, newpath string) error
+	RemoveAll(path string) error
 }
 
 // PathUtils implements the PathInterface
@@ -79,7 +89,22 @@ func (o OSUtils) ReadFile(path string) ([]byte, error) {
 	return os.ReadFile(path)
 }
 
+// ReadDir reads the directory named by name and returns its entries sorted by filename.
+func (o OSUtils) ReadDir(name string) ([]fs.DirEntry, error) {
+	return os.ReadDir(name)
+}
+
 // WriteFile writes data to a file, creating it if necessary, with specified permissions.
 func (o OSUtils) WriteFile(name string, content []byte, perm fs.FileMode) error {
 	return os.WriteFile(name, content, perm)
 }
+
+// Rename moves oldpath to newpath, replacing newpath if it already exists.
+func (o OSUtils) Rename(oldpath string, newpath string) error {
+	return os.Rename(oldpath, newpath)
+}
+
+// RemoveAll removes path and any children it contains, returning nil if path does not exist.
+func (o OSUtils) RemoveAll(path string) error {
+	return os.RemoveAll(path)
+}
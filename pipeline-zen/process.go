@@ -0,0 +1,327 @@
+package pipeline_zen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultGracePeriod is the time a process gets to exit after SIGTERM before it is killed
+const DefaultGracePeriod = 30 * time.Second
+
+// DefaultStderrTailLines is the number of trailing stderr lines kept in a ProcessResult
+const DefaultStderrTailLines = 50
+
+// maxLineSize is the longest output line streamed as a single log entry
+const maxLineSize = 1024 * 1024
+
+// ProcessOptions configures a supervised process
+type ProcessOptions struct {
+	Dir             string        // working directory
+	Env             []string      // variables appended to the environment of the daemon
+	Timeout         time.Duration // maximum runtime, zero disables the timeout
+	GracePeriod     time.Duration // time between SIGTERM and SIGKILL, defaults to DefaultGracePeriod
+	StderrTailLines int           // trailing stderr lines kept, defaults to DefaultStderrTailLines
+	Stdout          io.Writer     // optional sink receiving every stdout line
+	Stderr          io.Writer     // optional sink receiving every stderr line
+	LogFields       logrus.Fields // fields attached to every streamed output line
+}
+
+// ProcessResult describes how a supervised process ended
+type ProcessResult struct {
+	ExitCode   int           `json:"exit_code"` // -1 if the process was killed by a signal
+	Signal     string        `json:"signal,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	StderrTail []string      `json:"stderr_tail,omitempty"`
+	TimedOut   bool          `json:"timed_out,omitempty"`
+	Canceled   bool          `json:"canceled,omitempty"`
+	Err        error         `json:"-"` // error waiting for the process other than a non-zero exit
+}
+
+// Succeeded reports whether the process exited on its own with exit code 0
+func (r ProcessResult) Succeeded() bool {
+	return r.Err == nil && r.ExitCode == 0 && r.Signal == "" && !r.TimedOut && !r.Canceled
+}
+
+// Reason returns a short human readable description of how the process ended
+func (r ProcessResult) Reason() string {
+	var reason string
+	switch {
+	case r.Err != nil:
+		reason = r.Err.Error()
+	case r.Signal != "":
+		reason = "killed by " + r.Signal
+	default:
+		reason = fmt.Sprintf("exited with code %d", r.ExitCode)
+	}
+	switch {
+	case r.TimedOut:
+		reason += " after timing out"
+	case r.Canceled:
+		reason += " after being canceled"
+	}
+	return reason
+}
+
+// Process is a handle to a running supervised process. The process runs in its
+// own process group so that terminating it also stops every child it spawned.
+type Process struct {
+	cmd         *exec.Cmd
+	startTime   time.Time
+	gracePeriod time.Duration
+	done        chan struct{}
+	result      ProcessResult
+
+	mu        sync.Mutex
+	timedOut  bool
+	canceled  bool
+	terminate sync.Once
+}
+
+// StartProcess starts name with args under supervision. This function:
+// 1. Starts the process in a new process group
+// 2. Streams stdout and stderr line by line to the optional sinks, or to the log without them
+// 3. Terminates the process group when ctx is canceled or the timeout expires
+// Returns error if the process cannot be started.
+func StartProcess(ctx context.Context, name string, args []string, opts ProcessOptions) (*Process, error) {
+	if opts.GracePeriod <= 0 {
+		opts.GracePeriod = DefaultGracePeriod
+	}
+	if opts.StderrTailLines <= 0 {
+		opts.StderrTailLines = DefaultStderrTailLines
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = append(os.Environ(), opts.Env...)
+	setProcessGroup(cmd)
+
+	tail := newLineTail(opts.StderrTailLines)
+	stdout := newLineWriter("stdout", opts.LogFields, opts.Stdout, nil)
+	stderr := newLineWriter("stderr", opts.LogFields, opts.Stderr, tail)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Children that outlive the process group leader must not keep Wait blocked on their output
+	cmd.WaitDelay = opts.GracePeriod
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", name, err)
+	}
+
+	p := &Process{
+		cmd:         cmd,
+		startTime:   time.Now(),
+		gracePeriod: opts.GracePeriod,
+		done:        make(chan struct{}),
+	}
+	log.WithFields(opts.LogFields).WithField("pid", cmd.Process.Pid).Debug("Started supervised process")
+
+	go func() {
+		waitErr := cmd.Wait()
+		stdout.Flush()
+		stderr.Flush()
+		p.finish(waitErr, tail.Lines())
+	}()
+
+	go p.supervise(ctx, opts.Timeout)
+
+	return p, nil
+}
+
+// supervise terminates the process on cancellation of ctx or once the timeout expires
+func (p *Process) supervise(ctx context.Context, timeout time.Duration) {
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.canceled = true
+		p.mu.Unlock()
+		p.Terminate()
+	case <-timer:
+		p.mu.Lock()
+		p.timedOut = true
+		p.mu.Unlock()
+		log.WithField("timeout", timeout.String()).Warn("Supervised process timed out")
+		p.Terminate()
+	}
+}
+
+// finish records the result of the exited process and releases waiters
+func (p *Process) finish(waitErr error, stderrTail []string) {
+	p.mu.Lock()
+	result := ProcessResult{
+		ExitCode:   0,
+		Duration:   time.Since(p.startTime),
+		StderrTail: stderrTail,
+		TimedOut:   p.timedOut,
+		Canceled:   p.canceled,
+	}
+	p.mu.Unlock()
+
+	var exitErr *exec.ExitError
+	// ErrWaitDelay only means that leftover children held the output open after the process exited
+	if waitErr != nil && !errors.As(waitErr, &exitErr) && !errors.Is(waitErr, exec.ErrWaitDelay) {
+		result.Err = waitErr
+	}
+	if state := p.cmd.ProcessState; state != nil {
+		result.ExitCode = state.ExitCode()
+		result.Signal = exitSignal(state)
+	}
+
+	p.result = result
+	close(p.done)
+}
+
+// Pid returns the process ID, which is also the ID of its process group
+func (p *Process) Pid() int {
+	return p.cmd.Process.Pid
+}
+
+// Done returns a channel that is closed once the process has exited
+func (p *Process) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait blocks until the process has exited and returns its result
+func (p *Process) Wait() ProcessResult {
+	<-p.done
+	return p.result
+}
+
+// Terminate sends SIGTERM to the process group and SIGKILL once the grace period
+// has passed without the process exiting. It returns immediately; use Wait to
+// block until the process is gone.
+func (p *Process) Terminate() {
+	p.terminate.Do(func() {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		log.WithField("pid", p.Pid()).Info("Sending SIGTERM to process group")
+		if err := terminateProcessGroup(p.cmd); err != nil {
+			log.WithError(err).Warn("Failed to send SIGTERM to process group")
+		}
+
+		go func() {
+			timer := time.NewTimer(p.gracePeriod)
+			defer timer.Stop()
+			select {
+			case <-p.done:
+			case <-timer.C:
+				log.WithField("pid", p.Pid()).Warn("Process did not exit after SIGTERM, sending SIGKILL")
+				if err := killProcessGroup(p.cmd); err != nil {
+					log.WithError(err).Warn("Failed to send SIGKILL to process group")
+				}
+			}
+		}()
+	})
+}
+
+// lineWriter splits process output into lines, copying each line to an optional sink and
+// tail. Lines are logged at Debug when a sink keeps them, such as the job's log files, so
+// that training output stays out of the daemon log, and at Info otherwise. Lines longer
+// than maxLineSize are split.
+type lineWriter struct {
+	mu     sync.Mutex
+	source string
+	fields logrus.Fields
+	sink   io.Writer
+	tail   *lineTail
+	buf    []byte
+}
+
+func newLineWriter(source string, fields logrus.Fields, sink io.Writer, tail *lineTail) *lineWriter {
+	return &lineWriter{source: source, fields: fields, sink: sink, tail: tail}
+}
+
+// Write emits every complete line in data and buffers the remainder
+func (w *lineWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, data...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			if len(w.buf) >= maxLineSize {
+				w.emit(string(w.buf))
+				w.buf = w.buf[:0]
+			}
+			return len(data), nil
+		}
+		w.emit(string(bytes.TrimSuffix(w.buf[:i], []byte{'\r'})))
+		w.buf = w.buf[i+1:]
+	}
+}
+
+// Flush emits a trailing line without newline
+func (w *lineWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.emit(string(w.buf))
+		w.buf = w.buf[:0]
+	}
+}
+
+func (w *lineWriter) emit(line string) {
+	entry := log.WithFields(w.fields).WithField("source", w.source)
+	if w.sink == nil {
+		entry.Info(line)
+	} else {
+		entry.Debug(line)
+	}
+	if w.sink != nil {
+		if _, err := io.WriteString(w.sink, line+"\n"); err != nil {
+			log.WithError(err).WithField("source", w.source).Warn("Failed to write process output")
+			w.sink = nil
+		}
+	}
+	if w.tail != nil {
+		w.tail.Add(line)
+	}
+}
+
+// lineTail keeps the last lines written to it
+type lineTail struct {
+	mu    sync.Mutex
+	max   int
+	lines []string
+}
+
+func newLineTail(max int) *lineTail {
+	return &lineTail{max: max}
+}
+
+// Add appends a line, dropping the oldest line once the tail is full
+func (t *lineTail) Add(line string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lines = append(t.lines, line)
+	if len(t.lines) > t.max {
+		t.lines = t.lines[len(t.lines)-t.max:]
+	}
+}
+
+// Lines returns a copy of the kept lines
+func (t *lineTail) Lines() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.lines...)
+}
@@ -0,0 +1,170 @@
+//go:build !windows
+
+package pipeline_zen
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests how supervised processes end with cases:
+// 1. A clean exit succeeds and streams its output to the sinks
+// 2. A non-zero exit keeps the tail of stderr
+// 3. A timeout terminates the process with SIGTERM
+// 4. Canceling the context terminates the process
+// 5. A process ignoring SIGTERM is killed after the grace period
+func TestStartProcess(t *testing.T) {
+	tests := []struct {
+		name         string
+		script       string
+		timeout      time.Duration
+		cancelAfter  time.Duration
+		wantSuccess  bool
+		wantExitCode int
+		wantSignal   string
+		wantTimedOut bool
+		wantCanceled bool
+		wantTail     []string
+		wantStdout   string
+	}{
+		{
+			name:         "clean exit",
+			script:       "echo started; echo done",
+			wantSuccess:  true,
+			wantExitCode: 0,
+			wantStdout:   "started\ndone\n",
+		},
+		{
+			name:         "non-zero exit",
+			script:       "for i in 1 2 3 4; do echo line$i >&2; done; exit 3",
+			wantExitCode: 3,
+			wantTail:     []string{"line2", "line3", "line4"},
+		},
+		{
+			name:         "timeout",
+			script:       "sleep 30",
+			timeout:      200 * time.Millisecond,
+			wantExitCode: -1,
+			wantSignal:   "SIGTERM",
+			wantTimedOut: true,
+		},
+		{
+			name:         "canceled",
+			script:       "sleep 30",
+			cancelAfter:  200 * time.Millisecond,
+			wantExitCode: -1,
+			wantSignal:   "SIGTERM",
+			wantCanceled: true,
+		},
+		{
+			name:         "killed after grace period",
+			script:       "trap '' TERM; echo ready; while true; do sleep 0.1; done",
+			timeout:      500 * time.Millisecond,
+			wantExitCode: -1,
+			wantSignal:   "SIGKILL",
+			wantTimedOut: true,
+			wantStdout:   "ready\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var stdout bytes.Buffer
+			process, err := StartProcess(ctx, "sh", []string{"-c", tt.script}, ProcessOptions{
+				Timeout:         tt.timeout,
+				GracePeriod:     300 * time.Millisecond,
+				StderrTailLines: 3,
+				Stdout:          &stdout,
+			})
+			assert.NoError(t, err)
+
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			result := process.Wait()
+			assert.Equal(t, tt.wantSuccess, result.Succeeded(), result.Reason())
+			assert.Equal(t, tt.wantExitCode, result.ExitCode)
+			assert.Equal(t, tt.wantSignal, result.Signal)
+			assert.Equal(t, tt.wantTimedOut, result.TimedOut)
+			assert.Equal(t, tt.wantCanceled, result.Canceled)
+			assert.Equal(t, tt.wantTail, result.StderrTail)
+			if tt.wantStdout != "" {
+				assert.Equal(t, tt.wantStdout, stdout.String())
+			}
+			assert.Less(t, result.Duration, 10*time.Second)
+		})
+	}
+}
+
+// Tests that terminating a process also stops the children it spawned
+func TestProcessTerminatesProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+
+	process, err := StartProcess(context.Background(), "sh", []string{"-c", "sleep 30 & echo $! > " + pidFile + "; wait"},
+		ProcessOptions{GracePeriod: time.Second})
+	assert.NoError(t, err)
+
+	var childPid int
+	assert.Eventually(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			return false
+		}
+		childPid, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+
+	process.Terminate()
+	result := process.Wait()
+	assert.False(t, result.Succeeded())
+
+	// The orphaned child must be gone as well
+	assert.Eventually(t, func() bool {
+		return syscall.Kill(childPid, 0) != nil
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+// Tests that process output kept by a sink is logged at Debug only, while output without a
+// sink is logged at Info
+func TestLineWriterLogLevel(t *testing.T) {
+	originalLevel := log.GetLevel()
+	originalHooks := log.ReplaceHooks(make(logrus.LevelHooks))
+	defer func() {
+		log.SetLevel(originalLevel)
+		log.ReplaceHooks(originalHooks)
+	}()
+	log.SetLevel(logrus.DebugLevel)
+	hook := test.NewLocal(log.Logger)
+
+	var sink bytes.Buffer
+	withSink := newLineWriter("stdout", logrus.Fields{"jobId": "1"}, &sink, nil)
+	_, err := withSink.Write([]byte("epoch 1 loss 0.42\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "epoch 1 loss 0.42\n", sink.String())
+	if assert.NotNil(t, hook.LastEntry()) {
+		assert.Equal(t, logrus.DebugLevel, hook.LastEntry().Level)
+	}
+
+	withoutSink := newLineWriter("stdout", logrus.Fields{"jobId": "1"}, nil, nil)
+	_, err = withoutSink.Write([]byte("epoch 2 loss 0.40\n"))
+	assert.NoError(t, err)
+	if assert.NotNil(t, hook.LastEntry()) {
+		assert.Equal(t, logrus.InfoLevel, hook.LastEntry().Level)
+		assert.Equal(t, "epoch 2 loss 0.40", hook.LastEntry().Message)
+	}
+}
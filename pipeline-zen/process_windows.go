@@ -0,0 +1,26 @@
+//go:build windows
+
+package pipeline_zen
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setProcessGroup is a no-op, Windows has no POSIX process groups
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessGroup kills the process, Windows cannot deliver SIGTERM
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// killProcessGroup kills the process
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+// exitSignal always returns "", Windows processes do not die by signal
+func exitSignal(state *os.ProcessState) string {
+	return ""
+}
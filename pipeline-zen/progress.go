@@ -0,0 +1,101 @@
+package pipeline_zen
+
+import (
+	"lumino/core/types"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ProgressParser extracts training progress from the output of a workflow
+type ProgressParser interface {
+	// ParseLine updates progress with the values found in a single output line
+	// and reports whether the line carried any progress.
+	ParseLine(line string, progress *types.JobProgress) bool
+}
+
+var (
+	progressParsersMutex sync.RWMutex
+	// progressParsers holds the parsers registered per job_config_name
+	progressParsers = make(map[string]ProgressParser)
+)
+
+// RegisterProgressParser registers the progress parser of the workflow run for
+// jobConfigName, replacing any parser registered before.
+func RegisterProgressParser(jobConfigName string, parser ProgressParser) {
+	progressParsersMutex.Lock()
+	defer progressParsersMutex.Unlock()
+	progressParsers[jobConfigName] = parser
+}
+
+// ProgressParserFor returns the progress parser registered for jobConfigName.
+// Workflows without a parser of their own are torchtune recipes and get a TorchTuneProgressParser.
+func ProgressParserFor(jobConfigName string) ProgressParser {
+	progressParsersMutex.RLock()
+	defer progressParsersMutex.RUnlock()
+	if parser, ok := progressParsers[jobConfigName]; ok {
+		return parser
+	}
+	return TorchTuneProgressParser{}
+}
+
+// number matches a decimal or scientific float
+const number = `[-+]?(?:\d+\.?\d*|\.\d+)(?:[eE][-+]?\d+)?`
+
+var (
+	// tqdm description of the torchtune recipes: "<epoch>|<step>|Loss: <loss>"
+	torchTuneBarPattern = regexp.MustCompile(`(\d+)\|(\d+)\|Loss: (` + number + `)`)
+	// torchtune metric logger: "Step <step> | loss:<loss> lr:<lr> tokens_per_second_per_gpu:<tps>"
+	torchTuneMetricsPattern = regexp.MustCompile(`Step (\d+) \| (.*)`)
+	metricPattern           = regexp.MustCompile(`(\w+):(` + number + `)`)
+	// plain epoch announcements such as "Epoch 2/3" or "epoch: 2"
+	epochPattern = regexp.MustCompile(`\b[Ee]poch[ :=]+(\d+)(?:\s*/\s*(\d+))?`)
+)
+
+// TorchTuneProgressParser parses the progress bar and metric logger output of the
+// torchtune recipes run by torchtunewrapper.
+type TorchTuneProgressParser struct{}
+
+// ParseLine implements ProgressParser
+func (TorchTuneProgressParser) ParseLine(line string, progress *types.JobProgress) bool {
+	if match := torchTuneBarPattern.FindStringSubmatch(line); match != nil {
+		progress.Epoch, _ = strconv.Atoi(match[1])
+		progress.Step, _ = strconv.Atoi(match[2])
+		progress.Loss = parseFloat(match[3])
+		return true
+	}
+
+	if match := torchTuneMetricsPattern.FindStringSubmatch(line); match != nil {
+		progress.Step, _ = strconv.Atoi(match[1])
+		for _, metric := range metricPattern.FindAllStringSubmatch(match[2], -1) {
+			switch metric[1] {
+			case "loss":
+				progress.Loss = parseFloat(metric[2])
+			case "lr":
+				progress.LearningRate = parseFloat(metric[2])
+			case "tokens_per_second_per_gpu":
+				progress.Throughput = parseFloat(metric[2])
+			}
+		}
+		return true
+	}
+
+	if match := epochPattern.FindStringSubmatch(line); match != nil {
+		progress.Epoch, _ = strconv.Atoi(match[1])
+		if match[2] != "" {
+			progress.TotalEpochs, _ = strconv.Atoi(match[2])
+		}
+		return true
+	}
+	return false
+}
+
+// parseFloat returns a pointer to the value of s, or nil if s is not a number
+func parseFloat(s string) *float64 {
+	value, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
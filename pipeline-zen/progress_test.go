@@ -0,0 +1,86 @@
+package pipeline_zen
+
+import (
+	"lumino/core/types"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+// Tests parsing torchtune output with cases:
+// 1. The tqdm progress bar description
+// 2. The metric logger line
+// 3. An epoch announcement with and without total
+// 4. Lines without progress
+func TestTorchTuneProgressParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		want   types.JobProgress
+		wantOk bool
+	}{
+		{
+			name:   "progress bar",
+			line:   "1|25|Loss: 1.2345:  25%|██▌       | 25/100 [00:10<00:30,  2.50it/s]",
+			want:   types.JobProgress{Epoch: 1, Step: 25, Loss: floatPtr(1.2345)},
+			wantOk: true,
+		},
+		{
+			name: "metric logger",
+			line: "Step 40 | loss:0.8731 lr:2e-05 tokens_per_second_per_gpu:1520.5 peak_memory_alloc:12.3",
+			want: types.JobProgress{Step: 40, Loss: floatPtr(0.8731), LearningRate: floatPtr(2e-05),
+				Throughput: floatPtr(1520.5)},
+			wantOk: true,
+		},
+		{
+			name:   "epoch with total",
+			line:   "INFO Starting Epoch 2/3",
+			want:   types.JobProgress{Epoch: 2, TotalEpochs: 3},
+			wantOk: true,
+		},
+		{
+			name:   "epoch without total",
+			line:   "epoch: 4",
+			want:   types.JobProgress{Epoch: 4},
+			wantOk: true,
+		},
+		{
+			name: "no progress",
+			line: "Downloading model weights from the hub",
+			want: types.JobProgress{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var progress types.JobProgress
+			ok := TorchTuneProgressParser{}.ParseLine(tt.line, &progress)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, progress)
+		})
+	}
+}
+
+type stepParser struct{}
+
+func (stepParser) ParseLine(line string, progress *types.JobProgress) bool {
+	progress.Step++
+	return true
+}
+
+// Tests that workflows get their registered parser and fall back to torchtune
+func TestProgressParserFor(t *testing.T) {
+	RegisterProgressParser("custom_workflow", stepParser{})
+	defer func() {
+		progressParsersMutex.Lock()
+		delete(progressParsers, "custom_workflow")
+		progressParsersMutex.Unlock()
+	}()
+
+	assert.Equal(t, stepParser{}, ProgressParserFor("custom_workflow"))
+	assert.Equal(t, TorchTuneProgressParser{}, ProgressParserFor("llm_dummy"))
+}
@@ -0,0 +1,129 @@
+package pipeline_zen
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Runtime names
+const (
+	ProcessRuntimeName = "process"
+	DockerRuntimeName  = "docker"
+)
+
+// DefaultPython is the interpreter the process runtime checks for the Python environment of pipeline-zen
+const DefaultPython = "python3"
+
+// environmentCheckTimeout is the maximum time a runtime takes to check its environment
+const environmentCheckTimeout = 30 * time.Second
+
+// torchCheck exits non-zero if torch cannot be imported, without importing it
+const torchCheck = "import importlib.util, sys; sys.exit(0 if importlib.util.find_spec('torch') else 1)"
+
+// Execution is a running workflow. Its output is streamed to the sinks of its ProcessOptions
+// and its end is reported as a ProcessResult, whichever runtime it runs in.
+type Execution interface {
+	// Done returns a channel that is closed once the workflow has exited
+	Done() <-chan struct{}
+	// Wait blocks until the workflow has exited and returns its result
+	Wait() ProcessResult
+	// Terminate stops the workflow, killing it once the grace period has passed.
+	// It returns immediately; use Wait to block until the workflow is gone.
+	Terminate()
+}
+
+// RuntimeJob describes the job a workflow command runs for
+type RuntimeJob struct {
+	JobID           string
+	JobDir          string // job directory holding the job config, on the host
+	ResultsPath     string // results directory, relative to the pipeline-zen directory
+	PipelineZenPath string // pipeline-zen directory on the host
+}
+
+// Runtime runs the commands of workflows, as a process on the host or in a container
+type Runtime interface {
+	// Name is the value of executeJob --runtime selecting the runtime
+	Name() string
+	// PipelineZenPath returns where the workflow sees the pipeline-zen directory at hostPath
+	PipelineZenPath(hostPath string) string
+	// JobDirPath returns where the workflow sees the job directory at hostPath
+	JobDirPath(hostPath string) string
+	// Start runs the command of a workflow for job from the pipeline-zen directory,
+	// terminating it when ctx is canceled or the timeout of opts expires
+	Start(ctx context.Context, job RuntimeJob, name string, args []string, opts ProcessOptions) (Execution, error)
+	// CheckEnvironment returns an error describing why workflows cannot run from the
+	// pipeline-zen directory at hostPath
+	CheckEnvironment(hostPath string) error
+}
+
+// ProcessRuntime runs workflows as supervised processes on the host
+type ProcessRuntime struct {
+	Python string // interpreter of the pipeline-zen environment, defaults to DefaultPython
+}
+
+// Name implements Runtime
+func (ProcessRuntime) Name() string {
+	return ProcessRuntimeName
+}
+
+// PipelineZenPath implements Runtime, workflows run from the checkout on the host
+func (ProcessRuntime) PipelineZenPath(hostPath string) string {
+	return hostPath
+}
+
+// JobDirPath implements Runtime, workflows read the job directory on the host
+func (ProcessRuntime) JobDirPath(hostPath string) string {
+	return hostPath
+}
+
+// Start implements Runtime
+func (ProcessRuntime) Start(ctx context.Context, job RuntimeJob, name string, args []string, opts ProcessOptions) (Execution, error) {
+	if _, err := os.Stat(job.PipelineZenPath); err != nil {
+		return nil, fmt.Errorf("pipeline-zen directory not found at %s: %w", job.PipelineZenPath, err)
+	}
+	// Set working directory to 'pipeline-zen' folder
+	opts.Dir = job.PipelineZenPath
+	return StartProcess(ctx, name, args, opts)
+}
+
+// CheckEnvironment implements Runtime. The pipeline-zen directory must exist and the Python
+// interpreter must be on the PATH with torch installed.
+func (r ProcessRuntime) CheckEnvironment(hostPath string) error {
+	if _, err := os.Stat(hostPath); err != nil {
+		return fmt.Errorf("pipeline-zen directory not found at %s: %w", hostPath, err)
+	}
+	python := r.Python
+	if python == "" {
+		python = DefaultPython
+	}
+	interpreter, err := exec.LookPath(python)
+	if err != nil {
+		return fmt.Errorf("python interpreter %s not found: %w", python, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), environmentCheckTimeout)
+	defer cancel()
+	if output, err := exec.CommandContext(ctx, interpreter, "-c", torchCheck).CombinedOutput(); err != nil {
+		return fmt.Errorf("torch is not installed for %s: %w: %s", interpreter, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// envValue returns the value of key in a list of KEY=VALUE variables and the list without it
+func envValue(env []string, key string) (string, bool, []string) {
+	var value string
+	var found bool
+	rest := make([]string, 0, len(env))
+	for _, variable := range env {
+		if name, v, ok := strings.Cut(variable, "="); ok && name == key {
+			value, found = v, true
+			continue
+		}
+		rest = append(rest, variable)
+	}
+	return value, found, rest
+}
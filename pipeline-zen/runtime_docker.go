@@ -0,0 +1,401 @@
+package pipeline_zen
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultContainerImage is the image built by scripts/docker-build.sh
+	DefaultContainerImage = "go-client:latest"
+	// DefaultContainerPipelineZenDir is where the image holds the pipeline-zen checkout
+	DefaultContainerPipelineZenDir = "/pipeline-zen-jobs"
+	// containerEnvDir is where the .env of the node is mounted, read by pipeline-zen through PZ_ENV_DIR
+	containerEnvDir = "/root/.lumino"
+)
+
+// containerExitSignals maps the exit codes of containers killed by a signal to the signal
+var containerExitSignals = map[int]string{
+	128 + 1:  "SIGHUP",
+	128 + 2:  "SIGINT",
+	128 + 6:  "SIGABRT",
+	128 + 9:  "SIGKILL",
+	128 + 11: "SIGSEGV",
+	128 + 15: "SIGTERM",
+}
+
+// ContainerMount is a host path mounted into a container
+type ContainerMount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// ContainerSpec describes the container a workflow runs in
+type ContainerSpec struct {
+	Name    string
+	Image   string
+	WorkDir string
+	Command []string // program and arguments
+	Env     []string // KEY=VALUE
+	Mounts  []ContainerMount
+	GPUs    string // value of docker run --gpus, empty for no GPUs
+}
+
+// ContainerEngine makes the calls to the container runtime. DockerCLI implements it with
+// the docker command line.
+type ContainerEngine interface {
+	// RunCommand returns the program and arguments running a container attached, so that its
+	// output streams to the caller, which exits with the status of the container
+	RunCommand(spec ContainerSpec) (string, []string)
+	// Stop sends SIGTERM to a container and kills it once timeout has passed
+	Stop(name string, timeout time.Duration) error
+	// Remove force-removes a container; a container that does not exist is not an error
+	Remove(name string) error
+	// InspectImage returns an error if the image is not available to run containers from
+	InspectImage(image string) error
+}
+
+// DockerCLI is the ContainerEngine of the docker command line
+type DockerCLI struct {
+	Binary string // defaults to docker
+}
+
+func (d DockerCLI) binary() string {
+	if d.Binary == "" {
+		return "docker"
+	}
+	return d.Binary
+}
+
+// RunCommand implements ContainerEngine
+func (d DockerCLI) RunCommand(spec ContainerSpec) (string, []string) {
+	args := []string{"run", "--rm", "--name", spec.Name}
+	if spec.WorkDir != "" {
+		args = append(args, "--workdir", spec.WorkDir)
+	}
+	if spec.GPUs != "" {
+		args = append(args, "--gpus", spec.GPUs)
+	}
+	for _, variable := range spec.Env {
+		args = append(args, "-e", variable)
+	}
+	for _, mount := range spec.Mounts {
+		volume := mount.Source + ":" + mount.Target
+		if mount.ReadOnly {
+			volume += ":ro"
+		}
+		args = append(args, "-v", volume)
+	}
+	args = append(args, spec.Image)
+	return d.binary(), append(args, spec.Command...)
+}
+
+// Stop implements ContainerEngine
+func (d DockerCLI) Stop(name string, timeout time.Duration) error {
+	seconds := strconv.Itoa(int(timeout.Round(time.Second).Seconds()))
+	output, err := exec.Command(d.binary(), "stop", "--time", seconds, name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to stop container %s: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Remove implements ContainerEngine
+func (d DockerCLI) Remove(name string) error {
+	output, err := exec.Command(d.binary(), "rm", "--force", name).CombinedOutput()
+	if err != nil && !bytes.Contains(output, []byte("No such container")) {
+		return fmt.Errorf("failed to remove container %s: %w: %s", name, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// InspectImage implements ContainerEngine. Only local images count, so that a job does not
+// wait for an image to be pulled.
+func (d DockerCLI) InspectImage(image string) error {
+	binary, err := exec.LookPath(d.binary())
+	if err != nil {
+		return fmt.Errorf("docker not found: %w", err)
+	}
+	output, err := exec.Command(binary, "image", "inspect", "--format", "{{.Id}}", image).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("image %s not available: %w: %s", image, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// DockerRuntime runs every workflow in its own container of a pipeline-zen image, like
+// scripts/docker-run.sh runs the client. The job directory is mounted at its host path and
+// the results directory into the pipeline-zen directory of the image. The .env of the node
+// is mounted read-only for pipeline-zen, and its PZ_DEVICE=cuda gives the container every
+// GPU unless the job is pinned to GPUs of its own.
+type DockerRuntime struct {
+	Engine         ContainerEngine // defaults to DockerCLI{}
+	Image          string          // defaults to DefaultContainerImage
+	PipelineZenDir string          // pipeline-zen directory of the image, defaults to DefaultContainerPipelineZenDir
+	EnvFile        string          // .env of the node, optional
+}
+
+// Name implements Runtime
+func (r DockerRuntime) Name() string {
+	return DockerRuntimeName
+}
+
+// PipelineZenPath implements Runtime, workflows run from the checkout in the image
+func (r DockerRuntime) PipelineZenPath(hostPath string) string {
+	if r.PipelineZenDir == "" {
+		return DefaultContainerPipelineZenDir
+	}
+	return r.PipelineZenDir
+}
+
+// JobDirPath implements Runtime, the job directory is mounted at its host path so that the
+// paths the executor writes into the job config, like its dataset_path, hold in the container
+func (r DockerRuntime) JobDirPath(hostPath string) string {
+	return hostPath
+}
+
+// CheckEnvironment implements Runtime. The image holds pipeline-zen and its Python
+// environment, so only the image must be available; the pipeline-zen directory on the host
+// only receives the results and is created when a workflow starts.
+func (r DockerRuntime) CheckEnvironment(hostPath string) error {
+	return r.engine().InspectImage(r.image())
+}
+
+func (r DockerRuntime) image() string {
+	if r.Image == "" {
+		return DefaultContainerImage
+	}
+	return r.Image
+}
+
+func (r DockerRuntime) engine() ContainerEngine {
+	if r.Engine == nil {
+		return DockerCLI{}
+	}
+	return r.Engine
+}
+
+// ContainerName returns the name of the container of a job
+func ContainerName(jobId string) string {
+	return "lumino-job-" + jobId
+}
+
+// Start implements Runtime. This function:
+// 1. Creates the results directory on the host so that it is mounted with the owner of the executor
+// 2. Removes a container left over by an earlier attempt at the job
+// 3. Runs the container attached, streaming its output like a process
+// The container is stopped with the grace period of opts when ctx is canceled, the timeout
+// of opts expires or the execution is terminated.
+// Returns error if the results directory cannot be created or the container cannot be started.
+func (r DockerRuntime) Start(ctx context.Context, job RuntimeJob, name string, args []string, opts ProcessOptions) (Execution, error) {
+	hostResultsDir := filepath.Join(job.PipelineZenPath, job.ResultsPath)
+	if err := os.MkdirAll(hostResultsDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create results directory: %w", err)
+	}
+
+	spec, err := r.containerSpec(job, name, args, opts.Env)
+	if err != nil {
+		return nil, err
+	}
+	engine := r.engine()
+	if err := engine.Remove(spec.Name); err != nil {
+		return nil, err
+	}
+
+	if opts.GracePeriod <= 0 {
+		opts.GracePeriod = DefaultGracePeriod
+	}
+	execution := &containerExecution{
+		engine:      engine,
+		name:        spec.Name,
+		gracePeriod: opts.GracePeriod,
+		done:        make(chan struct{}),
+	}
+	// The container is stopped through the engine, the attached client only relays its output
+	timeout := opts.Timeout
+	opts.Timeout = 0
+	opts.Env = nil
+	opts.Dir = ""
+	program, programArgs := engine.RunCommand(spec)
+	client, err := StartProcess(context.Background(), program, programArgs, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start container %s: %w", spec.Name, err)
+	}
+	execution.client = client
+	log.WithFields(opts.LogFields).WithField("container", spec.Name).Info("Started job container")
+
+	go execution.wait()
+	go execution.supervise(ctx, timeout)
+	return execution, nil
+}
+
+// containerSpec describes the container running the command of a workflow for job
+func (r DockerRuntime) containerSpec(job RuntimeJob, name string, args []string, env []string) (ContainerSpec, error) {
+	zenDir := r.PipelineZenPath(job.PipelineZenPath)
+	spec := ContainerSpec{
+		Name:    ContainerName(job.JobID),
+		Image:   r.image(),
+		WorkDir: zenDir,
+		Command: append([]string{name}, args...),
+		Env:     []string{"PZ_ROOT_DIR=" + zenDir},
+		Mounts: []ContainerMount{
+			{Source: job.JobDir, Target: r.JobDirPath(job.JobDir)},
+			{Source: filepath.Join(job.PipelineZenPath, job.ResultsPath), Target: filepath.Join(zenDir, job.ResultsPath)},
+		},
+	}
+
+	var envFile map[string]string
+	if r.EnvFile != "" {
+		var err error
+		envFile, err = readEnvFile(r.EnvFile)
+		switch {
+		case err == nil:
+			spec.Mounts = append(spec.Mounts, ContainerMount{Source: r.EnvFile, Target: containerEnvDir + "/.env", ReadOnly: true})
+			spec.Env = append(spec.Env, "PZ_ENV_DIR="+containerEnvDir)
+		case !os.IsNotExist(err):
+			return spec, err
+		}
+	}
+
+	// GPUs pinned through CUDA_VISIBLE_DEVICES are handed to the container, which numbers them from 0
+	devices, pinned, rest := envValue(env, "CUDA_VISIBLE_DEVICES")
+	spec.Env = append(spec.Env, rest...)
+	switch {
+	case pinned && devices != "":
+		spec.GPUs = strconv.Quote("device=" + devices)
+	case !pinned && envFile["PZ_DEVICE"] == "cuda":
+		spec.GPUs = "all"
+	}
+	return spec, nil
+}
+
+// readEnvFile reads the KEY=VALUE lines of a .env file, skipping blank lines and comments
+func readEnvFile(envPath string) (map[string]string, error) {
+	file, err := os.Open(envPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimSuffix(scanner.Text(), "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, "'")
+		}
+		values[strings.TrimSpace(key)] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", envPath, err)
+	}
+	return values, nil
+}
+
+// containerExecution is a workflow running in a container, observed through the attached client
+type containerExecution struct {
+	client      *Process
+	engine      ContainerEngine
+	name        string
+	gracePeriod time.Duration
+	done        chan struct{}
+	result      ProcessResult
+
+	mu        sync.Mutex
+	timedOut  bool
+	canceled  bool
+	terminate sync.Once
+}
+
+// wait records the result of the container once the attached client has exited
+func (e *containerExecution) wait() {
+	result := e.client.Wait()
+	if signal, ok := containerExitSignals[result.ExitCode]; ok && result.Signal == "" {
+		result.ExitCode = -1
+		result.Signal = signal
+	}
+	e.mu.Lock()
+	result.TimedOut = e.timedOut
+	result.Canceled = e.canceled
+	e.mu.Unlock()
+	e.result = result
+	close(e.done)
+}
+
+// supervise stops the container on cancellation of ctx or once the timeout expires
+func (e *containerExecution) supervise(ctx context.Context, timeout time.Duration) {
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	select {
+	case <-e.done:
+	case <-ctx.Done():
+		e.mu.Lock()
+		e.canceled = true
+		e.mu.Unlock()
+		e.Terminate()
+	case <-timer:
+		e.mu.Lock()
+		e.timedOut = true
+		e.mu.Unlock()
+		log.WithFields(logrus.Fields{"container": e.name, "timeout": timeout.String()}).Warn("Job container timed out")
+		e.Terminate()
+	}
+}
+
+// Done implements Execution
+func (e *containerExecution) Done() <-chan struct{} {
+	return e.done
+}
+
+// Wait implements Execution
+func (e *containerExecution) Wait() ProcessResult {
+	<-e.done
+	return e.result
+}
+
+// Terminate implements Execution by stopping the container through the engine. If the
+// engine cannot stop it, the attached client is terminated instead.
+func (e *containerExecution) Terminate() {
+	e.terminate.Do(func() {
+		select {
+		case <-e.done:
+			return
+		default:
+		}
+
+		log.WithField("container", e.name).Info("Stopping job container")
+		go func() {
+			if err := e.engine.Stop(e.name, e.gracePeriod); err != nil {
+				log.WithError(err).WithField("container", e.name).Warn("Failed to stop job container, terminating its client")
+				e.client.Terminate()
+			}
+		}()
+	})
+}
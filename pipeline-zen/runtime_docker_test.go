@@ -0,0 +1,312 @@
+//go:build !windows
+
+package pipeline_zen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeContainerEngine runs containers as shell scripts on the host. Stopping a container
+// creates its stop file, which the scripts of the tests poll.
+type fakeContainerEngine struct {
+	script   string
+	stopFile string
+	stopErr  error
+	images   []string
+
+	mu      sync.Mutex
+	spec    ContainerSpec
+	stopped []string
+	removed []string
+}
+
+func (f *fakeContainerEngine) RunCommand(spec ContainerSpec) (string, []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.spec = spec
+	return "sh", []string{"-c", f.script}
+}
+
+func (f *fakeContainerEngine) Stop(name string, timeout time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = append(f.stopped, name)
+	if f.stopErr != nil {
+		return f.stopErr
+	}
+	return os.WriteFile(f.stopFile, nil, 0644)
+}
+
+func (f *fakeContainerEngine) Remove(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.removed = append(f.removed, name)
+	return nil
+}
+
+func (f *fakeContainerEngine) InspectImage(image string) error {
+	for _, available := range f.images {
+		if available == image {
+			return nil
+		}
+	}
+	return errors.New("no such image: " + image)
+}
+
+// Tests the containers described for jobs with cases:
+// 1. Without a .env only the job and results directories are mounted
+// 2. A .env with PZ_DEVICE=cuda is mounted read-only and gives the container every GPU
+// 3. GPUs pinned through CUDA_VISIBLE_DEVICES are handed to the container
+// 4. A job pinned to no GPUs gets none even with PZ_DEVICE=cuda
+func TestDockerRuntimeContainerSpec(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	assert.NoError(t, os.WriteFile(envFile, []byte("# device of pipeline-zen\nexport PZ_DEVICE=\"cuda\"\nPZ_HUGGINGFACE_TOKEN='hf_token'\n"), 0600))
+
+	job := RuntimeJob{
+		JobID:           "13",
+		JobDir:          "/home/node/.lumino/.jobs/13",
+		ResultsPath:     ResultsPath("0xabc", "13"),
+		PipelineZenPath: "/home/node/pipeline-zen",
+	}
+	baseMounts := []ContainerMount{
+		{Source: "/home/node/.lumino/.jobs/13", Target: "/home/node/.lumino/.jobs/13"},
+		{Source: "/home/node/pipeline-zen/.results/0xabc/13", Target: "/pipeline-zen-jobs/.results/0xabc/13"},
+	}
+	envMounts := append(append([]ContainerMount{}, baseMounts...), ContainerMount{Source: envFile, Target: "/root/.lumino/.env", ReadOnly: true})
+
+	tests := []struct {
+		name       string
+		envFile    string
+		env        []string
+		wantEnv    []string
+		wantMounts []ContainerMount
+		wantGPUs   string
+	}{
+		{
+			name:       "without env file",
+			envFile:    filepath.Join(t.TempDir(), ".env"),
+			env:        []string{"PZ_LOG_LEVEL=debug"},
+			wantEnv:    []string{"PZ_ROOT_DIR=/pipeline-zen-jobs", "PZ_LOG_LEVEL=debug"},
+			wantMounts: baseMounts,
+		},
+		{
+			name:       "env file with cuda device",
+			envFile:    envFile,
+			wantEnv:    []string{"PZ_ROOT_DIR=/pipeline-zen-jobs", "PZ_ENV_DIR=/root/.lumino"},
+			wantMounts: envMounts,
+			wantGPUs:   "all",
+		},
+		{
+			name:       "pinned GPUs",
+			envFile:    envFile,
+			env:        []string{"CUDA_VISIBLE_DEVICES=0,2"},
+			wantEnv:    []string{"PZ_ROOT_DIR=/pipeline-zen-jobs", "PZ_ENV_DIR=/root/.lumino"},
+			wantMounts: envMounts,
+			wantGPUs:   `"device=0,2"`,
+		},
+		{
+			name:       "pinned to no GPUs",
+			envFile:    envFile,
+			env:        []string{"CUDA_VISIBLE_DEVICES="},
+			wantEnv:    []string{"PZ_ROOT_DIR=/pipeline-zen-jobs", "PZ_ENV_DIR=/root/.lumino"},
+			wantMounts: envMounts,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtime := DockerRuntime{EnvFile: tt.envFile}
+			spec, err := runtime.containerSpec(job, "bash", []string{"scripts/runners/celery-wf.sh", "torchtunewrapper"}, tt.env)
+			assert.NoError(t, err)
+			assert.Equal(t, ContainerSpec{
+				Name:    "lumino-job-13",
+				Image:   DefaultContainerImage,
+				WorkDir: DefaultContainerPipelineZenDir,
+				Command: []string{"bash", "scripts/runners/celery-wf.sh", "torchtunewrapper"},
+				Env:     tt.wantEnv,
+				Mounts:  tt.wantMounts,
+				GPUs:    tt.wantGPUs,
+			}, spec)
+		})
+	}
+}
+
+// Tests the docker run command of a container spec
+func TestDockerCLIRunCommand(t *testing.T) {
+	name, args := DockerCLI{}.RunCommand(ContainerSpec{
+		Name:    "lumino-job-13",
+		Image:   "go-client:latest",
+		WorkDir: "/pipeline-zen-jobs",
+		Command: []string{"bash", "run.sh"},
+		Env:     []string{"PZ_ROOT_DIR=/pipeline-zen-jobs"},
+		Mounts:  []ContainerMount{{Source: "/jobs/13", Target: "/jobs/13"}, {Source: "/node/.env", Target: "/root/.lumino/.env", ReadOnly: true}},
+		GPUs:    `"device=1"`,
+	})
+	assert.Equal(t, "docker", name)
+	assert.Equal(t, []string{"run", "--rm", "--name", "lumino-job-13", "--workdir", "/pipeline-zen-jobs", "--gpus", `"device=1"`,
+		"-e", "PZ_ROOT_DIR=/pipeline-zen-jobs", "-v", "/jobs/13:/jobs/13", "-v", "/node/.env:/root/.lumino/.env:ro",
+		"go-client:latest", "bash", "run.sh"}, args)
+}
+
+// Tests how job containers end with cases:
+// 1. A clean exit succeeds and streams the container output to the sinks
+// 2. A container killed by SIGTERM reports the signal
+// 3. A timeout stops the container through the engine
+// 4. Canceling the context stops the container through the engine
+// 5. A container the engine cannot stop has its client terminated
+func TestDockerRuntimeStart(t *testing.T) {
+	tests := []struct {
+		name         string
+		script       string
+		stopErr      error
+		timeout      time.Duration
+		cancelAfter  time.Duration
+		wantSuccess  bool
+		wantExitCode int
+		wantSignal   string
+		wantTimedOut bool
+		wantCanceled bool
+		wantStopped  bool
+		wantStdout   string
+	}{
+		{
+			name:        "clean exit",
+			script:      "echo epoch 1; echo epoch 2",
+			wantSuccess: true,
+			wantStdout:  "epoch 1\nepoch 2\n",
+		},
+		{
+			name:         "killed by SIGTERM",
+			script:       "exit 143",
+			wantExitCode: -1,
+			wantSignal:   "SIGTERM",
+		},
+		{
+			name:         "timeout",
+			script:       `while [ ! -f "$STOP_FILE" ]; do sleep 0.05; done; exit 143`,
+			timeout:      200 * time.Millisecond,
+			wantExitCode: -1,
+			wantSignal:   "SIGTERM",
+			wantTimedOut: true,
+			wantStopped:  true,
+		},
+		{
+			name:         "canceled",
+			script:       `while [ ! -f "$STOP_FILE" ]; do sleep 0.05; done; exit 143`,
+			cancelAfter:  200 * time.Millisecond,
+			wantExitCode: -1,
+			wantSignal:   "SIGTERM",
+			wantCanceled: true,
+			wantStopped:  true,
+		},
+		{
+			name:         "engine failing to stop",
+			script:       "sleep 30",
+			stopErr:      errors.New("docker daemon unreachable"),
+			timeout:      200 * time.Millisecond,
+			wantExitCode: -1,
+			wantSignal:   "SIGTERM",
+			wantTimedOut: true,
+			wantStopped:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			pipelineZenPath := t.TempDir()
+			stopFile := filepath.Join(t.TempDir(), "stopped")
+			t.Setenv("STOP_FILE", stopFile)
+			engine := &fakeContainerEngine{script: tt.script, stopFile: stopFile, stopErr: tt.stopErr}
+			runtime := DockerRuntime{Engine: engine}
+
+			var stdout bytes.Buffer
+			execution, err := runtime.Start(ctx, RuntimeJob{
+				JobID:           "13",
+				JobDir:          t.TempDir(),
+				ResultsPath:     ResultsPath("0xabc", "13"),
+				PipelineZenPath: pipelineZenPath,
+			}, "bash", []string{"run.sh"}, ProcessOptions{
+				Timeout:     tt.timeout,
+				GracePeriod: 300 * time.Millisecond,
+				Stdout:      &stdout,
+			})
+			assert.NoError(t, err)
+			assert.DirExists(t, filepath.Join(pipelineZenPath, ".results", "0xabc", "13"))
+
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			result := execution.Wait()
+			assert.Equal(t, tt.wantSuccess, result.Succeeded(), result.Reason())
+			assert.Equal(t, tt.wantExitCode, result.ExitCode)
+			assert.Equal(t, tt.wantSignal, result.Signal)
+			assert.Equal(t, tt.wantTimedOut, result.TimedOut)
+			assert.Equal(t, tt.wantCanceled, result.Canceled)
+			if tt.wantStdout != "" {
+				assert.Equal(t, tt.wantStdout, stdout.String())
+			}
+
+			engine.mu.Lock()
+			defer engine.mu.Unlock()
+			assert.Equal(t, []string{"lumino-job-13"}, engine.removed)
+			assert.Equal(t, []string{"bash", "run.sh"}, engine.spec.Command)
+			if tt.wantStopped {
+				assert.Equal(t, []string{"lumino-job-13"}, engine.stopped)
+			} else {
+				assert.Empty(t, engine.stopped)
+			}
+		})
+	}
+}
+
+// Tests the environment checks of the runtimes with cases:
+// 1. The process runtime accepts a pipeline-zen directory and an interpreter with torch
+// 2. The process runtime rejects a missing pipeline-zen directory
+// 3. The process runtime rejects a missing interpreter or one without torch
+// 4. The docker runtime only requires its image, whatever the host directory
+func TestRuntimeCheckEnvironment(t *testing.T) {
+	zenDir := t.TempDir()
+	binDir := t.TempDir()
+	withTorch := filepath.Join(binDir, "python-torch")
+	assert.NoError(t, os.WriteFile(withTorch, []byte("#!/bin/sh\nexit 0\n"), 0755))
+	withoutTorch := filepath.Join(binDir, "python-bare")
+	assert.NoError(t, os.WriteFile(withoutTorch, []byte("#!/bin/sh\necho 'no torch' >&2\nexit 1\n"), 0755))
+
+	tests := []struct {
+		name     string
+		runtime  Runtime
+		hostPath string
+		wantErr  string
+	}{
+		{name: "process environment", runtime: ProcessRuntime{Python: withTorch}, hostPath: zenDir},
+		{name: "missing pipeline-zen", runtime: ProcessRuntime{Python: withTorch}, hostPath: filepath.Join(zenDir, "missing"), wantErr: "pipeline-zen directory not found"},
+		{name: "missing interpreter", runtime: ProcessRuntime{Python: filepath.Join(binDir, "python-missing")}, hostPath: zenDir, wantErr: "python interpreter"},
+		{name: "missing torch", runtime: ProcessRuntime{Python: withoutTorch}, hostPath: zenDir, wantErr: "torch is not installed"},
+		{name: "docker image", runtime: DockerRuntime{Engine: &fakeContainerEngine{images: []string{"pz:1"}}, Image: "pz:1"}, hostPath: filepath.Join(zenDir, "missing")},
+		{name: "missing docker image", runtime: DockerRuntime{Engine: &fakeContainerEngine{images: []string{"pz:1"}}}, hostPath: zenDir, wantErr: "no such image: " + DefaultContainerImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.runtime.CheckEnvironment(tt.hostPath)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
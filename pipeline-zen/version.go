@@ -0,0 +1,56 @@
+package pipeline_zen
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// UnknownVersion is reported when the version of a pipeline-zen checkout cannot be determined
+const UnknownVersion = "unknown"
+
+// Version returns the version of the pipeline-zen checkout at pipelineZenPath: the content
+// of its VERSION file, or else the commit checked out in its git repository, read from the
+// .git directory without running git. Returns UnknownVersion if neither is available.
+func Version(pipelineZenPath string) string {
+	if data, err := os.ReadFile(filepath.Join(pipelineZenPath, "VERSION")); err == nil {
+		if version := strings.TrimSpace(string(data)); version != "" {
+			return version
+		}
+	}
+	if commit := gitHead(filepath.Join(pipelineZenPath, ".git")); commit != "" {
+		return commit
+	}
+	return UnknownVersion
+}
+
+// gitHead resolves HEAD of the git directory to a commit, following a symbolic ref
+// through the loose refs and then packed-refs. Returns "" if HEAD cannot be resolved.
+func gitHead(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	ref, symbolic := strings.CutPrefix(head, "ref: ")
+	if !symbolic {
+		return head
+	}
+
+	if data, err := os.ReadFile(filepath.Join(gitDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	packed, err := os.Open(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer packed.Close()
+	scanner := bufio.NewScanner(packed)
+	for scanner.Scan() {
+		if commit, name, ok := strings.Cut(scanner.Text(), " "); ok && name == ref {
+			return commit
+		}
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package pipeline_zen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Tests resolving the version of a pipeline-zen checkout with cases:
+// 1. A VERSION file takes precedence over git
+// 2. A branch checkout resolves through the loose ref, then packed-refs
+// 3. A detached HEAD is the commit itself
+// 4. Without either the version is unknown
+func TestVersion(t *testing.T) {
+	const commit = "3f1c2a9d8e7b6a5f4e3d2c1b0a9f8e7d6c5b4a39"
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "version file",
+			files: map[string]string{"VERSION": "1.4.2\n", ".git/HEAD": commit + "\n"},
+			want:  "1.4.2",
+		},
+		{
+			name:  "loose ref",
+			files: map[string]string{".git/HEAD": "ref: refs/heads/main\n", ".git/refs/heads/main": commit + "\n"},
+			want:  commit,
+		},
+		{
+			name: "packed ref",
+			files: map[string]string{
+				".git/HEAD":        "ref: refs/heads/main\n",
+				".git/packed-refs": "# pack-refs with: peeled fully-peeled sorted\n" + commit + " refs/heads/main\n",
+			},
+			want: commit,
+		},
+		{
+			name:  "detached head",
+			files: map[string]string{".git/HEAD": commit + "\n"},
+			want:  commit,
+		},
+		{
+			name:  "unresolved ref",
+			files: map[string]string{".git/HEAD": "ref: refs/heads/main\n"},
+			want:  UnknownVersion,
+		},
+		{
+			name: "no version",
+			want: UnknownVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tt.files {
+				file := filepath.Join(dir, filepath.FromSlash(name))
+				assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+				assert.NoError(t, os.WriteFile(file, []byte(content), 0644))
+			}
+			assert.Equal(t, tt.want, Version(dir))
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package pipeline_zen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"lumino/core/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultWorkflow is the workflow of the job specs and job configs that do not select one
+const DefaultWorkflow = "torchtunewrapper"
+
+// Workflow is a kind of job executed on pipeline-zen, selected by the workflow field of a job spec
+type Workflow interface {
+	// Name is the value of the workflow field selecting the workflow
+	Name() string
+	// ValidateConfig checks a job config against the config schema of the workflow
+	ValidateConfig(config types.JobConfig) error
+	// Command returns the program and arguments running the workflow on the job config
+	// saved at configFile. The command runs from the pipeline-zen directory at pipelineZenPath.
+	// Both paths, and the dataset_path of config, are where the runtime of the job sees them.
+	Command(pipelineZenPath string, configFile string, config types.JobConfig) (string, []string, error)
+	// DetectCompletion reads how far the workflow got from the results directory of the job
+	DetectCompletion(resultsDir string) WorkflowState
+}
+
+// WorkflowState is how far a workflow got, as read from the results directory of its job
+type WorkflowState struct {
+	Started       bool   // the workflow marked its start
+	Finished      bool   // the workflow marked its end
+	FailureMarker string // name of the failure marker the workflow wrote
+	FailureReason string // content of the failure marker
+	HasResults    bool   // the results directory holds a file other than the markers
+	MetricsError  string // why the metrics file is unusable or reports a failure
+}
+
+var (
+	workflowsMutex sync.RWMutex
+	// workflows holds the registered workflows by name
+	workflows = make(map[string]Workflow)
+)
+
+func init() {
+	RegisterWorkflow(TorchTuneWrapperWorkflow{})
+}
+
+// RegisterWorkflow makes a workflow selectable by its name, replacing any workflow
+// registered before under the same name
+func RegisterWorkflow(workflow Workflow) {
+	workflowsMutex.Lock()
+	defer workflowsMutex.Unlock()
+	workflows[workflow.Name()] = workflow
+}
+
+// WorkflowFor returns the workflow registered as name, or the DefaultWorkflow if name is empty.
+// Returns error naming the registered workflows if there is no workflow of that name.
+func WorkflowFor(name string) (Workflow, error) {
+	if name == "" {
+		name = DefaultWorkflow
+	}
+	workflowsMutex.RLock()
+	workflow, ok := workflows[name]
+	workflowsMutex.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown workflow %q, expected one of %s", name, strings.Join(WorkflowNames(), ", "))
+	}
+	return workflow, nil
+}
+
+// WorkflowNames returns the names of the registered workflows in order
+func WorkflowNames() []string {
+	workflowsMutex.RLock()
+	defer workflowsMutex.RUnlock()
+	names := make([]string, 0, len(workflows))
+	for name := range workflows {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// StartWorkflow starts the workflow selected by the job config at configFile in runtime.
+// This function:
+// 1. Reads the job config and looks up its workflow
+// 2. Validates the job config against the schema of the workflow
+// 3. Resolves the pipeline-zen directory, the job config and the prefetched dataset to the
+// paths the runtime sees them at
+// 4. Starts the command of the workflow from the pipeline-zen directory
+// opts.Env is appended to the environment of the workflow, e.g. to pin the job to its GPUs.
+// The returned execution is terminated when ctx is canceled.
+// Returns error if the config is unreadable or invalid, or the workflow cannot be started.
+func StartWorkflow(ctx context.Context, runtime Runtime, pipelineZenPath string, configFile string, opts ProcessOptions) (Execution, error) {
+	configData, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read job config: %w", err)
+	}
+	var config types.JobConfig
+	if err := json.Unmarshal(configData, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse job config: %w", err)
+	}
+
+	workflow, err := WorkflowFor(config.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	if err := workflow.ValidateConfig(config); err != nil {
+		return nil, fmt.Errorf("invalid %s config: %w", workflow.Name(), err)
+	}
+
+	jobDir := filepath.Dir(configFile)
+	config.DatasetPath = runtimeJobPath(runtime, jobDir, config.DatasetPath)
+	name, args, err := workflow.Command(runtime.PipelineZenPath(pipelineZenPath), runtimeJobPath(runtime, jobDir, configFile), config)
+	if err != nil {
+		return nil, err
+	}
+	log.WithFields(logrus.Fields{
+		"workflow": workflow.Name(),
+		"runtime":  runtime.Name(),
+	}).Infof("Running command: %s %s", name, strings.Join(args, " "))
+
+	return runtime.Start(ctx, RuntimeJob{
+		JobID:           config.JobID,
+		JobDir:          jobDir,
+		ResultsPath:     ResultsPath(config.UserID, config.JobID),
+		PipelineZenPath: pipelineZenPath,
+	}, name, args, opts)
+}
+
+// runtimeJobPath returns where the runtime sees hostPath, a path in the job directory at
+// jobDir. Paths outside the job directory are returned as they are.
+func runtimeJobPath(runtime Runtime, jobDir string, hostPath string) string {
+	rel, err := filepath.Rel(jobDir, hostPath)
+	if hostPath == "" || err != nil || !filepath.IsLocal(rel) {
+		return hostPath
+	}
+	return filepath.Join(runtime.JobDirPath(jobDir), rel)
+}
+
+// ResultsPath returns the results directory of a job relative to the pipeline-zen directory
+func ResultsPath(userId string, jobId string) string {
+	return filepath.Join(".results", userId, jobId)
+}
+
+// WorkflowMarkers names the files a workflow marks its progress with in its results directory
+type WorkflowMarkers struct {
+	Started  string   `json:"started"`  // written when the workflow starts
+	Finished string   `json:"finished"` // written when the workflow ends
+	Failure  []string `json:"failure"`  // written when the workflow fails, holding the reason of the failure
+	Metrics  string   `json:"metrics"`  // optional JSON object describing the run, checked for a reported failure
+}
+
+// DefaultWorkflowMarkers are the markers written by the pipeline-zen workflows
+var DefaultWorkflowMarkers = WorkflowMarkers{
+	Started:  ".started",
+	Finished: ".finished",
+	Failure:  []string{".failed", ".error"},
+	Metrics:  "metrics.json",
+}
+
+// ReadState reads the state of a workflow from the markers in its results directory
+func (m WorkflowMarkers) ReadState(resultsDir string) WorkflowState {
+	var state WorkflowState
+	if m.Started != "" {
+		_, err := os.Stat(filepath.Join(resultsDir, m.Started))
+		state.Started = err == nil
+	}
+	if m.Finished != "" {
+		_, err := os.Stat(filepath.Join(resultsDir, m.Finished))
+		state.Finished = err == nil
+	}
+	for _, marker := range m.Failure {
+		if data, err := os.ReadFile(filepath.Join(resultsDir, marker)); err == nil {
+			state.FailureMarker = marker
+			state.FailureReason = strings.TrimSpace(string(data))
+			break
+		}
+	}
+	state.HasResults = hasResultFiles(resultsDir, m.names())
+	if m.Metrics != "" {
+		state.MetricsError = checkMetrics(resultsDir, m.Metrics)
+	}
+	return state
+}
+
+// names returns the set of marker file names, which are not results
+func (m WorkflowMarkers) names() map[string]bool {
+	names := map[string]bool{m.Started: true, m.Finished: true}
+	for _, marker := range m.Failure {
+		names[marker] = true
+	}
+	delete(names, "")
+	return names
+}
+
+// hasResultFiles reports whether resultsDir holds a regular file other than the markers
+func hasResultFiles(resultsDir string, markers map[string]bool) bool {
+	found := false
+	filepath.WalkDir(resultsDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		rel, _ := filepath.Rel(resultsDir, filePath)
+		if entry.Type().IsRegular() && !markers[filepath.ToSlash(rel)] {
+			found = true
+			return fs.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// checkMetrics reads the metrics file of a results directory if there is one.
+// Returns why the metrics cannot be used, or "" if the file is missing or describes a healthy run.
+func checkMetrics(resultsDir string, metricsFile string) string {
+	data, err := os.ReadFile(filepath.Join(resultsDir, metricsFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return ""
+	}
+	if err != nil {
+		return fmt.Sprintf("failed to read %s: %v", metricsFile, err)
+	}
+	var metrics map[string]interface{}
+	if err := json.Unmarshal(data, &metrics); err != nil {
+		return fmt.Sprintf("%s is not a JSON object: %v", metricsFile, err)
+	}
+	if message, ok := metrics["error"].(string); ok && message != "" {
+		return fmt.Sprintf("pipeline reported an error in %s: %s", metricsFile, message)
+	}
+	if status, ok := metrics["status"].(string); ok && (strings.EqualFold(status, "failed") || strings.EqualFold(status, "error")) {
+		return fmt.Sprintf("pipeline reported status %q in %s", status, metricsFile)
+	}
+	return ""
+}
+
+// WorkflowSchema declares the config object of a workflow: the fields it requires and
+// the JSON type of every field it accepts, one of string, number, integer, boolean,
+// object or array. A schema without Fields accepts any field.
+type WorkflowSchema struct {
+	Required []string          `json:"required"`
+	Fields   map[string]string `json:"fields"`
+}
+
+// Validate checks a config object against the schema.
+// Returns error listing every missing, unknown or mistyped field.
+func (s WorkflowSchema) Validate(config map[string]json.RawMessage) error {
+	var problems []string
+	var missing []string
+	for _, field := range s.Required {
+		if _, ok := config[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, "config is missing "+strings.Join(missing, ", "))
+	}
+
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if s.Fields == nil {
+			break
+		}
+		want, known := s.Fields[name]
+		if !known {
+			problems = append(problems, fmt.Sprintf("unknown config field %q", name))
+			continue
+		}
+		if got := jsonType(config[name]); got != want && !(want == "number" && got == "integer") {
+			problems = append(problems, fmt.Sprintf("config field %s must be of type %s, got %s", name, want, got))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// jsonType returns the schema type of a JSON value
+func jsonType(value json.RawMessage) string {
+	var decoded interface{}
+	if err := json.Unmarshal(value, &decoded); err != nil {
+		return "invalid JSON"
+	}
+	switch decoded.(type) {
+	case string:
+		return "string"
+	case float64:
+		if !strings.ContainsAny(string(value), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return "null"
+	}
+}
@@ -2,7 +2,9 @@ package pipeline_zen
 
 import (
 	"fmt"
+	"lumino/core/types"
 	"lumino/logger"
+	"path/filepath"
 )
 
 // Validate ensures all required fields are present and non-empty, with logging for each missing field
@@ -52,3 +54,70 @@ func (c *TorchTuneWrapperConfig) Validate() error {
 
 	return nil
 }
+
+// TorchTuneWrapperWorkflow runs the torchtune recipe named by job_config_name through
+// scripts/runners/celery-wf.sh, taking its arguments from the job config fields
+type TorchTuneWrapperWorkflow struct{}
+
+// Name implements Workflow
+func (TorchTuneWrapperWorkflow) Name() string {
+	return DefaultWorkflow
+}
+
+// ValidateConfig implements Workflow, requiring every argument of celery-wf.sh
+func (TorchTuneWrapperWorkflow) ValidateConfig(config types.JobConfig) error {
+	if len(config.Config) > 0 {
+		return fmt.Errorf("%s takes its arguments from the job config fields, not a config object", DefaultWorkflow)
+	}
+	wrapperConfig := newTorchTuneWrapperConfig(config)
+	return wrapperConfig.Validate()
+}
+
+// Command implements Workflow. Every value is passed as its own argument so config
+// values are never interpreted by the shell.
+func (TorchTuneWrapperWorkflow) Command(pipelineZenPath string, configFile string, config types.JobConfig) (string, []string, error) {
+	scriptPath := filepath.Join(pipelineZenPath, "scripts", "runners", "celery-wf.sh")
+	wrapperConfig := newTorchTuneWrapperConfig(config)
+	return "bash", []string{scriptPath, DefaultWorkflow,
+		"--job_config_name", wrapperConfig.JobConfigName,
+		"--job_id", wrapperConfig.JobID,
+		"--dataset_id", wrapperConfig.DatasetID,
+		"--batch_size", wrapperConfig.BatchSize,
+		"--shuffle", wrapperConfig.Shuffle,
+		"--num_epochs", wrapperConfig.NumEpochs,
+		"--use_lora", wrapperConfig.UseLora,
+		"--use_qlora", wrapperConfig.UseQlora,
+		"--lr", wrapperConfig.LearningRate,
+		"--seed", wrapperConfig.Seed,
+		"--num_gpus", wrapperConfig.NumGpus,
+		"--user_id", wrapperConfig.UserId,
+	}, nil
+}
+
+// DetectCompletion implements Workflow with the markers pipeline-zen writes
+func (TorchTuneWrapperWorkflow) DetectCompletion(resultsDir string) WorkflowState {
+	return DefaultWorkflowMarkers.ReadState(resultsDir)
+}
+
+// newTorchTuneWrapperConfig takes the torchtunewrapper arguments from a job config
+func newTorchTuneWrapperConfig(config types.JobConfig) TorchTuneWrapperConfig {
+	datasetID := config.DatasetID
+	if config.DatasetPath != "" {
+		// The executor prefetched the dataset, the pipeline reads the local copy instead of downloading it
+		datasetID = "file://" + filepath.ToSlash(config.DatasetPath)
+	}
+	return TorchTuneWrapperConfig{
+		JobConfigName: config.JobConfigName,
+		JobID:         config.JobID,
+		DatasetID:     datasetID,
+		BatchSize:     config.BatchSize,
+		Shuffle:       config.Shuffle,
+		NumEpochs:     config.NumEpochs,
+		UseLora:       config.UseLora,
+		UseQlora:      config.UseQlora,
+		LearningRate:  config.LearningRate,
+		Seed:          config.Seed,
+		NumGpus:       config.NumGPUs,
+		UserId:        config.UserID,
+	}
+}
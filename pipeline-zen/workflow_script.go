@@ -0,0 +1,138 @@
+package pipeline_zen
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lumino/core/types"
+	"os"
+	"path/filepath"
+)
+
+// ScriptWorkflow runs a script of the pipeline-zen checkout with the path of the job config
+// as its only argument. The script reads its arguments from the config object of the job
+// config, next to job_id, user_id, num_gpus and dataset_id, and writes its results and
+// markers to .results/<user_id>/<job_id> like the other workflows. Operators register
+// evaluation or batch inference workflows with a workflows file (see LoadScriptWorkflows),
+// or in code, for example:
+//
+//	RegisterWorkflow(ScriptWorkflow{
+//		WorkflowName: "evaluation",
+//		Script:       "scripts/runners/evaluate.sh",
+//		Schema:       WorkflowSchema{Required: []string{"model"}, Fields: map[string]string{"model": "string"}},
+//	})
+type ScriptWorkflow struct {
+	WorkflowName string          `json:"name"`
+	Script       string          `json:"script"`  // path of the script, relative to the pipeline-zen directory
+	Schema       WorkflowSchema  `json:"schema"`  // schema of the config object
+	Markers      WorkflowMarkers `json:"markers"` // defaults to DefaultWorkflowMarkers
+}
+
+// schemaTypes are the JSON types a WorkflowSchema declares fields with
+var schemaTypes = map[string]bool{"string": true, "number": true, "integer": true, "boolean": true, "object": true, "array": true}
+
+// Name implements Workflow
+func (w ScriptWorkflow) Name() string {
+	return w.WorkflowName
+}
+
+// ValidateConfig implements Workflow, checking the config object against the schema
+func (w ScriptWorkflow) ValidateConfig(config types.JobConfig) error {
+	if config.Config == nil {
+		return fmt.Errorf("%s needs a config object", w.WorkflowName)
+	}
+	return w.Schema.Validate(config.Config)
+}
+
+// Command implements Workflow, passing the job config at the path the runtime sees it
+func (w ScriptWorkflow) Command(pipelineZenPath string, configFile string, config types.JobConfig) (string, []string, error) {
+	return "bash", []string{filepath.Join(pipelineZenPath, w.Script), configFile}, nil
+}
+
+// DetectCompletion implements Workflow with the markers of the workflow
+func (w ScriptWorkflow) DetectCompletion(resultsDir string) WorkflowState {
+	markers := w.Markers
+	if markers.Finished == "" {
+		markers = DefaultWorkflowMarkers
+	}
+	return markers.ReadState(resultsDir)
+}
+
+// validate checks that the workflow can be registered next to the built-in workflows
+func (w ScriptWorkflow) validate() error {
+	if w.WorkflowName == "" {
+		return errors.New("workflow is missing name")
+	}
+	if w.WorkflowName == DefaultWorkflow {
+		return fmt.Errorf("workflow %s is built in and cannot be replaced", DefaultWorkflow)
+	}
+	if w.Script == "" || !filepath.IsLocal(w.Script) {
+		return fmt.Errorf("workflow %s: script must be a path inside the pipeline-zen directory, got %q", w.WorkflowName, w.Script)
+	}
+	for field, fieldType := range w.Schema.Fields {
+		if !schemaTypes[fieldType] {
+			return fmt.Errorf("workflow %s: config field %s has unknown type %q", w.WorkflowName, field, fieldType)
+		}
+	}
+	for _, field := range w.Schema.Required {
+		if _, ok := w.Schema.Fields[field]; w.Schema.Fields != nil && !ok {
+			return fmt.Errorf("workflow %s: required config field %s is not declared in fields", w.WorkflowName, field)
+		}
+	}
+	return nil
+}
+
+// LoadScriptWorkflows reads the script workflows of a workflows file, a JSON array of
+// workflows such as:
+//
+//	[{
+//		"name": "evaluation",
+//		"script": "scripts/runners/evaluate.sh",
+//		"schema": {"required": ["model"], "fields": {"model": "string", "max_samples": "integer"}},
+//		"markers": {"started": ".started", "finished": ".finished", "failure": [".failed"]}
+//	}]
+//
+// Returns error if the file is unreadable, holds unknown keys, or declares a workflow twice,
+// without a name or script, with a script outside the pipeline-zen directory, or replacing
+// torchtunewrapper.
+func LoadScriptWorkflows(workflowsFile string) ([]ScriptWorkflow, error) {
+	data, err := os.ReadFile(workflowsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflows file: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var scriptWorkflows []ScriptWorkflow
+	if err := decoder.Decode(&scriptWorkflows); err != nil {
+		return nil, fmt.Errorf("failed to parse workflows file %s: %w", workflowsFile, err)
+	}
+
+	names := make(map[string]bool)
+	for _, workflow := range scriptWorkflows {
+		if err := workflow.validate(); err != nil {
+			return nil, fmt.Errorf("invalid workflows file %s: %w", workflowsFile, err)
+		}
+		if names[workflow.WorkflowName] {
+			return nil, fmt.Errorf("invalid workflows file %s: workflow %s is declared twice", workflowsFile, workflow.WorkflowName)
+		}
+		names[workflow.WorkflowName] = true
+	}
+	return scriptWorkflows, nil
+}
+
+// RegisterScriptWorkflows registers the script workflows of a workflows file and returns
+// their names. Nothing is registered if any workflow of the file is invalid.
+// Returns error if the workflows file cannot be loaded.
+func RegisterScriptWorkflows(workflowsFile string) ([]string, error) {
+	scriptWorkflows, err := LoadScriptWorkflows(workflowsFile)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(scriptWorkflows))
+	for _, workflow := range scriptWorkflows {
+		RegisterWorkflow(workflow)
+		names = append(names, workflow.WorkflowName)
+	}
+	return names, nil
+}
@@ -0,0 +1,380 @@
+//go:build !windows
+
+package pipeline_zen
+
+import (
+	"context"
+	"encoding/json"
+	"lumino/core/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testScriptWorkflow is a script workflow writing its arguments to args.txt
+var testScriptWorkflow = ScriptWorkflow{
+	WorkflowName: "evaluation",
+	Script:       "scripts/runners/evaluate.sh",
+	Schema: WorkflowSchema{
+		Required: []string{"model"},
+		Fields:   map[string]string{"model": "string", "max_samples": "integer"},
+	},
+	Markers: WorkflowMarkers{Finished: "DONE", Failure: []string{"FAILED"}},
+}
+
+// registerTestScriptWorkflow registers testScriptWorkflow until the test ends
+func registerTestScriptWorkflow(t *testing.T) {
+	RegisterWorkflow(testScriptWorkflow)
+	t.Cleanup(func() {
+		workflowsMutex.Lock()
+		delete(workflows, testScriptWorkflow.Name())
+		workflowsMutex.Unlock()
+	})
+}
+
+// Tests looking up workflows: the default for an empty name, registered workflows,
+// and unknown workflows naming the registered ones
+func TestWorkflowFor(t *testing.T) {
+	registerTestScriptWorkflow(t)
+
+	workflow, err := WorkflowFor("")
+	assert.NoError(t, err)
+	assert.Equal(t, TorchTuneWrapperWorkflow{}, workflow)
+
+	workflow, err = WorkflowFor("evaluation")
+	assert.NoError(t, err)
+	assert.Equal(t, testScriptWorkflow, workflow)
+
+	_, err = WorkflowFor("distillation")
+	assert.EqualError(t, err, `unknown workflow "distillation", expected one of evaluation, torchtunewrapper`)
+}
+
+// Tests checking config objects against a workflow schema with cases:
+// 1. A config with every required field of the right type
+// 2. Missing required fields
+// 3. Unknown fields and fields of the wrong type
+// 4. Integers accepted as numbers and a schema without fields accepting anything
+func TestWorkflowSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  WorkflowSchema
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			schema: testScriptWorkflow.Schema,
+			config: `{"model": "llama3.1-8b", "max_samples": 500}`,
+		},
+		{
+			name:    "missing field",
+			schema:  testScriptWorkflow.Schema,
+			config:  `{"max_samples": 500}`,
+			wantErr: "config is missing model",
+		},
+		{
+			name:    "unknown and mistyped fields",
+			schema:  testScriptWorkflow.Schema,
+			config:  `{"model": 3, "max_samples": 1.5, "top_k": 5}`,
+			wantErr: "config field max_samples must be of type integer, got number; config field model must be of type string, got integer; unknown config field \"top_k\"",
+		},
+		{
+			name:   "integer as number",
+			schema: WorkflowSchema{Fields: map[string]string{"temperature": "number", "tags": "array", "sampling": "object", "greedy": "boolean"}},
+			config: `{"temperature": 1, "tags": ["a"], "sampling": {"top_p": 0.9}, "greedy": false}`,
+		},
+		{
+			name:   "schema without fields",
+			schema: WorkflowSchema{Required: []string{"model"}},
+			config: `{"model": "llama3.1-8b", "anything": null}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config map[string]json.RawMessage
+			assert.NoError(t, json.Unmarshal([]byte(tt.config), &config))
+			err := tt.schema.Validate(config)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// Tests reading the state of a workflow from the markers in its results directory
+// with the default markers and the markers of a script workflow
+func TestWorkflowMarkersReadState(t *testing.T) {
+	resultsDir := t.TempDir()
+	assert.Equal(t, WorkflowState{}, TorchTuneWrapperWorkflow{}.DetectCompletion(resultsDir))
+
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".started"), nil, 0644))
+	assert.Equal(t, WorkflowState{Started: true}, TorchTuneWrapperWorkflow{}.DetectCompletion(resultsDir))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(resultsDir, "checkpoints"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "checkpoints", "adapter.safetensors"), []byte("weights"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".finished"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, ".error"), []byte("dataset not found\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "metrics.json"), []byte(`{"status": "failed"}`), 0644))
+	assert.Equal(t, WorkflowState{
+		Started:       true,
+		Finished:      true,
+		FailureMarker: ".error",
+		FailureReason: "dataset not found",
+		HasResults:    true,
+		MetricsError:  `pipeline reported status "failed" in metrics.json`,
+	}, TorchTuneWrapperWorkflow{}.DetectCompletion(resultsDir))
+
+	// The script workflow marks its end with DONE and does not report metrics
+	assert.Equal(t, WorkflowState{HasResults: true}, testScriptWorkflow.DetectCompletion(resultsDir))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "DONE"), nil, 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(resultsDir, "FAILED"), []byte("model not found"), 0644))
+	assert.Equal(t, WorkflowState{Finished: true, FailureMarker: "FAILED", FailureReason: "model not found", HasResults: true},
+		testScriptWorkflow.DetectCompletion(resultsDir))
+
+	// Markers alone are no results
+	markersOnly := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(markersOnly, "DONE"), nil, 0644))
+	assert.Equal(t, WorkflowState{Finished: true}, testScriptWorkflow.DetectCompletion(markersOnly))
+}
+
+// Tests the checks of a results metrics file
+func TestCheckMetrics(t *testing.T) {
+	dir := t.TempDir()
+	assert.Empty(t, checkMetrics(dir, "metrics.json"))
+
+	for content, want := range map[string]string{
+		`{"loss": 0.42, "status": "completed"}`: "",
+		`{"error": "NaN loss at step 120"}`:     "pipeline reported an error in metrics.json: NaN loss at step 120",
+		`{"status": "ERROR"}`:                   `pipeline reported status "ERROR" in metrics.json`,
+		`[1, 2]`:                                "metrics.json is not a JSON object",
+		`{"loss":`:                              "metrics.json is not a JSON object",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "metrics.json"), []byte(content), 0644))
+		got := checkMetrics(dir, "metrics.json")
+		if want == "" {
+			assert.Empty(t, got, content)
+		} else {
+			assert.Contains(t, got, want, content)
+		}
+	}
+}
+
+// Tests starting the workflow selected by a job config with cases:
+// 1. The torchtunewrapper workflow passes the config fields to celery-wf.sh, and the local copy
+// of a dataset prefetched by the executor instead of its remote URI
+// 2. A script workflow gets the path of the job config
+// 3. Configs failing the schema of their workflow or naming an unknown workflow are rejected
+func TestStartWorkflow(t *testing.T) {
+	registerTestScriptWorkflow(t)
+	pipelineZenPath := t.TempDir()
+	argsFile := filepath.Join(pipelineZenPath, "args.txt")
+	for _, script := range []string{"scripts/runners/celery-wf.sh", testScriptWorkflow.Script} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(pipelineZenPath, script)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(pipelineZenPath, script), []byte(`echo "$@" > args.txt`+"\n"), 0755))
+	}
+
+	torchTuneConfig := types.JobConfig{Workflow: DefaultWorkflow, JobConfigName: "llm_dummy", JobID: "13", DatasetID: "gs://bucket/dataset",
+		BatchSize: "20", Shuffle: "true", NumEpochs: "1", UseLora: "true", UseQlora: "false", LearningRate: "0.01", Seed: "42",
+		NumGPUs: "1", UserID: "0x4118CFD00dD5e8CED96e0ff8061F56F2d155e83B"}
+
+	tests := []struct {
+		name     string
+		config   types.JobConfig
+		wantArgs string
+		wantErr  string
+	}{
+		{
+			name:   "torchtunewrapper",
+			config: torchTuneConfig,
+			wantArgs: "torchtunewrapper --job_config_name llm_dummy --job_id 13 --dataset_id gs://bucket/dataset --batch_size 20 " +
+				"--shuffle true --num_epochs 1 --use_lora true --use_qlora false --lr 0.01 --seed 42 --num_gpus 1 " +
+				"--user_id 0x4118CFD00dD5e8CED96e0ff8061F56F2d155e83B",
+		},
+		{
+			name: "torchtunewrapper with a prefetched dataset",
+			config: func() types.JobConfig {
+				config := torchTuneConfig
+				config.DatasetPath = "/root/.lumino/.jobs/13/dataset/dataset.jsonl"
+				return config
+			}(),
+			wantArgs: "torchtunewrapper --job_config_name llm_dummy --job_id 13 --dataset_id file:///root/.lumino/.jobs/13/dataset/dataset.jsonl " +
+				"--batch_size 20 --shuffle true --num_epochs 1 --use_lora true --use_qlora false --lr 0.01 --seed 42 --num_gpus 1 " +
+				"--user_id 0x4118CFD00dD5e8CED96e0ff8061F56F2d155e83B",
+		},
+		{
+			name:   "script workflow",
+			config: types.JobConfig{Workflow: "evaluation", JobID: "14", NumGPUs: "1", Config: map[string]json.RawMessage{"model": json.RawMessage(`"llama3.1-8b"`)}},
+		},
+		{
+			name:    "torchtunewrapper config missing fields",
+			config:  types.JobConfig{JobConfigName: "llm_dummy", JobID: "13"},
+			wantErr: "invalid torchtunewrapper config: missing required fields",
+		},
+		{
+			name:    "script workflow config failing the schema",
+			config:  types.JobConfig{Workflow: "evaluation", JobID: "14", Config: map[string]json.RawMessage{"max_samples": json.RawMessage(`5`)}},
+			wantErr: "invalid evaluation config: config is missing model",
+		},
+		{
+			name:    "unknown workflow",
+			config:  types.JobConfig{Workflow: "distillation", JobID: "15"},
+			wantErr: `unknown workflow "distillation"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(argsFile)
+			configFile := filepath.Join(t.TempDir(), "config.json")
+			data, err := json.Marshal(tt.config)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(configFile, data, 0644))
+
+			process, err := StartWorkflow(context.Background(), ProcessRuntime{}, pipelineZenPath, configFile, ProcessOptions{})
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			result := process.Wait()
+			assert.True(t, result.Succeeded(), result.Reason())
+
+			args, err := os.ReadFile(argsFile)
+			assert.NoError(t, err)
+			if tt.config.Workflow == DefaultWorkflow {
+				assert.Equal(t, tt.wantArgs, strings.TrimSpace(string(args)))
+			} else {
+				assert.Equal(t, configFile, strings.TrimSpace(string(args)))
+			}
+		})
+	}
+}
+
+// jobDirRuntime runs workflows on the host while reporting the job directory at JobDir,
+// like a runtime mounting it elsewhere
+type jobDirRuntime struct {
+	ProcessRuntime
+	JobDir string
+}
+
+func (r jobDirRuntime) JobDirPath(hostPath string) string {
+	return r.JobDir
+}
+
+// Tests that workflows get the job config and the prefetched dataset at the paths their
+// runtime sees the job directory at
+func TestStartWorkflowResolvesJobPaths(t *testing.T) {
+	registerTestScriptWorkflow(t)
+	pipelineZenPath := t.TempDir()
+	argsFile := filepath.Join(pipelineZenPath, "args.txt")
+	for _, script := range []string{"scripts/runners/celery-wf.sh", testScriptWorkflow.Script} {
+		assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(pipelineZenPath, script)), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(pipelineZenPath, script), []byte(`echo "$@" > args.txt`+"\n"), 0755))
+	}
+	jobDir := t.TempDir()
+	configFile := filepath.Join(jobDir, "config.json")
+	runtime := jobDirRuntime{JobDir: "/lumino/job"}
+
+	tests := []struct {
+		name     string
+		config   types.JobConfig
+		wantArgs string
+	}{
+		{
+			name:     "script workflow",
+			config:   types.JobConfig{Workflow: "evaluation", JobID: "14", Config: map[string]json.RawMessage{"model": json.RawMessage(`"llama3.1-8b"`)}},
+			wantArgs: "/lumino/job/config.json",
+		},
+		{
+			name: "torchtunewrapper with a prefetched dataset",
+			config: types.JobConfig{Workflow: DefaultWorkflow, JobConfigName: "llm_dummy", JobID: "13", DatasetID: "gs://bucket/dataset",
+				DatasetPath: filepath.Join(jobDir, "dataset", "dataset.jsonl"), BatchSize: "20", Shuffle: "true", NumEpochs: "1",
+				UseLora: "true", UseQlora: "false", LearningRate: "0.01", Seed: "42", NumGPUs: "1", UserID: "0xabc"},
+			wantArgs: "torchtunewrapper --job_config_name llm_dummy --job_id 13 --dataset_id file:///lumino/job/dataset/dataset.jsonl --batch_size 20 --shuffle true --num_epochs 1 " +
+				"--use_lora true --use_qlora false --lr 0.01 --seed 42 --num_gpus 1 --user_id 0xabc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.config)
+			assert.NoError(t, err)
+			assert.NoError(t, os.WriteFile(configFile, data, 0644))
+			os.Remove(argsFile)
+
+			process, err := StartWorkflow(context.Background(), runtime, pipelineZenPath, configFile, ProcessOptions{})
+			assert.NoError(t, err)
+			result := process.Wait()
+			assert.True(t, result.Succeeded(), result.Reason())
+
+			args, err := os.ReadFile(argsFile)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantArgs, strings.TrimSpace(string(args)))
+		})
+	}
+}
+
+// Tests registering the script workflows of a workflows file with cases:
+// 1. The workflows of a valid file are registered with their schema and markers
+// 2. Unreadable files, unknown keys and invalid workflows are rejected without registering anything
+func TestRegisterScriptWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkflows := func(content string) string {
+		file := filepath.Join(dir, "workflows.json")
+		assert.NoError(t, os.WriteFile(file, []byte(content), 0644))
+		return file
+	}
+	t.Cleanup(func() {
+		workflowsMutex.Lock()
+		delete(workflows, "evaluation")
+		delete(workflows, "inference")
+		workflowsMutex.Unlock()
+	})
+
+	names, err := RegisterScriptWorkflows(writeWorkflows(`[
+		{"name": "evaluation", "script": "scripts/runners/evaluate.sh",
+		 "schema": {"required": ["model"], "fields": {"model": "string", "max_samples": "integer"}},
+		 "markers": {"finished": "DONE", "failure": ["FAILED"]}},
+		{"name": "inference", "script": "scripts/runners/infer.sh"}
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"evaluation", "inference"}, names)
+	workflow, err := WorkflowFor("evaluation")
+	assert.NoError(t, err)
+	assert.Equal(t, testScriptWorkflow, workflow)
+	workflow, err = WorkflowFor("inference")
+	assert.NoError(t, err)
+	assert.Equal(t, ScriptWorkflow{WorkflowName: "inference", Script: "scripts/runners/infer.sh"}, workflow)
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown key", content: `[{"name": "distillation", "script": "a.sh", "command": "rm"}]`, wantErr: `json: unknown field "command"`},
+		{name: "missing name", content: `[{"script": "a.sh"}]`, wantErr: "workflow is missing name"},
+		{name: "built-in workflow", content: `[{"name": "torchtunewrapper", "script": "a.sh"}]`, wantErr: "workflow torchtunewrapper is built in and cannot be replaced"},
+		{name: "script outside the checkout", content: `[{"name": "distillation", "script": "../a.sh"}]`, wantErr: `workflow distillation: script must be a path inside the pipeline-zen directory, got "../a.sh"`},
+		{name: "absolute script", content: `[{"name": "distillation", "script": "/bin/a.sh"}]`, wantErr: `workflow distillation: script must be a path inside the pipeline-zen directory, got "/bin/a.sh"`},
+		{name: "unknown field type", content: `[{"name": "distillation", "script": "a.sh", "schema": {"fields": {"model": "text"}}}]`, wantErr: `workflow distillation: config field model has unknown type "text"`},
+		{name: "undeclared required field", content: `[{"name": "distillation", "script": "a.sh", "schema": {"required": ["model"], "fields": {"seed": "integer"}}}]`, wantErr: "workflow distillation: required config field model is not declared in fields"},
+		{name: "declared twice", content: `[{"name": "distillation", "script": "a.sh"}, {"name": "distillation", "script": "b.sh"}]`, wantErr: "workflow distillation is declared twice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := RegisterScriptWorkflows(writeWorkflows(tt.content))
+			assert.ErrorContains(t, err, tt.wantErr)
+			_, err = WorkflowFor("distillation")
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = RegisterScriptWorkflows(filepath.Join(dir, "missing.json"))
+	assert.ErrorContains(t, err, "failed to read workflows file")
+}
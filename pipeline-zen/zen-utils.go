@@ -2,7 +2,6 @@ package pipeline_zen
 
 import (
 	"bufio"
-	"encoding/json"
 	"fmt"
 	"io"
 	"lumino/logger"
@@ -108,55 +107,3 @@ func InstallDeps(pipelineZenPath string) error {
 	log.Info("Installation completed successfully")
 	return nil
 }
-
-// RunTorchTuneWrapper runs the torchtunewrapper workflow with the provided configuration
-func RunTorchTuneWrapper(pipelineZenPath string, configFile string) (string, error) {
-	// Read the config file
-	configData, err := os.ReadFile(configFile)
-	if err != nil {
-		logger.Error("Error reading config file: ", err)
-		return "", err
-	}
-
-	// Unmarshal the JSON data into the TorchTuneWrapperConfig struct
-	var config TorchTuneWrapperConfig
-	err = json.Unmarshal(configData, &config)
-	if err != nil {
-		logger.Error("Error parsing JSON config: ", err)
-		return "", err
-	}
-
-	// Validate required fields
-	if err := config.Validate(); err != nil {
-		logger.Error("Invalid config: ", err)
-		return "", err
-	}
-
-	scriptPath := filepath.Join(pipelineZenPath, "scripts", "runners", "celery-wf.sh")
-	if _, err := os.Stat(scriptPath); os.IsNotExist(err) {
-		logger.Error("Script not found at path: ", scriptPath)
-		return "", fmt.Errorf("script not found")
-	}
-
-	// Build the bash command string without the 'cd' and pipe
-	// command := fmt.Sprintf("./scripts/runners/celery-wf.sh torchtunewrapper --job_config_name %s --job_id %s --dataset_id %s --batch_size %s --shuffle %s --num_epochs %s --use_lora %s --use_qlora %s --lr %s --seed %s --num_gpus %s",
-	// 	config.JobConfigName, config.JobID, config.DatasetID, config.BatchSize, config.Shuffle, config.NumEpochs, config.UseLora, config.UseQlora, config.LearningRate, config.Seed, config.NumGpus)
-	command := fmt.Sprintf("%s torchtunewrapper --job_config_name %s --job_id %s --dataset_id %s --batch_size %s --shuffle %s --num_epochs %s --use_lora %s --use_qlora %s --lr %s --seed %s --num_gpus %s --user_id %s",
-		scriptPath, config.JobConfigName, config.JobID, config.DatasetID, config.BatchSize, config.Shuffle, config.NumEpochs, config.UseLora, config.UseQlora, config.LearningRate, config.Seed, config.NumGpus, config.UserId)
-
-	logger.Info("Running command: ", command)
-
-	// Set working directory to 'pipeline-zen' folder
-	cmd := exec.Command("bash", "-c", command)
-	cmd.Dir = pipelineZenPath
-
-	// Execute the command
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		logger.Error("Error running torchtunewrapper workflow: ", err)
-		return "", err
-	}
-
-	logger.Info("Command output: ", string(output))
-	return string(output), nil
-}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// datasetCacheIndexFile is the file of the cache directory mapping URIs to cached content
+const datasetCacheIndexFile = "index.json"
+
+// datasetCacheBlobDir is the directory of the cache holding the content, named by its SHA-256
+const datasetCacheBlobDir = "sha256"
+
+// DatasetCacheEntry is a dataset held by a DatasetCache
+type DatasetCacheEntry struct {
+	Digest   string    `json:"digest"` // hex SHA-256 of the content
+	Size     int64     `json:"size"`
+	URIs     []string  `json:"uris"` // URIs the content was fetched from
+	LastUsed time.Time `json:"last_used"`
+}
+
+// datasetCacheIndex is the content of the index file
+type datasetCacheIndex struct {
+	Entries []DatasetCacheEntry `json:"entries"`
+}
+
+// DatasetCache keeps the datasets downloaded for jobs under a directory, stored once per
+// content under its SHA-256. The index maps every URI fetched to its content and records
+// when the content was last used, so that the least recently used datasets are evicted
+// once the cache outgrows MaxSize. A URI is downloaded once: its content is taken to not
+// change while it is cached.
+type DatasetCache struct {
+	Dir     string
+	MaxSize int64     // in bytes, 0 disables eviction
+	Policy  URIPolicy // where datasets are downloaded from
+
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewDatasetCache returns the cache of the directory, which is created on the first fetch
+func NewDatasetCache(dir string, maxSize int64, policy URIPolicy) *DatasetCache {
+	return &DatasetCache{Dir: dir, MaxSize: maxSize, Policy: policy, now: time.Now}
+}
+
+// Path returns the file holding the content of an entry
+func (c *DatasetCache) Path(entry DatasetCacheEntry) string {
+	return filepath.Join(c.Dir, datasetCacheBlobDir, entry.Digest)
+}
+
+// Fetch returns the cached dataset of uri, downloading it on a miss. This function:
+// 1. Returns the entry holding the content of uri if it is cached, marking it used
+// 2. Otherwise downloads the dataset with OpenURI under the Policy of the cache while hashing it, and stores the content
+// unless the cache already holds it for another URI
+// 3. Evicts the least recently used datasets other than the fetched one until the cache
+// fits into MaxSize
+// Returns the entry and whether it was cached already, or error if the dataset cannot be
+// downloaded or the cache cannot be written.
+func (c *DatasetCache) Fetch(ctx context.Context, uri string) (DatasetCacheEntry, bool, error) {
+	c.mu.Lock()
+	index, err := c.loadIndex()
+	if err != nil {
+		c.mu.Unlock()
+		return DatasetCacheEntry{}, false, err
+	}
+	if i := index.find(uri); i >= 0 {
+		index.Entries[i].LastUsed = c.now().UTC()
+		entry := index.Entries[i]
+		err := c.saveIndex(index)
+		c.mu.Unlock()
+		return entry, true, err
+	}
+	c.mu.Unlock()
+
+	// Download without holding the lock so that other jobs can use the cache meanwhile
+	tmpPath, digest, size, err := c.download(ctx, uri)
+	if err != nil {
+		return DatasetCacheEntry{}, false, err
+	}
+	defer os.Remove(tmpPath)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index, err = c.loadIndex()
+	if err != nil {
+		return DatasetCacheEntry{}, false, err
+	}
+	now := c.now().UTC()
+	i := index.findDigest(digest)
+	if i < 0 {
+		if err := os.Rename(tmpPath, filepath.Join(c.Dir, datasetCacheBlobDir, digest)); err != nil {
+			return DatasetCacheEntry{}, false, fmt.Errorf("failed to store dataset %s: %w", uri, err)
+		}
+		index.Entries = append(index.Entries, DatasetCacheEntry{Digest: digest, Size: size})
+		i = len(index.Entries) - 1
+	}
+	if index.find(uri) < 0 {
+		index.Entries[i].URIs = append(index.Entries[i].URIs, uri)
+	}
+	index.Entries[i].LastUsed = now
+	entry := index.Entries[i]
+
+	if err := c.evict(&index, digest); err != nil {
+		return entry, false, err
+	}
+	return entry, false, c.saveIndex(index)
+}
+
+// Link makes the content of an entry available at target. The content is hard-linked, so
+// that evicting it later does not pull it away from the job using it, or copied where the
+// cache and target are on different filesystems.
+// Returns error if target cannot be created.
+func (c *DatasetCache) Link(entry DatasetCacheEntry, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to replace %s: %w", target, err)
+	}
+	if err := os.Link(c.Path(entry), target); err == nil {
+		return nil
+	}
+
+	source, err := os.Open(c.Path(entry))
+	if err != nil {
+		return fmt.Errorf("failed to open cached dataset %s: %w", entry.Digest, err)
+	}
+	defer source.Close()
+	store := NewLocalStore(filepath.Dir(target))
+	return store.Put(context.Background(), filepath.Base(target), source, entry.Size)
+}
+
+// download writes the dataset at uri to a temporary file of the cache while hashing it.
+// Returns the file, the hex SHA-256 and size of the content.
+func (c *DatasetCache) download(ctx context.Context, uri string) (string, string, int64, error) {
+	blobDir := filepath.Join(c.Dir, datasetCacheBlobDir)
+	if err := os.MkdirAll(blobDir, 0755); err != nil {
+		return "", "", 0, fmt.Errorf("failed to create dataset cache: %w", err)
+	}
+
+	reader, err := OpenURI(ctx, uri, c.Policy)
+	if err != nil {
+		return "", "", 0, err
+	}
+	defer reader.Close()
+
+	tmp, err := os.CreateTemp(blobDir, ".download-*")
+	if err != nil {
+		return "", "", 0, fmt.Errorf("failed to create dataset file: %w", err)
+	}
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), reader)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", "", 0, fmt.Errorf("failed to download dataset %s: %w", uri, err)
+	}
+	return tmp.Name(), hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// evict removes the least recently used entries other than keep until the cache fits into MaxSize
+func (c *DatasetCache) evict(index *datasetCacheIndex, keep string) error {
+	if c.MaxSize <= 0 {
+		return nil
+	}
+	var total int64
+	for _, entry := range index.Entries {
+		total += entry.Size
+	}
+	sort.SliceStable(index.Entries, func(i, j int) bool {
+		return index.Entries[i].LastUsed.Before(index.Entries[j].LastUsed)
+	})
+
+	kept := index.Entries[:0]
+	for _, entry := range index.Entries {
+		if total <= c.MaxSize || entry.Digest == keep {
+			kept = append(kept, entry)
+			continue
+		}
+		if err := os.Remove(c.Path(entry)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict dataset %s: %w", entry.Digest, err)
+		}
+		total -= entry.Size
+	}
+	index.Entries = kept
+	return nil
+}
+
+// loadIndex reads the index and reconciles it with the stored content: entries whose
+// content is gone are dropped, and content missing from the index, e.g. after a crash
+// between storing it and writing the index, is added so that it can be evicted.
+// A corrupt index is rebuilt from the stored content.
+func (c *DatasetCache) loadIndex() (datasetCacheIndex, error) {
+	var index datasetCacheIndex
+	data, err := os.ReadFile(filepath.Join(c.Dir, datasetCacheIndexFile))
+	if err != nil && !os.IsNotExist(err) {
+		return index, fmt.Errorf("failed to read dataset cache index: %w", err)
+	}
+	if err == nil && json.Unmarshal(data, &index) != nil {
+		index = datasetCacheIndex{}
+	}
+
+	files, err := os.ReadDir(filepath.Join(c.Dir, datasetCacheBlobDir))
+	if err != nil && !os.IsNotExist(err) {
+		return index, fmt.Errorf("failed to read dataset cache: %w", err)
+	}
+	stored := make(map[string]os.FileInfo, len(files))
+	for _, file := range files {
+		if info, err := file.Info(); err == nil && info.Mode().IsRegular() && len(file.Name()) == sha256.Size*2 {
+			stored[file.Name()] = info
+		}
+	}
+
+	entries := index.Entries[:0]
+	for _, entry := range index.Entries {
+		if info, ok := stored[entry.Digest]; ok && info.Size() == entry.Size {
+			entries = append(entries, entry)
+			delete(stored, entry.Digest)
+		}
+	}
+	for digest, info := range stored {
+		entries = append(entries, DatasetCacheEntry{Digest: digest, Size: info.Size(), LastUsed: info.ModTime().UTC()})
+	}
+	index.Entries = entries
+	return index, nil
+}
+
+// saveIndex replaces the index file, writing it to a temporary file first
+func (c *DatasetCache) saveIndex(index datasetCacheIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dataset cache index: %w", err)
+	}
+	if err := NewLocalStore(c.Dir).Put(context.Background(), datasetCacheIndexFile, bytes.NewReader(data), int64(len(data))); err != nil {
+		return fmt.Errorf("failed to write dataset cache index: %w", err)
+	}
+	return nil
+}
+
+// find returns the position of the entry fetched from uri, or -1
+func (index datasetCacheIndex) find(uri string) int {
+	for i, entry := range index.Entries {
+		for _, entryURI := range entry.URIs {
+			if entryURI == uri {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findDigest returns the position of the entry holding the content of digest, or -1
+func (index datasetCacheIndex) findDigest(digest string) int {
+	for i, entry := range index.Entries {
+		if entry.Digest == digest {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,421 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultS3Region is the region requests are signed for when none is configured
+const DefaultS3Region = "us-east-1"
+
+// unsignedPayload is the payload hash sent with uploads streamed without hashing them first
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// DefaultS3PartSize is the size of the parts objects larger than it are uploaded in
+const DefaultS3PartSize = 64 << 20
+
+// maxS3Parts is the number of parts S3 accepts for one multipart upload
+const maxS3Parts = 10000
+
+// S3Config locates a bucket of an S3-compatible service
+type S3Config struct {
+	Endpoint        string // base URL of the service, defaults to the AWS endpoint of the region
+	Region          string // region requests are signed for, defaults to DefaultS3Region
+	Bucket          string
+	Prefix          string // key prefix of every object, without leading or trailing slash
+	AccessKeyID     string // requests are sent unsigned when empty
+	SecretAccessKey string
+	SessionToken    string       // optional token of temporary credentials
+	HTTPClient      *http.Client // client sending the requests, defaults to http.DefaultClient
+}
+
+// WithEnvCredentials returns the config with the credentials of the AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables
+func (c S3Config) WithEnvCredentials() S3Config {
+	c.AccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	c.SecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	c.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	return c
+}
+
+// S3Store stores objects in a bucket of an S3-compatible service. Objects are
+// addressed path-style, <endpoint>/<bucket>/<key>, which AWS, MinIO and most
+// S3-compatible services accept, and requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+	partSize int64 // objects larger than it are uploaded in parts, S3 caps a single PUT at 5 GiB
+}
+
+// NewS3Store returns the store of the bucket.
+// Returns error if the region is not a region name or the endpoint is not an http(s) URL.
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Region == "" {
+		config.Region = DefaultS3Region
+	}
+	for _, c := range config.Region {
+		if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+			return nil, fmt.Errorf("invalid S3 region %q", config.Region)
+		}
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+	config.Prefix = strings.Trim(config.Prefix, "/")
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", config.Endpoint)
+	}
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &S3Store{config: config, endpoint: endpoint, client: client, now: time.Now, partSize: DefaultS3PartSize}, nil
+}
+
+// Put uploads the object with a single PUT request, or with a multipart upload when it
+// is larger than the part size.
+// Returns error if the service rejects the upload.
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+	if size > s.partSize {
+		return s.putMultipart(ctx, key, r, size)
+	}
+	body := r
+	if size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), body)
+	if err != nil {
+		return fmt.Errorf("failed to create request for %s: %w", key, err)
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("failed to upload %s: %w", key, responseError(resp))
+	}
+	return nil
+}
+
+// completedPart is a part of the CompleteMultipartUpload request
+type completedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+// putMultipart uploads the object in parts. This function:
+// 1. Initiates a multipart upload
+// 2. Uploads the parts in order, growing the part size so that no more than
+// maxS3Parts are needed
+// 3. Completes the upload with the ETags of the parts
+// The upload is aborted when any step fails so the service discards the stored parts.
+// Returns error if the service rejects any step of the upload.
+func (s *S3Store) putMultipart(ctx context.Context, key string, r io.Reader, size int64) error {
+	uploadID, err := s.createMultipartUpload(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+
+	partSize := s.partSize
+	if minPartSize := (size + maxS3Parts - 1) / maxS3Parts; partSize < minPartSize {
+		partSize = minPartSize
+	}
+	var parts []completedPart
+	for offset, number := int64(0), 1; offset < size; offset, number = offset+partSize, number+1 {
+		length := min(partSize, size-offset)
+		etag, err := s.uploadPart(ctx, key, uploadID, number, io.LimitReader(r, length), length)
+		if err != nil {
+			s.abortMultipartUpload(key, uploadID)
+			return fmt.Errorf("failed to upload part %d of %s: %w", number, key, err)
+		}
+		parts = append(parts, completedPart{PartNumber: number, ETag: etag})
+	}
+
+	if err := s.completeMultipartUpload(ctx, key, uploadID, parts); err != nil {
+		s.abortMultipartUpload(key, uploadID)
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// createMultipartUpload initiates a multipart upload of the object and returns its ID
+func (s *S3Store) createMultipartUpload(ctx context.Context, key string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.objectURL(key)+"?uploads", http.NoBody)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", responseError(resp)
+	}
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&result); err != nil || result.UploadID == "" {
+		return "", fmt.Errorf("invalid response to initiating a multipart upload")
+	}
+	return result.UploadID, nil
+}
+
+// uploadPart uploads a part of a multipart upload and returns its ETag
+func (s *S3Store) uploadPart(ctx context.Context, key string, uploadID string, number int, r io.Reader, size int64) (string, error) {
+	query := url.Values{"partNumber": {strconv.Itoa(number)}, "uploadId": {uploadID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key)+"?"+query.Encode(), r)
+	if err != nil {
+		return "", err
+	}
+	req.ContentLength = size
+
+	resp, err := s.do(req, unsignedPayload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", responseError(resp)
+	}
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		return "", fmt.Errorf("missing ETag of the uploaded part")
+	}
+	return etag, nil
+}
+
+// completeMultipartUpload assembles the uploaded parts into the object. The service may
+// report a failure with an error document after answering 200 OK, which is checked too.
+func (s *S3Store) completeMultipartUpload(ctx context.Context, key string, uploadID string, parts []completedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	query := url.Values{"uploadId": {uploadID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.objectURL(key)+"?"+query.Encode(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/xml")
+	payloadHash := sha256.Sum256(body)
+
+	resp, err := s.do(req, hex.EncodeToString(payloadHash[:]))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return responseError(resp)
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	var document struct {
+		XMLName xml.Name
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(data, &document) == nil && document.XMLName.Local == "Error" {
+		return fmt.Errorf("%s: %s: %s", resp.Status, document.Code, document.Message)
+	}
+	return nil
+}
+
+// abortMultipartUpload discards the parts of a failed multipart upload. It is best effort,
+// a bucket lifecycle rule cleans up the uploads it cannot abort.
+func (s *S3Store) abortMultipartUpload(key string, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	query := url.Values{"uploadId": {uploadID}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectURL(key)+"?"+query.Encode(), nil)
+	if err != nil {
+		return
+	}
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
+
+// Get downloads the object. The caller closes the returned body.
+// Returns ErrNotFound if the bucket holds no object under the key.
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", key, err)
+	}
+
+	resp, err := s.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %w", key, ErrNotFound)
+	}
+	if resp.StatusCode/100 != 2 {
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %w", key, responseError(resp))
+	}
+	return resp.Body, nil
+}
+
+func (s *S3Store) String() string {
+	if s.config.Prefix == "" {
+		return "s3://" + s.config.Bucket
+	}
+	return "s3://" + s.config.Bucket + "/" + s.config.Prefix
+}
+
+// objectURL returns the path-style URL of the object with every path segment URI-encoded
+func (s *S3Store) objectURL(key string) string {
+	objectPath := s.config.Bucket + "/" + key
+	if s.config.Prefix != "" {
+		objectPath = s.config.Bucket + "/" + s.config.Prefix + "/" + key
+	}
+	segments := strings.Split(objectPath, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.TrimSuffix(s.endpoint.String(), "/") + "/" + strings.Join(segments, "/")
+}
+
+// do signs the request when credentials are configured and sends it
+func (s *S3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.config.AccessKeyID != "" {
+		if s.config.SessionToken != "" {
+			req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+		}
+		signV4(req, payloadHash, s.config.AccessKeyID, s.config.SecretAccessKey, s.config.Region, s.now())
+	}
+	return s.client.Do(req)
+}
+
+// responseError describes a failed response by its status and the code of the S3 error document
+func responseError(resp *http.Response) error {
+	var document struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if xml.Unmarshal(data, &document) == nil && document.Code != "" {
+		return fmt.Errorf("%s: %s: %s", resp.Status, document.Code, document.Message)
+	}
+	return fmt.Errorf("%s", resp.Status)
+}
+
+// signV4 adds the X-Amz-Date and Authorization headers of AWS Signature Version 4 to the
+// request. The host, Content-Type, Content-MD5, Range and X-Amz-* headers are signed.
+func signV4(req *http.Request, payloadHash string, accessKeyID string, secretAccessKey string, region string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || name == "content-md5" || name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// canonicalQuery encodes the query parameters sorted by name and value
+func canonicalQuery(query url.Values) string {
+	var pairs []string
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters of RFC 3986
+func uriEncode(value string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			encoded.WriteByte(c)
+		} else {
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,79 @@
+// Package storage provides the object stores the client publishes job results to
+// and downloads job inputs from: a local directory or an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotFound is returned by Get when no object is stored under the key
+var ErrNotFound = errors.New("object not found")
+
+// Store is an object store keyed by slash-separated paths
+type Store interface {
+	// Put stores size bytes read from r under key, replacing any object already stored
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get opens the object stored under key. Returns ErrNotFound if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// String describes the location of the store for logs
+	String() string
+}
+
+// Open returns the store at location, which is either:
+//   - a local directory, given as a path or a file:// URL
+//   - an S3-compatible bucket, given as s3://bucket/prefix with the optional query
+//     parameters endpoint (defaults to AWS) and region (defaults to us-east-1)
+//
+// S3 credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN; requests are sent unsigned when no key is set.
+// Returns error if the location cannot be parsed.
+func Open(location string) (Store, error) {
+	if location == "" {
+		return nil, errors.New("store location is empty")
+	}
+	if !strings.Contains(location, "://") {
+		return NewLocalStore(location), nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store location %q: %w", location, err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid store location %q: missing directory", location)
+		}
+		return NewLocalStore(filepath.FromSlash(u.Path)), nil
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid store location %q: missing bucket", location)
+		}
+		return NewS3Store(S3Config{
+			Endpoint: u.Query().Get("endpoint"),
+			Region:   u.Query().Get("region"),
+			Bucket:   u.Host,
+			Prefix:   strings.Trim(u.Path, "/"),
+		}.WithEnvCredentials())
+	}
+	return nil, fmt.Errorf("unsupported store location %q, expected a directory, file:// or s3://", location)
+}
+
+// validateKey rejects keys that would escape the store
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// CanOpenURI reports whether OpenURI can download from uri: a file://, http://, https://
+// or s3:// URL. Other URIs, such as gs:// datasets, are left to the pipeline.
+func CanOpenURI(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	switch u.Scheme {
+	case "file", "http", "https", "s3":
+		return true
+	}
+	return false
+}
+
+// URIPolicy restricts where OpenURI downloads from. Dataset URIs are chosen by job creators,
+// so local files are only read under FileRoot, and http(s) and S3 endpoints on loopback,
+// private or link-local addresses, such as cloud metadata services, are refused.
+type URIPolicy struct {
+	FileRoot          string // directory file:// URIs must be under, file:// URIs are refused when empty
+	AllowPrivateHosts bool   // allow connections to loopback, private and link-local addresses
+}
+
+// ErrRefusedURI is returned by OpenURI for URIs its URIPolicy does not allow
+var ErrRefusedURI = errors.New("refused URI")
+
+// blockedNetworks are the address ranges not covered by the net.IP predicates that are not
+// reachable on the internet: "this network" and the shared address space of carrier-grade
+// NAT, which cloud providers also use for their metadata services
+var blockedNetworks = []*net.IPNet{
+	{IP: net.IPv4(0, 0, 0, 0), Mask: net.CIDRMask(8, 32)},
+	{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)},
+}
+
+// publicHTTPClient connects only to public addresses. The address is checked when the
+// connection is made, after name resolution and on every redirect, so that a host name
+// resolving to a private address is refused too.
+var publicHTTPClient = &http.Client{
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   checkPublicAddress,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	},
+}
+
+// httpClient returns the client connecting to the hosts the policy allows
+func (p URIPolicy) httpClient() *http.Client {
+	if p.AllowPrivateHosts {
+		return http.DefaultClient
+	}
+	return publicHTTPClient
+}
+
+// OpenURI opens the object at uri, which is either:
+//   - a local file under the FileRoot of policy, given as a file:// URL
+//   - a web resource, given as an http:// or https:// URL
+//   - an object of an S3-compatible bucket, given as s3://bucket/key with the query
+//     parameters of Open. The credentials of the environment are only used with the
+//     default AWS endpoint, never sent to an endpoint given by the URI.
+//
+// The caller closes the returned reader.
+// Returns ErrNotFound if there is no object at uri, ErrRefusedURI if policy does not
+// allow it, or error if it cannot be opened.
+func OpenURI(ctx context.Context, uri string, policy URIPolicy) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI %q: %w", uri, err)
+	}
+	switch u.Scheme {
+	case "file":
+		filePath, err := policy.resolveFile(filepath.FromSlash(u.Path))
+		if err != nil {
+			return nil, err
+		}
+		return openFile(filePath)
+	case "http", "https":
+		return openHTTP(ctx, policy.httpClient(), uri)
+	case "s3":
+		key := strings.TrimPrefix(u.Path, "/")
+		if u.Host == "" || key == "" {
+			return nil, fmt.Errorf("invalid URI %q: expected s3://bucket/key", uri)
+		}
+		config := S3Config{
+			Endpoint:   u.Query().Get("endpoint"),
+			Region:     u.Query().Get("region"),
+			Bucket:     u.Host,
+			HTTPClient: policy.httpClient(),
+		}
+		if config.Endpoint == "" {
+			config = config.WithEnvCredentials()
+		}
+		store, err := NewS3Store(config)
+		if err != nil {
+			return nil, err
+		}
+		return store.Get(ctx, key)
+	}
+	return nil, fmt.Errorf("unsupported URI %q, expected file://, http(s):// or s3://", uri)
+}
+
+// resolveFile returns the file at filePath with its symbolic links resolved.
+// Returns ErrRefusedURI if the file, or the target of a link, is outside FileRoot.
+func (p URIPolicy) resolveFile(filePath string) (string, error) {
+	if p.FileRoot == "" {
+		return "", fmt.Errorf("%w: file://%s, no dataset root is configured for local files", ErrRefusedURI, filepath.ToSlash(filePath))
+	}
+	root, err := filepath.Abs(p.FileRoot)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve dataset root %s: %w", p.FileRoot, err)
+	}
+	if !isUnder(root, filePath) {
+		return "", fmt.Errorf("%w: %s is outside the dataset root %s", ErrRefusedURI, filePath, root)
+	}
+
+	resolved, err := filepath.EvalSymlinks(filePath)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%s: %w", filePath, ErrNotFound)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve dataset root %s: %w", root, err)
+	}
+	if !isUnder(resolvedRoot, resolved) {
+		return "", fmt.Errorf("%w: %s links outside the dataset root %s", ErrRefusedURI, filePath, root)
+	}
+	return resolved, nil
+}
+
+// isUnder reports whether filePath is root or a path below it
+func isUnder(root string, filePath string) bool {
+	rel, err := filepath.Rel(root, filePath)
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// checkPublicAddress refuses connections to addresses that are not reachable on the internet
+func checkPublicAddress(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrRefusedURI, host)
+	}
+	return nil
+}
+
+// isPublicIP reports whether ip is a unicast address reachable on the internet
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// openFile opens a regular file
+func openFile(filePath string) (io.ReadCloser, error) {
+	info, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s: %w", filePath, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, fmt.Errorf("failed to open %s: not a regular file", filePath)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", filePath, err)
+	}
+	return file, nil
+}
+
+// openHTTP downloads a web resource with a GET request
+func openHTTP(ctx context.Context, client *http.Client, uri string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", uri, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", uri, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %w", uri, ErrNotFound)
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download %s: %s", uri, resp.Status)
+	}
+	return resp.Body, nil
+}